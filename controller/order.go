@@ -3,7 +3,11 @@ package controller
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -33,24 +37,58 @@ func NewOrder(log hclog.Logger, usecaseOrder usecase.Order) *Order {
 // @Summary      Importar Legado
 // @Description  Importação de pedidos do sistema legado.<br/><br/>
 // @Description  <strong>ATENÇÃO:</strong><br/>
-// @Description  A API mantém apenas os pedidos do último arquivo importado.<br/>
+// @Description  Por padrão (mode=replace) a API mantém apenas os pedidos do último arquivo importado.<br/>
+// @Description  Com mode=merge os usuários, pedidos e produtos do arquivo são incluídos ou atualizados nos pedidos já existentes, os produtos de cada pedido importado são substituídos e o total do pedido é recalculado.<br/>
 // @Description  Todo o conteúdo do arquivo será desconsiderado caso ocorra algum erro durante a importação.<br/>
+// @Description  O formato do arquivo é identificado pelo Content-Type: text/plain (posição fixa), text/csv (primeira linha com o nome das colunas) ou application/x-ndjson (um objeto JSON por linha).<br/>
+// @Description  Também são aceitos arquivos compactados application/gzip e application/zip, o formato dos arquivos compactados é identificado pela extensão (.csv, .ndjson ou posição fixa) e todos os arquivos do zip são importados como um único arquivo.<br/>
+// @Description  O arquivo é lido durante o envio e é limitado a LEGACY_IMPORT_MAX_SIZE bytes, os campos do formulário devem ser enviados antes do arquivo.<br/>
+// @Description  Cada importação é mantida no histórico (get /order/legacy/imports) e pode ser restaurada posteriormente.<br/>
+// @Description  Um arquivo com o mesmo conteúdo (SHA-256) ou a mesma Idempotency-Key de uma importação dos pedidos atuais não é importado novamente, o resultado da importação já realizada é retornado com o cabeçalho Idempotent-Replayed.<br/>
+// @Description  Os erros dos registros são retornados por campo com um código estável (ex.: USER_ID_INVALID), a posição do campo no registro de posição fixa e o conteúdo do campo, limitados a LEGACY_IMPORT_MAX_ERRORS erros.<br/>
+// @Description  O arquivo é convertido para UTF-8 de acordo com o campo encoding, as posições dos campos são contadas em caracteres, o BOM do UTF-8, as quebras de linha CRLF e as linhas em branco no final do arquivo são desconsiderados.<br/>
+// @Description  Com has_header=true e has_trailer=true o arquivo de posição fixa possui um header (data de geração e origem) e um trailer (quantidade de registros e total dos valores dos produtos), o arquivo é recusado quando o trailer for diferente dos registros lidos.<br/>
+// @Description  Com lenient=true apenas os registros inválidos são desconsiderados e ficam disponíveis para consulta na quarentena (get /order/legacy/rejects).<br/>
 // @Description  Por padrão a API armazena as informações do arquivo importado em memória, portanto se a API for reiniciada os pedidos do último arquivo importado serão perdido e precisará ser importado novamente.<br/>
 // @Description  É possível configurar a API para realizar o armazenamento do último arquivo importado em banco de dados.
 // @Tags         Pedidos
 // @Accept       json
 // @Produce      json
-// @Param        file   formData      file  false  "Arquivo a ser importado (formato TXT com posição fixa)" example(data_1.txt) validate(required)
+// @Param        file     formData      file  false  "Arquivo a ser importado (TXT com posição fixa definida pelo layout, CSV ou NDJSON, compactado ou não com gzip ou zip)" example(data_1.txt) validate(required)
+// @Param        mode     query         string  false  "Modo de importação" Enums(replace, merge) default(replace)
+// @Param        target   query         string  false  "Destino da importação, stage mantém os pedidos em homologação até serem promovidos" Enums(live, stage) default(live)
+// @Param        layout   query         string  false  "Layout dos registros do arquivo" default(default)
+// @Param        async    query         bool    false  "Executa a importação em segundo plano e retorna o Job criado" default(false)
+// @Param        lenient  query         bool    false  "Importa os registros válidos e mantém os registros rejeitados em quarentena" default(false)
+// @Param        encoding     formData  string  false  "Codificação dos caracteres do arquivo, auto identifica cada linha como UTF-8 ou Windows-1252" Enums(auto, utf-8, latin-1, windows-1252) default(auto)
+// @Param        has_header   formData  bool  false  "O primeiro registro do arquivo de posição fixa é o header com a data de geração e a origem" default(false)
+// @Param        has_trailer  formData  bool  false  "O último registro do arquivo de posição fixa é o trailer com a quantidade de registros e o total dos valores dos produtos" default(false)
+// @Param        X-Requested-By  header  string  false  "Solicitante da importação mantido no histórico, por padrão o endereço do cliente"
+// @Param        Idempotency-Key  header  string  false  "Chave da requisição, uma nova tentativa com a mesma chave retorna o resultado da importação já realizada"
 // @Success      200  {object}  model.LegacyImportResult
-// @Failure      400  {object}  model.Error
+// @Header       200  {string}  Idempotent-Replayed  "true quando o resultado é de uma importação já realizada"
+// @Success      202  {object}  model.LegacyImportJob
+// @Failure      400  {object}  model.ErrorRecordsValidate
 // @Failure      500  {object}  model.Error
 // @Router       /order/legacy/import [post]
 func (controllerOrder *Order) LegacyImport(rw http.ResponseWriter, req *http.Request) {
-	// Parse the multipart form
-	err := req.ParseMultipartForm(10 << 20) // Limit the maximum file size to 10MB
+	file, fileType, ok := controllerOrder.legacyFormFile(rw, req)
+
+	if !ok {
+		return
+	}
+
+	defer file.Close()
+
+	controllerOrder.legacyImport(rw, req, file, fileType)
+}
+
+// legacyImport imports the file received with the options of the request
+func (controllerOrder *Order) legacyImport(rw http.ResponseWriter, req *http.Request, file io.Reader, fileType legacyFile) {
+	modelLegacyImportOptions, async, err := validateParamsLegacyImport(req)
 
 	if err != nil {
-		responseError := model.BadRequestFormParsing()
+		responseError := model.BadRequestParamValidate(err.Error())
 
 		logger.LogErrorRequest(controllerOrder.Log, req, responseError.Message, err)
 
@@ -59,22 +97,101 @@ func (controllerOrder *Order) LegacyImport(rw http.ResponseWriter, req *http.Req
 		return
 	}
 
-	file, fileHeader, err := req.FormFile("file")
+	fileType.options(modelLegacyImportOptions)
+
+	if async {
+		controllerOrder.legacyImportAsync(rw, req, file, modelLegacyImportOptions)
+		return
+	}
+
+	modelLegacyImportResult, err := controllerOrder.UsecaseOrder.LegacyImport(file, modelLegacyImportOptions)
 
+	controllerOrder.legacyImportResponse(rw, req, modelLegacyImportResult, err)
+}
+
+// legacyImportResponse writes the result of the import or its error
+func (controllerOrder *Order) legacyImportResponse(rw http.ResponseWriter, req *http.Request, modelLegacyImportResult *model.LegacyImportResult, err error) {
 	if err != nil {
-		responseError := model.BadRequestRetrievingFile()
+		// the errors of the records are returned in their own structure
+		if errRecordValidate, ok := err.(usecase.ErrRecordValidate); ok {
+			rw.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(rw).Encode(model.BadRequestFileRecordValidate(errRecordValidate.Message, errRecordValidate.RecordsError, errRecordValidate.Total, errRecordValidate.Truncated))
+			return
+		}
 
-		logger.LogErrorRequest(controllerOrder.Log, req, responseError.Message, err)
+		var responseError *model.Error
+
+		if _, ok := err.(usecase.ErrParamValidate); ok {
+			responseError = model.BadRequestParamValidate(err.Error())
+
+			rw.WriteHeader(http.StatusBadRequest)
+		} else if _, ok := err.(usecase.ErrFileValidate); ok {
+			responseError = model.BadRequestFileValidate(err.Error())
+
+			rw.WriteHeader(http.StatusBadRequest)
+		} else if _, ok := err.(repository.ErrDuplicateKey); ok {
+			responseError = model.BadRequestRepositoryPersist(controllerOrder.Title, err.Error())
+
+			rw.WriteHeader(http.StatusBadRequest)
+		} else if _, ok := err.(usecase.ErrNotFound); ok {
+			responseError = model.NotFound("Upload")
+
+			rw.WriteHeader(http.StatusNotFound)
+		} else if _, ok := err.(usecase.ErrConflict); ok {
+			responseError = model.Conflict(err.Error())
+
+			rw.WriteHeader(http.StatusConflict)
+		} else {
+			responseError = model.InternalServerErrorRepositoryPersist(controllerOrder.Title)
+
+			logger.LogErrorRequest(controllerOrder.Log, req, responseError.Message, err)
+
+			rw.WriteHeader(http.StatusInternalServerError)
+		}
 
-		rw.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(rw).Encode(responseError)
 		return
 	}
 
+	if modelLegacyImportResult.Replayed {
+		rw.Header().Set(headerIdempotentReplayed, "true")
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	json.NewEncoder(rw).Encode(modelLegacyImportResult)
+}
+
+// LegacyValidate godoc
+// @Summary      Validar Legado
+// @Description  Valida o arquivo do sistema legado sem importar os pedidos.<br/>
+// @Description  Retorna o resumo que a importação teria com as opções informadas e todos os registros com erro.
+// @Tags         Pedidos
+// @Accept       json
+// @Produce      json
+// @Param        file     formData      file  false  "Arquivo a ser validado (TXT com posição fixa definida pelo layout, CSV ou NDJSON, compactado ou não com gzip ou zip)" example(data_1.txt) validate(required)
+// @Param        mode     query         string  false  "Modo de importação" Enums(replace, merge) default(replace)
+// @Param        layout   query         string  false  "Layout dos registros do arquivo" default(default)
+// @Param        lenient  query         bool    false  "Considera válido o arquivo que possuir ao menos um registro válido" default(false)
+// @Param        encoding     formData  string  false  "Codificação dos caracteres do arquivo, auto identifica cada linha como UTF-8 ou Windows-1252" Enums(auto, utf-8, latin-1, windows-1252) default(auto)
+// @Param        has_header   formData  bool  false  "O primeiro registro do arquivo de posição fixa é o header com a data de geração e a origem" default(false)
+// @Param        has_trailer  formData  bool  false  "O último registro do arquivo de posição fixa é o trailer com a quantidade de registros e o total dos valores dos produtos" default(false)
+// @Success      200  {object}  model.LegacyValidateResult
+// @Failure      400  {object}  model.Error
+// @Failure      500  {object}  model.Error
+// @Router       /order/legacy/validate [post]
+func (controllerOrder *Order) LegacyValidate(rw http.ResponseWriter, req *http.Request) {
+	file, fileType, ok := controllerOrder.legacyFormFile(rw, req)
+
+	if !ok {
+		return
+	}
+
 	defer file.Close()
 
-	if fileHeader.Header.Get("Content-Type") != "text/plain" {
-		responseError := model.BadRequestFileType()
+	modelLegacyImportOptions, err := validateParamsLegacyValidate(req)
+
+	if err != nil {
+		responseError := model.BadRequestParamValidate(err.Error())
 
 		logger.LogErrorRequest(controllerOrder.Log, req, responseError.Message, err)
 
@@ -83,21 +200,23 @@ func (controllerOrder *Order) LegacyImport(rw http.ResponseWriter, req *http.Req
 		return
 	}
 
-	modelLegacyImportResult, err := controllerOrder.UsecaseOrder.LegacyImport(file, false)
+	fileType.options(modelLegacyImportOptions)
+
+	modelLegacyValidateResult, err := controllerOrder.UsecaseOrder.LegacyValidate(file, modelLegacyImportOptions)
 
 	if err != nil {
 		var responseError *model.Error
 
-		if _, ok := err.(usecase.ErrRecordValidate); ok {
-			responseError = model.BadRequestFileRecordValidate(err.Error())
+		if _, ok := err.(usecase.ErrParamValidate); ok {
+			responseError = model.BadRequestParamValidate(err.Error())
 
 			rw.WriteHeader(http.StatusBadRequest)
-		} else if _, ok := err.(repository.ErrDuplicateKey); ok {
-			responseError = model.BadRequestRepositoryPersist(controllerOrder.Title, err.Error())
+		} else if _, ok := err.(usecase.ErrFileValidate); ok {
+			responseError = model.BadRequestFileValidate(err.Error())
 
 			rw.WriteHeader(http.StatusBadRequest)
 		} else {
-			responseError = model.InternalServerErrorRepositoryPersist(controllerOrder.Title)
+			responseError = model.InternalServerErrorGeneral(err.Error())
 
 			logger.LogErrorRequest(controllerOrder.Log, req, responseError.Message, err)
 
@@ -108,8 +227,175 @@ func (controllerOrder *Order) LegacyImport(rw http.ResponseWriter, req *http.Req
 		return
 	}
 
-	rw.WriteHeader(http.StatusOK)
-	json.NewEncoder(rw).Encode(modelLegacyImportResult)
+	json.NewEncoder(rw).Encode(modelLegacyValidateResult)
+}
+
+// legacyFile describes the format and the compression of the uploaded file
+type legacyFile struct {
+	format      string
+	compression string
+	name        string
+}
+
+// legacyFileTypes identifies the legacy file by content type, the format of
+// the compressed files is identified by the extension of the files inside it
+var legacyFileTypes = map[string]legacyFile{
+	"text/plain":                   {format: model.LegacyImportFormatFixedWidth},
+	"text/csv":                     {format: model.LegacyImportFormatCSV},
+	"application/x-ndjson":         {format: model.LegacyImportFormatNDJSON},
+	"application/gzip":             {compression: model.LegacyImportCompressionGzip},
+	"application/x-gzip":           {compression: model.LegacyImportCompressionGzip},
+	"application/zip":              {compression: model.LegacyImportCompressionZip},
+	"application/x-zip-compressed": {compression: model.LegacyImportCompressionZip},
+}
+
+func (legacyFile legacyFile) options(modelLegacyImportOptions *model.LegacyImportOptions) {
+	modelLegacyImportOptions.Format = legacyFile.format
+	modelLegacyImportOptions.Compression = legacyFile.compression
+	modelLegacyImportOptions.FileName = legacyFile.name
+}
+
+// legacyFormValueMaxSize limits the size of the fields of the form
+const legacyFormValueMaxSize = 10 << 10
+
+const legacyFormValueErrorMessageMaxSize = "The param %s is greater than %d bytes"
+
+// legacyFormFile retrieves the legacy file of the multipart form and its
+// type, writing the error response when it is not possible. The file is
+// streamed from the request body instead of being stored by the form parsing,
+// so the fields of the form are read only when sent before the file.
+func (controllerOrder *Order) legacyFormFile(rw http.ResponseWriter, req *http.Request) (io.ReadCloser, legacyFile, bool) {
+	// the query params are available as form values
+	err := req.ParseForm()
+
+	var multipartReader *multipart.Reader
+
+	if err == nil {
+		multipartReader, err = req.MultipartReader()
+	}
+
+	var part *multipart.Part
+
+	for err == nil {
+		part, err = multipartReader.NextPart()
+
+		if err != nil || part.FormName() == "file" {
+			break
+		}
+
+		var value []byte
+
+		// the byte beyond the limit tells a truncated field apart
+		value, err = io.ReadAll(io.LimitReader(part, legacyFormValueMaxSize+1))
+
+		if err == nil && len(value) > legacyFormValueMaxSize {
+			responseError := model.BadRequestParamValidate(fmt.Sprintf(legacyFormValueErrorMessageMaxSize, part.FormName(), legacyFormValueMaxSize))
+
+			rw.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(rw).Encode(responseError)
+			return nil, legacyFile{}, false
+		}
+
+		req.Form.Add(part.FormName(), string(value))
+		req.PostForm.Add(part.FormName(), string(value))
+	}
+
+	if err == io.EOF {
+		responseError := model.BadRequestRetrievingFile()
+
+		logger.LogErrorRequest(controllerOrder.Log, req, responseError.Message, err)
+
+		rw.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(rw).Encode(responseError)
+		return nil, legacyFile{}, false
+	}
+
+	if err != nil {
+		responseError := model.BadRequestFormParsing()
+
+		logger.LogErrorRequest(controllerOrder.Log, req, responseError.Message, err)
+
+		rw.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(rw).Encode(responseError)
+		return nil, legacyFile{}, false
+	}
+
+	fileType, ok := legacyFileTypes[legacyMediaType(part.Header.Get("Content-Type"))]
+
+	if !ok {
+		part.Close()
+
+		responseError := model.BadRequestFileType()
+
+		logger.LogErrorRequest(controllerOrder.Log, req, responseError.Message, err)
+
+		rw.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(rw).Encode(responseError)
+		return nil, legacyFile{}, false
+	}
+
+	fileType.name = part.FileName()
+
+	return legacyFilePart{Part: part, multipartReader: multipartReader}, fileType, true
+}
+
+// legacyFilePart reports the errors reading the uploaded file, as an upload
+// interrupted, as errors of the file instead of internal errors. A field sent
+// after the file is rejected at the end of the file, before the import is
+// persisted, instead of being ignored.
+type legacyFilePart struct {
+	*multipart.Part
+	multipartReader *multipart.Reader
+}
+
+func (part legacyFilePart) Read(p []byte) (int, error) {
+	n, err := part.Part.Read(p)
+
+	if err == io.EOF {
+		if nextPart, nextErr := part.multipartReader.NextPart(); nextErr == nil {
+			err = usecase.ErrFileValidate{Message: fmt.Sprintf(usecase.OrderErrorMessageFormFieldAfterFile, nextPart.FormName())}
+		}
+	} else if err != nil {
+		err = usecase.ErrFileValidate{Message: fmt.Sprintf(usecase.OrderErrorMessageFileRead, err)}
+	}
+
+	return n, err
+}
+
+// ListLegacyRejects godoc
+// @Summary      Listar Registros Rejeitados
+// @Description  Retorna os registros rejeitados na última importação realizada com lenient=true.
+// @Tags         Pedidos
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  model.LegacyRejects
+// @Failure      404  {object}  model.Error
+// @Failure      500  {object}  model.Error
+// @Router       /order/legacy/rejects [get]
+// @Router       /staging/order/legacy/rejects [get]
+func (controllerOrder *Order) ListLegacyRejects(rw http.ResponseWriter, req *http.Request) {
+	modelLegacyRejects, err := controllerOrder.UsecaseOrder.ListLegacyRejects()
+
+	if err != nil {
+		var responseError *model.Error
+
+		if _, ok := err.(repository.ErrNotFound); ok {
+			responseError = model.NotFound("Reject")
+
+			rw.WriteHeader(http.StatusNotFound)
+		} else {
+			responseError = model.InternalServerErrorRepositoryLoad("Reject")
+
+			logger.LogErrorRequest(controllerOrder.Log, req, responseError.Message, err)
+
+			rw.WriteHeader(http.StatusInternalServerError)
+		}
+
+		json.NewEncoder(rw).Encode(responseError)
+		return
+	}
+
+	json.NewEncoder(rw).Encode(modelLegacyRejects)
 }
 
 // GetDetailsByOrderID godoc
@@ -124,8 +410,11 @@ func (controllerOrder *Order) LegacyImport(rw http.ResponseWriter, req *http.Req
 // @Failure      404  {object}  model.Error
 // @Failure      500  {object}  model.Error
 // @Router       /order/{id} [get]
+// @Router       /staging/order/{id} [get]
 func (controllerOrder *Order) GetDetailsByOrderID(rw http.ResponseWriter, req *http.Request) {
-	paramOrderID := strings.Split(req.URL.Path, "/")[3]
+	// the last segment, the same handler serves the staged orders
+	paths := strings.Split(req.URL.Path, "/")
+	paramOrderID := paths[len(paths)-1]
 
 	orderID, err := strconv.ParseInt(paramOrderID, 10, 64)
 
@@ -162,43 +451,51 @@ func (controllerOrder *Order) GetDetailsByOrderID(rw http.ResponseWriter, req *h
 
 // ListDetails godoc
 // @Summary      Listar Pedidos
-// @Description  Retorna todos os Pedidos ou os Pedidos referente ao período informado. O período não pode ser superior a 31 dias.
+// @Description  Retorna todos os Pedidos ou os Pedidos referente ao período informado. O período não pode ser superior ao limite da política de validação (padrão 31 dias).<br/>
+// @Description  Os filtros informados são combinados e os Pedidos são paginados pelo usuário e pedido, o cabeçalho Link (rel="next") informa a URL da próxima página.
 // @Tags         Pedidos
 // @Accept       json
 // @Produce      json
 // @Param        from query      string  false  "Data da Compra Inicial (AAAA-MM-DD)" example("2020-05-23")
 // @Param        to   query      string  false  "Data da Compra Final (AAAA-MM-DD)" example("2020-05-23")
+// @Param        user_id     query  []int   false  "IDs dos Usuários, repetindo o parâmetro ou separados por vírgula" collectionFormat(multi)
+// @Param        order_id    query  []int   false  "IDs dos Pedidos, repetindo o parâmetro ou separados por vírgula" collectionFormat(multi)
+// @Param        product_id  query  int     false  "ID do Produto contido no Pedido"
+// @Param        min_total   query  number  false  "Valor Total mínimo do Pedido" example(100.00)
+// @Param        max_total   query  number  false  "Valor Total máximo do Pedido" example(2000.00)
+// @Param        min_value   query  number  false  "Valor mínimo de um Produto do Pedido, do mesmo Produto de product_id quando informado" example(10.00)
+// @Param        max_value   query  number  false  "Valor máximo de um Produto do Pedido, do mesmo Produto de product_id quando informado" example(500.00)
+// @Param        name        query  string  false  "Parte do Nome do Usuário, sem diferenciar maiúsculas e minúsculas" example(prosacco)
+// @Param        limit   query   int     false  "Quantidade de Pedidos da página, limitada por ORDER_PAGE_MAX_SIZE" example(100)
+// @Param        cursor  query   string  false  "Cursor da página retornado no cabeçalho Link da página anterior"
 // @Success      200  {object}  model.OrdersDetails
+// @Header       200  {string}  Link  "URL da próxima página (rel=\"next\")"
 // @Failure      400  {object}  model.Error
 // @Failure      404  {object}  model.Error
 // @Failure      500  {object}  model.Error
 // @Router       /order [get]
+// @Router       /staging/order [get]
 func (controllerOrder *Order) ListDetails(rw http.ResponseWriter, req *http.Request) {
-	fromParam := req.URL.Query().Get("from")
-	toParam := req.URL.Query().Get("to")
-
-	modelOrdersDetails := &model.OrdersDetails{}
-	modelOrderRangeBuyDate := &model.OrderRangeBuyDate{}
-	var err error
+	modelOrderFilter := &model.OrderFilter{}
 
-	if fromParam == "" && toParam == "" {
-		modelOrdersDetails, err = controllerOrder.UsecaseOrder.ListDetails()
-	} else {
-		modelOrderRangeBuyDate, err = validateQueryParamsOrderRangeBuyDate(fromParam, toParam)
+	modelOrderPage, err := validateQueryParamsOrderPage(req.URL.Query().Get("limit"), req.URL.Query().Get("cursor"))
 
-		if err != nil {
-			responseError := model.BadRequestParamValidate(err.Error())
+	if err == nil {
+		modelOrderFilter, err = validateQueryParamsOrderFilter(req.URL.Query())
+	}
 
-			logger.LogErrorRequest(controllerOrder.Log, req, responseError.Message, err)
+	if err != nil {
+		responseError := model.BadRequestParamValidate(err.Error())
 
-			rw.WriteHeader(http.StatusBadRequest)
-			json.NewEncoder(rw).Encode(responseError)
-			return
-		}
+		logger.LogErrorRequest(controllerOrder.Log, req, responseError.Message, err)
 
-		modelOrdersDetails, err = controllerOrder.UsecaseOrder.ListDetailsByRangeBuyDate(modelOrderRangeBuyDate)
+		rw.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(rw).Encode(responseError)
+		return
 	}
 
+	modelOrdersDetailsPage, err := controllerOrder.UsecaseOrder.ListDetails(modelOrderFilter, modelOrderPage)
+
 	if err != nil {
 		var responseError *model.Error
 
@@ -222,7 +519,134 @@ func (controllerOrder *Order) ListDetails(rw http.ResponseWriter, req *http.Requ
 		return
 	}
 
-	json.NewEncoder(rw).Encode(modelOrdersDetails)
+	if modelOrdersDetailsPage.Next != nil {
+		setHeaderLinkNext(rw, req, modelOrdersDetailsPage.Next.String())
+	}
+
+	json.NewEncoder(rw).Encode(modelOrdersDetailsPage.OrdersDetails)
+}
+
+// setHeaderLinkNext informs the URL of the next page, which is the same
+// request with the cursor of the last item of the page
+func setHeaderLinkNext(rw http.ResponseWriter, req *http.Request, cursor string) {
+	urlNext := *req.URL
+	query := urlNext.Query()
+	query.Set("cursor", cursor)
+	urlNext.RawQuery = query.Encode()
+
+	rw.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", urlNext.RequestURI()))
+}
+
+func validateQueryParamsOrderPage(limitParam, cursorParam string) (*model.OrderPage, error) {
+	modelOrderPage := &model.OrderPage{}
+
+	messages := []string{}
+
+	if limitParam != "" {
+		limit, err := strconv.Atoi(limitParam)
+
+		if err != nil || limit < 1 {
+			messages = append(messages, usecase.OrderPageErrorMessageLimitInvalid)
+		}
+
+		modelOrderPage.Limit = limit
+	}
+
+	if cursorParam != "" {
+		modelOrderCursor, err := model.ParseOrderCursor(cursorParam)
+
+		if err != nil {
+			messages = append(messages, usecase.OrderPageErrorMessageCursorInvalid)
+		}
+
+		modelOrderPage.Cursor = modelOrderCursor
+	}
+
+	if len(messages) > 0 {
+		return nil, errors.New(strings.Join(messages, ";"))
+	}
+
+	return modelOrderPage, nil
+}
+
+// validateQueryParamsOrderFilter reads the filters of the order list, the
+// lists of IDs are informed repeating the param or separated by commas
+func validateQueryParamsOrderFilter(query url.Values) (*model.OrderFilter, error) {
+	modelOrderFilter := &model.OrderFilter{Name: strings.TrimSpace(query.Get("name"))}
+
+	fromParam := query.Get("from")
+	toParam := query.Get("to")
+
+	if fromParam != "" || toParam != "" {
+		modelOrderRangeBuyDate, err := validateQueryParamsOrderRangeBuyDate(fromParam, toParam)
+
+		if err != nil {
+			return nil, err
+		}
+
+		modelOrderFilter.BuyDate = modelOrderRangeBuyDate
+	}
+
+	messages := []string{}
+
+	paramIDs := func(name string) []int64 {
+		ids := []int64{}
+
+		for _, param := range query[name] {
+			for _, value := range strings.Split(param, ",") {
+				id, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+
+				if err != nil {
+					messages = append(messages, fmt.Sprintf(usecase.OrderFilterErrorMessageParamInvalid, name))
+					return nil
+				}
+
+				ids = append(ids, id)
+			}
+		}
+
+		return ids
+	}
+
+	modelOrderFilter.UserIDs = paramIDs("user_id")
+	modelOrderFilter.OrderIDs = paramIDs("order_id")
+
+	if productIDParam := query.Get("product_id"); productIDParam != "" {
+		productID, err := strconv.ParseInt(productIDParam, 10, 64)
+
+		if err != nil {
+			messages = append(messages, fmt.Sprintf(usecase.OrderFilterErrorMessageParamInvalid, "product_id"))
+		}
+
+		modelOrderFilter.ProductID = &productID
+	}
+
+	paramMoney := func(name string) *model.Money {
+		moneyParam := query.Get(name)
+
+		if moneyParam == "" {
+			return nil
+		}
+
+		money, err := model.ParseMoney(moneyParam)
+
+		if err != nil {
+			messages = append(messages, fmt.Sprintf(usecase.OrderFilterErrorMessageParamInvalid, name))
+		}
+
+		return &money
+	}
+
+	modelOrderFilter.MinTotal = paramMoney("min_total")
+	modelOrderFilter.MaxTotal = paramMoney("max_total")
+	modelOrderFilter.MinValue = paramMoney("min_value")
+	modelOrderFilter.MaxValue = paramMoney("max_value")
+
+	if len(messages) > 0 {
+		return nil, errors.New(strings.Join(messages, ";"))
+	}
+
+	return modelOrderFilter, nil
 }
 
 func validateQueryParamsOrderRangeBuyDate(fromParam, toParam string) (*model.OrderRangeBuyDate, error) {
@@ -260,3 +684,77 @@ func validateQueryParamsOrderRangeBuyDate(fromParam, toParam string) (*model.Ord
 
 	return modelOrderRangeBuyDate, nil
 }
+
+func validateParamsLegacyImport(req *http.Request) (*model.LegacyImportOptions, bool, error) {
+	modelLegacyImportOptions, messages := paramsLegacyImportOptions(req)
+
+	async, err := parseParamBool(req.URL.Query().Get("async"))
+
+	if err != nil {
+		messages = append(messages, "async invalid")
+	}
+
+	if len(messages) > 0 {
+		return nil, false, errors.New(strings.Join(messages, ";"))
+	}
+
+	modelLegacyImportOptions.IdempotencyKey = strings.TrimSpace(req.Header.Get(headerIdempotencyKey))
+
+	return modelLegacyImportOptions, async, nil
+}
+
+func validateParamsLegacyValidate(req *http.Request) (*model.LegacyImportOptions, error) {
+	modelLegacyImportOptions, messages := paramsLegacyImportOptions(req)
+
+	if len(messages) > 0 {
+		return nil, errors.New(strings.Join(messages, ";"))
+	}
+
+	return modelLegacyImportOptions, nil
+}
+
+func paramsLegacyImportOptions(req *http.Request) (*model.LegacyImportOptions, []string) {
+	modelLegacyImportOptions := &model.LegacyImportOptions{
+		Mode:        req.FormValue("mode"),
+		Target:      req.FormValue("target"),
+		Layout:      req.FormValue("layout"),
+		Encoding:    req.FormValue("encoding"),
+		RequestedBy: requestedBy(req),
+	}
+
+	messages := []string{}
+
+	hasHeader, err := parseParamBool(req.FormValue("has_header"))
+
+	if err != nil {
+		messages = append(messages, "has_header invalid")
+	}
+
+	modelLegacyImportOptions.HasHeader = hasHeader
+
+	hasTrailer, err := parseParamBool(req.FormValue("has_trailer"))
+
+	if err != nil {
+		messages = append(messages, "has_trailer invalid")
+	}
+
+	modelLegacyImportOptions.HasTrailer = hasTrailer
+
+	lenient, err := parseParamBool(req.FormValue("lenient"))
+
+	if err != nil {
+		messages = append(messages, "lenient invalid")
+	}
+
+	modelLegacyImportOptions.Lenient = lenient
+
+	return modelLegacyImportOptions, messages
+}
+
+func parseParamBool(param string) (bool, error) {
+	if param == "" {
+		return false, nil
+	}
+
+	return strconv.ParseBool(param)
+}
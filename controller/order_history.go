@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+	logger "github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/logger"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/usecase"
+)
+
+// headerRequestedBy identifies who requested the import, the client address
+// is used when it is not informed
+const headerRequestedBy = "X-Requested-By"
+
+// headerIdempotencyKey identifies the retries of the same import request and
+// headerIdempotentReplayed marks the response with the result of the import
+// already persisted
+const (
+	headerIdempotencyKey     = "Idempotency-Key"
+	headerIdempotentReplayed = "Idempotent-Replayed"
+)
+
+// ListLegacyImports godoc
+// @Summary      Listar Histórico de Importações
+// @Description  Retorna as importações do sistema legado, da mais recente para a mais antiga.<br/>
+// @Description  A importação com live=true é a que possui os pedidos disponíveis na API.
+// @Tags         Pedidos
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  model.LegacyImports
+// @Failure      404  {object}  model.Error
+// @Failure      500  {object}  model.Error
+// @Router       /order/legacy/imports [get]
+func (controllerOrder *Order) ListLegacyImports(rw http.ResponseWriter, req *http.Request) {
+	modelLegacyImports, err := controllerOrder.UsecaseOrder.ListLegacyImports()
+
+	if err != nil {
+		var responseError *model.Error
+
+		if _, ok := err.(repository.ErrNotFound); ok {
+			responseError = model.NotFound("Import")
+
+			rw.WriteHeader(http.StatusNotFound)
+		} else {
+			responseError = model.InternalServerErrorRepositoryLoad("Import")
+
+			logger.LogErrorRequest(controllerOrder.Log, req, responseError.Message, err)
+
+			rw.WriteHeader(http.StatusInternalServerError)
+		}
+
+		json.NewEncoder(rw).Encode(responseError)
+		return
+	}
+
+	json.NewEncoder(rw).Encode(modelLegacyImports)
+}
+
+// LegacyImportRestore godoc
+// @Summary      Restaurar Importação
+// @Description  Torna novamente disponíveis na API os pedidos e os registros rejeitados de uma importação anterior do histórico.<br/>
+// @Description  Os pedidos atuais são substituídos de forma atômica e o cache é limpo.
+// @Tags         Pedidos
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  false  "ID da Importação" example(1) validate(required)
+// @Success      200  {object}  model.LegacyImport
+// @Failure      400  {object}  model.Error
+// @Failure      404  {object}  model.Error
+// @Failure      409  {object}  model.Error
+// @Failure      500  {object}  model.Error
+// @Router       /order/legacy/imports/{id}/restore [post]
+func (controllerOrder *Order) LegacyImportRestore(rw http.ResponseWriter, req *http.Request) {
+	paramImportID := strings.Split(req.URL.Path, "/")[5]
+
+	importID, err := strconv.ParseInt(paramImportID, 10, 64)
+
+	if err != nil {
+		responseError := model.BadRequestParamValidate("ID invalid")
+		rw.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(rw).Encode(responseError)
+		return
+	}
+
+	modelLegacyImport, err := controllerOrder.UsecaseOrder.LegacyImportRestore(importID)
+
+	if err != nil {
+		var responseError *model.Error
+
+		if _, ok := err.(repository.ErrNotFound); ok {
+			responseError = model.NotFound("Import")
+
+			rw.WriteHeader(http.StatusNotFound)
+		} else if _, ok := err.(usecase.ErrConflict); ok {
+			responseError = model.Conflict(err.Error())
+
+			rw.WriteHeader(http.StatusConflict)
+		} else {
+			responseError = model.InternalServerErrorRepositoryPersist("Import")
+
+			logger.LogErrorRequest(controllerOrder.Log, req, responseError.Message, err)
+
+			rw.WriteHeader(http.StatusInternalServerError)
+		}
+
+		json.NewEncoder(rw).Encode(responseError)
+		return
+	}
+
+	json.NewEncoder(rw).Encode(modelLegacyImport)
+}
+
+func requestedBy(req *http.Request) string {
+	if requester := strings.TrimSpace(req.Header.Get(headerRequestedBy)); requester != "" {
+		return requester
+	}
+
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+
+	if err != nil {
+		return req.RemoteAddr
+	}
+
+	return host
+}
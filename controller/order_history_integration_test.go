@@ -0,0 +1,221 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/usecase"
+)
+
+// testIntegrationOrderLegacyImportRestore imports over the file imported by
+// testIntegrationOrderLegacyImport a file with a rejected record and, after
+// restoring the first import, a merge. The rejects are restored with the
+// dataset and the merge over the dataset it was merged into. The file of
+// testIntegrationOrderLegacyImport is live again at the end.
+func testIntegrationOrderLegacyImportRestore(t *testing.T) {
+	recordRejected := "0000000075                                  Bobbie Batz00000007980000000002     1578.5720211308"
+
+	lines := []string{
+		"0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308",
+		recordRejected,
+	}
+
+	testIntegrationOrderLegacyImportFile(t, lines, map[string]string{"lenient": "true"}, &model.LegacyImportResult{ImportID: 2, Users: 1, Orders: 1, Products: 1, Accepted: 1, Rejected: 1})
+
+	modelLegacyRejects, code := testIntegrationOrderListLegacyRejects()
+
+	if code != http.StatusOK || len(*modelLegacyRejects) != 1 || (*modelLegacyRejects)[0].Record != recordRejected {
+		t.Fatalf("ListLegacyRejects() got res.code = %v, res.body = %v, want %v with the record %v", code, modelLegacyRejects, http.StatusOK, recordRejected)
+	}
+
+	type test struct {
+		name         string
+		reqParam     int64
+		mergeLines   []string
+		wantRejects  *model.LegacyRejects
+		wantTotals   map[int64]model.Money
+		wantNotFound []int64
+	}
+
+	tests := []test{
+		{
+			name:         "RestoreWithoutRejects",
+			reqParam:     1,
+			wantRejects:  &model.LegacyRejects{},
+			wantTotals:   map[int64]model.Money{753: 284628, 523: 58674},
+			wantNotFound: []int64{},
+		},
+		{
+			name:         "RestoreWithRejects",
+			reqParam:     2,
+			wantRejects:  modelLegacyRejects,
+			wantTotals:   map[int64]model.Money{753: 183674},
+			wantNotFound: []int64{523},
+		},
+		{
+			name:     "RestoreMergedIntoRestored",
+			reqParam: 3,
+			// merged into the first import restored below
+			mergeLines: []string{
+				"0000000070                              Palmer Prosacco00000007530000000004       10.0020210308",
+			},
+			wantRejects:  &model.LegacyRejects{},
+			wantTotals:   map[int64]model.Money{753: 1000, 523: 58674},
+			wantNotFound: []int64{},
+		},
+		{
+			name:         "RestoreFirstImport",
+			reqParam:     1,
+			wantRejects:  &model.LegacyRejects{},
+			wantTotals:   map[int64]model.Money{753: 284628, 523: 58674},
+			wantNotFound: []int64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mergeLines != nil {
+				testIntegrationOrderLegacyImportRestoreID(t, 1)
+				testIntegrationOrderLegacyImportFile(t, tt.mergeLines, map[string]string{"mode": model.LegacyImportModeMerge}, &model.LegacyImportResult{ImportID: tt.reqParam, Users: 1, Orders: 1, Products: 1, Accepted: 1})
+				testIntegrationOrderLegacyImportRestoreID(t, 2)
+			}
+
+			testIntegrationOrderLegacyImportRestoreID(t, tt.reqParam)
+
+			modelLegacyRejects, _ := testIntegrationOrderListLegacyRejects()
+
+			if !reflect.DeepEqual(modelLegacyRejects, tt.wantRejects) {
+				t.Errorf("ListLegacyRejects() got res.body = %v, want %v", modelLegacyRejects, tt.wantRejects)
+			}
+
+			for orderID, wantTotal := range tt.wantTotals {
+				modelOrderDetails, code := testIntegrationOrderGetDetails(orderID)
+
+				if code != http.StatusOK || modelOrderDetails.Orders[0].Total != wantTotal {
+					t.Errorf("GetDetailsByOrderID(%v) got res.code = %v, res.body = %v, want %v with the total %v", orderID, code, modelOrderDetails, http.StatusOK, wantTotal)
+				}
+			}
+
+			for _, orderID := range tt.wantNotFound {
+				if _, code := testIntegrationOrderGetDetails(orderID); code != http.StatusNotFound {
+					t.Errorf("GetDetailsByOrderID(%v) got res.code = %v, want %v", orderID, code, http.StatusNotFound)
+				}
+			}
+		})
+	}
+}
+
+// testIntegrationOrderLegacyImportNotRestorable imports without copying the
+// records to the history a file and a merge over it, neither can be restored.
+// The file of testIntegrationOrderLegacyImport is live again at the end.
+func testIntegrationOrderLegacyImportNotRestorable(t *testing.T) {
+	lines := []string{
+		"0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308",
+	}
+
+	testIntegrationConfig.LegacyImportHistoryRecords = false
+	defer func() { testIntegrationConfig.LegacyImportHistoryRecords = true }()
+
+	testIntegrationOrderLegacyImportFile(t, lines, map[string]string{}, &model.LegacyImportResult{ImportID: 4, Users: 1, Orders: 1, Products: 1, Accepted: 1})
+
+	testIntegrationConfig.LegacyImportHistoryRecords = true
+
+	// the merge needs the records of the import it was merged into
+	lines[0] = "0000000070                              Palmer Prosacco00000007530000000004       10.0020210308"
+
+	testIntegrationOrderLegacyImportFile(t, lines, map[string]string{"mode": model.LegacyImportModeMerge}, &model.LegacyImportResult{ImportID: 5, Users: 1, Orders: 1, Products: 1, Accepted: 1})
+
+	for _, importID := range []int64{4, 5} {
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/order/legacy/imports/%v/restore", importID), nil)
+		res := httptest.NewRecorder()
+
+		http.HandlerFunc(testIntegrationControllerOrder.LegacyImportRestore).ServeHTTP(res, req)
+
+		resBody := &model.Error{}
+		json.NewDecoder(res.Body).Decode(resBody)
+
+		wantResBody := model.Conflict(usecase.OrderErrorMessageImportNotRestorable)
+
+		if res.Code != http.StatusConflict || !reflect.DeepEqual(resBody, wantResBody) {
+			t.Errorf("LegacyImportRestore(%v) got res.code = %v, res.body = %v, want %v, %v", importID, res.Code, resBody, http.StatusConflict, wantResBody)
+		}
+	}
+
+	testIntegrationOrderLegacyImportRestoreID(t, 1)
+}
+
+func testIntegrationOrderLegacyImportFile(t *testing.T, lines []string, fields map[string]string, wantResBody *model.LegacyImportResult) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	// the fields of the form are sent before the file
+	for name, value := range fields {
+		writer.WriteField(name, value)
+	}
+
+	fileWriter, _ := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Disposition": []string{`form-data; name="file"; filename="file.txt"`},
+		"Content-Type":        []string{"text/plain"},
+	})
+	fileWriter.Write([]byte(strings.Join(lines, "\n")))
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/order/legacy/import", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	res := httptest.NewRecorder()
+
+	http.HandlerFunc(testIntegrationControllerOrder.LegacyImport).ServeHTTP(res, req)
+
+	modelLegacyImportResult := &model.LegacyImportResult{}
+	json.NewDecoder(res.Body).Decode(modelLegacyImportResult)
+
+	if res.Code != http.StatusOK || !reflect.DeepEqual(modelLegacyImportResult, wantResBody) {
+		t.Fatalf("LegacyImport() got res.code = %v, res.body = %v, want %v, %v", res.Code, modelLegacyImportResult, http.StatusOK, wantResBody)
+	}
+}
+
+func testIntegrationOrderLegacyImportRestoreID(t *testing.T, importID int64) {
+	url := fmt.Sprintf("/api/order/legacy/imports/%v/restore", importID)
+
+	req := httptest.NewRequest(http.MethodPost, url, nil)
+	res := httptest.NewRecorder()
+
+	http.HandlerFunc(testIntegrationControllerOrder.LegacyImportRestore).ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("LegacyImportRestore(%v) got res.code = %v, want %v", importID, res.Code, http.StatusOK)
+	}
+}
+
+func testIntegrationOrderListLegacyRejects() (*model.LegacyRejects, int) {
+	req := httptest.NewRequest(http.MethodGet, "/api/order/legacy/rejects", nil)
+	res := httptest.NewRecorder()
+
+	http.HandlerFunc(testIntegrationControllerOrder.ListLegacyRejects).ServeHTTP(res, req)
+
+	modelLegacyRejects := &model.LegacyRejects{}
+	json.NewDecoder(res.Body).Decode(modelLegacyRejects)
+
+	return modelLegacyRejects, res.Code
+}
+
+func testIntegrationOrderGetDetails(orderID int64) (*model.OrderDetails, int) {
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/order/%v", orderID), nil)
+	res := httptest.NewRecorder()
+
+	http.HandlerFunc(testIntegrationControllerOrder.GetDetailsByOrderID).ServeHTTP(res, req)
+
+	modelOrderDetails := &model.OrderDetails{}
+	json.NewDecoder(res.Body).Decode(modelOrderDetails)
+
+	return modelOrderDetails, res.Code
+}
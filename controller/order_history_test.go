@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	mock_usecase "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/usecase"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/usecase"
+	"github.com/hashicorp/go-hclog"
+)
+
+var testOrderLegacyImport = model.LegacyImport{
+	ID:         1,
+	ImportedAt: time.Date(2023, 06, 11, 00, 00, 00, 000, time.UTC),
+	FileName:   "data_1.txt",
+	Checksum:   "741875fa236e53dd2e8fc0635482a1503a39aafafb54d087a6b1eb36e2e81ff2",
+	Mode:       model.LegacyImportModeReplace,
+	Result: model.LegacyImportResult{
+		Users:    100,
+		Orders:   1084,
+		Products: 2352,
+		Accepted: 2352,
+	},
+	RequestedBy: "192.168.0.1",
+	Live:        true,
+}
+
+func TestOrderListLegacyImports(t *testing.T) {
+	modelLegacyImports := model.LegacyImports{testOrderLegacyImport}
+
+	type test struct {
+		name        string
+		resBody     interface{}
+		wantResCode int
+		wantResBody interface{}
+		mockOn      func(*mock_usecase.MockUsecaseOrder)
+	}
+
+	tests := []test{
+		{
+			name:        "NotFoundError",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusNotFound,
+			wantResBody: model.NotFound("Import"),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("ListLegacyImports").Return(nil, repository.ErrNotFound{Message: "not found"})
+			},
+		},
+		{
+			name:        "InternalServerError",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusInternalServerError,
+			wantResBody: model.InternalServerErrorRepositoryLoad("Import"),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("ListLegacyImports").Return(nil, errors.New("InternalServerError"))
+			},
+		},
+		{
+			name:        "Success",
+			resBody:     &model.LegacyImports{},
+			wantResCode: http.StatusOK,
+			wantResBody: &modelLegacyImports,
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("ListLegacyImports").Return(&modelLegacyImports, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := hclog.New(&hclog.LoggerOptions{Level: hclog.LevelFromString("OFF")})
+			mockUsecaseOrder := new(mock_usecase.MockUsecaseOrder)
+
+			tt.mockOn(mockUsecaseOrder)
+
+			controllerOrder := NewOrder(log, mockUsecaseOrder)
+
+			req, _ := http.NewRequest(http.MethodGet, "/api/order/legacy/imports", nil)
+			handler := http.HandlerFunc(controllerOrder.ListLegacyImports)
+			res := httptest.NewRecorder()
+
+			handler.ServeHTTP(res, req)
+
+			if !reflect.DeepEqual(res.Code, tt.wantResCode) {
+				t.Errorf("ListLegacyImports() got res.code = %v, want %v", res.Code, tt.wantResCode)
+			}
+
+			json.NewDecoder(res.Body).Decode(tt.resBody)
+
+			if !reflect.DeepEqual(tt.resBody, tt.wantResBody) {
+				t.Errorf("ListLegacyImports() got res.body = %v, want %v", tt.resBody, tt.wantResBody)
+			}
+		})
+	}
+}
+
+func TestOrderLegacyImportRestore(t *testing.T) {
+	type test struct {
+		name        string
+		reqImportID string
+		resBody     interface{}
+		wantResCode int
+		wantResBody interface{}
+		mockOn      func(*mock_usecase.MockUsecaseOrder)
+	}
+
+	tests := []test{
+		{
+			name:        "ParamIDError",
+			reqImportID: "X",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestParamValidate("ID invalid"),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+			},
+		},
+		{
+			name:        "NotFoundError",
+			reqImportID: "2",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusNotFound,
+			wantResBody: model.NotFound("Import"),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("LegacyImportRestore").Return(nil, repository.ErrNotFound{Message: "not found"})
+			},
+		},
+		{
+			name:        "NotRestorableError",
+			reqImportID: "1",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusConflict,
+			wantResBody: model.Conflict(usecase.OrderErrorMessageImportNotRestorable),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("LegacyImportRestore").Return(nil, usecase.ErrConflict{Message: usecase.OrderErrorMessageImportNotRestorable})
+			},
+		},
+		{
+			name:        "InternalServerError",
+			reqImportID: "1",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusInternalServerError,
+			wantResBody: model.InternalServerErrorRepositoryPersist("Import"),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("LegacyImportRestore").Return(nil, errors.New("InternalServerError"))
+			},
+		},
+		{
+			name:        "Success",
+			reqImportID: "1",
+			resBody:     &model.LegacyImport{},
+			wantResCode: http.StatusOK,
+			wantResBody: &testOrderLegacyImport,
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("LegacyImportRestore").Return(&testOrderLegacyImport, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := hclog.New(&hclog.LoggerOptions{Level: hclog.LevelFromString("OFF")})
+			mockUsecaseOrder := new(mock_usecase.MockUsecaseOrder)
+
+			tt.mockOn(mockUsecaseOrder)
+
+			controllerOrder := NewOrder(log, mockUsecaseOrder)
+
+			req, _ := http.NewRequest(http.MethodPost, "/api/order/legacy/imports/"+tt.reqImportID+"/restore", nil)
+			handler := http.HandlerFunc(controllerOrder.LegacyImportRestore)
+			res := httptest.NewRecorder()
+
+			handler.ServeHTTP(res, req)
+
+			if !reflect.DeepEqual(res.Code, tt.wantResCode) {
+				t.Errorf("LegacyImportRestore() got res.code = %v, want %v", res.Code, tt.wantResCode)
+			}
+
+			json.NewDecoder(res.Body).Decode(tt.resBody)
+
+			if !reflect.DeepEqual(tt.resBody, tt.wantResBody) {
+				t.Errorf("LegacyImportRestore() got res.body = %v, want %v", tt.resBody, tt.wantResBody)
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
 	cache "github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/cache/redis"
@@ -26,7 +27,7 @@ var (
 	testIntegrationConfig, _            = util.LoadConfig("./../")
 	testIntegrationRepository, _        = repository.NewInMemory(testIntegrationConfig)
 	testIntegrationCache, _             = cache.NewRedis(testIntegrationConfig)
-	testIntegrationUsecaseOrder         = usecase.NewOrder(testIntegrationRepository, testIntegrationCache)
+	testIntegrationUsecaseOrder         = usecase.NewOrder(testIntegrationRepository, testIntegrationCache, testIntegrationConfig)
 	testIntegrationControllerOrder      = NewOrder(testIntegrationLog, testIntegrationUsecaseOrder)
 	testIntegrationControllerOrderTitle = "Order"
 )
@@ -35,6 +36,13 @@ func TestIntegrationOrder(t *testing.T) {
 	testIntegrationOrderLegacyImport(t)
 	testIntegrationOrderGetDetailsByOrderID(t)
 	testIntegrationOrderListDetails(t)
+	testIntegrationUserGetDetailsByUserID(t)
+	testIntegrationUserListSummaries(t)
+	testIntegrationProductGetSummaryByProductID(t)
+	testIntegrationProductListOrdersDetails(t)
+	testIntegrationReport(t)
+	testIntegrationOrderLegacyImportRestore(t)
+	testIntegrationOrderLegacyImportNotRestorable(t)
 }
 
 func testIntegrationOrderLegacyImport(t *testing.T) {
@@ -122,7 +130,7 @@ func testIntegrationOrderLegacyImport(t *testing.T) {
 
 				fileWriter, _ := writer.CreatePart(textproto.MIMEHeader{
 					"Content-Disposition": []string{`form-data; name="file"; filename="file.txt"`},
-					"Content-Type":        []string{"text/csv"},
+					"Content-Type":        []string{"application/pdf"},
 				})
 
 				if _, err := io.Copy(fileWriter, fileBuffer); err != nil {
@@ -162,25 +170,21 @@ func testIntegrationOrderLegacyImport(t *testing.T) {
 
 				return writer, body
 			},
-			resBody:     &model.Error{},
+			resBody:     &model.ErrorRecordsValidate{},
 			wantResCode: http.StatusBadRequest,
 			wantResBody: func() interface{} {
-				jsonBytes, _ := json.Marshal(model.LegacyRecordsError{
-					{
-						Line: 1,
-						Message: strings.Join([]string{
-							usecase.OrderErrorMessageUserIDInvalid,
-							usecase.OrderErrorMessageUserNameInvalid,
-							usecase.OrderErrorMessageOrderIDInvalid,
-							usecase.OrderErrorMessageProductIDInvalid,
-							usecase.OrderErrorMessageProductValueInvalid,
-							usecase.OrderErrorMessageBuyDateInvalid,
-						},
-							";"),
-					},
-				})
+				fieldErrors := []model.LegacyFieldError{
+					{Code: usecase.OrderErrorCodeUserIDInvalid, Field: model.LegacyFieldUserID, ColumnStart: 1, ColumnEnd: 10, Value: "000000007x", Message: usecase.OrderErrorMessageUserIDInvalid},
+					{Code: usecase.OrderErrorCodeUserNameInvalid, Field: model.LegacyFieldUserName, ColumnStart: 11, ColumnEnd: 55, Value: "o", Rule: model.ValidationRuleUserNameLength, Message: usecase.OrderErrorMessageUserNameInvalid},
+					{Code: usecase.OrderErrorCodeOrderIDInvalid, Field: model.LegacyFieldOrderID, ColumnStart: 56, ColumnEnd: 65, Value: "000000075x", Message: usecase.OrderErrorMessageOrderIDInvalid},
+					{Code: usecase.OrderErrorCodeProductIDInvalid, Field: model.LegacyFieldProductID, ColumnStart: 66, ColumnEnd: 75, Value: "000000000x", Message: usecase.OrderErrorMessageProductIDInvalid},
+					{Code: usecase.OrderErrorCodeProductValueInvalid, Field: model.LegacyFieldProductValue, ColumnStart: 76, ColumnEnd: 87, Value: "1836.7x", Message: usecase.OrderErrorMessageProductValueInvalid},
+					{Code: usecase.OrderErrorCodeBuyDateInvalid, Field: model.LegacyFieldBuyDate, ColumnStart: 88, ColumnEnd: 95, Value: "20211308", Message: usecase.OrderErrorMessageBuyDateInvalid},
+				}
+
+				modelLegacyRecordsError := model.LegacyRecordsError{{Line: 1, Errors: fieldErrors}}
 
-				return model.BadRequestFileRecordValidate(string(jsonBytes))
+				return model.BadRequestFileRecordValidate(usecase.OrderErrorMessageRecordValidate, modelLegacyRecordsError, len(fieldErrors), false)
 			},
 		},
 		{
@@ -215,7 +219,7 @@ func testIntegrationOrderLegacyImport(t *testing.T) {
 			resBody:     &model.LegacyImportResult{},
 			wantResCode: http.StatusOK,
 			wantResBody: func() interface{} {
-				return &model.LegacyImportResult{Users: 2, Orders: 3, Products: 4}
+				return &model.LegacyImportResult{ImportID: 1, Users: 2, Orders: 3, Products: 4, Accepted: 4}
 			},
 		},
 	}
@@ -285,15 +289,15 @@ func testIntegrationOrderGetDetailsByOrderID(t *testing.T) {
 					{
 						OrderID: 753,
 						BuyDate: "2021-03-08",
-						Total:   2846.28,
+						Total:   284628,
 						Products: []model.OrderDetailsProduct{
 							{
 								ID:    3,
-								Value: 1836.74,
+								Value: 183674,
 							},
 							{
 								ID:    3,
-								Value: 1009.54,
+								Value: 100954,
 							},
 						},
 					},
@@ -368,7 +372,7 @@ func testIntegrationOrderListDetails(t *testing.T) {
 			reqParam:    fmt.Sprintf("?from=%v&to=2020-01-01", usecase.OrderBuyDateMin.AddDate(0, 0, -1).Format("2006-01-02")),
 			resBody:     &model.Error{},
 			wantResCode: http.StatusBadRequest,
-			wantResBody: model.BadRequestParamValidate(usecase.OrderRangeBuyDateErrorMessageFromBetween),
+			wantResBody: model.BadRequestParamValidate(fmt.Sprintf(usecase.OrderRangeBuyDateErrorMessageFromBetween, usecase.OrderBuyDateMin.Format("2006-01-02"), time.Now().UTC().Format("2006-01-02"))),
 		},
 		{
 			name:        "NotFoundError",
@@ -390,11 +394,11 @@ func testIntegrationOrderListDetails(t *testing.T) {
 						{
 							OrderID: 798,
 							BuyDate: "2021-11-16",
-							Total:   1578.57,
+							Total:   157857,
 							Products: []model.OrderDetailsProduct{
 								{
 									ID:    2,
-									Value: 1578.57,
+									Value: 157857,
 								},
 							},
 						},
@@ -415,15 +419,15 @@ func testIntegrationOrderListDetails(t *testing.T) {
 						{
 							OrderID: 753,
 							BuyDate: "2021-03-08",
-							Total:   2846.28,
+							Total:   284628,
 							Products: []model.OrderDetailsProduct{
 								{
 									ID:    3,
-									Value: 1836.74,
+									Value: 183674,
 								},
 								{
 									ID:    3,
-									Value: 1009.54,
+									Value: 100954,
 								},
 							},
 						},
@@ -433,25 +437,128 @@ func testIntegrationOrderListDetails(t *testing.T) {
 					UserID:   75,
 					UserName: "Bobbie Batz",
 					Orders: []model.OrderDetailsOrder{
+						{
+							OrderID: 523,
+							BuyDate: "2021-09-03",
+							Total:   58674,
+							Products: []model.OrderDetailsProduct{
+								{
+									ID:    3,
+									Value: 58674,
+								},
+							},
+						},
 						{
 							OrderID: 798,
 							BuyDate: "2021-11-16",
-							Total:   1578.57,
+							Total:   157857,
 							Products: []model.OrderDetailsProduct{
 								{
 									ID:    2,
-									Value: 1578.57,
+									Value: 157857,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "FilterNotFoundError",
+			reqParam:    "?user_id=70&order_id=798",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusNotFound,
+			wantResBody: model.NotFound("Order"),
+		},
+		{
+			name:        "FilterNameTotalSuccess",
+			reqParam:    "?name=BATZ&min_total=1000",
+			resBody:     &model.OrdersDetails{},
+			wantResCode: http.StatusOK,
+			wantResBody: &model.OrdersDetails{
+				{
+					UserID:   75,
+					UserName: "Bobbie Batz",
+					Orders: []model.OrderDetailsOrder{
+						{
+							OrderID: 798,
+							BuyDate: "2021-11-16",
+							Total:   157857,
+							Products: []model.OrderDetailsProduct{
+								{
+									ID:    2,
+									Value: 157857,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "FilterProductValueSuccess",
+			reqParam:    "?product_id=3&max_value=1009.54",
+			resBody:     &model.OrdersDetails{},
+			wantResCode: http.StatusOK,
+			wantResBody: &model.OrdersDetails{
+				{
+					UserID:   70,
+					UserName: "Palmer Prosacco",
+					Orders: []model.OrderDetailsOrder{
+						{
+							OrderID: 753,
+							BuyDate: "2021-03-08",
+							Total:   284628,
+							Products: []model.OrderDetailsProduct{
+								{
+									ID:    3,
+									Value: 183674,
+								},
+								{
+									ID:    3,
+									Value: 100954,
 								},
 							},
 						},
+					},
+				},
+				{
+					UserID:   75,
+					UserName: "Bobbie Batz",
+					Orders: []model.OrderDetailsOrder{
+						{
+							OrderID: 523,
+							BuyDate: "2021-09-03",
+							Total:   58674,
+							Products: []model.OrderDetailsProduct{
+								{
+									ID:    3,
+									Value: 58674,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "NextPageSuccess",
+			reqParam:    "?limit=1&cursor=NzA6NzUz",
+			resBody:     &model.OrdersDetails{},
+			wantResCode: http.StatusOK,
+			wantResBody: &model.OrdersDetails{
+				{
+					UserID:   75,
+					UserName: "Bobbie Batz",
+					Orders: []model.OrderDetailsOrder{
 						{
 							OrderID: 523,
 							BuyDate: "2021-09-03",
-							Total:   586.74,
+							Total:   58674,
 							Products: []model.OrderDetailsProduct{
 								{
 									ID:    3,
-									Value: 586.74,
+									Value: 58674,
 								},
 							},
 						},
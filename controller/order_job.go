@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	logger "github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/logger"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/usecase"
+)
+
+const pathApiOrderLegacyImportJobs = "/api/order/legacy/import/jobs/"
+
+func (controllerOrder *Order) legacyImportAsync(rw http.ResponseWriter, req *http.Request, file io.Reader, modelLegacyImportOptions *model.LegacyImportOptions) {
+	modelLegacyImportJob, err := controllerOrder.UsecaseOrder.LegacyImportAsync(file, modelLegacyImportOptions)
+
+	if err != nil {
+		var responseError *model.Error
+
+		if _, ok := err.(usecase.ErrParamValidate); ok {
+			responseError = model.BadRequestParamValidate(err.Error())
+
+			rw.WriteHeader(http.StatusBadRequest)
+		} else if _, ok := err.(usecase.ErrFileValidate); ok {
+			responseError = model.BadRequestFileValidate(err.Error())
+
+			rw.WriteHeader(http.StatusBadRequest)
+		} else {
+			responseError = model.InternalServerErrorGeneral(err.Error())
+
+			logger.LogErrorRequest(controllerOrder.Log, req, responseError.Message, err)
+
+			rw.WriteHeader(http.StatusInternalServerError)
+		}
+
+		json.NewEncoder(rw).Encode(responseError)
+		return
+	}
+
+	rw.Header().Set("Location", pathApiOrderLegacyImportJobs+modelLegacyImportJob.ID)
+	rw.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(rw).Encode(modelLegacyImportJob)
+}
+
+// GetLegacyImportJob godoc
+// @Summary      Consultar Job de Importação
+// @Description  Retorna a situação, o progresso e o resultado do Job de importação do sistema legado.
+// @Tags         Pedidos
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  false  "ID do Job" validate(required)
+// @Success      200  {object}  model.LegacyImportJob
+// @Failure      404  {object}  model.Error
+// @Router       /order/legacy/import/jobs/{id} [get]
+func (controllerOrder *Order) GetLegacyImportJob(rw http.ResponseWriter, req *http.Request) {
+	jobID := strings.TrimPrefix(req.URL.Path, pathApiOrderLegacyImportJobs)
+
+	modelLegacyImportJob, err := controllerOrder.UsecaseOrder.GetLegacyImportJob(jobID)
+
+	if err != nil {
+		responseError := model.NotFound("Job")
+
+		rw.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(rw).Encode(responseError)
+		return
+	}
+
+	json.NewEncoder(rw).Encode(modelLegacyImportJob)
+}
+
+// CancelLegacyImportJob godoc
+// @Summary      Cancelar Job de Importação
+// @Description  Solicita o cancelamento do Job de importação do sistema legado.<br/>
+// @Description  O cancelamento é recusado (409) quando a importação já iniciou a gravação dos registros (situação persisting).
+// @Tags         Pedidos
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  false  "ID do Job" validate(required)
+// @Success      202  {object}  model.LegacyImportJob
+// @Failure      404  {object}  model.Error
+// @Failure      409  {object}  model.Error
+// @Router       /order/legacy/import/jobs/{id} [delete]
+func (controllerOrder *Order) CancelLegacyImportJob(rw http.ResponseWriter, req *http.Request) {
+	jobID := strings.TrimPrefix(req.URL.Path, pathApiOrderLegacyImportJobs)
+
+	modelLegacyImportJob, err := controllerOrder.UsecaseOrder.CancelLegacyImportJob(jobID)
+
+	if err != nil {
+		var responseError *model.Error
+
+		if _, ok := err.(usecase.ErrConflict); ok {
+			responseError = model.Conflict(err.Error())
+
+			rw.WriteHeader(http.StatusConflict)
+		} else {
+			responseError = model.NotFound("Job")
+
+			rw.WriteHeader(http.StatusNotFound)
+		}
+
+		json.NewEncoder(rw).Encode(responseError)
+		return
+	}
+
+	rw.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(rw).Encode(modelLegacyImportJob)
+}
@@ -0,0 +1,248 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"reflect"
+	"testing"
+	"time"
+
+	mock_usecase "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/usecase"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/usecase"
+	"github.com/hashicorp/go-hclog"
+)
+
+func TestOrderLegacyImportAsync(t *testing.T) {
+	modelLegacyImportJob := model.LegacyImportJob{
+		ID:        "0b4f7d6c-3a57-4d1c-9d0e-5c9d1a2b3c4d",
+		State:     model.LegacyImportJobStatePending,
+		CreatedAt: time.Date(2023, 06, 11, 00, 00, 00, 000, time.UTC),
+	}
+
+	type test struct {
+		name         string
+		reqParam     string
+		resBody      interface{}
+		wantResCode  int
+		wantResBody  interface{}
+		wantLocation string
+		mockOn       func(*mock_usecase.MockUsecaseOrder)
+	}
+
+	tests := []test{
+		{
+			name:        "ParamAsyncError",
+			reqParam:    "?async=X",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestParamValidate("async invalid"),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+			},
+		},
+		{
+			name:        "ParamLenientError",
+			reqParam:    "?async=true&lenient=X",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestParamValidate("lenient invalid"),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+			},
+		},
+		{
+			name:        "ParamModeError",
+			reqParam:    "?async=true&mode=append",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestParamValidate(usecase.OrderErrorMessageModeInvalid),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("LegacyImportAsync").Return(nil, usecase.ErrParamValidate{Message: usecase.OrderErrorMessageModeInvalid})
+			},
+		},
+		{
+			name:        "FileValidateError",
+			reqParam:    "?async=true",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestFileValidate(fmt.Sprintf(usecase.OrderErrorMessageFileSizeLimit, 10)),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("LegacyImportAsync").Return(nil, usecase.ErrFileValidate{Message: fmt.Sprintf(usecase.OrderErrorMessageFileSizeLimit, 10)})
+			},
+		},
+		{
+			name:         "Accepted",
+			reqParam:     "?async=true",
+			resBody:      &model.LegacyImportJob{},
+			wantResCode:  http.StatusAccepted,
+			wantResBody:  &modelLegacyImportJob,
+			wantLocation: "/api/order/legacy/import/jobs/" + modelLegacyImportJob.ID,
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("LegacyImportAsync").Return(&modelLegacyImportJob, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := hclog.New(&hclog.LoggerOptions{Level: hclog.LevelFromString("OFF")})
+			mockUsecaseOrder := new(mock_usecase.MockUsecaseOrder)
+
+			tt.mockOn(mockUsecaseOrder)
+
+			controllerOrder := NewOrder(log, mockUsecaseOrder)
+
+			// Create a new HTTP request with a file upload
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+
+			fileWriter, _ := writer.CreatePart(textproto.MIMEHeader{
+				"Content-Disposition": []string{`form-data; name="file"; filename="file.txt"`},
+				"Content-Type":        []string{"text/plain"},
+			})
+
+			fileContent := "0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308"
+
+			if _, err := io.Copy(fileWriter, bytes.NewBufferString(fileContent)); err != nil {
+				t.Fatalf("Failed to write file content to form file: %v", err)
+			}
+			writer.Close()
+
+			url := fmt.Sprintf("/api/order/legacy/import%v", tt.reqParam)
+
+			req := httptest.NewRequest(http.MethodPost, url, body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+
+			handler := http.HandlerFunc(controllerOrder.LegacyImport)
+			res := httptest.NewRecorder()
+
+			handler.ServeHTTP(res, req)
+
+			if !reflect.DeepEqual(res.Code, tt.wantResCode) {
+				t.Errorf("LegacyImport() got res.code = %v, want %v", res.Code, tt.wantResCode)
+			}
+
+			if res.Header().Get("Location") != tt.wantLocation {
+				t.Errorf("LegacyImport() got res.location = %v, want %v", res.Header().Get("Location"), tt.wantLocation)
+			}
+
+			json.NewDecoder(res.Body).Decode(tt.resBody)
+
+			if !reflect.DeepEqual(tt.resBody, tt.wantResBody) {
+				t.Errorf("LegacyImport() got res.body = %v, want %v", tt.resBody, tt.wantResBody)
+			}
+		})
+	}
+}
+
+func TestOrderGetLegacyImportJob(t *testing.T) {
+	modelLegacyImportJob := model.LegacyImportJob{
+		ID:             "0b4f7d6c-3a57-4d1c-9d0e-5c9d1a2b3c4d",
+		State:          model.LegacyImportJobStateRunning,
+		LinesParsed:    1000,
+		LinesPersisted: 0,
+		CreatedAt:      time.Date(2023, 06, 11, 00, 00, 00, 000, time.UTC),
+	}
+
+	type test struct {
+		name        string
+		reqMethod   string
+		resBody     interface{}
+		wantResCode int
+		wantResBody interface{}
+		mockOn      func(*mock_usecase.MockUsecaseOrder)
+	}
+
+	tests := []test{
+		{
+			name:        "GetNotFoundError",
+			reqMethod:   http.MethodGet,
+			resBody:     &model.Error{},
+			wantResCode: http.StatusNotFound,
+			wantResBody: model.NotFound("Job"),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("GetLegacyImportJob").Return(nil, usecase.ErrNotFound{Message: usecase.OrderErrorMessageJobNotFound})
+			},
+		},
+		{
+			name:        "GetSuccess",
+			reqMethod:   http.MethodGet,
+			resBody:     &model.LegacyImportJob{},
+			wantResCode: http.StatusOK,
+			wantResBody: &modelLegacyImportJob,
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("GetLegacyImportJob").Return(&modelLegacyImportJob, nil)
+			},
+		},
+		{
+			name:        "CancelNotFoundError",
+			reqMethod:   http.MethodDelete,
+			resBody:     &model.Error{},
+			wantResCode: http.StatusNotFound,
+			wantResBody: model.NotFound("Job"),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("CancelLegacyImportJob").Return(nil, usecase.ErrNotFound{Message: usecase.OrderErrorMessageJobNotFound})
+			},
+		},
+		{
+			name:        "CancelConflictError",
+			reqMethod:   http.MethodDelete,
+			resBody:     &model.Error{},
+			wantResCode: http.StatusConflict,
+			wantResBody: model.Conflict(usecase.OrderErrorMessageJobFinished),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("CancelLegacyImportJob").Return(nil, usecase.ErrConflict{Message: usecase.OrderErrorMessageJobFinished})
+			},
+		},
+		{
+			name:        "CancelSuccess",
+			reqMethod:   http.MethodDelete,
+			resBody:     &model.LegacyImportJob{},
+			wantResCode: http.StatusAccepted,
+			wantResBody: &modelLegacyImportJob,
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("CancelLegacyImportJob").Return(&modelLegacyImportJob, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := hclog.New(&hclog.LoggerOptions{Level: hclog.LevelFromString("OFF")})
+			mockUsecaseOrder := new(mock_usecase.MockUsecaseOrder)
+
+			tt.mockOn(mockUsecaseOrder)
+
+			controllerOrder := NewOrder(log, mockUsecaseOrder)
+
+			url := fmt.Sprintf("/api/order/legacy/import/jobs/%v", modelLegacyImportJob.ID)
+
+			req, _ := http.NewRequest(tt.reqMethod, url, nil)
+
+			handler := http.HandlerFunc(controllerOrder.GetLegacyImportJob)
+
+			if tt.reqMethod == http.MethodDelete {
+				handler = http.HandlerFunc(controllerOrder.CancelLegacyImportJob)
+			}
+
+			res := httptest.NewRecorder()
+
+			handler.ServeHTTP(res, req)
+
+			if !reflect.DeepEqual(res.Code, tt.wantResCode) {
+				t.Errorf("LegacyImportJob() got res.code = %v, want %v", res.Code, tt.wantResCode)
+			}
+
+			json.NewDecoder(res.Body).Decode(tt.resBody)
+
+			if !reflect.DeepEqual(tt.resBody, tt.wantResBody) {
+				t.Errorf("LegacyImportJob() got res.body = %v, want %v", tt.resBody, tt.wantResBody)
+			}
+		})
+	}
+}
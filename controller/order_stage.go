@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+	logger "github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/logger"
+)
+
+// LegacyStagePromote godoc
+// @Summary      Promover Importação em Homologação
+// @Description  Torna disponíveis na API os pedidos importados com target=stage, que podem ser consultados antes em /staging/order.<br/>
+// @Description  Os pedidos atuais são substituídos de forma atômica, a importação é incluída no histórico e o cache é limpo.
+// @Tags         Pedidos
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  model.LegacyImport
+// @Failure      404  {object}  model.Error
+// @Failure      500  {object}  model.Error
+// @Router       /order/legacy/stage/promote [post]
+func (controllerOrder *Order) LegacyStagePromote(rw http.ResponseWriter, req *http.Request) {
+	modelLegacyImport, err := controllerOrder.UsecaseOrder.LegacyStagePromote()
+
+	if err != nil {
+		var responseError *model.Error
+
+		if _, ok := err.(repository.ErrNotFound); ok {
+			responseError = model.NotFound("Stage")
+
+			rw.WriteHeader(http.StatusNotFound)
+		} else {
+			responseError = model.InternalServerErrorRepositoryPersist("Stage")
+
+			logger.LogErrorRequest(controllerOrder.Log, req, responseError.Message, err)
+
+			rw.WriteHeader(http.StatusInternalServerError)
+		}
+
+		json.NewEncoder(rw).Encode(responseError)
+		return
+	}
+
+	json.NewEncoder(rw).Encode(modelLegacyImport)
+}
@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	mock_usecase "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/usecase"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+	"github.com/hashicorp/go-hclog"
+)
+
+func TestOrderLegacyStagePromote(t *testing.T) {
+	type test struct {
+		name        string
+		resBody     interface{}
+		wantResCode int
+		wantResBody interface{}
+		mockOn      func(*mock_usecase.MockUsecaseOrder)
+	}
+
+	tests := []test{
+		{
+			name:        "NotFoundError",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusNotFound,
+			wantResBody: model.NotFound("Stage"),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("LegacyStagePromote").Return(nil, repository.ErrNotFound{Message: "not found"})
+			},
+		},
+		{
+			name:        "InternalServerError",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusInternalServerError,
+			wantResBody: model.InternalServerErrorRepositoryPersist("Stage"),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("LegacyStagePromote").Return(nil, errors.New("InternalServerError"))
+			},
+		},
+		{
+			name:        "Success",
+			resBody:     &model.LegacyImport{},
+			wantResCode: http.StatusOK,
+			wantResBody: &testOrderLegacyImport,
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("LegacyStagePromote").Return(&testOrderLegacyImport, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := hclog.New(&hclog.LoggerOptions{Level: hclog.LevelFromString("OFF")})
+			mockUsecaseOrder := new(mock_usecase.MockUsecaseOrder)
+
+			tt.mockOn(mockUsecaseOrder)
+
+			controllerOrder := NewOrder(log, mockUsecaseOrder)
+
+			req, _ := http.NewRequest(http.MethodPost, "/api/order/legacy/stage/promote", nil)
+			handler := http.HandlerFunc(controllerOrder.LegacyStagePromote)
+			res := httptest.NewRecorder()
+
+			handler.ServeHTTP(res, req)
+
+			if !reflect.DeepEqual(res.Code, tt.wantResCode) {
+				t.Errorf("LegacyStagePromote() got res.code = %v, want %v", res.Code, tt.wantResCode)
+			}
+
+			json.NewDecoder(res.Body).Decode(tt.resBody)
+
+			if !reflect.DeepEqual(tt.resBody, tt.wantResBody) {
+				t.Errorf("LegacyStagePromote() got res.body = %v, want %v", tt.resBody, tt.wantResBody)
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"net/http/httptest"
 	"net/textproto"
 	"reflect"
+	"strings"
 	"testing"
 
 	mock_usecase "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/usecase"
@@ -120,7 +121,7 @@ func TestOrderLegacyImport(t *testing.T) {
 
 				fileWriter, _ := writer.CreatePart(textproto.MIMEHeader{
 					"Content-Disposition": []string{`form-data; name="file"; filename="file.txt"`},
-					"Content-Type":        []string{"text/csv"},
+					"Content-Type":        []string{"application/pdf"},
 				})
 
 				if _, err := io.Copy(fileWriter, fileBuffer); err != nil {
@@ -137,7 +138,7 @@ func TestOrderLegacyImport(t *testing.T) {
 			},
 		},
 		{
-			name: "BadRequestFileRecordValidate",
+			name: "BadRequestParamValidate",
 			reqFormData: func() (*multipart.Writer, *bytes.Buffer) {
 				// Create a new test file with content
 				fileContent :=
@@ -156,15 +157,64 @@ func TestOrderLegacyImport(t *testing.T) {
 				if _, err := io.Copy(fileWriter, fileBuffer); err != nil {
 					t.Fatalf("Failed to write file content to form file: %v", err)
 				}
+				writer.WriteField("mode", "append")
 				writer.Close()
 
 				return writer, body
 			},
 			resBody:     &model.Error{},
 			wantResCode: http.StatusBadRequest,
-			wantResBody: model.BadRequestFileRecordValidate("Record Error"),
+			wantResBody: model.BadRequestParamValidate(usecase.OrderErrorMessageModeInvalid),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("LegacyImport").Return(nil, usecase.ErrParamValidate{Message: usecase.OrderErrorMessageModeInvalid})
+			},
+		},
+		{
+			name: "BadRequestFileRecordValidate",
+			reqFormData: func() (*multipart.Writer, *bytes.Buffer) {
+				// Create a new test file with content
+				fileContent :=
+					"0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308"
+				fileBuffer := bytes.NewBufferString(fileContent)
+
+				// Create a new HTTP request with a file upload
+				body := &bytes.Buffer{}
+				writer := multipart.NewWriter(body)
+
+				fileWriter, _ := writer.CreatePart(textproto.MIMEHeader{
+					"Content-Disposition": []string{`form-data; name="file"; filename="file.txt"`},
+					"Content-Type":        []string{"text/plain"},
+				})
+
+				if _, err := io.Copy(fileWriter, fileBuffer); err != nil {
+					t.Fatalf("Failed to write file content to form file: %v", err)
+				}
+				writer.Close()
+
+				return writer, body
+			},
+			resBody:     &model.ErrorRecordsValidate{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: &model.ErrorRecordsValidate{
+				Code:    400.8,
+				Message: "Record Error",
+				Errors: []model.LegacyRecordFieldError{
+					{Line: 1, LegacyFieldError: model.LegacyFieldError{Code: "USER_ID_INVALID", Field: "user_id", ColumnStart: 1, ColumnEnd: 10, Value: "000000007x", Message: "UserID invalid"}},
+					{Line: 2, LegacyFieldError: model.LegacyFieldError{Code: "ORDER_USER_DIVERGENT", Message: "OrderID belongs to more than one UserID"}, Lines: []model.LegacyRecordLine{{Line: 2}, {Line: 3}}},
+				},
+				Total:     3,
+				Truncated: true,
+			},
 			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
-				mockUsecaseOrder.On("LegacyImport").Return(nil, usecase.ErrRecordValidate{Message: "Record Error"})
+				mockUsecaseOrder.On("LegacyImport").Return(nil, usecase.ErrRecordValidate{
+					Message: "Record Error",
+					RecordsError: model.LegacyRecordsError{
+						{Line: 1, Message: "UserID invalid", Errors: []model.LegacyFieldError{{Code: "USER_ID_INVALID", Field: "user_id", ColumnStart: 1, ColumnEnd: 10, Value: "000000007x", Message: "UserID invalid"}}},
+						{Line: 2, Message: "OrderID belongs to more than one UserID", Lines: []model.LegacyRecordLine{{Line: 2}, {Line: 3}}, Errors: []model.LegacyFieldError{{Code: "ORDER_USER_DIVERGENT", Message: "OrderID belongs to more than one UserID"}}},
+					},
+					Total:     3,
+					Truncated: true,
+				})
 			},
 		},
 		{
@@ -296,6 +346,136 @@ func TestOrderLegacyImport(t *testing.T) {
 	}
 }
 
+func TestOrderLegacyValidate(t *testing.T) {
+	modelLegacyValidateResult := model.LegacyValidateResult{
+		Valid: false,
+		Result: model.LegacyImportResult{
+			Users:    1,
+			Orders:   1,
+			Products: 1,
+			Accepted: 1,
+			Rejected: 1,
+		},
+		Errors: model.LegacyRecordsError{
+			{Line: 2, Message: usecase.OrderErrorMessageUserIDInvalid},
+		},
+	}
+
+	type test struct {
+		name        string
+		reqParam    string
+		resBody     interface{}
+		wantResCode int
+		wantResBody interface{}
+		mockOn      func(*mock_usecase.MockUsecaseOrder)
+	}
+
+	tests := []test{
+		{
+			name:        "ParamLenientError",
+			reqParam:    "?lenient=X",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestParamValidate("lenient invalid"),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+			},
+		},
+		{
+			name:        "ParamHasHeaderHasTrailerError",
+			reqParam:    "?has_header=X&has_trailer=Y",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestParamValidate("has_header invalid;has_trailer invalid"),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+			},
+		},
+		{
+			name:        "ParamModeError",
+			reqParam:    "?mode=append",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestParamValidate(usecase.OrderErrorMessageModeInvalid),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("LegacyValidate").Return(nil, usecase.ErrParamValidate{Message: usecase.OrderErrorMessageModeInvalid})
+			},
+		},
+		{
+			name:        "BadRequestFileValidate",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestFileValidate(fmt.Sprintf(usecase.OrderErrorMessageDecompressedSizeLimit, 1000)),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("LegacyValidate").Return(nil, usecase.ErrFileValidate{Message: fmt.Sprintf(usecase.OrderErrorMessageDecompressedSizeLimit, 1000)})
+			},
+		},
+		{
+			name:        "InternalServerError",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusInternalServerError,
+			wantResBody: model.InternalServerErrorGeneral("InternalServerError"),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("LegacyValidate").Return(nil, errors.New("InternalServerError"))
+			},
+		},
+		{
+			name:        "Success",
+			resBody:     &model.LegacyValidateResult{},
+			wantResCode: http.StatusOK,
+			wantResBody: &modelLegacyValidateResult,
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("LegacyValidate").Return(&modelLegacyValidateResult, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := hclog.New(&hclog.LoggerOptions{Level: hclog.LevelFromString("OFF")})
+			mockUsecaseOrder := new(mock_usecase.MockUsecaseOrder)
+
+			tt.mockOn(mockUsecaseOrder)
+
+			controllerOrder := NewOrder(log, mockUsecaseOrder)
+
+			// Create a new HTTP request with a file upload
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+
+			fileWriter, _ := writer.CreatePart(textproto.MIMEHeader{
+				"Content-Disposition": []string{`form-data; name="file"; filename="file.txt"`},
+				"Content-Type":        []string{"text/plain"},
+			})
+
+			fileContent := "0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308"
+
+			if _, err := io.Copy(fileWriter, bytes.NewBufferString(fileContent)); err != nil {
+				t.Fatalf("Failed to write file content to form file: %v", err)
+			}
+			writer.Close()
+
+			url := fmt.Sprintf("/api/order/legacy/validate%v", tt.reqParam)
+
+			req := httptest.NewRequest(http.MethodPost, url, body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+
+			handler := http.HandlerFunc(controllerOrder.LegacyValidate)
+			res := httptest.NewRecorder()
+
+			handler.ServeHTTP(res, req)
+
+			if !reflect.DeepEqual(res.Code, tt.wantResCode) {
+				t.Errorf("LegacyValidate() got res.code = %v, want %v", res.Code, tt.wantResCode)
+			}
+
+			json.NewDecoder(res.Body).Decode(tt.resBody)
+
+			if !reflect.DeepEqual(tt.resBody, tt.wantResBody) {
+				t.Errorf("LegacyValidate() got res.body = %v, want %v", tt.resBody, tt.wantResBody)
+			}
+		})
+	}
+}
+
 func TestOrderGetDetailsByOrderID(t *testing.T) {
 	modelOrderDetails := model.OrderDetails{
 		UserID:   70,
@@ -304,11 +484,11 @@ func TestOrderGetDetailsByOrderID(t *testing.T) {
 			{
 				OrderID: 753,
 				BuyDate: "2021-03-08",
-				Total:   1836.74,
+				Total:   183674,
 				Products: []model.OrderDetailsProduct{
 					{
 						ID:    3,
-						Value: 1836.74,
+						Value: 183674,
 					},
 				},
 			},
@@ -405,11 +585,11 @@ func TestOrderListDetails(t *testing.T) {
 				{
 					OrderID: 753,
 					BuyDate: "2021-03-08",
-					Total:   1836.74,
+					Total:   183674,
 					Products: []model.OrderDetailsProduct{
 						{
 							ID:    3,
-							Value: 1836.74,
+							Value: 183674,
 						},
 					},
 				},
@@ -417,16 +597,53 @@ func TestOrderListDetails(t *testing.T) {
 		},
 	}
 
+	modelOrdersDetailsPage := model.OrdersDetailsPage{OrdersDetails: modelOrdersDetails}
+
+	modelOrdersDetailsPageNext := model.OrdersDetailsPage{
+		OrdersDetails: modelOrdersDetails,
+		Next:          &model.OrderCursor{UserID: 70, OrderID: 753},
+	}
+
 	type test struct {
 		name        string
 		reqParam    string
 		resBody     interface{}
 		wantResCode int
 		wantResBody interface{}
+		wantResLink string
 		mockOn      func(*mock_usecase.MockUsecaseOrder)
 	}
 
 	tests := []test{
+		{
+			name:        "ParamLimitInvalidError",
+			reqParam:    "?limit=0",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestParamValidate(usecase.OrderPageErrorMessageLimitInvalid),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+			},
+		},
+		{
+			name:        "ParamFilterInvalidError",
+			reqParam:    "?user_id=1,X&product_id=X&min_total=1.234",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestParamValidate(fmt.Sprintf(usecase.OrderFilterErrorMessageParamInvalid, "user_id") + ";" +
+				fmt.Sprintf(usecase.OrderFilterErrorMessageParamInvalid, "product_id") + ";" +
+				fmt.Sprintf(usecase.OrderFilterErrorMessageParamInvalid, "min_total")),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+			},
+		},
+		{
+			name:        "ParamCursorInvalidError",
+			reqParam:    "?cursor=X",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestParamValidate(usecase.OrderPageErrorMessageCursorInvalid),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+			},
+		},
 		{
 			name:        "ParamFromEmptyError",
 			reqParam:    "?from=&to=2020-01-01",
@@ -470,7 +687,7 @@ func TestOrderListDetails(t *testing.T) {
 			wantResCode: http.StatusBadRequest,
 			wantResBody: model.BadRequestParamValidate(usecase.OrderRangeBuyDateErrorMessageFromBetween),
 			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
-				mockUsecaseOrder.On("ListDetailsByRangeBuyDate").Return(nil, usecase.ErrParamValidate{Message: usecase.OrderRangeBuyDateErrorMessageFromBetween})
+				mockUsecaseOrder.On("ListDetails").Return(nil, usecase.ErrParamValidate{Message: usecase.OrderRangeBuyDateErrorMessageFromBetween})
 			},
 		},
 		{
@@ -500,7 +717,7 @@ func TestOrderListDetails(t *testing.T) {
 			wantResCode: http.StatusOK,
 			wantResBody: &modelOrdersDetails,
 			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
-				mockUsecaseOrder.On("ListDetailsByRangeBuyDate").Return(&modelOrdersDetails, nil)
+				mockUsecaseOrder.On("ListDetails").Return(&modelOrdersDetailsPage, nil)
 			},
 		},
 		{
@@ -510,7 +727,18 @@ func TestOrderListDetails(t *testing.T) {
 			wantResCode: http.StatusOK,
 			wantResBody: &modelOrdersDetails,
 			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
-				mockUsecaseOrder.On("ListDetails").Return(&modelOrdersDetails, nil)
+				mockUsecaseOrder.On("ListDetails").Return(&modelOrdersDetailsPage, nil)
+			},
+		},
+		{
+			name:        "NextPageSuccess",
+			reqParam:    "?limit=1",
+			resBody:     &model.OrdersDetails{},
+			wantResCode: http.StatusOK,
+			wantResBody: &modelOrdersDetails,
+			wantResLink: `</api/order?cursor=NzA6NzUz&limit=1>; rel="next"`,
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("ListDetails").Return(&modelOrdersDetailsPageNext, nil)
 			},
 		},
 	}
@@ -536,6 +764,10 @@ func TestOrderListDetails(t *testing.T) {
 				t.Errorf("LegacyImport() got res.code = %v, want %v", res.Code, tt.wantResCode)
 			}
 
+			if res.Header().Get("Link") != tt.wantResLink {
+				t.Errorf("ListDetails() got res.link = %v, want %v", res.Header().Get("Link"), tt.wantResLink)
+			}
+
 			json.NewDecoder(res.Body).Decode(tt.resBody)
 
 			if !reflect.DeepEqual(tt.resBody, tt.wantResBody) {
@@ -544,3 +776,279 @@ func TestOrderListDetails(t *testing.T) {
 		})
 	}
 }
+
+func TestOrderListLegacyRejects(t *testing.T) {
+	modelLegacyRejects := model.LegacyRejects{
+		{
+			LegacyRecordError: model.LegacyRecordError{
+				Line:    2,
+				Message: usecase.OrderErrorMessageUserIDInvalid,
+			},
+			Record: "000000007x                              Palmer Prosacco00000007530000000003     1836.7420210308",
+		},
+	}
+
+	type test struct {
+		name        string
+		resBody     interface{}
+		wantResCode int
+		wantResBody interface{}
+		mockOn      func(*mock_usecase.MockUsecaseOrder)
+	}
+
+	tests := []test{
+		{
+			name:        "NotFoundError",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusNotFound,
+			wantResBody: model.NotFound("Reject"),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("ListLegacyRejects").Return(nil, repository.ErrNotFound{Message: "not found"})
+			},
+		},
+		{
+			name:        "InternalServerError",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusInternalServerError,
+			wantResBody: model.InternalServerErrorRepositoryLoad("Reject"),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("ListLegacyRejects").Return(nil, errors.New("InternalServerError"))
+			},
+		},
+		{
+			name:        "Success",
+			resBody:     &model.LegacyRejects{},
+			wantResCode: http.StatusOK,
+			wantResBody: &modelLegacyRejects,
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("ListLegacyRejects").Return(&modelLegacyRejects, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := hclog.New(&hclog.LoggerOptions{Level: hclog.LevelFromString("OFF")})
+			mockUsecaseOrder := new(mock_usecase.MockUsecaseOrder)
+
+			tt.mockOn(mockUsecaseOrder)
+
+			controllerOrder := NewOrder(log, mockUsecaseOrder)
+
+			req, _ := http.NewRequest(http.MethodGet, "/api/order/legacy/rejects", nil)
+			handler := http.HandlerFunc(controllerOrder.ListLegacyRejects)
+			res := httptest.NewRecorder()
+
+			handler.ServeHTTP(res, req)
+
+			if !reflect.DeepEqual(res.Code, tt.wantResCode) {
+				t.Errorf("ListLegacyRejects() got res.code = %v, want %v", res.Code, tt.wantResCode)
+			}
+
+			json.NewDecoder(res.Body).Decode(tt.resBody)
+
+			if !reflect.DeepEqual(tt.resBody, tt.wantResBody) {
+				t.Errorf("ListLegacyRejects() got res.body = %v, want %v", tt.resBody, tt.wantResBody)
+			}
+		})
+	}
+}
+
+func TestOrderLegacyFilePart(t *testing.T) {
+	fileContent := "0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308"
+
+	type test struct {
+		name      string
+		reqFields map[string]string
+		wantError error
+	}
+
+	tests := []test{
+		{
+			name:      "FormFieldAfterFileError",
+			reqFields: map[string]string{"mode": "merge"},
+			wantError: usecase.ErrFileValidate{Message: fmt.Sprintf(usecase.OrderErrorMessageFormFieldAfterFile, "mode")},
+		},
+		{
+			name:      "Success",
+			reqFields: map[string]string{},
+			wantError: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+
+			fileWriter, _ := writer.CreatePart(textproto.MIMEHeader{
+				"Content-Disposition": []string{`form-data; name="file"; filename="file.txt"`},
+				"Content-Type":        []string{"text/plain"},
+			})
+
+			fileWriter.Write([]byte(fileContent))
+
+			for name, value := range tt.reqFields {
+				writer.WriteField(name, value)
+			}
+
+			writer.Close()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/order/legacy/import", body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+
+			log := hclog.New(&hclog.LoggerOptions{Level: hclog.LevelFromString("OFF")})
+			controllerOrder := NewOrder(log, new(mock_usecase.MockUsecaseOrder))
+
+			file, _, ok := controllerOrder.legacyFormFile(httptest.NewRecorder(), req)
+
+			if !ok {
+				t.Fatalf("legacyFormFile() got ok = false, want true")
+			}
+
+			content, err := io.ReadAll(file)
+
+			if !reflect.DeepEqual(err, tt.wantError) {
+				t.Errorf("legacyFilePart.Read() got error = %v, want %v", err, tt.wantError)
+			}
+
+			if string(content) != fileContent {
+				t.Errorf("legacyFilePart.Read() got content = %v, want %v", string(content), fileContent)
+			}
+		})
+	}
+}
+
+func TestOrderLegacyFormValue(t *testing.T) {
+	type test struct {
+		name        string
+		reqValue    string
+		wantOk      bool
+		wantResCode int
+		wantResBody interface{}
+	}
+
+	tests := []test{
+		{
+			name:        "MaxSizeError",
+			reqValue:    strings.Repeat("x", legacyFormValueMaxSize+1),
+			wantOk:      false,
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestParamValidate(fmt.Sprintf(legacyFormValueErrorMessageMaxSize, "idempotency_key", legacyFormValueMaxSize)),
+		},
+		{
+			name:        "MaxSizeSuccess",
+			reqValue:    strings.Repeat("x", legacyFormValueMaxSize),
+			wantOk:      true,
+			wantResCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+
+			writer.WriteField("idempotency_key", tt.reqValue)
+
+			fileWriter, _ := writer.CreatePart(textproto.MIMEHeader{
+				"Content-Disposition": []string{`form-data; name="file"; filename="file.txt"`},
+				"Content-Type":        []string{"text/plain"},
+			})
+
+			fileWriter.Write([]byte("0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308"))
+
+			writer.Close()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/order/legacy/import", body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			res := httptest.NewRecorder()
+
+			log := hclog.New(&hclog.LoggerOptions{Level: hclog.LevelFromString("OFF")})
+			controllerOrder := NewOrder(log, new(mock_usecase.MockUsecaseOrder))
+
+			_, _, ok := controllerOrder.legacyFormFile(res, req)
+
+			if ok != tt.wantOk {
+				t.Fatalf("legacyFormFile() got ok = %v, want %v", ok, tt.wantOk)
+			}
+
+			if res.Code != tt.wantResCode {
+				t.Errorf("legacyFormFile() got res.code = %v, want %v", res.Code, tt.wantResCode)
+			}
+
+			if !ok {
+				resBody := &model.Error{}
+				json.NewDecoder(res.Body).Decode(resBody)
+
+				if !reflect.DeepEqual(resBody, tt.wantResBody) {
+					t.Errorf("legacyFormFile() got res.body = %v, want %v", resBody, tt.wantResBody)
+				}
+
+				return
+			}
+
+			if value := req.FormValue("idempotency_key"); value != tt.reqValue {
+				t.Errorf("legacyFormFile() got value size = %v, want %v", len(value), len(tt.reqValue))
+			}
+		})
+	}
+}
+
+func TestOrderLegacyImportReplayed(t *testing.T) {
+	type test struct {
+		name      string
+		inputRes  model.LegacyImportResult
+		wantValue string
+	}
+
+	tests := []test{
+		{
+			name:      "Imported",
+			inputRes:  model.LegacyImportResult{ImportID: 2, Users: 1, Orders: 1, Products: 1, Accepted: 1},
+			wantValue: "",
+		},
+		{
+			name:      "Replayed",
+			inputRes:  model.LegacyImportResult{ImportID: 1, Users: 1, Orders: 1, Products: 1, Accepted: 1, Replayed: true},
+			wantValue: "true",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := hclog.New(&hclog.LoggerOptions{Level: hclog.LevelFromString("OFF")})
+			mockUsecaseOrder := new(mock_usecase.MockUsecaseOrder)
+			mockUsecaseOrder.On("LegacyImport").Return(&tt.inputRes, nil)
+
+			controllerOrder := NewOrder(log, mockUsecaseOrder)
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+
+			fileWriter, _ := writer.CreatePart(textproto.MIMEHeader{
+				"Content-Disposition": []string{`form-data; name="file"; filename="file.txt"`},
+				"Content-Type":        []string{"text/plain"},
+			})
+
+			fileWriter.Write([]byte("0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308"))
+			writer.Close()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/order/legacy/import", body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			req.Header.Set(headerIdempotencyKey, "key-1")
+
+			res := httptest.NewRecorder()
+
+			http.HandlerFunc(controllerOrder.LegacyImport).ServeHTTP(res, req)
+
+			if res.Code != http.StatusOK {
+				t.Errorf("LegacyImport() got res.code = %v, want %v", res.Code, http.StatusOK)
+			}
+
+			if got := res.Header().Get(headerIdempotentReplayed); got != tt.wantValue {
+				t.Errorf("LegacyImport() got header %s = %v, want %v", headerIdempotentReplayed, got, tt.wantValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,369 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	logger "github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/logger"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/usecase"
+)
+
+const (
+	pathApiOrderLegacyUploads = "/api/order/legacy/uploads/"
+	pathLegacyUploadFinalize  = "/finalize"
+)
+
+// legacyContentRange is the range of the chunk, the total size after the
+// slash is informative
+var legacyContentRange = regexp.MustCompile(`^bytes (\d+)-(\d+)/(\d+|\*)$`)
+
+// LegacyImportRaw godoc
+// @Summary      Importar Legado (corpo da requisição)
+// @Description  Importação de pedidos do sistema legado com o arquivo enviado no corpo da requisição, sem o formulário multipart.<br/>
+// @Description  O formato do arquivo é identificado pelo Content-Type da requisição (text/plain, text/csv, application/x-ndjson, application/gzip ou application/zip) e o nome do arquivo pode ser informado no cabeçalho Content-Disposition.<br/>
+// @Description  As opções da importação são informadas na query e as demais regras são as mesmas da importação com formulário (post /order/legacy/import).
+// @Tags         Pedidos
+// @Accept       plain
+// @Produce      json
+// @Param        file     body          string  true   "Conteúdo do arquivo a ser importado"
+// @Param        mode     query         string  false  "Modo de importação" Enums(replace, merge) default(replace)
+// @Param        target   query         string  false  "Destino da importação, stage mantém os pedidos em homologação até serem promovidos" Enums(live, stage) default(live)
+// @Param        layout   query         string  false  "Layout dos registros do arquivo" default(default)
+// @Param        async    query         bool    false  "Executa a importação em segundo plano e retorna o Job criado" default(false)
+// @Param        lenient  query         bool    false  "Importa os registros válidos e mantém os registros rejeitados em quarentena" default(false)
+// @Param        encoding     query     string  false  "Codificação dos caracteres do arquivo" Enums(auto, utf-8, latin-1, windows-1252) default(auto)
+// @Param        has_header   query     bool    false  "O primeiro registro do arquivo de posição fixa é o header" default(false)
+// @Param        has_trailer  query     bool    false  "O último registro do arquivo de posição fixa é o trailer" default(false)
+// @Param        X-Requested-By  header  string  false  "Solicitante da importação mantido no histórico, por padrão o endereço do cliente"
+// @Param        Idempotency-Key  header  string  false  "Chave da requisição, uma nova tentativa com a mesma chave retorna o resultado da importação já realizada"
+// @Success      200  {object}  model.LegacyImportResult
+// @Header       200  {string}  Idempotent-Replayed  "true quando o resultado é de uma importação já realizada"
+// @Success      202  {object}  model.LegacyImportJob
+// @Failure      400  {object}  model.ErrorRecordsValidate
+// @Failure      500  {object}  model.Error
+// @Router       /order/legacy/import [put]
+func (controllerOrder *Order) LegacyImportRaw(rw http.ResponseWriter, req *http.Request) {
+	fileType, ok := legacyFileTypes[legacyMediaType(req.Header.Get("Content-Type"))]
+
+	if !ok {
+		responseError := model.BadRequestFileType()
+
+		logger.LogErrorRequest(controllerOrder.Log, req, responseError.Message, nil)
+
+		rw.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(rw).Encode(responseError)
+		return
+	}
+
+	if _, params, err := mime.ParseMediaType(req.Header.Get("Content-Disposition")); err == nil {
+		fileType.name = params["filename"]
+	}
+
+	file := legacyFileBody{ReadCloser: req.Body}
+	defer file.Close()
+
+	controllerOrder.legacyImport(rw, req, file, fileType)
+}
+
+func legacyMediaType(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	return mediaType
+}
+
+// legacyFileBody reports the errors reading the request body, as an upload
+// interrupted, as errors of the file instead of internal errors
+type legacyFileBody struct {
+	io.ReadCloser
+}
+
+func (body legacyFileBody) Read(p []byte) (int, error) {
+	n, err := body.ReadCloser.Read(p)
+
+	if err != nil && err != io.EOF {
+		err = usecase.ErrFileValidate{Message: fmt.Sprintf(usecase.OrderErrorMessageFileRead, err)}
+	}
+
+	return n, err
+}
+
+// CreateLegacyUpload godoc
+// @Summary      Criar Upload do Legado
+// @Description  Cria um upload retomável do arquivo do sistema legado para conexões instáveis.<br/><br/>
+// @Description  O arquivo é enviado em trechos (put /order/legacy/uploads/{id}) com o cabeçalho Content-Range e o upload é finalizado com a importação do arquivo (post /order/legacy/uploads/{id}/finalize).<br/>
+// @Description  Após uma falha de conexão a quantidade de bytes recebidos é consultada (get /order/legacy/uploads/{id}) e o envio continua a partir dela.<br/>
+// @Description  O upload que não receber trechos durante LEGACY_UPLOAD_EXPIRATION é descartado.
+// @Tags         Pedidos
+// @Accept       json
+// @Produce      json
+// @Param        file_name     query  string  false  "Nome do arquivo"
+// @Param        content_type  query  string  false  "Content-Type do arquivo que identifica o formato e a compactação" Enums(text/plain, text/csv, application/x-ndjson, application/gzip, application/zip) default(text/plain)
+// @Param        size          query  int     false  "Tamanho total do arquivo em bytes, a finalização exige que todos os bytes tenham sido recebidos"
+// @Success      201  {object}  model.LegacyUpload
+// @Failure      400  {object}  model.Error
+// @Failure      500  {object}  model.Error
+// @Router       /order/legacy/uploads [post]
+func (controllerOrder *Order) CreateLegacyUpload(rw http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+
+	modelLegacyUpload := &model.LegacyUpload{
+		FileName:    query.Get("file_name"),
+		ContentType: query.Get("content_type"),
+	}
+
+	if modelLegacyUpload.ContentType == "" {
+		modelLegacyUpload.ContentType = "text/plain"
+	}
+
+	if _, ok := legacyFileTypes[modelLegacyUpload.ContentType]; !ok {
+		responseError := model.BadRequestFileType()
+
+		rw.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(rw).Encode(responseError)
+		return
+	}
+
+	if size := query.Get("size"); size != "" {
+		var err error
+
+		modelLegacyUpload.Size, err = strconv.ParseInt(size, 10, 64)
+
+		if err != nil {
+			responseError := model.BadRequestParamValidate(usecase.OrderErrorMessageUploadSizeInvalid)
+
+			rw.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(rw).Encode(responseError)
+			return
+		}
+	}
+
+	modelLegacyUpload, err := controllerOrder.UsecaseOrder.CreateLegacyUpload(modelLegacyUpload)
+
+	if err != nil {
+		controllerOrder.legacyUploadError(rw, req, err)
+		return
+	}
+
+	rw.Header().Set("Location", pathApiOrderLegacyUploads+modelLegacyUpload.ID)
+	rw.WriteHeader(http.StatusCreated)
+	json.NewEncoder(rw).Encode(modelLegacyUpload)
+}
+
+// GetLegacyUpload godoc
+// @Summary      Consultar Upload do Legado
+// @Description  Retorna o upload retomável com a quantidade de bytes recebidos, a posição de envio do próximo trecho.
+// @Tags         Pedidos
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  false  "ID do Upload" validate(required)
+// @Success      200  {object}  model.LegacyUpload
+// @Failure      404  {object}  model.Error
+// @Router       /order/legacy/uploads/{id} [get]
+func (controllerOrder *Order) GetLegacyUpload(rw http.ResponseWriter, req *http.Request) {
+	modelLegacyUpload, err := controllerOrder.UsecaseOrder.GetLegacyUpload(legacyUploadID(req))
+
+	if err != nil {
+		controllerOrder.legacyUploadError(rw, req, err)
+		return
+	}
+
+	json.NewEncoder(rw).Encode(modelLegacyUpload)
+}
+
+// LegacyUploadChunk godoc
+// @Summary      Enviar Trecho do Upload do Legado
+// @Description  Grava o trecho do arquivo enviado no corpo da requisição na posição do cabeçalho Content-Range (ex.: bytes 0-1048575/5242880).<br/>
+// @Description  O trecho não pode iniciar após os bytes já recebidos e um trecho enviado novamente substitui os bytes recebidos. Sem o cabeçalho Content-Range o trecho é gravado após os bytes recebidos.<br/>
+// @Description  Os bytes recebidos antes de uma falha de conexão são mantidos.
+// @Tags         Pedidos
+// @Accept       octet-stream
+// @Produce      json
+// @Param        id             path    string  false  "ID do Upload" validate(required)
+// @Param        Content-Range  header  string  false  "Posição do trecho no arquivo" example(bytes 0-1048575/5242880)
+// @Param        chunk          body    string  true   "Conteúdo do trecho"
+// @Success      200  {object}  model.LegacyUpload
+// @Failure      400  {object}  model.Error
+// @Failure      404  {object}  model.Error
+// @Failure      409  {object}  model.Error
+// @Failure      500  {object}  model.Error
+// @Router       /order/legacy/uploads/{id} [put]
+func (controllerOrder *Order) LegacyUploadChunk(rw http.ResponseWriter, req *http.Request) {
+	offset, chunk, err := legacyUploadChunkRange(req)
+
+	if err != nil {
+		responseError := model.BadRequestParamValidate(err.Error())
+
+		rw.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(rw).Encode(responseError)
+		return
+	}
+
+	modelLegacyUpload, err := controllerOrder.UsecaseOrder.LegacyUploadChunk(legacyUploadID(req), offset, chunk)
+
+	if err != nil {
+		controllerOrder.legacyUploadError(rw, req, err)
+		return
+	}
+
+	json.NewEncoder(rw).Encode(modelLegacyUpload)
+}
+
+// legacyUploadChunkRange returns the offset of the chunk and its content
+// limited by the Content-Range header
+func legacyUploadChunkRange(req *http.Request) (int64, io.Reader, error) {
+	body := legacyFileBody{ReadCloser: req.Body}
+
+	contentRange := req.Header.Get("Content-Range")
+
+	if contentRange == "" {
+		return usecase.OrderLegacyUploadOffsetAppend, body, nil
+	}
+
+	matches := legacyContentRange.FindStringSubmatch(contentRange)
+
+	if matches == nil {
+		return 0, nil, fmt.Errorf("Content-Range invalid")
+	}
+
+	start, errStart := strconv.ParseInt(matches[1], 10, 64)
+	end, errEnd := strconv.ParseInt(matches[2], 10, 64)
+
+	if errStart != nil || errEnd != nil || end < start {
+		return 0, nil, fmt.Errorf("Content-Range invalid")
+	}
+
+	return start, io.LimitReader(body, end-start+1), nil
+}
+
+// DeleteLegacyUpload godoc
+// @Summary      Cancelar Upload do Legado
+// @Description  Descarta o upload retomável e os bytes recebidos.
+// @Tags         Pedidos
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  false  "ID do Upload" validate(required)
+// @Success      204
+// @Failure      404  {object}  model.Error
+// @Router       /order/legacy/uploads/{id} [delete]
+func (controllerOrder *Order) DeleteLegacyUpload(rw http.ResponseWriter, req *http.Request) {
+	err := controllerOrder.UsecaseOrder.DeleteLegacyUpload(legacyUploadID(req))
+
+	if err != nil {
+		controllerOrder.legacyUploadError(rw, req, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// LegacyUploadFinalize godoc
+// @Summary      Finalizar Upload do Legado
+// @Description  Finaliza o upload retomável importando o arquivo recebido com as mesmas opções e respostas da importação (post /order/legacy/import).<br/>
+// @Description  O upload com tamanho informado só é finalizado após o recebimento de todos os bytes, e é mantido quando as opções da importação forem inválidas.
+// @Tags         Pedidos
+// @Accept       json
+// @Produce      json
+// @Param        id       path          string  false  "ID do Upload" validate(required)
+// @Param        mode     query         string  false  "Modo de importação" Enums(replace, merge) default(replace)
+// @Param        target   query         string  false  "Destino da importação, stage mantém os pedidos em homologação até serem promovidos" Enums(live, stage) default(live)
+// @Param        layout   query         string  false  "Layout dos registros do arquivo" default(default)
+// @Param        async    query         bool    false  "Executa a importação em segundo plano e retorna o Job criado" default(false)
+// @Param        lenient  query         bool    false  "Importa os registros válidos e mantém os registros rejeitados em quarentena" default(false)
+// @Param        encoding     query     string  false  "Codificação dos caracteres do arquivo" Enums(auto, utf-8, latin-1, windows-1252) default(auto)
+// @Param        has_header   query     bool    false  "O primeiro registro do arquivo de posição fixa é o header" default(false)
+// @Param        has_trailer  query     bool    false  "O último registro do arquivo de posição fixa é o trailer" default(false)
+// @Param        X-Requested-By  header  string  false  "Solicitante da importação mantido no histórico, por padrão o endereço do cliente"
+// @Param        Idempotency-Key  header  string  false  "Chave da requisição, uma nova tentativa com a mesma chave retorna o resultado da importação já realizada"
+// @Success      200  {object}  model.LegacyImportResult
+// @Header       200  {string}  Idempotent-Replayed  "true quando o resultado é de uma importação já realizada"
+// @Success      202  {object}  model.LegacyImportJob
+// @Failure      400  {object}  model.ErrorRecordsValidate
+// @Failure      404  {object}  model.Error
+// @Failure      409  {object}  model.Error
+// @Failure      500  {object}  model.Error
+// @Router       /order/legacy/uploads/{id}/finalize [post]
+func (controllerOrder *Order) LegacyUploadFinalize(rw http.ResponseWriter, req *http.Request) {
+	uploadID := legacyUploadID(req)
+
+	modelLegacyUpload, err := controllerOrder.UsecaseOrder.GetLegacyUpload(uploadID)
+
+	if err != nil {
+		controllerOrder.legacyUploadError(rw, req, err)
+		return
+	}
+
+	modelLegacyImportOptions, async, err := validateParamsLegacyImport(req)
+
+	if err != nil {
+		responseError := model.BadRequestParamValidate(err.Error())
+
+		rw.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(rw).Encode(responseError)
+		return
+	}
+
+	fileType := legacyFileTypes[modelLegacyUpload.ContentType]
+	fileType.name = modelLegacyUpload.FileName
+	fileType.options(modelLegacyImportOptions)
+
+	if async {
+		modelLegacyImportJob, err := controllerOrder.UsecaseOrder.LegacyUploadImportAsync(uploadID, modelLegacyImportOptions)
+
+		if err != nil {
+			controllerOrder.legacyUploadError(rw, req, err)
+			return
+		}
+
+		rw.Header().Set("Location", pathApiOrderLegacyImportJobs+modelLegacyImportJob.ID)
+		rw.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(rw).Encode(modelLegacyImportJob)
+		return
+	}
+
+	modelLegacyImportResult, err := controllerOrder.UsecaseOrder.LegacyUploadImport(uploadID, modelLegacyImportOptions)
+
+	controllerOrder.legacyImportResponse(rw, req, modelLegacyImportResult, err)
+}
+
+// legacyUploadID extracts the id of the upload of the paths
+// /api/order/legacy/uploads/{id} and /api/order/legacy/uploads/{id}/finalize
+func legacyUploadID(req *http.Request) string {
+	return strings.TrimSuffix(strings.TrimPrefix(req.URL.Path, pathApiOrderLegacyUploads), pathLegacyUploadFinalize)
+}
+
+func (controllerOrder *Order) legacyUploadError(rw http.ResponseWriter, req *http.Request, err error) {
+	var responseError *model.Error
+
+	if _, ok := err.(usecase.ErrNotFound); ok {
+		responseError = model.NotFound("Upload")
+
+		rw.WriteHeader(http.StatusNotFound)
+	} else if _, ok := err.(usecase.ErrConflict); ok {
+		responseError = model.Conflict(err.Error())
+
+		rw.WriteHeader(http.StatusConflict)
+	} else if _, ok := err.(usecase.ErrParamValidate); ok {
+		responseError = model.BadRequestParamValidate(err.Error())
+
+		rw.WriteHeader(http.StatusBadRequest)
+	} else if _, ok := err.(usecase.ErrFileValidate); ok {
+		responseError = model.BadRequestFileValidate(err.Error())
+
+		rw.WriteHeader(http.StatusBadRequest)
+	} else {
+		responseError = model.InternalServerErrorGeneral(err.Error())
+
+		logger.LogErrorRequest(controllerOrder.Log, req, responseError.Message, err)
+
+		rw.WriteHeader(http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(rw).Encode(responseError)
+}
@@ -0,0 +1,410 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	mock_usecase "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/usecase"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/usecase"
+	"github.com/hashicorp/go-hclog"
+)
+
+func TestOrderLegacyImportRaw(t *testing.T) {
+	type test struct {
+		name           string
+		reqContentType string
+		reqParam       string
+		resBody        interface{}
+		wantResCode    int
+		wantResBody    interface{}
+		mockOn         func(*mock_usecase.MockUsecaseOrder)
+	}
+
+	tests := []test{
+		{
+			name:           "FileTypeError",
+			reqContentType: "application/pdf",
+			resBody:        &model.Error{},
+			wantResCode:    http.StatusBadRequest,
+			wantResBody:    model.BadRequestFileType(),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+			},
+		},
+		{
+			name:           "ParamLenientError",
+			reqContentType: "text/plain",
+			reqParam:       "?lenient=X",
+			resBody:        &model.Error{},
+			wantResCode:    http.StatusBadRequest,
+			wantResBody:    model.BadRequestParamValidate("lenient invalid"),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+			},
+		},
+		{
+			name:           "Success",
+			reqContentType: "text/plain; charset=utf-8",
+			resBody:        &model.LegacyImportResult{},
+			wantResCode:    http.StatusOK,
+			wantResBody:    &model.LegacyImportResult{Accepted: 1},
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("LegacyImport").Return(&model.LegacyImportResult{Accepted: 1}, nil)
+			},
+		},
+		{
+			name:           "Accepted",
+			reqContentType: "text/csv",
+			reqParam:       "?async=true",
+			resBody:        &model.LegacyImportJob{},
+			wantResCode:    http.StatusAccepted,
+			wantResBody:    &model.LegacyImportJob{ID: "0b4f7d6c-3a57-4d1c-9d0e-5c9d1a2b3c4d", State: model.LegacyImportJobStatePending},
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("LegacyImportAsync").Return(&model.LegacyImportJob{ID: "0b4f7d6c-3a57-4d1c-9d0e-5c9d1a2b3c4d", State: model.LegacyImportJobStatePending}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := hclog.New(&hclog.LoggerOptions{Level: hclog.LevelFromString("OFF")})
+			mockUsecaseOrder := new(mock_usecase.MockUsecaseOrder)
+
+			tt.mockOn(mockUsecaseOrder)
+
+			controllerOrder := NewOrder(log, mockUsecaseOrder)
+
+			fileContent := "0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308"
+
+			url := fmt.Sprintf("/api/order/legacy/import%v", tt.reqParam)
+
+			req := httptest.NewRequest(http.MethodPut, url, bytes.NewBufferString(fileContent))
+			req.Header.Set("Content-Type", tt.reqContentType)
+			req.Header.Set("Content-Disposition", `attachment; filename="data_1.txt"`)
+
+			handler := http.HandlerFunc(controllerOrder.LegacyImportRaw)
+			res := httptest.NewRecorder()
+
+			handler.ServeHTTP(res, req)
+
+			if !reflect.DeepEqual(res.Code, tt.wantResCode) {
+				t.Errorf("LegacyImportRaw() got res.code = %v, want %v", res.Code, tt.wantResCode)
+			}
+
+			json.NewDecoder(res.Body).Decode(tt.resBody)
+
+			if !reflect.DeepEqual(tt.resBody, tt.wantResBody) {
+				t.Errorf("LegacyImportRaw() got res.body = %v, want %v", tt.resBody, tt.wantResBody)
+			}
+		})
+	}
+}
+
+func TestOrderCreateLegacyUpload(t *testing.T) {
+	modelLegacyUpload := model.LegacyUpload{
+		ID:          "0b4f7d6c-3a57-4d1c-9d0e-5c9d1a2b3c4d",
+		FileName:    "data_1.txt",
+		ContentType: "text/plain",
+		Size:        1024,
+		CreatedAt:   time.Date(2023, 06, 11, 00, 00, 00, 000, time.UTC),
+		ExpiresAt:   time.Date(2023, 06, 12, 00, 00, 00, 000, time.UTC),
+	}
+
+	type test struct {
+		name         string
+		reqParam     string
+		resBody      interface{}
+		wantResCode  int
+		wantResBody  interface{}
+		wantLocation string
+		mockOn       func(*mock_usecase.MockUsecaseOrder)
+	}
+
+	tests := []test{
+		{
+			name:        "ParamContentTypeError",
+			reqParam:    "?content_type=application/pdf",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestFileType(),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+			},
+		},
+		{
+			name:        "ParamSizeError",
+			reqParam:    "?size=X",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestParamValidate(usecase.OrderErrorMessageUploadSizeInvalid),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+			},
+		},
+		{
+			name:        "SizeLimitError",
+			reqParam:    "?size=1073741824",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestFileValidate(fmt.Sprintf(usecase.OrderErrorMessageFileSizeLimit, 1024)),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("CreateLegacyUpload").Return(nil, usecase.ErrFileValidate{Message: fmt.Sprintf(usecase.OrderErrorMessageFileSizeLimit, 1024)})
+			},
+		},
+		{
+			name:         "Created",
+			reqParam:     "?file_name=data_1.txt&size=1024",
+			resBody:      &model.LegacyUpload{},
+			wantResCode:  http.StatusCreated,
+			wantResBody:  &modelLegacyUpload,
+			wantLocation: "/api/order/legacy/uploads/" + modelLegacyUpload.ID,
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("CreateLegacyUpload").Return(&modelLegacyUpload, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := hclog.New(&hclog.LoggerOptions{Level: hclog.LevelFromString("OFF")})
+			mockUsecaseOrder := new(mock_usecase.MockUsecaseOrder)
+
+			tt.mockOn(mockUsecaseOrder)
+
+			controllerOrder := NewOrder(log, mockUsecaseOrder)
+
+			url := fmt.Sprintf("/api/order/legacy/uploads%v", tt.reqParam)
+
+			req := httptest.NewRequest(http.MethodPost, url, nil)
+
+			handler := http.HandlerFunc(controllerOrder.CreateLegacyUpload)
+			res := httptest.NewRecorder()
+
+			handler.ServeHTTP(res, req)
+
+			if !reflect.DeepEqual(res.Code, tt.wantResCode) {
+				t.Errorf("CreateLegacyUpload() got res.code = %v, want %v", res.Code, tt.wantResCode)
+			}
+
+			if res.Header().Get("Location") != tt.wantLocation {
+				t.Errorf("CreateLegacyUpload() got res.location = %v, want %v", res.Header().Get("Location"), tt.wantLocation)
+			}
+
+			json.NewDecoder(res.Body).Decode(tt.resBody)
+
+			if !reflect.DeepEqual(tt.resBody, tt.wantResBody) {
+				t.Errorf("CreateLegacyUpload() got res.body = %v, want %v", tt.resBody, tt.wantResBody)
+			}
+		})
+	}
+}
+
+func TestOrderLegacyUploadChunk(t *testing.T) {
+	modelLegacyUpload := model.LegacyUpload{
+		ID:          "0b4f7d6c-3a57-4d1c-9d0e-5c9d1a2b3c4d",
+		ContentType: "text/plain",
+		Received:    512,
+		CreatedAt:   time.Date(2023, 06, 11, 00, 00, 00, 000, time.UTC),
+		ExpiresAt:   time.Date(2023, 06, 12, 00, 00, 00, 000, time.UTC),
+	}
+
+	type test struct {
+		name            string
+		reqContentRange string
+		resBody         interface{}
+		wantResCode     int
+		wantResBody     interface{}
+		mockOn          func(*mock_usecase.MockUsecaseOrder)
+	}
+
+	tests := []test{
+		{
+			name:            "ContentRangeError",
+			reqContentRange: "bytes 10-0/*",
+			resBody:         &model.Error{},
+			wantResCode:     http.StatusBadRequest,
+			wantResBody:     model.BadRequestParamValidate("Content-Range invalid"),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+			},
+		},
+		{
+			name:        "NotFoundError",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusNotFound,
+			wantResBody: model.NotFound("Upload"),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("LegacyUploadChunk").Return(nil, usecase.ErrNotFound{Message: usecase.OrderErrorMessageUploadNotFound})
+			},
+		},
+		{
+			name:            "OffsetConflictError",
+			reqContentRange: "bytes 1024-1535/*",
+			resBody:         &model.Error{},
+			wantResCode:     http.StatusConflict,
+			wantResBody:     model.Conflict(fmt.Sprintf(usecase.OrderErrorMessageUploadOffset, 1024, 512)),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("LegacyUploadChunk").Return(nil, usecase.ErrConflict{Message: fmt.Sprintf(usecase.OrderErrorMessageUploadOffset, 1024, 512)})
+			},
+		},
+		{
+			name:            "Success",
+			reqContentRange: "bytes 0-511/1024",
+			resBody:         &model.LegacyUpload{},
+			wantResCode:     http.StatusOK,
+			wantResBody:     &modelLegacyUpload,
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("LegacyUploadChunk").Return(&modelLegacyUpload, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := hclog.New(&hclog.LoggerOptions{Level: hclog.LevelFromString("OFF")})
+			mockUsecaseOrder := new(mock_usecase.MockUsecaseOrder)
+
+			tt.mockOn(mockUsecaseOrder)
+
+			controllerOrder := NewOrder(log, mockUsecaseOrder)
+
+			url := "/api/order/legacy/uploads/" + modelLegacyUpload.ID
+
+			req := httptest.NewRequest(http.MethodPut, url, bytes.NewBuffer(make([]byte, 512)))
+
+			if tt.reqContentRange != "" {
+				req.Header.Set("Content-Range", tt.reqContentRange)
+			}
+
+			handler := http.HandlerFunc(controllerOrder.LegacyUploadChunk)
+			res := httptest.NewRecorder()
+
+			handler.ServeHTTP(res, req)
+
+			if !reflect.DeepEqual(res.Code, tt.wantResCode) {
+				t.Errorf("LegacyUploadChunk() got res.code = %v, want %v", res.Code, tt.wantResCode)
+			}
+
+			json.NewDecoder(res.Body).Decode(tt.resBody)
+
+			if !reflect.DeepEqual(tt.resBody, tt.wantResBody) {
+				t.Errorf("LegacyUploadChunk() got res.body = %v, want %v", tt.resBody, tt.wantResBody)
+			}
+		})
+	}
+}
+
+func TestOrderLegacyUploadFinalize(t *testing.T) {
+	modelLegacyUpload := model.LegacyUpload{
+		ID:          "0b4f7d6c-3a57-4d1c-9d0e-5c9d1a2b3c4d",
+		FileName:    "data_1.txt",
+		ContentType: "text/plain",
+		Size:        1024,
+		Received:    512,
+	}
+
+	modelLegacyImportJob := model.LegacyImportJob{
+		ID:    "7e0a1f2b-5c3d-4e6f-8a9b-0c1d2e3f4a5b",
+		State: model.LegacyImportJobStatePending,
+	}
+
+	type test struct {
+		name         string
+		reqParam     string
+		resBody      interface{}
+		wantResCode  int
+		wantResBody  interface{}
+		wantLocation string
+		mockOn       func(*mock_usecase.MockUsecaseOrder)
+	}
+
+	tests := []test{
+		{
+			name:        "NotFoundError",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusNotFound,
+			wantResBody: model.NotFound("Upload"),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("GetLegacyUpload").Return(nil, usecase.ErrNotFound{Message: usecase.OrderErrorMessageUploadNotFound})
+			},
+		},
+		{
+			name:        "ParamAsyncError",
+			reqParam:    "?async=X",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestParamValidate("async invalid"),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("GetLegacyUpload").Return(&modelLegacyUpload, nil)
+			},
+		},
+		{
+			name:        "IncompleteError",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusConflict,
+			wantResBody: model.Conflict(fmt.Sprintf(usecase.OrderErrorMessageUploadIncomplete, 512, 1024)),
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("GetLegacyUpload").Return(&modelLegacyUpload, nil)
+				mockUsecaseOrder.On("LegacyUploadImport").Return(nil, usecase.ErrConflict{Message: fmt.Sprintf(usecase.OrderErrorMessageUploadIncomplete, 512, 1024)})
+			},
+		},
+		{
+			name:        "Success",
+			resBody:     &model.LegacyImportResult{},
+			wantResCode: http.StatusOK,
+			wantResBody: &model.LegacyImportResult{Accepted: 1},
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("GetLegacyUpload").Return(&modelLegacyUpload, nil)
+				mockUsecaseOrder.On("LegacyUploadImport").Return(&model.LegacyImportResult{Accepted: 1}, nil)
+			},
+		},
+		{
+			name:         "Accepted",
+			reqParam:     "?async=true",
+			resBody:      &model.LegacyImportJob{},
+			wantResCode:  http.StatusAccepted,
+			wantResBody:  &modelLegacyImportJob,
+			wantLocation: "/api/order/legacy/import/jobs/" + modelLegacyImportJob.ID,
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("GetLegacyUpload").Return(&modelLegacyUpload, nil)
+				mockUsecaseOrder.On("LegacyUploadImportAsync").Return(&modelLegacyImportJob, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := hclog.New(&hclog.LoggerOptions{Level: hclog.LevelFromString("OFF")})
+			mockUsecaseOrder := new(mock_usecase.MockUsecaseOrder)
+
+			tt.mockOn(mockUsecaseOrder)
+
+			controllerOrder := NewOrder(log, mockUsecaseOrder)
+
+			url := fmt.Sprintf("/api/order/legacy/uploads/%v/finalize%v", modelLegacyUpload.ID, tt.reqParam)
+
+			req := httptest.NewRequest(http.MethodPost, url, nil)
+
+			handler := http.HandlerFunc(controllerOrder.LegacyUploadFinalize)
+			res := httptest.NewRecorder()
+
+			handler.ServeHTTP(res, req)
+
+			if !reflect.DeepEqual(res.Code, tt.wantResCode) {
+				t.Errorf("LegacyUploadFinalize() got res.code = %v, want %v", res.Code, tt.wantResCode)
+			}
+
+			if res.Header().Get("Location") != tt.wantLocation {
+				t.Errorf("LegacyUploadFinalize() got res.location = %v, want %v", res.Header().Get("Location"), tt.wantLocation)
+			}
+
+			json.NewDecoder(res.Body).Decode(tt.resBody)
+
+			if !reflect.DeepEqual(tt.resBody, tt.wantResBody) {
+				t.Errorf("LegacyUploadFinalize() got res.body = %v, want %v", tt.resBody, tt.wantResBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+	logger "github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/logger"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/usecase"
+	"github.com/hashicorp/go-hclog"
+)
+
+type Product struct {
+	Title          string
+	Log            hclog.Logger
+	UsecaseProduct usecase.Product
+}
+
+func NewProduct(log hclog.Logger, usecaseProduct usecase.Product) *Product {
+	return &Product{
+		Title:          "Product",
+		Log:            log,
+		UsecaseProduct: usecaseProduct,
+	}
+}
+
+// GetSummaryByProductID godoc
+// @Summary      Consultar Produto por ID
+// @Description  Retorna a quantidade de vendas, a quantidade de Usuários distintos, o menor, o maior e o Valor médio e a data da primeira e da última venda do Produto referente ao ID informado.
+// @Tags         Produtos
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  false  "ID do Produto" example(3) validate(required)
+// @Success      200  {object}  model.ProductSummary
+// @Failure      400  {object}  model.Error
+// @Failure      404  {object}  model.Error
+// @Failure      500  {object}  model.Error
+// @Router       /product/{id} [get]
+func (controllerProduct *Product) GetSummaryByProductID(rw http.ResponseWriter, req *http.Request) {
+	productID, ok := controllerProduct.paramProductID(rw, req)
+
+	if !ok {
+		return
+	}
+
+	modelProductSummary, err := controllerProduct.UsecaseProduct.GetSummaryByProductID(productID)
+
+	if err != nil {
+		var responseError *model.Error
+
+		if _, ok := err.(repository.ErrNotFound); ok {
+			responseError = model.NotFound(controllerProduct.Title)
+
+			rw.WriteHeader(http.StatusNotFound)
+		} else {
+			responseError = model.InternalServerErrorRepositoryLoad(controllerProduct.Title)
+
+			logger.LogErrorRequest(controllerProduct.Log, req, responseError.Message, err)
+
+			rw.WriteHeader(http.StatusInternalServerError)
+		}
+
+		json.NewEncoder(rw).Encode(responseError)
+		return
+	}
+
+	json.NewEncoder(rw).Encode(modelProductSummary)
+}
+
+// ListOrdersDetails godoc
+// @Summary      Listar Pedidos do Produto
+// @Description  Retorna os Pedidos que contém o Produto referente ao ID informado, agrupados por Usuário.<br/>
+// @Description  Os Pedidos são paginados pelo usuário e pedido, o cabeçalho Link (rel="next") informa a URL da próxima página.
+// @Tags         Produtos
+// @Accept       json
+// @Produce      json
+// @Param        id      path    string  false  "ID do Produto" example(3) validate(required)
+// @Param        limit   query   int     false  "Quantidade de Pedidos da página, limitada por ORDER_PAGE_MAX_SIZE" example(100)
+// @Param        cursor  query   string  false  "Cursor da página retornado no cabeçalho Link da página anterior"
+// @Success      200  {object}  model.OrdersDetails
+// @Header       200  {string}  Link  "URL da próxima página (rel=\"next\")"
+// @Failure      400  {object}  model.Error
+// @Failure      404  {object}  model.Error
+// @Failure      500  {object}  model.Error
+// @Router       /product/{id}/orders [get]
+func (controllerProduct *Product) ListOrdersDetails(rw http.ResponseWriter, req *http.Request) {
+	productID, ok := controllerProduct.paramProductID(rw, req)
+
+	if !ok {
+		return
+	}
+
+	modelOrderPage, err := validateQueryParamsOrderPage(req.URL.Query().Get("limit"), req.URL.Query().Get("cursor"))
+
+	if err != nil {
+		responseError := model.BadRequestParamValidate(err.Error())
+
+		logger.LogErrorRequest(controllerProduct.Log, req, responseError.Message, err)
+
+		rw.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(rw).Encode(responseError)
+		return
+	}
+
+	modelOrdersDetailsPage, err := controllerProduct.UsecaseProduct.ListOrdersDetails(productID, modelOrderPage)
+
+	if err != nil {
+		var responseError *model.Error
+
+		if _, ok := err.(usecase.ErrParamValidate); ok {
+			responseError = model.BadRequestParamValidate(err.Error())
+
+			rw.WriteHeader(http.StatusBadRequest)
+		} else if _, ok := err.(repository.ErrNotFound); ok {
+			responseError = model.NotFound(controllerProduct.Title)
+
+			rw.WriteHeader(http.StatusNotFound)
+		} else {
+			responseError = model.InternalServerErrorRepositoryLoad(controllerProduct.Title)
+
+			logger.LogErrorRequest(controllerProduct.Log, req, responseError.Message, err)
+
+			rw.WriteHeader(http.StatusInternalServerError)
+		}
+
+		json.NewEncoder(rw).Encode(responseError)
+		return
+	}
+
+	if modelOrdersDetailsPage.Next != nil {
+		setHeaderLinkNext(rw, req, modelOrdersDetailsPage.Next.String())
+	}
+
+	json.NewEncoder(rw).Encode(modelOrdersDetailsPage.OrdersDetails)
+}
+
+// paramProductID reads the ID of /api/product/{product_id}, responding the
+// request when it is invalid
+func (*Product) paramProductID(rw http.ResponseWriter, req *http.Request) (int64, bool) {
+	productID, err := strconv.ParseInt(strings.Split(req.URL.Path, "/")[3], 10, 64)
+
+	if err != nil {
+		responseError := model.BadRequestParamValidate("ID invalid")
+		rw.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(rw).Encode(responseError)
+		return 0, false
+	}
+
+	return productID, true
+}
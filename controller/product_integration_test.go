@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/usecase"
+)
+
+var (
+	testIntegrationUsecaseProduct    = usecase.NewProduct(testIntegrationRepository, testIntegrationConfig)
+	testIntegrationControllerProduct = NewProduct(testIntegrationLog, testIntegrationUsecaseProduct)
+)
+
+// testIntegrationProductGetSummaryByProductID queries the products of the file
+// imported by testIntegrationOrderLegacyImport
+func testIntegrationProductGetSummaryByProductID(t *testing.T) {
+	type test struct {
+		name        string
+		reqParam    string
+		resBody     interface{}
+		wantResCode int
+		wantResBody interface{}
+	}
+
+	tests := []test{
+		{
+			name:        "NotFoundError",
+			reqParam:    "4",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusNotFound,
+			wantResBody: model.NotFound("Product"),
+		},
+		{
+			name:        "Success",
+			reqParam:    "3",
+			resBody:     &model.ProductSummary{},
+			wantResCode: http.StatusOK,
+			wantResBody: &model.ProductSummary{
+				ProductID:     3,
+				TimesSold:     3,
+				Buyers:        2,
+				MinValue:      58674,
+				MaxValue:      183674,
+				AvgValue:      114434,
+				FirstSaleDate: "2021-03-08",
+				LastSaleDate:  "2021-09-03",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := fmt.Sprintf("/api/product/%v", tt.reqParam)
+
+			req, _ := http.NewRequest(http.MethodGet, url, nil)
+			handler := http.HandlerFunc(testIntegrationControllerProduct.GetSummaryByProductID)
+			res := httptest.NewRecorder()
+
+			handler.ServeHTTP(res, req)
+
+			if !reflect.DeepEqual(res.Code, tt.wantResCode) {
+				t.Errorf("GetSummaryByProductID() got res.code = %v, want %v", res.Code, tt.wantResCode)
+			}
+
+			json.NewDecoder(res.Body).Decode(tt.resBody)
+
+			if !reflect.DeepEqual(tt.resBody, tt.wantResBody) {
+				t.Errorf("GetSummaryByProductID() got res.body = %v, want %v", tt.resBody, tt.wantResBody)
+			}
+		})
+	}
+}
+
+func testIntegrationProductListOrdersDetails(t *testing.T) {
+	type test struct {
+		name        string
+		reqParam    string
+		resBody     interface{}
+		wantResCode int
+		wantResBody interface{}
+	}
+
+	tests := []test{
+		{
+			name:        "NotFoundError",
+			reqParam:    "4",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusNotFound,
+			wantResBody: model.NotFound("Product"),
+		},
+		{
+			name:        "Success",
+			reqParam:    "2",
+			resBody:     &model.OrdersDetails{},
+			wantResCode: http.StatusOK,
+			wantResBody: &model.OrdersDetails{
+				{
+					UserID:   75,
+					UserName: "Bobbie Batz",
+					Orders: []model.OrderDetailsOrder{
+						{
+							OrderID: 798,
+							BuyDate: "2021-11-16",
+							Total:   157857,
+							Products: []model.OrderDetailsProduct{
+								{
+									ID:    2,
+									Value: 157857,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := fmt.Sprintf("/api/product/%v/orders", tt.reqParam)
+
+			req, _ := http.NewRequest(http.MethodGet, url, nil)
+			handler := http.HandlerFunc(testIntegrationControllerProduct.ListOrdersDetails)
+			res := httptest.NewRecorder()
+
+			handler.ServeHTTP(res, req)
+
+			if !reflect.DeepEqual(res.Code, tt.wantResCode) {
+				t.Errorf("ListOrdersDetails() got res.code = %v, want %v", res.Code, tt.wantResCode)
+			}
+
+			json.NewDecoder(res.Body).Decode(tt.resBody)
+
+			if !reflect.DeepEqual(tt.resBody, tt.wantResBody) {
+				t.Errorf("ListOrdersDetails() got res.body = %v, want %v", tt.resBody, tt.wantResBody)
+			}
+		})
+	}
+}
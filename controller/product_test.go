@@ -0,0 +1,232 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	mock_usecase "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/usecase"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/usecase"
+	"github.com/hashicorp/go-hclog"
+)
+
+func TestProductGetSummaryByProductID(t *testing.T) {
+	modelProductSummary := model.ProductSummary{
+		ProductID:     3,
+		TimesSold:     2,
+		Buyers:        1,
+		MinValue:      100954,
+		MaxValue:      183674,
+		AvgValue:      142314,
+		FirstSaleDate: "2021-03-08",
+		LastSaleDate:  "2021-03-08",
+	}
+
+	type test struct {
+		name        string
+		reqParam    string
+		resBody     interface{}
+		wantResCode int
+		wantResBody interface{}
+		mockOn      func(*mock_usecase.MockUsecaseProduct)
+	}
+
+	tests := []test{
+		{
+			name:        "RequestParamError",
+			reqParam:    "X",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestParamValidate("ID invalid"),
+			mockOn: func(mockUsecaseProduct *mock_usecase.MockUsecaseProduct) {
+			},
+		},
+		{
+			name:        "NotFoundError",
+			reqParam:    "4",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusNotFound,
+			wantResBody: model.NotFound("Product"),
+			mockOn: func(mockUsecaseProduct *mock_usecase.MockUsecaseProduct) {
+				mockUsecaseProduct.On("GetSummaryByProductID").Return(nil, repository.ErrNotFound{Message: "not found"})
+			},
+		},
+		{
+			name:        "InternalServerError",
+			reqParam:    "3",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusInternalServerError,
+			wantResBody: model.InternalServerErrorRepositoryLoad("Product"),
+			mockOn: func(mockUsecaseProduct *mock_usecase.MockUsecaseProduct) {
+				mockUsecaseProduct.On("GetSummaryByProductID").Return(nil, errors.New("InternalServerError"))
+			},
+		},
+		{
+			name:        "Success",
+			reqParam:    "3",
+			resBody:     &model.ProductSummary{},
+			wantResCode: http.StatusOK,
+			wantResBody: &modelProductSummary,
+			mockOn: func(mockUsecaseProduct *mock_usecase.MockUsecaseProduct) {
+				mockUsecaseProduct.On("GetSummaryByProductID").Return(&modelProductSummary, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := hclog.New(&hclog.LoggerOptions{Level: hclog.LevelFromString("OFF")})
+			mockUsecaseProduct := new(mock_usecase.MockUsecaseProduct)
+
+			tt.mockOn(mockUsecaseProduct)
+
+			controllerProduct := NewProduct(log, mockUsecaseProduct)
+
+			url := fmt.Sprintf("/api/product/%v", tt.reqParam)
+
+			req, _ := http.NewRequest(http.MethodGet, url, nil)
+			handler := http.HandlerFunc(controllerProduct.GetSummaryByProductID)
+			res := httptest.NewRecorder()
+
+			handler.ServeHTTP(res, req)
+
+			if !reflect.DeepEqual(res.Code, tt.wantResCode) {
+				t.Errorf("GetSummaryByProductID() got res.code = %v, want %v", res.Code, tt.wantResCode)
+			}
+
+			json.NewDecoder(res.Body).Decode(tt.resBody)
+
+			if !reflect.DeepEqual(tt.resBody, tt.wantResBody) {
+				t.Errorf("GetSummaryByProductID() got res.body = %v, want %v", tt.resBody, tt.wantResBody)
+			}
+		})
+	}
+}
+
+func TestProductListOrdersDetails(t *testing.T) {
+	modelOrdersDetails := model.OrdersDetails{
+		{
+			UserID:   70,
+			UserName: "Palmer Prosacco",
+			Orders: []model.OrderDetailsOrder{
+				{
+					OrderID: 753,
+					BuyDate: "2021-03-08",
+					Total:   183674,
+					Products: []model.OrderDetailsProduct{
+						{
+							ID:    3,
+							Value: 183674,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	modelOrdersDetailsPageNext := model.OrdersDetailsPage{
+		OrdersDetails: modelOrdersDetails,
+		Next:          &model.OrderCursor{UserID: 70, OrderID: 753},
+	}
+
+	type test struct {
+		name        string
+		reqParam    string
+		resBody     interface{}
+		wantResCode int
+		wantResBody interface{}
+		wantResLink string
+		mockOn      func(*mock_usecase.MockUsecaseProduct)
+	}
+
+	tests := []test{
+		{
+			name:        "RequestParamError",
+			reqParam:    "X/orders",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestParamValidate("ID invalid"),
+			mockOn: func(mockUsecaseProduct *mock_usecase.MockUsecaseProduct) {
+			},
+		},
+		{
+			name:        "ParamCursorInvalidError",
+			reqParam:    "3/orders?cursor=X",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestParamValidate(usecase.OrderPageErrorMessageCursorInvalid),
+			mockOn: func(mockUsecaseProduct *mock_usecase.MockUsecaseProduct) {
+			},
+		},
+		{
+			name:        "NotFoundError",
+			reqParam:    "4/orders",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusNotFound,
+			wantResBody: model.NotFound("Product"),
+			mockOn: func(mockUsecaseProduct *mock_usecase.MockUsecaseProduct) {
+				mockUsecaseProduct.On("ListOrdersDetails").Return(nil, repository.ErrNotFound{Message: "not found"})
+			},
+		},
+		{
+			name:        "InternalServerError",
+			reqParam:    "3/orders",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusInternalServerError,
+			wantResBody: model.InternalServerErrorRepositoryLoad("Product"),
+			mockOn: func(mockUsecaseProduct *mock_usecase.MockUsecaseProduct) {
+				mockUsecaseProduct.On("ListOrdersDetails").Return(nil, errors.New("InternalServerError"))
+			},
+		},
+		{
+			name:        "NextPageSuccess",
+			reqParam:    "3/orders?limit=1",
+			resBody:     &model.OrdersDetails{},
+			wantResCode: http.StatusOK,
+			wantResBody: &modelOrdersDetails,
+			wantResLink: `</api/product/3/orders?cursor=NzA6NzUz&limit=1>; rel="next"`,
+			mockOn: func(mockUsecaseProduct *mock_usecase.MockUsecaseProduct) {
+				mockUsecaseProduct.On("ListOrdersDetails").Return(&modelOrdersDetailsPageNext, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := hclog.New(&hclog.LoggerOptions{Level: hclog.LevelFromString("OFF")})
+			mockUsecaseProduct := new(mock_usecase.MockUsecaseProduct)
+
+			tt.mockOn(mockUsecaseProduct)
+
+			controllerProduct := NewProduct(log, mockUsecaseProduct)
+
+			url := fmt.Sprintf("/api/product/%v", tt.reqParam)
+
+			req, _ := http.NewRequest(http.MethodGet, url, nil)
+			handler := http.HandlerFunc(controllerProduct.ListOrdersDetails)
+			res := httptest.NewRecorder()
+
+			handler.ServeHTTP(res, req)
+
+			if !reflect.DeepEqual(res.Code, tt.wantResCode) {
+				t.Errorf("ListOrdersDetails() got res.code = %v, want %v", res.Code, tt.wantResCode)
+			}
+
+			if res.Header().Get("Link") != tt.wantResLink {
+				t.Errorf("ListOrdersDetails() got res.link = %v, want %v", res.Header().Get("Link"), tt.wantResLink)
+			}
+
+			json.NewDecoder(res.Body).Decode(tt.resBody)
+
+			if !reflect.DeepEqual(tt.resBody, tt.wantResBody) {
+				t.Errorf("ListOrdersDetails() got res.body = %v, want %v", tt.resBody, tt.wantResBody)
+			}
+		})
+	}
+}
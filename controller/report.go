@@ -0,0 +1,223 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+	logger "github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/logger"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/usecase"
+	"github.com/hashicorp/go-hclog"
+)
+
+type Report struct {
+	Title         string
+	Log           hclog.Logger
+	UsecaseReport usecase.Report
+}
+
+func NewReport(log hclog.Logger, usecaseReport usecase.Report) *Report {
+	return &Report{
+		Title:         "Report",
+		Log:           log,
+		UsecaseReport: usecaseReport,
+	}
+}
+
+// ListRevenue godoc
+// @Summary      Relatório de Faturamento
+// @Description  Retorna a quantidade e o Valor Total dos Pedidos por dia, semana (iniciada na segunda-feira) ou mês da data da Compra.<br/>
+// @Description  Sem os parâmetros from e to considera todos os Pedidos.
+// @Tags         Relatórios
+// @Accept       json
+// @Produce      json
+// @Param        period  query   string  false  "Período de agrupamento: day, week ou month (padrão day)" example(month)
+// @Param        from    query   string  false  "Data da Compra inicial no formato AAAA-MM-DD, obrigatória com o parâmetro to" example(2021-01-01)
+// @Param        to      query   string  false  "Data da Compra final no formato AAAA-MM-DD, obrigatória com o parâmetro from" example(2021-12-31)
+// @Success      200  {object}  model.ReportsRevenue
+// @Failure      400  {object}  model.Error
+// @Failure      404  {object}  model.Error
+// @Failure      500  {object}  model.Error
+// @Router       /report/revenue [get]
+func (controllerReport *Report) ListRevenue(rw http.ResponseWriter, req *http.Request) {
+	modelReportFilter, ok := controllerReport.reportFilter(rw, req, false)
+
+	if !ok {
+		return
+	}
+
+	modelReportFilter.Period = req.URL.Query().Get("period")
+
+	modelReportsRevenue, err := controllerReport.UsecaseReport.ListRevenue(modelReportFilter)
+
+	controllerReport.response(rw, req, modelReportsRevenue, err)
+}
+
+// ListTopUsers godoc
+// @Summary      Relatório dos maiores Usuários
+// @Description  Retorna os Usuários com o maior Valor Total dos Pedidos no período da data da Compra.<br/>
+// @Description  Sem os parâmetros from e to considera todos os Pedidos.
+// @Tags         Relatórios
+// @Accept       json
+// @Produce      json
+// @Param        limit  query   int     false  "Quantidade de Usuários, limitada por REPORT_TOP_MAX_SIZE" example(10)
+// @Param        from   query   string  false  "Data da Compra inicial no formato AAAA-MM-DD, obrigatória com o parâmetro to" example(2021-01-01)
+// @Param        to     query   string  false  "Data da Compra final no formato AAAA-MM-DD, obrigatória com o parâmetro from" example(2021-12-31)
+// @Success      200  {object}  model.ReportTopUsers
+// @Failure      400  {object}  model.Error
+// @Failure      404  {object}  model.Error
+// @Failure      500  {object}  model.Error
+// @Router       /report/top-users [get]
+func (controllerReport *Report) ListTopUsers(rw http.ResponseWriter, req *http.Request) {
+	modelReportFilter, ok := controllerReport.reportFilter(rw, req, true)
+
+	if !ok {
+		return
+	}
+
+	modelReportTopUsers, err := controllerReport.UsecaseReport.ListTopUsers(modelReportFilter)
+
+	controllerReport.response(rw, req, modelReportTopUsers, err)
+}
+
+// ListTopProducts godoc
+// @Summary      Relatório dos Produtos mais vendidos
+// @Description  Retorna os Produtos com o maior Valor Total das vendas no período da data da Compra.<br/>
+// @Description  Sem os parâmetros from e to considera todos os Pedidos.
+// @Tags         Relatórios
+// @Accept       json
+// @Produce      json
+// @Param        limit  query   int     false  "Quantidade de Produtos, limitada por REPORT_TOP_MAX_SIZE" example(10)
+// @Param        from   query   string  false  "Data da Compra inicial no formato AAAA-MM-DD, obrigatória com o parâmetro to" example(2021-01-01)
+// @Param        to     query   string  false  "Data da Compra final no formato AAAA-MM-DD, obrigatória com o parâmetro from" example(2021-12-31)
+// @Success      200  {object}  model.ReportTopProducts
+// @Failure      400  {object}  model.Error
+// @Failure      404  {object}  model.Error
+// @Failure      500  {object}  model.Error
+// @Router       /report/top-products [get]
+func (controllerReport *Report) ListTopProducts(rw http.ResponseWriter, req *http.Request) {
+	modelReportFilter, ok := controllerReport.reportFilter(rw, req, true)
+
+	if !ok {
+		return
+	}
+
+	modelReportTopProducts, err := controllerReport.UsecaseReport.ListTopProducts(modelReportFilter)
+
+	controllerReport.response(rw, req, modelReportTopProducts, err)
+}
+
+// GetBasket godoc
+// @Summary      Relatório da Cesta média
+// @Description  Retorna a quantidade de Pedidos e de Produtos, o Valor Total, a quantidade média de Produtos e o Valor médio dos Pedidos no período da data da Compra.<br/>
+// @Description  Sem os parâmetros from e to considera todos os Pedidos.
+// @Tags         Relatórios
+// @Accept       json
+// @Produce      json
+// @Param        from  query   string  false  "Data da Compra inicial no formato AAAA-MM-DD, obrigatória com o parâmetro to" example(2021-01-01)
+// @Param        to    query   string  false  "Data da Compra final no formato AAAA-MM-DD, obrigatória com o parâmetro from" example(2021-12-31)
+// @Success      200  {object}  model.ReportBasket
+// @Failure      400  {object}  model.Error
+// @Failure      404  {object}  model.Error
+// @Failure      500  {object}  model.Error
+// @Router       /report/basket [get]
+func (controllerReport *Report) GetBasket(rw http.ResponseWriter, req *http.Request) {
+	modelReportFilter, ok := controllerReport.reportFilter(rw, req, false)
+
+	if !ok {
+		return
+	}
+
+	modelReportBasket, err := controllerReport.UsecaseReport.GetBasket(modelReportFilter)
+
+	controllerReport.response(rw, req, modelReportBasket, err)
+}
+
+// reportFilter writes the bad request when the params of the filter are
+// invalid and returns false
+func (controllerReport *Report) reportFilter(rw http.ResponseWriter, req *http.Request, withLimit bool) (*model.ReportFilter, bool) {
+	limitParam := ""
+
+	if withLimit {
+		limitParam = req.URL.Query().Get("limit")
+	}
+
+	modelReportFilter, err := validateQueryParamsReportFilter(req.URL.Query().Get("from"), req.URL.Query().Get("to"), limitParam)
+
+	if err != nil {
+		responseError := model.BadRequestParamValidate(err.Error())
+
+		logger.LogErrorRequest(controllerReport.Log, req, responseError.Message, err)
+
+		rw.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(rw).Encode(responseError)
+		return nil, false
+	}
+
+	return modelReportFilter, true
+}
+
+func (controllerReport *Report) response(rw http.ResponseWriter, req *http.Request, report interface{}, err error) {
+	if err != nil {
+		var responseError *model.Error
+
+		if _, ok := err.(usecase.ErrParamValidate); ok {
+			responseError = model.BadRequestParamValidate(err.Error())
+
+			rw.WriteHeader(http.StatusBadRequest)
+		} else if _, ok := err.(repository.ErrNotFound); ok {
+			responseError = model.NotFound(controllerReport.Title)
+
+			rw.WriteHeader(http.StatusNotFound)
+		} else {
+			responseError = model.InternalServerErrorRepositoryLoad(controllerReport.Title)
+
+			logger.LogErrorRequest(controllerReport.Log, req, responseError.Message, err)
+
+			rw.WriteHeader(http.StatusInternalServerError)
+		}
+
+		json.NewEncoder(rw).Encode(responseError)
+		return
+	}
+
+	json.NewEncoder(rw).Encode(report)
+}
+
+// validateQueryParamsReportFilter validates the period of the buy date only
+// when one of its params is informed
+func validateQueryParamsReportFilter(fromParam, toParam, limitParam string) (*model.ReportFilter, error) {
+	modelReportFilter := &model.ReportFilter{}
+
+	messages := []string{}
+
+	if fromParam != "" || toParam != "" {
+		modelOrderRangeBuyDate, err := validateQueryParamsOrderRangeBuyDate(fromParam, toParam)
+
+		if err != nil {
+			messages = append(messages, err.Error())
+		}
+
+		modelReportFilter.BuyDate = modelOrderRangeBuyDate
+	}
+
+	if limitParam != "" {
+		limit, err := strconv.Atoi(limitParam)
+
+		if err != nil || limit < 1 {
+			messages = append(messages, usecase.OrderPageErrorMessageLimitInvalid)
+		}
+
+		modelReportFilter.Size = limit
+	}
+
+	if len(messages) > 0 {
+		return nil, errors.New(strings.Join(messages, ";"))
+	}
+
+	return modelReportFilter, nil
+}
@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/usecase"
+)
+
+var (
+	testIntegrationUsecaseReport    = usecase.NewReport(testIntegrationRepository, testIntegrationCache, testIntegrationConfig)
+	testIntegrationControllerReport = NewReport(testIntegrationLog, testIntegrationUsecaseReport)
+)
+
+// testIntegrationReport aggregates the orders of the file imported by
+// testIntegrationOrderLegacyImport
+func testIntegrationReport(t *testing.T) {
+	type test struct {
+		name        string
+		reqPath     string
+		reqParam    string
+		handler     http.HandlerFunc
+		resBody     interface{}
+		wantResCode int
+		wantResBody interface{}
+	}
+
+	tests := []test{
+		{
+			name:        "RevenueNotFoundError",
+			reqPath:     "revenue",
+			reqParam:    "?from=2020-01-01&to=2020-12-31",
+			handler:     testIntegrationControllerReport.ListRevenue,
+			resBody:     &model.Error{},
+			wantResCode: http.StatusNotFound,
+			wantResBody: model.NotFound("Report"),
+		},
+		{
+			name:        "RevenueMonthSuccess",
+			reqPath:     "revenue",
+			reqParam:    "?period=month",
+			handler:     testIntegrationControllerReport.ListRevenue,
+			resBody:     &model.ReportsRevenue{},
+			wantResCode: http.StatusOK,
+			wantResBody: &model.ReportsRevenue{
+				{Period: "2021-03-01", Orders: 1, Revenue: 284628},
+				{Period: "2021-09-01", Orders: 1, Revenue: 58674},
+				{Period: "2021-11-01", Orders: 1, Revenue: 157857},
+			},
+		},
+		{
+			name:        "RevenueWeekSuccess",
+			reqPath:     "revenue",
+			reqParam:    "?period=week&from=2021-09-01&to=2021-12-31",
+			handler:     testIntegrationControllerReport.ListRevenue,
+			resBody:     &model.ReportsRevenue{},
+			wantResCode: http.StatusOK,
+			wantResBody: &model.ReportsRevenue{
+				{Period: "2021-08-30", Orders: 1, Revenue: 58674},
+				{Period: "2021-11-15", Orders: 1, Revenue: 157857},
+			},
+		},
+		{
+			name:        "TopUsersSuccess",
+			reqPath:     "top-users",
+			reqParam:    "",
+			handler:     testIntegrationControllerReport.ListTopUsers,
+			resBody:     &model.ReportTopUsers{},
+			wantResCode: http.StatusOK,
+			wantResBody: &model.ReportTopUsers{
+				{UserID: 70, UserName: "Palmer Prosacco", Orders: 1, Total: 284628},
+				{UserID: 75, UserName: "Bobbie Batz", Orders: 2, Total: 216531},
+			},
+		},
+		{
+			name:        "TopProductsSuccess",
+			reqPath:     "top-products",
+			reqParam:    "?limit=1",
+			handler:     testIntegrationControllerReport.ListTopProducts,
+			resBody:     &model.ReportTopProducts{},
+			wantResCode: http.StatusOK,
+			wantResBody: &model.ReportTopProducts{
+				{ProductID: 3, TimesSold: 3, Revenue: 343302},
+			},
+		},
+		{
+			name:        "BasketSuccess",
+			reqPath:     "basket",
+			reqParam:    "",
+			handler:     testIntegrationControllerReport.GetBasket,
+			resBody:     &model.ReportBasket{},
+			wantResCode: http.StatusOK,
+			wantResBody: &model.ReportBasket{
+				Orders:      3,
+				Products:    4,
+				Revenue:     501159,
+				AvgProducts: 1.33,
+				AvgTotal:    167053,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := fmt.Sprintf("/api/report/%v%v", tt.reqPath, tt.reqParam)
+
+			req, _ := http.NewRequest(http.MethodGet, url, nil)
+			res := httptest.NewRecorder()
+
+			tt.handler.ServeHTTP(res, req)
+
+			if !reflect.DeepEqual(res.Code, tt.wantResCode) {
+				t.Errorf("Report() got res.code = %v, want %v", res.Code, tt.wantResCode)
+			}
+
+			json.NewDecoder(res.Body).Decode(tt.resBody)
+
+			if !reflect.DeepEqual(tt.resBody, tt.wantResBody) {
+				t.Errorf("Report() got res.body = %v, want %v", tt.resBody, tt.wantResBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,353 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	mock_usecase "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/usecase"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/usecase"
+	"github.com/hashicorp/go-hclog"
+)
+
+func TestReportListRevenue(t *testing.T) {
+	modelReportsRevenue := model.ReportsRevenue{
+		{
+			Period:  "2021-03-01",
+			Orders:  1,
+			Revenue: 284628,
+		},
+	}
+
+	type test struct {
+		name        string
+		reqParam    string
+		resBody     interface{}
+		wantResCode int
+		wantResBody interface{}
+		mockOn      func(*mock_usecase.MockUsecaseReport)
+	}
+
+	tests := []test{
+		{
+			name:        "ParamBuyDateError",
+			reqParam:    "?from=2021-03-01",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestParamValidate(usecase.OrderRangeBuyDateErrorMessageToEmpty),
+			mockOn: func(mockUsecaseReport *mock_usecase.MockUsecaseReport) {
+			},
+		},
+		{
+			name:        "ParamValidateError",
+			reqParam:    "?period=year",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestParamValidate(usecase.ReportErrorMessagePeriodInvalid),
+			mockOn: func(mockUsecaseReport *mock_usecase.MockUsecaseReport) {
+				mockUsecaseReport.On("ListRevenue").Return(nil, usecase.ErrParamValidate{Message: usecase.ReportErrorMessagePeriodInvalid})
+			},
+		},
+		{
+			name:        "NotFoundError",
+			reqParam:    "?from=2020-01-01&to=2020-12-31",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusNotFound,
+			wantResBody: model.NotFound("Report"),
+			mockOn: func(mockUsecaseReport *mock_usecase.MockUsecaseReport) {
+				mockUsecaseReport.On("ListRevenue").Return(nil, repository.ErrNotFound{Message: "not found"})
+			},
+		},
+		{
+			name:        "InternalServerError",
+			reqParam:    "",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusInternalServerError,
+			wantResBody: model.InternalServerErrorRepositoryLoad("Report"),
+			mockOn: func(mockUsecaseReport *mock_usecase.MockUsecaseReport) {
+				mockUsecaseReport.On("ListRevenue").Return(nil, errors.New("InternalServerError"))
+			},
+		},
+		{
+			name:        "Success",
+			reqParam:    "?period=month&from=2021-03-01&to=2021-03-31",
+			resBody:     &model.ReportsRevenue{},
+			wantResCode: http.StatusOK,
+			wantResBody: &modelReportsRevenue,
+			mockOn: func(mockUsecaseReport *mock_usecase.MockUsecaseReport) {
+				mockUsecaseReport.On("ListRevenue").Return(&modelReportsRevenue, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := hclog.New(&hclog.LoggerOptions{Level: hclog.LevelFromString("OFF")})
+			mockUsecaseReport := new(mock_usecase.MockUsecaseReport)
+
+			tt.mockOn(mockUsecaseReport)
+
+			controllerReport := NewReport(log, mockUsecaseReport)
+
+			url := fmt.Sprintf("/api/report/revenue%v", tt.reqParam)
+
+			req, _ := http.NewRequest(http.MethodGet, url, nil)
+			handler := http.HandlerFunc(controllerReport.ListRevenue)
+			res := httptest.NewRecorder()
+
+			handler.ServeHTTP(res, req)
+
+			if !reflect.DeepEqual(res.Code, tt.wantResCode) {
+				t.Errorf("ListRevenue() got res.code = %v, want %v", res.Code, tt.wantResCode)
+			}
+
+			json.NewDecoder(res.Body).Decode(tt.resBody)
+
+			if !reflect.DeepEqual(tt.resBody, tt.wantResBody) {
+				t.Errorf("ListRevenue() got res.body = %v, want %v", tt.resBody, tt.wantResBody)
+			}
+		})
+	}
+}
+
+func TestReportListTopUsers(t *testing.T) {
+	modelReportTopUsers := model.ReportTopUsers{
+		{
+			UserID:   70,
+			UserName: "Palmer Prosacco",
+			Orders:   1,
+			Total:    284628,
+		},
+	}
+
+	type test struct {
+		name        string
+		reqParam    string
+		resBody     interface{}
+		wantResCode int
+		wantResBody interface{}
+		mockOn      func(*mock_usecase.MockUsecaseReport)
+	}
+
+	tests := []test{
+		{
+			name:        "ParamLimitInvalidError",
+			reqParam:    "?limit=X",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestParamValidate(usecase.OrderPageErrorMessageLimitInvalid),
+			mockOn: func(mockUsecaseReport *mock_usecase.MockUsecaseReport) {
+			},
+		},
+		{
+			name:        "InternalServerError",
+			reqParam:    "",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusInternalServerError,
+			wantResBody: model.InternalServerErrorRepositoryLoad("Report"),
+			mockOn: func(mockUsecaseReport *mock_usecase.MockUsecaseReport) {
+				mockUsecaseReport.On("ListTopUsers").Return(nil, errors.New("InternalServerError"))
+			},
+		},
+		{
+			name:        "Success",
+			reqParam:    "?limit=1&from=2021-03-01&to=2021-03-31",
+			resBody:     &model.ReportTopUsers{},
+			wantResCode: http.StatusOK,
+			wantResBody: &modelReportTopUsers,
+			mockOn: func(mockUsecaseReport *mock_usecase.MockUsecaseReport) {
+				mockUsecaseReport.On("ListTopUsers").Return(&modelReportTopUsers, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := hclog.New(&hclog.LoggerOptions{Level: hclog.LevelFromString("OFF")})
+			mockUsecaseReport := new(mock_usecase.MockUsecaseReport)
+
+			tt.mockOn(mockUsecaseReport)
+
+			controllerReport := NewReport(log, mockUsecaseReport)
+
+			url := fmt.Sprintf("/api/report/top-users%v", tt.reqParam)
+
+			req, _ := http.NewRequest(http.MethodGet, url, nil)
+			handler := http.HandlerFunc(controllerReport.ListTopUsers)
+			res := httptest.NewRecorder()
+
+			handler.ServeHTTP(res, req)
+
+			if !reflect.DeepEqual(res.Code, tt.wantResCode) {
+				t.Errorf("ListTopUsers() got res.code = %v, want %v", res.Code, tt.wantResCode)
+			}
+
+			json.NewDecoder(res.Body).Decode(tt.resBody)
+
+			if !reflect.DeepEqual(tt.resBody, tt.wantResBody) {
+				t.Errorf("ListTopUsers() got res.body = %v, want %v", tt.resBody, tt.wantResBody)
+			}
+		})
+	}
+}
+
+func TestReportListTopProducts(t *testing.T) {
+	modelReportTopProducts := model.ReportTopProducts{
+		{
+			ProductID: 3,
+			TimesSold: 3,
+			Revenue:   343302,
+		},
+	}
+
+	type test struct {
+		name        string
+		reqParam    string
+		resBody     interface{}
+		wantResCode int
+		wantResBody interface{}
+		mockOn      func(*mock_usecase.MockUsecaseReport)
+	}
+
+	tests := []test{
+		{
+			name:        "ParamLimitInvalidError",
+			reqParam:    "?limit=0&from=X&to=2021-12-31",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestParamValidate(usecase.OrderRangeBuyDateErrorMessageFromInvalid + ";" + usecase.OrderPageErrorMessageLimitInvalid),
+			mockOn: func(mockUsecaseReport *mock_usecase.MockUsecaseReport) {
+			},
+		},
+		{
+			name:        "ParamValidateError",
+			reqParam:    "?limit=101",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestParamValidate(fmt.Sprintf(usecase.OrderPageErrorMessageLimitBetween, 100)),
+			mockOn: func(mockUsecaseReport *mock_usecase.MockUsecaseReport) {
+				mockUsecaseReport.On("ListTopProducts").Return(nil, usecase.ErrParamValidate{Message: fmt.Sprintf(usecase.OrderPageErrorMessageLimitBetween, 100)})
+			},
+		},
+		{
+			name:        "Success",
+			reqParam:    "?limit=1",
+			resBody:     &model.ReportTopProducts{},
+			wantResCode: http.StatusOK,
+			wantResBody: &modelReportTopProducts,
+			mockOn: func(mockUsecaseReport *mock_usecase.MockUsecaseReport) {
+				mockUsecaseReport.On("ListTopProducts").Return(&modelReportTopProducts, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := hclog.New(&hclog.LoggerOptions{Level: hclog.LevelFromString("OFF")})
+			mockUsecaseReport := new(mock_usecase.MockUsecaseReport)
+
+			tt.mockOn(mockUsecaseReport)
+
+			controllerReport := NewReport(log, mockUsecaseReport)
+
+			url := fmt.Sprintf("/api/report/top-products%v", tt.reqParam)
+
+			req, _ := http.NewRequest(http.MethodGet, url, nil)
+			handler := http.HandlerFunc(controllerReport.ListTopProducts)
+			res := httptest.NewRecorder()
+
+			handler.ServeHTTP(res, req)
+
+			if !reflect.DeepEqual(res.Code, tt.wantResCode) {
+				t.Errorf("ListTopProducts() got res.code = %v, want %v", res.Code, tt.wantResCode)
+			}
+
+			json.NewDecoder(res.Body).Decode(tt.resBody)
+
+			if !reflect.DeepEqual(tt.resBody, tt.wantResBody) {
+				t.Errorf("ListTopProducts() got res.body = %v, want %v", tt.resBody, tt.wantResBody)
+			}
+		})
+	}
+}
+
+func TestReportGetBasket(t *testing.T) {
+	modelReportBasket := model.NewReportBasket(2, 5, 343302)
+
+	type test struct {
+		name        string
+		reqParam    string
+		resBody     interface{}
+		wantResCode int
+		wantResBody interface{}
+		mockOn      func(*mock_usecase.MockUsecaseReport)
+	}
+
+	tests := []test{
+		{
+			name:        "ParamBuyDateError",
+			reqParam:    "?to=2021-03-31",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestParamValidate(usecase.OrderRangeBuyDateErrorMessageFromEmpty),
+			mockOn: func(mockUsecaseReport *mock_usecase.MockUsecaseReport) {
+			},
+		},
+		{
+			name:        "NotFoundError",
+			reqParam:    "?from=2020-01-01&to=2020-12-31",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusNotFound,
+			wantResBody: model.NotFound("Report"),
+			mockOn: func(mockUsecaseReport *mock_usecase.MockUsecaseReport) {
+				mockUsecaseReport.On("GetBasket").Return(nil, repository.ErrNotFound{Message: "not found"})
+			},
+		},
+		{
+			// the limit is not a param of the basket
+			name:        "Success",
+			reqParam:    "?limit=X",
+			resBody:     &model.ReportBasket{},
+			wantResCode: http.StatusOK,
+			wantResBody: modelReportBasket,
+			mockOn: func(mockUsecaseReport *mock_usecase.MockUsecaseReport) {
+				mockUsecaseReport.On("GetBasket").Return(modelReportBasket, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := hclog.New(&hclog.LoggerOptions{Level: hclog.LevelFromString("OFF")})
+			mockUsecaseReport := new(mock_usecase.MockUsecaseReport)
+
+			tt.mockOn(mockUsecaseReport)
+
+			controllerReport := NewReport(log, mockUsecaseReport)
+
+			url := fmt.Sprintf("/api/report/basket%v", tt.reqParam)
+
+			req, _ := http.NewRequest(http.MethodGet, url, nil)
+			handler := http.HandlerFunc(controllerReport.GetBasket)
+			res := httptest.NewRecorder()
+
+			handler.ServeHTTP(res, req)
+
+			if !reflect.DeepEqual(res.Code, tt.wantResCode) {
+				t.Errorf("GetBasket() got res.code = %v, want %v", res.Code, tt.wantResCode)
+			}
+
+			json.NewDecoder(res.Body).Decode(tt.resBody)
+
+			if !reflect.DeepEqual(tt.resBody, tt.wantResBody) {
+				t.Errorf("GetBasket() got res.body = %v, want %v", tt.resBody, tt.wantResBody)
+			}
+		})
+	}
+}
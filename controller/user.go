@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+	logger "github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/logger"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/usecase"
+	"github.com/hashicorp/go-hclog"
+)
+
+type User struct {
+	Title       string
+	Log         hclog.Logger
+	UsecaseUser usecase.User
+}
+
+func NewUser(log hclog.Logger, usecaseUser usecase.User) *User {
+	return &User{
+		Title:       "User",
+		Log:         log,
+		UsecaseUser: usecaseUser,
+	}
+}
+
+// GetDetailsByUserID godoc
+// @Summary      Consultar Usuário por ID
+// @Description  Retorna todos os Pedidos do Usuário referente ao ID informado.
+// @Tags         Usuários
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  false  "ID do Usuário" example(1) validate(required)
+// @Success      200  {object}  model.OrderDetails
+// @Failure      400  {object}  model.Error
+// @Failure      404  {object}  model.Error
+// @Failure      500  {object}  model.Error
+// @Router       /user/{id} [get]
+func (controllerUser *User) GetDetailsByUserID(rw http.ResponseWriter, req *http.Request) {
+	paths := strings.Split(req.URL.Path, "/")
+	paramUserID := paths[len(paths)-1]
+
+	userID, err := strconv.ParseInt(paramUserID, 10, 64)
+
+	if err != nil {
+		responseError := model.BadRequestParamValidate("ID invalid")
+		rw.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(rw).Encode(responseError)
+		return
+	}
+
+	modelOrderDetails, err := controllerUser.UsecaseUser.GetDetailsByUserID(userID)
+
+	if err != nil {
+		var responseError *model.Error
+
+		if _, ok := err.(repository.ErrNotFound); ok {
+			responseError = model.NotFound(controllerUser.Title)
+
+			rw.WriteHeader(http.StatusNotFound)
+		} else {
+			responseError = model.InternalServerErrorRepositoryLoad(controllerUser.Title)
+
+			logger.LogErrorRequest(controllerUser.Log, req, responseError.Message, err)
+
+			rw.WriteHeader(http.StatusInternalServerError)
+		}
+
+		json.NewEncoder(rw).Encode(responseError)
+		return
+	}
+
+	json.NewEncoder(rw).Encode(modelOrderDetails)
+}
+
+// ListSummaries godoc
+// @Summary      Listar Usuários
+// @Description  Retorna os Usuários com a quantidade de Pedidos, a data da primeira e da última Compra e o Valor Total dos Pedidos.<br/>
+// @Description  Os Usuários são paginados pelo ID, o cabeçalho Link (rel="next") informa a URL da próxima página.
+// @Tags         Usuários
+// @Accept       json
+// @Produce      json
+// @Param        name    query   string  false  "Parte do Nome do Usuário, sem diferenciar maiúsculas e minúsculas" example(prosacco)
+// @Param        limit   query   int     false  "Quantidade de Usuários da página, limitada por USER_PAGE_MAX_SIZE" example(100)
+// @Param        cursor  query   string  false  "Cursor da página retornado no cabeçalho Link da página anterior"
+// @Success      200  {object}  model.UserSummaries
+// @Header       200  {string}  Link  "URL da próxima página (rel=\"next\")"
+// @Failure      400  {object}  model.Error
+// @Failure      404  {object}  model.Error
+// @Failure      500  {object}  model.Error
+// @Router       /user [get]
+func (controllerUser *User) ListSummaries(rw http.ResponseWriter, req *http.Request) {
+	modelUserFilter := &model.UserFilter{Name: strings.TrimSpace(req.URL.Query().Get("name"))}
+
+	modelUserPage, err := validateQueryParamsUserPage(req.URL.Query().Get("limit"), req.URL.Query().Get("cursor"))
+
+	if err != nil {
+		responseError := model.BadRequestParamValidate(err.Error())
+
+		logger.LogErrorRequest(controllerUser.Log, req, responseError.Message, err)
+
+		rw.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(rw).Encode(responseError)
+		return
+	}
+
+	modelUserSummariesPage, err := controllerUser.UsecaseUser.ListSummaries(modelUserFilter, modelUserPage)
+
+	if err != nil {
+		var responseError *model.Error
+
+		if _, ok := err.(usecase.ErrParamValidate); ok {
+			responseError = model.BadRequestParamValidate(err.Error())
+
+			rw.WriteHeader(http.StatusBadRequest)
+		} else if _, ok := err.(repository.ErrNotFound); ok {
+			responseError = model.NotFound(controllerUser.Title)
+
+			rw.WriteHeader(http.StatusNotFound)
+		} else {
+			responseError = model.InternalServerErrorRepositoryLoad(controllerUser.Title)
+
+			logger.LogErrorRequest(controllerUser.Log, req, responseError.Message, err)
+
+			rw.WriteHeader(http.StatusInternalServerError)
+		}
+
+		json.NewEncoder(rw).Encode(responseError)
+		return
+	}
+
+	if modelUserSummariesPage.Next != nil {
+		setHeaderLinkNext(rw, req, modelUserSummariesPage.Next.String())
+	}
+
+	json.NewEncoder(rw).Encode(modelUserSummariesPage.UserSummaries)
+}
+
+func validateQueryParamsUserPage(limitParam, cursorParam string) (*model.UserPage, error) {
+	modelUserPage := &model.UserPage{}
+
+	messages := []string{}
+
+	if limitParam != "" {
+		limit, err := strconv.Atoi(limitParam)
+
+		if err != nil || limit < 1 {
+			messages = append(messages, usecase.OrderPageErrorMessageLimitInvalid)
+		}
+
+		modelUserPage.Limit = limit
+	}
+
+	if cursorParam != "" {
+		modelUserCursor, err := model.ParseUserCursor(cursorParam)
+
+		if err != nil {
+			messages = append(messages, usecase.OrderPageErrorMessageCursorInvalid)
+		}
+
+		modelUserPage.Cursor = modelUserCursor
+	}
+
+	if len(messages) > 0 {
+		return nil, errors.New(strings.Join(messages, ";"))
+	}
+
+	return modelUserPage, nil
+}
@@ -0,0 +1,197 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/usecase"
+)
+
+var (
+	testIntegrationUsecaseUser    = usecase.NewUser(testIntegrationRepository, testIntegrationCache, testIntegrationConfig)
+	testIntegrationControllerUser = NewUser(testIntegrationLog, testIntegrationUsecaseUser)
+)
+
+// testIntegrationUserGetDetailsByUserID queries the users of the file imported
+// by testIntegrationOrderLegacyImport
+func testIntegrationUserGetDetailsByUserID(t *testing.T) {
+	type test struct {
+		name        string
+		reqParam    string
+		resBody     interface{}
+		wantResCode int
+		wantResBody interface{}
+	}
+
+	tests := []test{
+		{
+			name:        "RequestParamError",
+			reqParam:    "X",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestParamValidate("ID invalid"),
+		},
+		{
+			name:        "NotFoundError",
+			reqParam:    "753",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusNotFound,
+			wantResBody: model.NotFound("User"),
+		},
+		{
+			name:        "Success",
+			reqParam:    "75",
+			resBody:     &model.OrderDetails{},
+			wantResCode: http.StatusOK,
+			wantResBody: &model.OrderDetails{
+				UserID:   75,
+				UserName: "Bobbie Batz",
+				Orders: []model.OrderDetailsOrder{
+					{
+						OrderID: 523,
+						BuyDate: "2021-09-03",
+						Total:   58674,
+						Products: []model.OrderDetailsProduct{
+							{
+								ID:    3,
+								Value: 58674,
+							},
+						},
+					},
+					{
+						OrderID: 798,
+						BuyDate: "2021-11-16",
+						Total:   157857,
+						Products: []model.OrderDetailsProduct{
+							{
+								ID:    2,
+								Value: 157857,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := fmt.Sprintf("/api/user/%v", tt.reqParam)
+
+			req, _ := http.NewRequest(http.MethodGet, url, nil)
+			handler := http.HandlerFunc(testIntegrationControllerUser.GetDetailsByUserID)
+			res := httptest.NewRecorder()
+
+			handler.ServeHTTP(res, req)
+
+			if !reflect.DeepEqual(res.Code, tt.wantResCode) {
+				t.Errorf("GetDetailsByUserID() got res.code = %v, want %v", res.Code, tt.wantResCode)
+			}
+
+			json.NewDecoder(res.Body).Decode(tt.resBody)
+
+			if !reflect.DeepEqual(tt.resBody, tt.wantResBody) {
+				t.Errorf("GetDetailsByUserID() got res.body = %v, want %v", tt.resBody, tt.wantResBody)
+			}
+		})
+	}
+}
+
+func testIntegrationUserListSummaries(t *testing.T) {
+	modelUserSummary70 := model.UserSummary{
+		UserID:       70,
+		UserName:     "Palmer Prosacco",
+		Orders:       1,
+		FirstBuyDate: "2021-03-08",
+		LastBuyDate:  "2021-03-08",
+		Total:        284628,
+	}
+
+	modelUserSummary75 := model.UserSummary{
+		UserID:       75,
+		UserName:     "Bobbie Batz",
+		Orders:       2,
+		FirstBuyDate: "2021-09-03",
+		LastBuyDate:  "2021-11-16",
+		Total:        216531,
+	}
+
+	type test struct {
+		name        string
+		reqParam    string
+		resBody     interface{}
+		wantResCode int
+		wantResBody interface{}
+		wantResLink string
+	}
+
+	tests := []test{
+		{
+			name:        "NotFoundError",
+			reqParam:    "?name=joao",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusNotFound,
+			wantResBody: model.NotFound("User"),
+		},
+		{
+			name:        "AllSuccess",
+			reqParam:    "",
+			resBody:     &model.UserSummaries{},
+			wantResCode: http.StatusOK,
+			wantResBody: &model.UserSummaries{modelUserSummary70, modelUserSummary75},
+		},
+		{
+			name:        "NameSuccess",
+			reqParam:    "?name=BATZ",
+			resBody:     &model.UserSummaries{},
+			wantResCode: http.StatusOK,
+			wantResBody: &model.UserSummaries{modelUserSummary75},
+		},
+		{
+			name:        "FirstPageSuccess",
+			reqParam:    "?limit=1",
+			resBody:     &model.UserSummaries{},
+			wantResCode: http.StatusOK,
+			wantResBody: &model.UserSummaries{modelUserSummary70},
+			wantResLink: `</api/user?cursor=dTo3MA&limit=1>; rel="next"`,
+		},
+		{
+			name:        "NextPageSuccess",
+			reqParam:    "?limit=1&cursor=dTo3MA",
+			resBody:     &model.UserSummaries{},
+			wantResCode: http.StatusOK,
+			wantResBody: &model.UserSummaries{modelUserSummary75},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := fmt.Sprintf("/api/user%v", tt.reqParam)
+
+			req, _ := http.NewRequest(http.MethodGet, url, nil)
+			handler := http.HandlerFunc(testIntegrationControllerUser.ListSummaries)
+			res := httptest.NewRecorder()
+
+			handler.ServeHTTP(res, req)
+
+			if !reflect.DeepEqual(res.Code, tt.wantResCode) {
+				t.Errorf("ListSummaries() got res.code = %v, want %v", res.Code, tt.wantResCode)
+			}
+
+			if res.Header().Get("Link") != tt.wantResLink {
+				t.Errorf("ListSummaries() got res.link = %v, want %v", res.Header().Get("Link"), tt.wantResLink)
+			}
+
+			json.NewDecoder(res.Body).Decode(tt.resBody)
+
+			if !reflect.DeepEqual(tt.resBody, tt.wantResBody) {
+				t.Errorf("ListSummaries() got res.body = %v, want %v", tt.resBody, tt.wantResBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	mock_usecase "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/usecase"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/usecase"
+	"github.com/hashicorp/go-hclog"
+)
+
+func TestUserGetDetailsByUserID(t *testing.T) {
+	modelOrderDetails := model.OrderDetails{
+		UserID:   70,
+		UserName: "Palmer Prosacco",
+		Orders: []model.OrderDetailsOrder{
+			{
+				OrderID: 753,
+				BuyDate: "2021-03-08",
+				Total:   183674,
+				Products: []model.OrderDetailsProduct{
+					{
+						ID:    3,
+						Value: 183674,
+					},
+				},
+			},
+		},
+	}
+
+	type test struct {
+		name        string
+		reqParam    string
+		resBody     interface{}
+		wantResCode int
+		wantResBody interface{}
+		mockOn      func(*mock_usecase.MockUsecaseUser)
+	}
+
+	tests := []test{
+		{
+			name:        "RequestParamError",
+			reqParam:    "X",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestParamValidate("ID invalid"),
+			mockOn: func(mockUsecaseUser *mock_usecase.MockUsecaseUser) {
+			},
+		},
+		{
+			name:        "NotFoundError",
+			reqParam:    "71",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusNotFound,
+			wantResBody: model.NotFound("User"),
+			mockOn: func(mockUsecaseUser *mock_usecase.MockUsecaseUser) {
+				mockUsecaseUser.On("GetDetailsByUserID").Return(nil, repository.ErrNotFound{Message: "not found"})
+			},
+		},
+		{
+			name:        "InternalServerError",
+			reqParam:    "70",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusInternalServerError,
+			wantResBody: model.InternalServerErrorRepositoryLoad("User"),
+			mockOn: func(mockUsecaseUser *mock_usecase.MockUsecaseUser) {
+				mockUsecaseUser.On("GetDetailsByUserID").Return(nil, errors.New("InternalServerError"))
+			},
+		},
+		{
+			name:        "Success",
+			reqParam:    "70",
+			resBody:     &model.OrderDetails{},
+			wantResCode: http.StatusOK,
+			wantResBody: &modelOrderDetails,
+			mockOn: func(mockUsecaseUser *mock_usecase.MockUsecaseUser) {
+				mockUsecaseUser.On("GetDetailsByUserID").Return(&modelOrderDetails, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := hclog.New(&hclog.LoggerOptions{Level: hclog.LevelFromString("OFF")})
+			mockUsecaseUser := new(mock_usecase.MockUsecaseUser)
+
+			tt.mockOn(mockUsecaseUser)
+
+			controllerUser := NewUser(log, mockUsecaseUser)
+
+			url := fmt.Sprintf("/api/user/%v", tt.reqParam)
+
+			req, _ := http.NewRequest(http.MethodGet, url, nil)
+			handler := http.HandlerFunc(controllerUser.GetDetailsByUserID)
+			res := httptest.NewRecorder()
+
+			handler.ServeHTTP(res, req)
+
+			if !reflect.DeepEqual(res.Code, tt.wantResCode) {
+				t.Errorf("GetDetailsByUserID() got res.code = %v, want %v", res.Code, tt.wantResCode)
+			}
+
+			json.NewDecoder(res.Body).Decode(tt.resBody)
+
+			if !reflect.DeepEqual(tt.resBody, tt.wantResBody) {
+				t.Errorf("GetDetailsByUserID() got res.body = %v, want %v", tt.resBody, tt.wantResBody)
+			}
+		})
+	}
+}
+
+func TestUserListSummaries(t *testing.T) {
+	modelUserSummaries := model.UserSummaries{
+		{
+			UserID:       70,
+			UserName:     "Palmer Prosacco",
+			Orders:       1,
+			FirstBuyDate: "2021-03-08",
+			LastBuyDate:  "2021-03-08",
+			Total:        183674,
+		},
+	}
+
+	modelUserSummariesPage := model.UserSummariesPage{UserSummaries: modelUserSummaries}
+
+	modelUserSummariesPageNext := model.UserSummariesPage{
+		UserSummaries: modelUserSummaries,
+		Next:          &model.UserCursor{UserID: 70},
+	}
+
+	type test struct {
+		name        string
+		reqParam    string
+		resBody     interface{}
+		wantResCode int
+		wantResBody interface{}
+		wantResLink string
+		mockOn      func(*mock_usecase.MockUsecaseUser)
+	}
+
+	tests := []test{
+		{
+			name:        "ParamLimitInvalidError",
+			reqParam:    "?limit=X&cursor=X",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestParamValidate(usecase.OrderPageErrorMessageLimitInvalid + ";" + usecase.OrderPageErrorMessageCursorInvalid),
+			mockOn: func(mockUsecaseUser *mock_usecase.MockUsecaseUser) {
+			},
+		},
+		{
+			name:        "ParamValidateError",
+			reqParam:    "?limit=1001",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusBadRequest,
+			wantResBody: model.BadRequestParamValidate(fmt.Sprintf(usecase.OrderPageErrorMessageLimitBetween, 1000)),
+			mockOn: func(mockUsecaseUser *mock_usecase.MockUsecaseUser) {
+				mockUsecaseUser.On("ListSummaries").Return(nil, usecase.ErrParamValidate{Message: fmt.Sprintf(usecase.OrderPageErrorMessageLimitBetween, 1000)})
+			},
+		},
+		{
+			name:        "NotFoundError",
+			reqParam:    "?name=X",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusNotFound,
+			wantResBody: model.NotFound("User"),
+			mockOn: func(mockUsecaseUser *mock_usecase.MockUsecaseUser) {
+				mockUsecaseUser.On("ListSummaries").Return(nil, repository.ErrNotFound{Message: "not found"})
+			},
+		},
+		{
+			name:        "InternalServerError",
+			reqParam:    "",
+			resBody:     &model.Error{},
+			wantResCode: http.StatusInternalServerError,
+			wantResBody: model.InternalServerErrorRepositoryLoad("User"),
+			mockOn: func(mockUsecaseUser *mock_usecase.MockUsecaseUser) {
+				mockUsecaseUser.On("ListSummaries").Return(nil, errors.New("InternalServerError"))
+			},
+		},
+		{
+			name:        "AllSuccess",
+			reqParam:    "",
+			resBody:     &model.UserSummaries{},
+			wantResCode: http.StatusOK,
+			wantResBody: &modelUserSummaries,
+			mockOn: func(mockUsecaseUser *mock_usecase.MockUsecaseUser) {
+				mockUsecaseUser.On("ListSummaries").Return(&modelUserSummariesPage, nil)
+			},
+		},
+		{
+			name:        "NextPageSuccess",
+			reqParam:    "?limit=1&name=prosacco",
+			resBody:     &model.UserSummaries{},
+			wantResCode: http.StatusOK,
+			wantResBody: &modelUserSummaries,
+			wantResLink: `</api/user?cursor=dTo3MA&limit=1&name=prosacco>; rel="next"`,
+			mockOn: func(mockUsecaseUser *mock_usecase.MockUsecaseUser) {
+				mockUsecaseUser.On("ListSummaries").Return(&modelUserSummariesPageNext, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := hclog.New(&hclog.LoggerOptions{Level: hclog.LevelFromString("OFF")})
+			mockUsecaseUser := new(mock_usecase.MockUsecaseUser)
+
+			tt.mockOn(mockUsecaseUser)
+
+			controllerUser := NewUser(log, mockUsecaseUser)
+
+			url := fmt.Sprintf("/api/user%v", tt.reqParam)
+
+			req, _ := http.NewRequest(http.MethodGet, url, nil)
+			handler := http.HandlerFunc(controllerUser.ListSummaries)
+			res := httptest.NewRecorder()
+
+			handler.ServeHTTP(res, req)
+
+			if !reflect.DeepEqual(res.Code, tt.wantResCode) {
+				t.Errorf("ListSummaries() got res.code = %v, want %v", res.Code, tt.wantResCode)
+			}
+
+			if res.Header().Get("Link") != tt.wantResLink {
+				t.Errorf("ListSummaries() got res.link = %v, want %v", res.Header().Get("Link"), tt.wantResLink)
+			}
+
+			json.NewDecoder(res.Body).Decode(tt.resBody)
+
+			if !reflect.DeepEqual(tt.resBody, tt.wantResBody) {
+				t.Errorf("ListSummaries() got res.body = %v, want %v", tt.resBody, tt.wantResBody)
+			}
+		})
+	}
+}
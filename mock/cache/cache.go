@@ -14,6 +14,16 @@ func (mockCache *MockCache) Order() cache.Order {
 	return args.Get(0).(cache.Order)
 }
 
+func (mockCache *MockCache) User() cache.User {
+	args := mockCache.Called()
+	return args.Get(0).(cache.User)
+}
+
+func (mockCache *MockCache) Report() cache.Report {
+	args := mockCache.Called()
+	return args.Get(0).(cache.Report)
+}
+
 func (mockCache *MockCache) Check() error {
 	args := mockCache.Called()
 
@@ -0,0 +1,82 @@
+package mock_cache
+
+import (
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCacheReport struct {
+	mock.Mock
+}
+
+func (mockCacheReport *MockCacheReport) SetRevenue(version string, modelReportFilter *model.ReportFilter, modelReportsRevenue *model.ReportsRevenue) error {
+	args := mockCacheReport.Called()
+
+	return args.Error(0)
+}
+
+func (mockCacheReport *MockCacheReport) GetRevenue(version string, modelReportFilter *model.ReportFilter) (*model.ReportsRevenue, error) {
+	args := mockCacheReport.Called()
+
+	var modelReportsRevenue *model.ReportsRevenue
+
+	if args.Get(0) != nil {
+		modelReportsRevenue = args.Get(0).(*model.ReportsRevenue)
+	}
+
+	return modelReportsRevenue, args.Error(1)
+}
+
+func (mockCacheReport *MockCacheReport) SetTopUsers(version string, modelReportFilter *model.ReportFilter, modelReportTopUsers *model.ReportTopUsers) error {
+	args := mockCacheReport.Called()
+
+	return args.Error(0)
+}
+
+func (mockCacheReport *MockCacheReport) GetTopUsers(version string, modelReportFilter *model.ReportFilter) (*model.ReportTopUsers, error) {
+	args := mockCacheReport.Called()
+
+	var modelReportTopUsers *model.ReportTopUsers
+
+	if args.Get(0) != nil {
+		modelReportTopUsers = args.Get(0).(*model.ReportTopUsers)
+	}
+
+	return modelReportTopUsers, args.Error(1)
+}
+
+func (mockCacheReport *MockCacheReport) SetTopProducts(version string, modelReportFilter *model.ReportFilter, modelReportTopProducts *model.ReportTopProducts) error {
+	args := mockCacheReport.Called()
+
+	return args.Error(0)
+}
+
+func (mockCacheReport *MockCacheReport) GetTopProducts(version string, modelReportFilter *model.ReportFilter) (*model.ReportTopProducts, error) {
+	args := mockCacheReport.Called()
+
+	var modelReportTopProducts *model.ReportTopProducts
+
+	if args.Get(0) != nil {
+		modelReportTopProducts = args.Get(0).(*model.ReportTopProducts)
+	}
+
+	return modelReportTopProducts, args.Error(1)
+}
+
+func (mockCacheReport *MockCacheReport) SetBasket(version string, modelReportFilter *model.ReportFilter, modelReportBasket *model.ReportBasket) error {
+	args := mockCacheReport.Called()
+
+	return args.Error(0)
+}
+
+func (mockCacheReport *MockCacheReport) GetBasket(version string, modelReportFilter *model.ReportFilter) (*model.ReportBasket, error) {
+	args := mockCacheReport.Called()
+
+	var modelReportBasket *model.ReportBasket
+
+	if args.Get(0) != nil {
+		modelReportBasket = args.Get(0).(*model.ReportBasket)
+	}
+
+	return modelReportBasket, args.Error(1)
+}
@@ -0,0 +1,28 @@
+package mock_cache
+
+import (
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCacheUser struct {
+	mock.Mock
+}
+
+func (mockCacheUser *MockCacheUser) SetDetailsByUserID(version string, modelOrderDetails *model.OrderDetails) error {
+	args := mockCacheUser.Called()
+
+	return args.Error(0)
+}
+
+func (mockCacheUser *MockCacheUser) GetDetailsByUserID(version string, userID int64) (*model.OrderDetails, error) {
+	args := mockCacheUser.Called()
+
+	var modelOrderDetails *model.OrderDetails
+
+	if args.Get(0) != nil {
+		modelOrderDetails = args.Get(0).(*model.OrderDetails)
+	}
+
+	return modelOrderDetails, args.Error(1)
+}
@@ -2,6 +2,7 @@ package mock_repository
 
 import (
 	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -9,12 +10,24 @@ type MockRepositoryOrder struct {
 	mock.Mock
 }
 
-func (mockRepositoryOrder *MockRepositoryOrder) LegacyBulkInsert(modelUsers *model.Users, modelOrders *model.Orders, modelOrdersProducts *model.OrdersProducts) error {
+func (mockRepositoryOrder *MockRepositoryOrder) LegacyBulkInsert(modelLegacyImport *model.LegacyImport, legacyDataset repository.LegacyDataset) error {
 	args := mockRepositoryOrder.Called()
 
 	return args.Error(0)
 }
 
+func (mockRepositoryOrder *MockRepositoryOrder) LegacyBulkUpsert(modelLegacyImport *model.LegacyImport, legacyDataset repository.LegacyDataset) ([]int64, error) {
+	args := mockRepositoryOrder.Called()
+
+	var orderIDs []int64
+
+	if args.Get(0) != nil {
+		orderIDs = args.Get(0).([]int64)
+	}
+
+	return orderIDs, args.Error(1)
+}
+
 func (mockRepositoryOrder *MockRepositoryOrder) GetDetailsByOrderID(orderID int64) (*model.OrderDetails, error) {
 	args := mockRepositoryOrder.Called()
 
@@ -27,26 +40,80 @@ func (mockRepositoryOrder *MockRepositoryOrder) GetDetailsByOrderID(orderID int6
 	return modelOrderDetails, args.Error(1)
 }
 
-func (mockRepositoryOrder *MockRepositoryOrder) ListDetailsByRangeBuyDate(modelOrderRangeBuyDate *model.OrderRangeBuyDate) (*model.OrdersDetails, error) {
+func (mockRepositoryOrder *MockRepositoryOrder) ListDetails(modelOrderFilter *model.OrderFilter, modelOrderPage *model.OrderPage) (*model.OrdersDetailsPage, error) {
 	args := mockRepositoryOrder.Called()
 
-	var modelOrdersDetails *model.OrdersDetails
+	var modelOrdersDetailsPage *model.OrdersDetailsPage
 
 	if args.Get(0) != nil {
-		modelOrdersDetails = args.Get(0).(*model.OrdersDetails)
+		modelOrdersDetailsPage = args.Get(0).(*model.OrdersDetailsPage)
 	}
 
-	return modelOrdersDetails, args.Error(1)
+	return modelOrdersDetailsPage, args.Error(1)
+}
+
+func (mockRepositoryOrder *MockRepositoryOrder) ListLegacyRejects() (*model.LegacyRejects, error) {
+	args := mockRepositoryOrder.Called()
+
+	var modelLegacyRejects *model.LegacyRejects
+
+	if args.Get(0) != nil {
+		modelLegacyRejects = args.Get(0).(*model.LegacyRejects)
+	}
+
+	return modelLegacyRejects, args.Error(1)
+}
+
+func (mockRepositoryOrder *MockRepositoryOrder) ListLegacyImports() (*model.LegacyImports, error) {
+	args := mockRepositoryOrder.Called()
+
+	var modelLegacyImports *model.LegacyImports
+
+	if args.Get(0) != nil {
+		modelLegacyImports = args.Get(0).(*model.LegacyImports)
+	}
+
+	return modelLegacyImports, args.Error(1)
+}
+
+func (mockRepositoryOrder *MockRepositoryOrder) GetLegacyImportLive() (*model.LegacyImport, error) {
+	args := mockRepositoryOrder.Called()
+
+	var modelLegacyImport *model.LegacyImport
+
+	if args.Get(0) != nil {
+		modelLegacyImport = args.Get(0).(*model.LegacyImport)
+	}
+
+	return modelLegacyImport, args.Error(1)
+}
+
+func (mockRepositoryOrder *MockRepositoryOrder) LegacyImportRestore(importID int64) (*model.LegacyImport, error) {
+	args := mockRepositoryOrder.Called()
+
+	var modelLegacyImport *model.LegacyImport
+
+	if args.Get(0) != nil {
+		modelLegacyImport = args.Get(0).(*model.LegacyImport)
+	}
+
+	return modelLegacyImport, args.Error(1)
+}
+
+func (mockRepositoryOrder *MockRepositoryOrder) LegacyImportsPrune(size int) error {
+	args := mockRepositoryOrder.Called()
+
+	return args.Error(0)
 }
 
-func (mockRepositoryOrder *MockRepositoryOrder) ListDetails() (*model.OrdersDetails, error) {
+func (mockRepositoryOrder *MockRepositoryOrder) LegacyStagePromote() (*model.LegacyImport, error) {
 	args := mockRepositoryOrder.Called()
 
-	var modelOrdersDetails *model.OrdersDetails
+	var modelLegacyImport *model.LegacyImport
 
 	if args.Get(0) != nil {
-		modelOrdersDetails = args.Get(0).(*model.OrdersDetails)
+		modelLegacyImport = args.Get(0).(*model.LegacyImport)
 	}
 
-	return modelOrdersDetails, args.Error(1)
+	return modelLegacyImport, args.Error(1)
 }
@@ -0,0 +1,22 @@
+package mock_repository
+
+import (
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRepositoryProduct struct {
+	mock.Mock
+}
+
+func (mockRepositoryProduct *MockRepositoryProduct) GetSummaryByProductID(productID int64) (*model.ProductSummary, error) {
+	args := mockRepositoryProduct.Called()
+
+	var modelProductSummary *model.ProductSummary
+
+	if args.Get(0) != nil {
+		modelProductSummary = args.Get(0).(*model.ProductSummary)
+	}
+
+	return modelProductSummary, args.Error(1)
+}
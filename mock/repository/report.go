@@ -0,0 +1,58 @@
+package mock_repository
+
+import (
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRepositoryReport struct {
+	mock.Mock
+}
+
+func (mockRepositoryReport *MockRepositoryReport) ListRevenue(modelReportFilter *model.ReportFilter) (*model.ReportsRevenue, error) {
+	args := mockRepositoryReport.Called()
+
+	var modelReportsRevenue *model.ReportsRevenue
+
+	if args.Get(0) != nil {
+		modelReportsRevenue = args.Get(0).(*model.ReportsRevenue)
+	}
+
+	return modelReportsRevenue, args.Error(1)
+}
+
+func (mockRepositoryReport *MockRepositoryReport) ListTopUsers(modelReportFilter *model.ReportFilter) (*model.ReportTopUsers, error) {
+	args := mockRepositoryReport.Called()
+
+	var modelReportTopUsers *model.ReportTopUsers
+
+	if args.Get(0) != nil {
+		modelReportTopUsers = args.Get(0).(*model.ReportTopUsers)
+	}
+
+	return modelReportTopUsers, args.Error(1)
+}
+
+func (mockRepositoryReport *MockRepositoryReport) ListTopProducts(modelReportFilter *model.ReportFilter) (*model.ReportTopProducts, error) {
+	args := mockRepositoryReport.Called()
+
+	var modelReportTopProducts *model.ReportTopProducts
+
+	if args.Get(0) != nil {
+		modelReportTopProducts = args.Get(0).(*model.ReportTopProducts)
+	}
+
+	return modelReportTopProducts, args.Error(1)
+}
+
+func (mockRepositoryReport *MockRepositoryReport) GetBasket(modelReportFilter *model.ReportFilter) (*model.ReportBasket, error) {
+	args := mockRepositoryReport.Called()
+
+	var modelReportBasket *model.ReportBasket
+
+	if args.Get(0) != nil {
+		modelReportBasket = args.Get(0).(*model.ReportBasket)
+	}
+
+	return modelReportBasket, args.Error(1)
+}
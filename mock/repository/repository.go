@@ -14,6 +14,26 @@ func (mockRepository *MockRepository) Order() repository.Order {
 	return args.Get(0).(repository.Order)
 }
 
+func (mockRepository *MockRepository) User() repository.User {
+	args := mockRepository.Called()
+	return args.Get(0).(repository.User)
+}
+
+func (mockRepository *MockRepository) Product() repository.Product {
+	args := mockRepository.Called()
+	return args.Get(0).(repository.Product)
+}
+
+func (mockRepository *MockRepository) Report() repository.Report {
+	args := mockRepository.Called()
+	return args.Get(0).(repository.Report)
+}
+
+func (mockRepository *MockRepository) Staging() repository.Repository {
+	args := mockRepository.Called()
+	return args.Get(0).(repository.Repository)
+}
+
 func (mockRepository *MockRepository) Check() error {
 	args := mockRepository.Called()
 
@@ -0,0 +1,34 @@
+package mock_repository
+
+import (
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRepositoryUser struct {
+	mock.Mock
+}
+
+func (mockRepositoryUser *MockRepositoryUser) GetDetailsByUserID(userID int64) (*model.OrderDetails, error) {
+	args := mockRepositoryUser.Called()
+
+	var modelOrderDetails *model.OrderDetails
+
+	if args.Get(0) != nil {
+		modelOrderDetails = args.Get(0).(*model.OrderDetails)
+	}
+
+	return modelOrderDetails, args.Error(1)
+}
+
+func (mockRepositoryUser *MockRepositoryUser) ListSummaries(modelUserFilter *model.UserFilter, modelUserPage *model.UserPage) (*model.UserSummariesPage, error) {
+	args := mockRepositoryUser.Called()
+
+	var modelUserSummariesPage *model.UserSummariesPage
+
+	if args.Get(0) != nil {
+		modelUserSummariesPage = args.Get(0).(*model.UserSummariesPage)
+	}
+
+	return modelUserSummariesPage, args.Error(1)
+}
@@ -23,7 +23,7 @@ func (mockUsecaseOrder *MockUsecaseOrder) GetDetailsByOrderID(orderID int64) (*m
 	return modelOrderDetails, args.Error(1)
 }
 
-func (mockUsecaseOrder *MockUsecaseOrder) LegacyImport(file io.Reader, hasHeader bool) (*model.LegacyImportResult, error) {
+func (mockUsecaseOrder *MockUsecaseOrder) LegacyImport(file io.Reader, modelLegacyImportOptions *model.LegacyImportOptions) (*model.LegacyImportResult, error) {
 	args := mockUsecaseOrder.Called()
 
 	var modelLegacyImportResult *model.LegacyImportResult
@@ -35,26 +35,176 @@ func (mockUsecaseOrder *MockUsecaseOrder) LegacyImport(file io.Reader, hasHeader
 	return modelLegacyImportResult, args.Error(1)
 }
 
-func (mockUsecaseOrder *MockUsecaseOrder) ListDetailsByRangeBuyDate(modelOrderRangeBuyDate *model.OrderRangeBuyDate) (*model.OrdersDetails, error) {
+func (mockUsecaseOrder *MockUsecaseOrder) ListDetails(modelOrderFilter *model.OrderFilter, modelOrderPage *model.OrderPage) (*model.OrdersDetailsPage, error) {
 	args := mockUsecaseOrder.Called()
 
-	var modelOrdersDetails *model.OrdersDetails
+	var modelOrdersDetailsPage *model.OrdersDetailsPage
 
 	if args.Get(0) != nil {
-		modelOrdersDetails = args.Get(0).(*model.OrdersDetails)
+		modelOrdersDetailsPage = args.Get(0).(*model.OrdersDetailsPage)
 	}
 
-	return modelOrdersDetails, args.Error(1)
+	return modelOrdersDetailsPage, args.Error(1)
 }
 
-func (mockUsecaseOrder *MockUsecaseOrder) ListDetails() (*model.OrdersDetails, error) {
+func (mockUsecaseOrder *MockUsecaseOrder) LegacyImportAsync(file io.Reader, modelLegacyImportOptions *model.LegacyImportOptions) (*model.LegacyImportJob, error) {
 	args := mockUsecaseOrder.Called()
 
-	var modelOrdersDetails *model.OrdersDetails
+	var modelLegacyImportJob *model.LegacyImportJob
 
 	if args.Get(0) != nil {
-		modelOrdersDetails = args.Get(0).(*model.OrdersDetails)
+		modelLegacyImportJob = args.Get(0).(*model.LegacyImportJob)
 	}
 
-	return modelOrdersDetails, args.Error(1)
+	return modelLegacyImportJob, args.Error(1)
+}
+
+func (mockUsecaseOrder *MockUsecaseOrder) GetLegacyImportJob(jobID string) (*model.LegacyImportJob, error) {
+	args := mockUsecaseOrder.Called()
+
+	var modelLegacyImportJob *model.LegacyImportJob
+
+	if args.Get(0) != nil {
+		modelLegacyImportJob = args.Get(0).(*model.LegacyImportJob)
+	}
+
+	return modelLegacyImportJob, args.Error(1)
+}
+
+func (mockUsecaseOrder *MockUsecaseOrder) CancelLegacyImportJob(jobID string) (*model.LegacyImportJob, error) {
+	args := mockUsecaseOrder.Called()
+
+	var modelLegacyImportJob *model.LegacyImportJob
+
+	if args.Get(0) != nil {
+		modelLegacyImportJob = args.Get(0).(*model.LegacyImportJob)
+	}
+
+	return modelLegacyImportJob, args.Error(1)
+}
+
+func (mockUsecaseOrder *MockUsecaseOrder) ListLegacyRejects() (*model.LegacyRejects, error) {
+	args := mockUsecaseOrder.Called()
+
+	var modelLegacyRejects *model.LegacyRejects
+
+	if args.Get(0) != nil {
+		modelLegacyRejects = args.Get(0).(*model.LegacyRejects)
+	}
+
+	return modelLegacyRejects, args.Error(1)
+}
+
+func (mockUsecaseOrder *MockUsecaseOrder) LegacyValidate(file io.Reader, modelLegacyImportOptions *model.LegacyImportOptions) (*model.LegacyValidateResult, error) {
+	args := mockUsecaseOrder.Called()
+
+	var modelLegacyValidateResult *model.LegacyValidateResult
+
+	if args.Get(0) != nil {
+		modelLegacyValidateResult = args.Get(0).(*model.LegacyValidateResult)
+	}
+
+	return modelLegacyValidateResult, args.Error(1)
+}
+
+func (mockUsecaseOrder *MockUsecaseOrder) ListLegacyImports() (*model.LegacyImports, error) {
+	args := mockUsecaseOrder.Called()
+
+	var modelLegacyImports *model.LegacyImports
+
+	if args.Get(0) != nil {
+		modelLegacyImports = args.Get(0).(*model.LegacyImports)
+	}
+
+	return modelLegacyImports, args.Error(1)
+}
+
+func (mockUsecaseOrder *MockUsecaseOrder) LegacyImportRestore(importID int64) (*model.LegacyImport, error) {
+	args := mockUsecaseOrder.Called()
+
+	var modelLegacyImport *model.LegacyImport
+
+	if args.Get(0) != nil {
+		modelLegacyImport = args.Get(0).(*model.LegacyImport)
+	}
+
+	return modelLegacyImport, args.Error(1)
+}
+
+func (mockUsecaseOrder *MockUsecaseOrder) CreateLegacyUpload(modelLegacyUpload *model.LegacyUpload) (*model.LegacyUpload, error) {
+	args := mockUsecaseOrder.Called()
+
+	var modelLegacyUploadCreated *model.LegacyUpload
+
+	if args.Get(0) != nil {
+		modelLegacyUploadCreated = args.Get(0).(*model.LegacyUpload)
+	}
+
+	return modelLegacyUploadCreated, args.Error(1)
+}
+
+func (mockUsecaseOrder *MockUsecaseOrder) GetLegacyUpload(uploadID string) (*model.LegacyUpload, error) {
+	args := mockUsecaseOrder.Called()
+
+	var modelLegacyUpload *model.LegacyUpload
+
+	if args.Get(0) != nil {
+		modelLegacyUpload = args.Get(0).(*model.LegacyUpload)
+	}
+
+	return modelLegacyUpload, args.Error(1)
+}
+
+func (mockUsecaseOrder *MockUsecaseOrder) LegacyUploadChunk(uploadID string, offset int64, chunk io.Reader) (*model.LegacyUpload, error) {
+	args := mockUsecaseOrder.Called()
+
+	var modelLegacyUpload *model.LegacyUpload
+
+	if args.Get(0) != nil {
+		modelLegacyUpload = args.Get(0).(*model.LegacyUpload)
+	}
+
+	return modelLegacyUpload, args.Error(1)
+}
+
+func (mockUsecaseOrder *MockUsecaseOrder) DeleteLegacyUpload(uploadID string) error {
+	args := mockUsecaseOrder.Called()
+
+	return args.Error(0)
+}
+
+func (mockUsecaseOrder *MockUsecaseOrder) LegacyUploadImport(uploadID string, modelLegacyImportOptions *model.LegacyImportOptions) (*model.LegacyImportResult, error) {
+	args := mockUsecaseOrder.Called()
+
+	var modelLegacyImportResult *model.LegacyImportResult
+
+	if args.Get(0) != nil {
+		modelLegacyImportResult = args.Get(0).(*model.LegacyImportResult)
+	}
+
+	return modelLegacyImportResult, args.Error(1)
+}
+
+func (mockUsecaseOrder *MockUsecaseOrder) LegacyUploadImportAsync(uploadID string, modelLegacyImportOptions *model.LegacyImportOptions) (*model.LegacyImportJob, error) {
+	args := mockUsecaseOrder.Called()
+
+	var modelLegacyImportJob *model.LegacyImportJob
+
+	if args.Get(0) != nil {
+		modelLegacyImportJob = args.Get(0).(*model.LegacyImportJob)
+	}
+
+	return modelLegacyImportJob, args.Error(1)
+}
+
+func (mockUsecaseOrder *MockUsecaseOrder) LegacyStagePromote() (*model.LegacyImport, error) {
+	args := mockUsecaseOrder.Called()
+
+	var modelLegacyImport *model.LegacyImport
+
+	if args.Get(0) != nil {
+		modelLegacyImport = args.Get(0).(*model.LegacyImport)
+	}
+
+	return modelLegacyImport, args.Error(1)
 }
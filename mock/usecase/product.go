@@ -0,0 +1,34 @@
+package mock_usecase
+
+import (
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUsecaseProduct struct {
+	mock.Mock
+}
+
+func (mockUsecaseProduct *MockUsecaseProduct) GetSummaryByProductID(productID int64) (*model.ProductSummary, error) {
+	args := mockUsecaseProduct.Called()
+
+	var modelProductSummary *model.ProductSummary
+
+	if args.Get(0) != nil {
+		modelProductSummary = args.Get(0).(*model.ProductSummary)
+	}
+
+	return modelProductSummary, args.Error(1)
+}
+
+func (mockUsecaseProduct *MockUsecaseProduct) ListOrdersDetails(productID int64, modelOrderPage *model.OrderPage) (*model.OrdersDetailsPage, error) {
+	args := mockUsecaseProduct.Called()
+
+	var modelOrdersDetailsPage *model.OrdersDetailsPage
+
+	if args.Get(0) != nil {
+		modelOrdersDetailsPage = args.Get(0).(*model.OrdersDetailsPage)
+	}
+
+	return modelOrdersDetailsPage, args.Error(1)
+}
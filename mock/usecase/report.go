@@ -0,0 +1,58 @@
+package mock_usecase
+
+import (
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUsecaseReport struct {
+	mock.Mock
+}
+
+func (mockUsecaseReport *MockUsecaseReport) ListRevenue(modelReportFilter *model.ReportFilter) (*model.ReportsRevenue, error) {
+	args := mockUsecaseReport.Called()
+
+	var modelReportsRevenue *model.ReportsRevenue
+
+	if args.Get(0) != nil {
+		modelReportsRevenue = args.Get(0).(*model.ReportsRevenue)
+	}
+
+	return modelReportsRevenue, args.Error(1)
+}
+
+func (mockUsecaseReport *MockUsecaseReport) ListTopUsers(modelReportFilter *model.ReportFilter) (*model.ReportTopUsers, error) {
+	args := mockUsecaseReport.Called()
+
+	var modelReportTopUsers *model.ReportTopUsers
+
+	if args.Get(0) != nil {
+		modelReportTopUsers = args.Get(0).(*model.ReportTopUsers)
+	}
+
+	return modelReportTopUsers, args.Error(1)
+}
+
+func (mockUsecaseReport *MockUsecaseReport) ListTopProducts(modelReportFilter *model.ReportFilter) (*model.ReportTopProducts, error) {
+	args := mockUsecaseReport.Called()
+
+	var modelReportTopProducts *model.ReportTopProducts
+
+	if args.Get(0) != nil {
+		modelReportTopProducts = args.Get(0).(*model.ReportTopProducts)
+	}
+
+	return modelReportTopProducts, args.Error(1)
+}
+
+func (mockUsecaseReport *MockUsecaseReport) GetBasket(modelReportFilter *model.ReportFilter) (*model.ReportBasket, error) {
+	args := mockUsecaseReport.Called()
+
+	var modelReportBasket *model.ReportBasket
+
+	if args.Get(0) != nil {
+		modelReportBasket = args.Get(0).(*model.ReportBasket)
+	}
+
+	return modelReportBasket, args.Error(1)
+}
@@ -58,10 +58,35 @@ func BadRequestFileType() *Error {
 	}
 }
 
-func BadRequestFileRecordValidate(message string) *Error {
+// ErrorRecordsValidate is the error of the validation of the records of a
+// file, with the errors of each field instead of a message
+type ErrorRecordsValidate struct {
+	// Código do Erro
+	Code float32 `json:"code" validate:"required" example:"400.8" format:"float"`
+	// Descrição do Erro
+	Message string `json:"message" validate:"required" example:"Error validating the file records"`
+	// Erros dos campos dos registros, limitados pela quantidade máxima de erros
+	Errors []LegacyRecordFieldError `json:"errors" validate:"required"`
+	// Quantidade de erros dos campos encontrados no arquivo
+	Total int `json:"total" validate:"required" example:"1"`
+	// Indica que os erros foram limitados
+	Truncated bool `json:"truncated" validate:"required"`
+}
+
+func BadRequestFileRecordValidate(message string, modelLegacyRecordsError LegacyRecordsError, total int, truncated bool) *ErrorRecordsValidate {
+	return &ErrorRecordsValidate{
+		Code:      400.8,
+		Message:   message,
+		Errors:    modelLegacyRecordsError.Fields(),
+		Total:     total,
+		Truncated: truncated,
+	}
+}
+
+func BadRequestFileValidate(message string) *Error {
 	return &Error{
-		Code:    400.8,
-		Message: message,
+		Code:    400.9,
+		Message: fmt.Sprintf("Error validating file: %s", message),
 	}
 }
 
@@ -72,6 +97,13 @@ func NotFound(controllerTitle string) *Error {
 	}
 }
 
+func Conflict(message string) *Error {
+	return &Error{
+		Code:    409.1,
+		Message: message,
+	}
+}
+
 func InternalServerErrorGeneral(message string) *Error {
 	return &Error{
 		Code:    500.1,
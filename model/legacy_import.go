@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+type LegacyImport struct {
+	// ID da Importação
+	ID int64 `json:"id" validate:"required" example:"1"`
+	// Data da Importação
+	ImportedAt time.Time `json:"imported_at" validate:"required"`
+	// Nome do arquivo importado
+	FileName string `json:"file_name" validate:"required" example:"data_1.txt"`
+	// SHA-256 do arquivo importado
+	Checksum string `json:"checksum" validate:"required" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	// Modo da Importação
+	Mode string `json:"mode" validate:"required" example:"replace" enums:"replace,merge"`
+	// Resumo da Importação
+	Result LegacyImportResult `json:"result" validate:"required"`
+	// Solicitante da Importação
+	RequestedBy string `json:"requested_by" validate:"required" example:"192.168.0.1"`
+	// Chave de idempotência informada na Importação
+	IdempotencyKey string `json:"idempotency_key,omitempty" example:"2023-06-11-data_1"`
+	// Indica se os pedidos da Importação são os pedidos disponíveis na API
+	Live bool `json:"live" validate:"required"`
+	// Indica se os registros da Importação foram mantidos no histórico para serem restaurados
+	Restorable bool `json:"restorable" validate:"required"`
+}
+
+type LegacyImports []LegacyImport
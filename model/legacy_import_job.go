@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+const (
+	LegacyImportJobStatePending    = "pending"
+	LegacyImportJobStateRunning    = "running"
+	LegacyImportJobStatePersisting = "persisting"
+	LegacyImportJobStateSucceeded  = "succeeded"
+	LegacyImportJobStateFailed     = "failed"
+	LegacyImportJobStateCanceled   = "canceled"
+)
+
+type LegacyImportJob struct {
+	// ID do Job
+	ID string `json:"id" validate:"required" example:"0b4f7d6c-3a57-4d1c-9d0e-5c9d1a2b3c4d"`
+	// Situação do Job
+	State string `json:"state" validate:"required" example:"running" enums:"pending,running,persisting,succeeded,failed,canceled"`
+	// Quantidade de linhas lidas do arquivo
+	LinesParsed int64 `json:"lines_parsed" validate:"required" example:"1000"`
+	// Quantidade de linhas gravadas no repositório
+	LinesPersisted int64 `json:"lines_persisted" validate:"required" example:"0"`
+	// Resumo da importação quando finalizada com sucesso
+	Result *LegacyImportResult `json:"result,omitempty"`
+	// Registros com erro quando a validação do arquivo falhar, limitados pela quantidade máxima de erros
+	Errors LegacyRecordsError `json:"errors,omitempty"`
+	// Quantidade de erros dos campos encontrados no arquivo
+	ErrorsTotal int `json:"errors_total,omitempty" example:"0"`
+	// Indica que os registros com erro foram limitados
+	ErrorsTruncated bool `json:"errors_truncated,omitempty"`
+	// Descrição do erro quando a importação falhar
+	Message string `json:"message,omitempty"`
+	// Data de criação do Job
+	CreatedAt time.Time `json:"created_at" validate:"required"`
+	// Data de finalização do Job
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+func (modelLegacyImportJob *LegacyImportJob) Finished() bool {
+	return modelLegacyImportJob.State == LegacyImportJobStateSucceeded ||
+		modelLegacyImportJob.State == LegacyImportJobStateFailed ||
+		modelLegacyImportJob.State == LegacyImportJobStateCanceled
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// LegacyInboxReport is written next to a file of the inbox moved to the
+// failed directory
+type LegacyInboxReport struct {
+	// Nome do arquivo
+	File string `json:"file" example:"data_1.txt"`
+	// Data e hora da falha da importação
+	FailedAt time.Time `json:"failed_at"`
+	// Descrição do erro
+	Message string `json:"message" example:"Error validating the file records"`
+	// Primeiros registros com erro quando o arquivo possui registros inválidos
+	Errors LegacyRecordsError `json:"errors,omitempty"`
+	// Quantidade de erros dos campos encontrados no arquivo
+	ErrorsTotal int `json:"errors_total,omitempty" example:"1"`
+	// Indica que os erros foram limitados
+	ErrorsTruncated bool `json:"errors_truncated,omitempty" example:"false"`
+}
@@ -0,0 +1,110 @@
+package model
+
+const LegacyLayoutNameDefault = "default"
+
+const (
+	LegacyFieldUserID       = "user_id"
+	LegacyFieldUserName     = "user_name"
+	LegacyFieldOrderID      = "order_id"
+	LegacyFieldProductID    = "product_id"
+	LegacyFieldProductValue = "product_value"
+	LegacyFieldBuyDate      = "buy_date"
+)
+
+// fields of the header and trailer records
+const (
+	LegacyFieldGenerationDate = "generation_date"
+	LegacyFieldSource         = "source"
+	LegacyFieldRecordCount    = "record_count"
+	LegacyFieldControlTotal   = "control_total"
+)
+
+const (
+	LegacyFieldTypeInt     = "int"
+	LegacyFieldTypeString  = "string"
+	LegacyFieldTypeDecimal = "decimal"
+	LegacyFieldTypeDate    = "date"
+)
+
+const (
+	LegacyFieldTrimNone  = ""
+	LegacyFieldTrimLeft  = "left"
+	LegacyFieldTrimRight = "right"
+	LegacyFieldTrimBoth  = "both"
+)
+
+// LegacyLayoutField describes the position of a field in the fixed-width record
+type LegacyLayoutField struct {
+	// one of the LegacyField constants
+	Name string `json:"name"`
+	// position of the first character of the field, starting at 1
+	Start  int `json:"start"`
+	Length int `json:"length"`
+	// one of the LegacyFieldType constants
+	Type string `json:"type"`
+	// side where the pad characters are removed, one of the LegacyFieldTrim constants
+	Trim string `json:"trim"`
+	// character used to fill the field, space when empty
+	Pad string `json:"pad"`
+	// number of implied decimal places of a decimal field without separator
+	Decimals int `json:"decimals"`
+	// time layout of a date field, 20060102 when empty
+	Format string `json:"format"`
+	// column of the CSV header or key of the NDJSON object, the field name when empty
+	Column string `json:"column"`
+}
+
+// LegacyLayout describes a fixed-width record of a legacy source
+type LegacyLayout struct {
+	Name string `json:"name"`
+	// record size, the end of the last field when zero
+	Size   int                 `json:"size"`
+	Fields []LegacyLayoutField `json:"fields"`
+	// fields of the header record with the generation date and the source,
+	// the header is only skipped when empty
+	Header []LegacyLayoutField `json:"header"`
+	// fields of the trailer record with the record count and the control total
+	Trailer []LegacyLayoutField `json:"trailer"`
+}
+
+type LegacyLayouts []LegacyLayout
+
+// LegacyLayoutDefault is the layout of the files generated by the legacy order system
+var LegacyLayoutDefault = LegacyLayout{
+	Name: LegacyLayoutNameDefault,
+	Size: 95,
+	Fields: []LegacyLayoutField{
+		{Name: LegacyFieldUserID, Start: 1, Length: 10, Type: LegacyFieldTypeInt},
+		{Name: LegacyFieldUserName, Start: 11, Length: 45, Type: LegacyFieldTypeString, Trim: LegacyFieldTrimBoth},
+		{Name: LegacyFieldOrderID, Start: 56, Length: 10, Type: LegacyFieldTypeInt},
+		{Name: LegacyFieldProductID, Start: 66, Length: 10, Type: LegacyFieldTypeInt},
+		{Name: LegacyFieldProductValue, Start: 76, Length: 12, Type: LegacyFieldTypeDecimal, Trim: LegacyFieldTrimBoth},
+		{Name: LegacyFieldBuyDate, Start: 88, Length: 8, Type: LegacyFieldTypeDate, Format: "20060102"},
+	},
+	Header: []LegacyLayoutField{
+		{Name: LegacyFieldGenerationDate, Start: 1, Length: 8, Type: LegacyFieldTypeDate, Format: "20060102"},
+		{Name: LegacyFieldSource, Start: 9, Length: 87, Type: LegacyFieldTypeString, Trim: LegacyFieldTrimBoth},
+	},
+	Trailer: []LegacyLayoutField{
+		{Name: LegacyFieldRecordCount, Start: 1, Length: 10, Type: LegacyFieldTypeInt},
+		{Name: LegacyFieldControlTotal, Start: 11, Length: 20, Type: LegacyFieldTypeDecimal, Trim: LegacyFieldTrimBoth},
+	},
+}
+
+func (modelLegacyLayoutField *LegacyLayoutField) ColumnName() string {
+	if modelLegacyLayoutField.Column == "" {
+		return modelLegacyLayoutField.Name
+	}
+
+	return modelLegacyLayoutField.Column
+}
+
+func (modelLegacyLayout *LegacyLayout) Field(name string) *LegacyLayoutField {
+	for index := range modelLegacyLayout.Fields {
+		if modelLegacyLayout.Fields[index].Name == name {
+			return &modelLegacyLayout.Fields[index]
+		}
+	}
+
+	return nil
+}
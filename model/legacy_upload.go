@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// LegacyUpload is a resumable upload of a legacy file sent in chunks and
+// imported when finalized
+type LegacyUpload struct {
+	// ID do Upload
+	ID string `json:"id" validate:"required" example:"0b4f7d6c-3a57-4d1c-9d0e-5c9d1a2b3c4d"`
+	// Nome do arquivo enviado
+	FileName string `json:"file_name,omitempty" example:"data_1.txt"`
+	// Content-Type do arquivo que identifica o formato e a compactação
+	ContentType string `json:"content_type" validate:"required" example:"text/plain"`
+	// Tamanho total do arquivo em bytes, não informado quando desconhecido
+	Size int64 `json:"size,omitempty" example:"1048576"`
+	// Quantidade de bytes recebidos, a posição de envio do próximo trecho
+	Received int64 `json:"received" validate:"required" example:"524288"`
+	// Data de criação do Upload
+	CreatedAt time.Time `json:"created_at" validate:"required"`
+	// Data de expiração do Upload que não for finalizado
+	ExpiresAt time.Time `json:"expires_at" validate:"required"`
+}
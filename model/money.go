@@ -0,0 +1,148 @@
+package model
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MoneyDecimals is the number of decimal places of the Money values
+const MoneyDecimals = 2
+
+var ErrMoneyInvalid = errors.New("money value invalid")
+
+// Money is an amount in cents, avoiding the rounding errors of the float values.
+// It is serialized to JSON as a number and to the database as a decimal text.
+type Money int64
+
+// ParseMoney converts a decimal text, with an optional sign and dot, to Money.
+// The decimal places beyond the cents must be zero, so no value is rounded.
+func ParseMoney(value string) (Money, error) {
+	value = strings.TrimSpace(value)
+
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(strings.TrimPrefix(value, "-"), "+")
+
+	integer, fraction, _ := strings.Cut(value, ".")
+
+	if integer == "" && fraction == "" {
+		return 0, ErrMoneyInvalid
+	}
+
+	if len(fraction) > MoneyDecimals {
+		if strings.Trim(fraction[MoneyDecimals:], "0") != "" {
+			return 0, ErrMoneyInvalid
+		}
+
+		fraction = fraction[:MoneyDecimals]
+	}
+
+	fraction += strings.Repeat("0", MoneyDecimals-len(fraction))
+
+	for _, char := range integer + fraction {
+		if char < '0' || char > '9' {
+			return 0, ErrMoneyInvalid
+		}
+	}
+
+	cents, err := strconv.ParseInt(integer+fraction, 10, 64)
+
+	if err != nil {
+		return 0, ErrMoneyInvalid
+	}
+
+	if negative {
+		cents = -cents
+	}
+
+	return Money(cents), nil
+}
+
+// String returns the value with the two decimal places, as 1836.70
+func (money Money) String() string {
+	cents := int64(money)
+	sign := ""
+
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// Average divides the value by count rounding the half cent away from zero,
+// the same way of the ROUND of the database
+func (money Money) Average(count int) Money {
+	if count == 0 {
+		return 0
+	}
+
+	cents := int64(money)
+	sign := int64(1)
+
+	if cents < 0 {
+		sign = -1
+		cents = -cents
+	}
+
+	return Money(sign * ((2*cents + int64(count)) / (2 * int64(count))))
+}
+
+// MarshalJSON writes the value without the trailing zeros, as 1836.7, which
+// is the same output of the float values used before.
+func (money Money) MarshalJSON() ([]byte, error) {
+	return []byte(strings.TrimSuffix(strings.TrimRight(money.String(), "0"), ".")), nil
+}
+
+func (money *Money) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	value, err := ParseMoney(string(data))
+
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, data)
+	}
+
+	*money = value
+
+	return nil
+}
+
+// Value writes the value to the numeric columns of the database
+func (money Money) Value() (driver.Value, error) {
+	return money.String(), nil
+}
+
+// Scan reads the value of the numeric columns of the database
+func (money *Money) Scan(src interface{}) error {
+	var value string
+
+	switch src := src.(type) {
+	case []byte:
+		value = string(src)
+	case string:
+		value = src
+	case int64:
+		*money = Money(src * 100)
+		return nil
+	case float64:
+		value = strconv.FormatFloat(src, 'f', -1, 64)
+	default:
+		return fmt.Errorf("%w: %v", ErrMoneyInvalid, src)
+	}
+
+	parsed, err := ParseMoney(value)
+
+	if err != nil {
+		return err
+	}
+
+	*money = parsed
+
+	return nil
+}
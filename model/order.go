@@ -7,7 +7,7 @@ type Legacy struct {
 	UserName     string
 	OrderID      int64
 	ProductID    int64
-	ProductValue float64
+	ProductValue Money
 	BuyDate      string
 	ImportedAt   time.Time
 }
@@ -21,20 +21,168 @@ type LegacyRecord struct {
 	BuyDate      string
 }
 
+type LegacyRecordLine struct {
+	// Arquivo do registro quando importado de um arquivo compactado zip
+	File string `json:"file,omitempty"`
+	Line int64  `json:"line"`
+}
+
+// LegacyFieldError is an error of a field of the record, or of the whole
+// record when the field is not informed
+type LegacyFieldError struct {
+	// Código do erro
+	Code string `json:"code" example:"USER_ID_INVALID"`
+	// Campo do registro, não informado quando o erro é do registro
+	Field string `json:"field,omitempty" example:"user_id"`
+	// Posição inicial do campo no registro de posição fixa
+	ColumnStart int `json:"column_start,omitempty" example:"1"`
+	// Posição final do campo no registro de posição fixa
+	ColumnEnd int `json:"column_end,omitempty" example:"10"`
+	// Conteúdo do campo no registro
+	Value string `json:"value,omitempty" example:"00000000X0"`
+	// Regra da política de validação violada pelo campo
+	Rule string `json:"rule,omitempty" example:"user_id_range"`
+	// Descrição do erro
+	Message string `json:"message" example:"UserID invalid"`
+}
+
 type LegacyRecordError struct {
+	// Arquivo do registro quando importado de um arquivo compactado zip
+	File    string `json:"file,omitempty"`
 	Line    int64  `json:"line"`
 	Message string `json:"message"`
+	// Todas as linhas em conflito quando o erro é um conflito entre registros
+	Lines []LegacyRecordLine `json:"lines,omitempty"`
+	// Erros de cada campo do registro
+	Errors []LegacyFieldError `json:"errors,omitempty"`
 }
 
 type LegacyRecordsError []LegacyRecordError
 
+// LegacyRecordFieldError is an error of a field with the record where it is
+type LegacyRecordFieldError struct {
+	// Arquivo do registro quando importado de um arquivo compactado zip
+	File string `json:"file,omitempty"`
+	Line int64  `json:"line"`
+	LegacyFieldError
+	// Todas as linhas em conflito quando o erro é um conflito entre registros
+	Lines []LegacyRecordLine `json:"lines,omitempty"`
+}
+
+// Fields returns the errors of the fields of all the records, in the order
+// of the records
+func (modelLegacyRecordsError LegacyRecordsError) Fields() []LegacyRecordFieldError {
+	modelLegacyRecordFieldErrors := []LegacyRecordFieldError{}
+
+	for _, modelLegacyRecordError := range modelLegacyRecordsError {
+		for _, modelLegacyFieldError := range modelLegacyRecordError.Errors {
+			modelLegacyRecordFieldErrors = append(modelLegacyRecordFieldErrors, LegacyRecordFieldError{
+				File:             modelLegacyRecordError.File,
+				Line:             modelLegacyRecordError.Line,
+				LegacyFieldError: modelLegacyFieldError,
+				Lines:            modelLegacyRecordError.Lines,
+			})
+		}
+	}
+
+	return modelLegacyRecordFieldErrors
+}
+
+type LegacyReject struct {
+	LegacyRecordError
+	// Conteúdo da linha rejeitada
+	Record string `json:"record"`
+}
+
+type LegacyRejects []LegacyReject
+
+const (
+	LegacyImportModeReplace = "replace"
+	LegacyImportModeMerge   = "merge"
+)
+
+// the import replaces the live dataset or the staged one, which is promoted
+// to live later
+const (
+	LegacyImportTargetLive  = "live"
+	LegacyImportTargetStage = "stage"
+)
+
+const (
+	LegacyImportEncodingAuto        = "auto"
+	LegacyImportEncodingUTF8        = "utf-8"
+	LegacyImportEncodingLatin1      = "latin-1"
+	LegacyImportEncodingWindows1252 = "windows-1252"
+)
+
+const (
+	LegacyImportCompressionGzip = "gzip"
+	LegacyImportCompressionZip  = "zip"
+)
+
+const (
+	LegacyImportFormatFixedWidth = "fixed_width"
+	LegacyImportFormatCSV        = "csv"
+	LegacyImportFormatNDJSON     = "ndjson"
+)
+
+type LegacyImportOptions struct {
+	// the first record of the fixed-width file is the header
+	HasHeader bool
+	// the last record of the fixed-width file is the trailer with the control totals
+	HasTrailer bool
+	Mode       string
+	// one of the LegacyImportTarget constants, LegacyImportTargetLive when empty
+	Target string
+	// one of the LegacyImportFormat constants, LegacyImportFormatFixedWidth when empty
+	Format string
+	// the format was not informed, so the format of a decompressed file is
+	// identified by its extension, set by the validation of the options
+	FormatByFileName bool
+	// one of the LegacyImportCompression constants, empty when not compressed
+	Compression string
+	// one of the LegacyImportEncoding constants, LegacyImportEncodingAuto when empty
+	Encoding string
+	// name of the uploaded file, used to identify the format of the compressed files
+	FileName string
+	// name of the layout of the records, LegacyLayoutNameDefault when empty
+	Layout string
+	// imports the valid records and quarantines the rejected ones
+	Lenient bool
+	// identification of who requested the import, kept in the import history
+	RequestedBy string
+	// key of the request, a retried import with the same key is not imported again
+	IdempotencyKey string
+}
+
 type LegacyImportResult struct {
+	// ID da Importação no histórico
+	ImportID int64 `json:"import_id,omitempty" example:"1"`
 	// Quantidade de usuários importados
 	Users int `json:"users" validate:"required"`
 	// Quantidade de pedidos importados
 	Orders int `json:"orders" validate:"required"`
 	// Quantidade de produtos importados
 	Products int `json:"products" validate:"required"`
+	// Quantidade de linhas aceitas
+	Accepted int `json:"accepted" validate:"required"`
+	// Quantidade de linhas rejeitadas
+	Rejected int `json:"rejected" validate:"required"`
+	// indicates the result of a previous import returned again
+	Replayed bool `json:"-"`
+}
+
+type LegacyValidateResult struct {
+	// Indica se o arquivo seria importado com as opções informadas
+	Valid bool `json:"valid" validate:"required"`
+	// Resumo da importação caso o arquivo fosse importado
+	Result LegacyImportResult `json:"result" validate:"required"`
+	// Registros com erro, limitados pela quantidade máxima de erros
+	Errors LegacyRecordsError `json:"errors" validate:"required"`
+	// Quantidade de erros dos campos encontrados no arquivo
+	ErrorsTotal int `json:"errors_total" validate:"required" example:"0"`
+	// Indica que os registros com erro foram limitados
+	ErrorsTruncated bool `json:"errors_truncated" validate:"required"`
 }
 
 type User struct {
@@ -48,7 +196,7 @@ type Order struct {
 	ID      int64
 	UserID  int64
 	BuyDate string
-	Total   float64
+	Total   Money
 }
 
 type Orders []Order
@@ -56,7 +204,7 @@ type Orders []Order
 type OrderProduct struct {
 	OrderID      int64
 	ProductID    int64
-	ProductValue float64
+	ProductValue Money
 }
 
 type OrdersProducts []OrderProduct
@@ -64,11 +212,11 @@ type OrdersProducts []OrderProduct
 type OrderUserProduct struct {
 	OrderID      int64
 	OrderBuyDate time.Time
-	OrderTotal   float64
+	OrderTotal   Money
 	UserID       int64
 	UserName     string
 	ProductID    int64
-	ProductValue float64
+	ProductValue Money
 }
 
 type OrderDetails struct {
@@ -86,7 +234,7 @@ type OrderDetailsOrder struct {
 	// Data da Compra
 	BuyDate string `json:"date" validate:"required" example:"2019-08-24" format:"date"`
 	// Valor Total do Pedido
-	Total float64 `json:"total" validate:"required" example:"23.45" format:"float"`
+	Total Money `json:"total" validate:"required" example:"23.45" format:"float" swaggertype:"number"`
 	// Lista de Produtos
 	Products []OrderDetailsProduct `json:"products" validate:"required"`
 }
@@ -95,7 +243,7 @@ type OrderDetailsProduct struct {
 	// ID do Produto
 	ID int64 `json:"product_id" validate:"required" example:"1"`
 	// Valor do Produto
-	Value float64 `json:"value" validate:"required" example:"23.45" format:"float"`
+	Value Money `json:"value" validate:"required" example:"23.45" format:"float" swaggertype:"number"`
 }
 
 type OrdersDetails []OrderDetails
@@ -0,0 +1,28 @@
+package model
+
+// OrderFilter selects the orders of the list, all the filters informed are
+// applied and the ones not informed select every order
+type OrderFilter struct {
+	// period of the buy date, nil when not informed
+	BuyDate *OrderRangeBuyDate
+	// orders of any of the users
+	UserIDs []int64
+	// any of the orders
+	OrderIDs []int64
+	// orders with the product, the same one of the range of value when both
+	// are informed
+	ProductID *int64
+	// range of the total of the order
+	MinTotal *Money
+	MaxTotal *Money
+	// range of the value of a product of the order
+	MinValue *Money
+	MaxValue *Money
+	// case-insensitive substring of the name of the user
+	Name string
+}
+
+// ProductFiltered tells if the order must have a product matching the filter
+func (modelOrderFilter *OrderFilter) ProductFiltered() bool {
+	return modelOrderFilter.ProductID != nil || modelOrderFilter.MinValue != nil || modelOrderFilter.MaxValue != nil
+}
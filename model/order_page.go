@@ -0,0 +1,55 @@
+package model
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// OrderPage selects a page of the orders sorted by user and order
+type OrderPage struct {
+	// number of orders of the page, all of them when zero
+	Limit int
+	// position of the last order of the previous page, the first page when nil
+	Cursor *OrderCursor
+}
+
+// OrderCursor is the position of an order in the sort by user and order, it
+// is sent to the client as an opaque token
+type OrderCursor struct {
+	UserID  int64
+	OrderID int64
+}
+
+// OrdersDetailsPage is a page of the orders grouped by user, the orders of a
+// user can continue on the next page
+type OrdersDetailsPage struct {
+	OrdersDetails OrdersDetails
+	// cursor of the next page, nil on the last page
+	Next *OrderCursor
+}
+
+var errOrderCursorInvalid = errors.New("invalid order cursor")
+
+// ParseOrderCursor reads the token returned by OrderCursor.String
+func ParseOrderCursor(token string) (*OrderCursor, error) {
+	value, err := base64.RawURLEncoding.DecodeString(token)
+
+	if err != nil {
+		return nil, errOrderCursorInvalid
+	}
+
+	modelOrderCursor := &OrderCursor{}
+
+	_, err = fmt.Sscanf(string(value), "%d:%d", &modelOrderCursor.UserID, &modelOrderCursor.OrderID)
+
+	if err != nil || modelOrderCursor.String() != token {
+		return nil, errOrderCursorInvalid
+	}
+
+	return modelOrderCursor, nil
+}
+
+func (modelOrderCursor *OrderCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", modelOrderCursor.UserID, modelOrderCursor.OrderID)))
+}
@@ -0,0 +1,20 @@
+package model
+
+type ProductSummary struct {
+	// ID do Produto
+	ProductID int64 `json:"product_id" validate:"required" example:"3"`
+	// Quantidade de vendas do Produto
+	TimesSold int `json:"times_sold" validate:"required" example:"12"`
+	// Quantidade de Usuários distintos que compraram o Produto
+	Buyers int `json:"buyers" validate:"required" example:"9"`
+	// Menor Valor de venda do Produto
+	MinValue Money `json:"min_value" validate:"required" example:"586.74" format:"float" swaggertype:"number"`
+	// Maior Valor de venda do Produto
+	MaxValue Money `json:"max_value" validate:"required" example:"1836.74" format:"float" swaggertype:"number"`
+	// Valor médio de venda do Produto
+	AvgValue Money `json:"avg_value" validate:"required" example:"1009.54" format:"float" swaggertype:"number"`
+	// Data da primeira venda do Produto
+	FirstSaleDate string `json:"first_sale_date" validate:"required" example:"2021-03-08" format:"date"`
+	// Data da última venda do Produto
+	LastSaleDate string `json:"last_sale_date" validate:"required" example:"2021-11-16" format:"date"`
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	ReportPeriodDay   = "day"
+	ReportPeriodWeek  = "week"
+	ReportPeriodMonth = "month"
+)
+
+// ReportFilter selects the orders aggregated by the reports and how they are
+// aggregated
+type ReportFilter struct {
+	// period of the buy date, every order when nil
+	BuyDate *OrderRangeBuyDate
+	// period of the revenue report, day, week starting on monday or month
+	Period string
+	// number of users or products of the top reports, all of them when zero
+	Size int
+}
+
+// Key identifies the report in the cache
+func (modelReportFilter *ReportFilter) Key() string {
+	from, to := "", ""
+
+	if modelReportFilter.BuyDate != nil {
+		from = modelReportFilter.BuyDate.From.Format("2006-01-02")
+		to = modelReportFilter.BuyDate.To.Format("2006-01-02")
+	}
+
+	return fmt.Sprintf("%s:%s:%s:%d", from, to, modelReportFilter.Period, modelReportFilter.Size)
+}
+
+type ReportRevenue struct {
+	// Data inicial do período (dia, semana iniciada na segunda-feira ou mês)
+	Period string `json:"period" validate:"required" example:"2021-03-01" format:"date"`
+	// Quantidade de Pedidos do período
+	Orders int `json:"orders" validate:"required" example:"12"`
+	// Valor Total dos Pedidos do período
+	Revenue Money `json:"revenue" validate:"required" example:"15836.74" format:"float" swaggertype:"number"`
+}
+
+type ReportsRevenue []ReportRevenue
+
+type ReportTopUser struct {
+	// ID do Usuário
+	UserID int64 `json:"user_id" validate:"required" example:"1"`
+	// Nome do Usuário
+	UserName string `json:"name" validate:"required" example:"Joao"`
+	// Quantidade de Pedidos
+	Orders int `json:"orders" validate:"required" example:"2"`
+	// Valor Total dos Pedidos
+	Total Money `json:"total" validate:"required" example:"1836.74" format:"float" swaggertype:"number"`
+}
+
+type ReportTopUsers []ReportTopUser
+
+type ReportTopProduct struct {
+	// ID do Produto
+	ProductID int64 `json:"product_id" validate:"required" example:"3"`
+	// Quantidade de vendas do Produto
+	TimesSold int `json:"times_sold" validate:"required" example:"12"`
+	// Valor Total das vendas do Produto
+	Revenue Money `json:"revenue" validate:"required" example:"15836.74" format:"float" swaggertype:"number"`
+}
+
+type ReportTopProducts []ReportTopProduct
+
+type ReportBasket struct {
+	// Quantidade de Pedidos
+	Orders int `json:"orders" validate:"required" example:"12"`
+	// Quantidade de Produtos dos Pedidos
+	Products int `json:"products" validate:"required" example:"30"`
+	// Valor Total dos Pedidos
+	Revenue Money `json:"revenue" validate:"required" example:"15836.74" format:"float" swaggertype:"number"`
+	// Quantidade média de Produtos por Pedido
+	AvgProducts float64 `json:"avg_products" validate:"required" example:"2.5"`
+	// Valor médio dos Pedidos
+	AvgTotal Money `json:"avg_total" validate:"required" example:"1319.73" format:"float" swaggertype:"number"`
+}
+
+// NewReportBasket calculates the averages of the orders, rounded to two
+// decimal places
+func NewReportBasket(orders, products int, revenue Money) *ReportBasket {
+	modelReportBasket := &ReportBasket{
+		Orders:   orders,
+		Products: products,
+		Revenue:  revenue,
+		AvgTotal: revenue.Average(orders),
+	}
+
+	if orders > 0 {
+		modelReportBasket.AvgProducts = math.Round(float64(products)*100/float64(orders)) / 100
+	}
+
+	return modelReportBasket
+}
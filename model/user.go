@@ -0,0 +1,77 @@
+package model
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+type UserSummary struct {
+	// ID do Usuário
+	UserID int64 `json:"user_id" validate:"required" example:"1"`
+	// Nome do Usuário
+	UserName string `json:"name" validate:"required" example:"Joao"`
+	// Quantidade de Pedidos
+	Orders int `json:"orders" validate:"required" example:"2"`
+	// Data da primeira Compra, não informada quando o usuário não possui pedidos
+	FirstBuyDate string `json:"first_buy_date,omitempty" example:"2021-03-08" format:"date"`
+	// Data da última Compra, não informada quando o usuário não possui pedidos
+	LastBuyDate string `json:"last_buy_date,omitempty" example:"2021-11-20" format:"date"`
+	// Valor Total dos Pedidos
+	Total Money `json:"total" validate:"required" example:"1836.74" format:"float" swaggertype:"number"`
+}
+
+type UserSummaries []UserSummary
+
+// UserFilter selects the users of the list, the ones not informed select
+// every user
+type UserFilter struct {
+	// case-insensitive substring of the name of the user
+	Name string
+}
+
+// UserPage selects a page of the users sorted by ID
+type UserPage struct {
+	// number of users of the page, all of them when zero
+	Limit int
+	// position of the last user of the previous page, the first page when nil
+	Cursor *UserCursor
+}
+
+// UserCursor is the position of a user in the sort by ID, it is sent to the
+// client as an opaque token
+type UserCursor struct {
+	UserID int64
+}
+
+// UserSummariesPage is a page of the users
+type UserSummariesPage struct {
+	UserSummaries UserSummaries
+	// cursor of the next page, nil on the last page
+	Next *UserCursor
+}
+
+var errUserCursorInvalid = errors.New("invalid user cursor")
+
+// ParseUserCursor reads the token returned by UserCursor.String
+func ParseUserCursor(token string) (*UserCursor, error) {
+	value, err := base64.RawURLEncoding.DecodeString(token)
+
+	if err != nil {
+		return nil, errUserCursorInvalid
+	}
+
+	modelUserCursor := &UserCursor{}
+
+	_, err = fmt.Sscanf(string(value), "u:%d", &modelUserCursor.UserID)
+
+	if err != nil || modelUserCursor.String() != token {
+		return nil, errUserCursorInvalid
+	}
+
+	return modelUserCursor, nil
+}
+
+func (modelUserCursor *UserCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("u:%d", modelUserCursor.UserID)))
+}
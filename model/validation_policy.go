@@ -0,0 +1,42 @@
+package model
+
+// names of the rules of the validation policy, reported with the violations
+const (
+	ValidationRuleBuyDateWindow     = "buy_date_window"
+	ValidationRuleProductValueRange = "product_value_range"
+	ValidationRuleUserIDRange       = "user_id_range"
+	ValidationRuleOrderIDRange      = "order_id_range"
+	ValidationRuleProductIDRange    = "product_id_range"
+	ValidationRuleUserNameLength    = "user_name_length"
+	ValidationRuleUserNamePattern   = "user_name_pattern"
+	ValidationRuleRangeMaxDays      = "range_max_days"
+)
+
+// ValidationPolicy is the set of rules validating the orders of the legacy
+// files and the params of the queries, loaded from the config. The dates are
+// relative to the moment of the request and the zero values are the defaults.
+type ValidationPolicy struct {
+	// oldest buy date in the format 2006-01-02, 1900-01-01 when empty
+	BuyDateMin string `mapstructure:"VALIDATION_BUY_DATE_MIN"`
+	// days before the current date of the oldest buy date, only BuyDateMin when zero
+	BuyDatePastDays int `mapstructure:"VALIDATION_BUY_DATE_PAST_DAYS"`
+	// days after the current date of the newest buy date, the current date when zero
+	BuyDateFutureDays int `mapstructure:"VALIDATION_BUY_DATE_FUTURE_DAYS"`
+	// limits of the product value with two decimal places, unlimited when empty
+	ProductValueMin string `mapstructure:"VALIDATION_PRODUCT_VALUE_MIN"`
+	ProductValueMax string `mapstructure:"VALIDATION_PRODUCT_VALUE_MAX"`
+	// limits of the ids, unlimited when zero
+	UserIDMin    int64 `mapstructure:"VALIDATION_USER_ID_MIN"`
+	UserIDMax    int64 `mapstructure:"VALIDATION_USER_ID_MAX"`
+	OrderIDMin   int64 `mapstructure:"VALIDATION_ORDER_ID_MIN"`
+	OrderIDMax   int64 `mapstructure:"VALIDATION_ORDER_ID_MAX"`
+	ProductIDMin int64 `mapstructure:"VALIDATION_PRODUCT_ID_MIN"`
+	ProductIDMax int64 `mapstructure:"VALIDATION_PRODUCT_ID_MAX"`
+	// limits in characters of the user name, 2 and unlimited when zero
+	UserNameMinLength int `mapstructure:"VALIDATION_USER_NAME_MIN_LENGTH"`
+	UserNameMaxLength int `mapstructure:"VALIDATION_USER_NAME_MAX_LENGTH"`
+	// regular expression of the allowed user names, any name when empty
+	UserNamePattern string `mapstructure:"VALIDATION_USER_NAME_PATTERN"`
+	// days between the dates of the range of the queries, 31 when zero
+	RangeMaxDays int `mapstructure:"VALIDATION_RANGE_MAX_DAYS"`
+}
@@ -5,8 +5,10 @@ import (
 	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/usecase"
 )
 
-func OrderRoute(params *RouteParameters) {
-	usecaseOrder := usecase.NewOrder(params.Repository, params.Cache)
+// OrderRoute includes the routes of the orders and returns their usecase,
+// shared with the other ways to import the legacy files
+func OrderRoute(params *RouteParameters) usecase.Order {
+	usecaseOrder := usecase.NewOrder(params.Repository, params.Cache, params.Config)
 	controllerOrder := controller.NewOrder(params.Log, usecaseOrder)
 
 	pathApiOrder := "/api/order"
@@ -16,4 +18,38 @@ func OrderRoute(params *RouteParameters) {
 	params.AppRouter.Get(pathApiOrder, controllerOrder.ListDetails)
 
 	params.AppRouter.Post(pathApiOrder+"/legacy/import", controllerOrder.LegacyImport)
+	params.AppRouter.Put(pathApiOrder+"/legacy/import", controllerOrder.LegacyImportRaw)
+	params.AppRouter.Post(pathApiOrder+"/legacy/validate", controllerOrder.LegacyValidate)
+	params.AppRouter.Get(pathApiOrder+"/legacy/rejects", controllerOrder.ListLegacyRejects)
+
+	paramImportID := params.AppRouter.PathFormat("/%s", "import_id")
+
+	params.AppRouter.Get(pathApiOrder+"/legacy/imports", controllerOrder.ListLegacyImports)
+	params.AppRouter.Post(pathApiOrder+"/legacy/imports"+paramImportID+"/restore", controllerOrder.LegacyImportRestore)
+	params.AppRouter.Post(pathApiOrder+"/legacy/stage/promote", controllerOrder.LegacyStagePromote)
+
+	paramJobID := params.AppRouter.PathFormat("/%s", "job_id")
+
+	params.AppRouter.Get(pathApiOrder+"/legacy/import/jobs"+paramJobID, controllerOrder.GetLegacyImportJob)
+	params.AppRouter.Delete(pathApiOrder+"/legacy/import/jobs"+paramJobID, controllerOrder.CancelLegacyImportJob)
+
+	paramUploadID := params.AppRouter.PathFormat("/%s", "upload_id")
+
+	params.AppRouter.Post(pathApiOrder+"/legacy/uploads", controllerOrder.CreateLegacyUpload)
+	params.AppRouter.Get(pathApiOrder+"/legacy/uploads"+paramUploadID, controllerOrder.GetLegacyUpload)
+	params.AppRouter.Put(pathApiOrder+"/legacy/uploads"+paramUploadID, controllerOrder.LegacyUploadChunk)
+	params.AppRouter.Delete(pathApiOrder+"/legacy/uploads"+paramUploadID, controllerOrder.DeleteLegacyUpload)
+	params.AppRouter.Post(pathApiOrder+"/legacy/uploads"+paramUploadID+"/finalize", controllerOrder.LegacyUploadFinalize)
+
+	// the staged orders are queried the same way until they are promoted
+	usecaseOrderStaging := usecase.NewOrderStaging(params.Repository, params.Cache, params.Config)
+	controllerOrderStaging := controller.NewOrder(params.Log, usecaseOrderStaging)
+
+	pathApiStagingOrder := "/api/staging/order"
+
+	params.AppRouter.Get(pathApiStagingOrder+paramID, controllerOrderStaging.GetDetailsByOrderID)
+	params.AppRouter.Get(pathApiStagingOrder, controllerOrderStaging.ListDetails)
+	params.AppRouter.Get(pathApiStagingOrder+"/legacy/rejects", controllerOrderStaging.ListLegacyRejects)
+
+	return usecaseOrder
 }
@@ -0,0 +1,17 @@
+package route
+
+import (
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/controller"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/usecase"
+)
+
+func ProductRoute(params *RouteParameters) {
+	usecaseProduct := usecase.NewProduct(params.Repository, params.Config)
+	controllerProduct := controller.NewProduct(params.Log, usecaseProduct)
+
+	pathApiProduct := "/api/product"
+	paramID := params.AppRouter.PathFormat("/%s", "product_id")
+
+	params.AppRouter.Get(pathApiProduct+paramID, controllerProduct.GetSummaryByProductID)
+	params.AppRouter.Get(pathApiProduct+paramID+"/orders", controllerProduct.ListOrdersDetails)
+}
@@ -0,0 +1,18 @@
+package route
+
+import (
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/controller"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/usecase"
+)
+
+func ReportRoute(params *RouteParameters) {
+	usecaseReport := usecase.NewReport(params.Repository, params.Cache, params.Config)
+	controllerReport := controller.NewReport(params.Log, usecaseReport)
+
+	pathApiReport := "/api/report"
+
+	params.AppRouter.Get(pathApiReport+"/revenue", controllerReport.ListRevenue)
+	params.AppRouter.Get(pathApiReport+"/top-users", controllerReport.ListTopUsers)
+	params.AppRouter.Get(pathApiReport+"/top-products", controllerReport.ListTopProducts)
+	params.AppRouter.Get(pathApiReport+"/basket", controllerReport.GetBasket)
+}
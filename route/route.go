@@ -4,6 +4,7 @@ import (
 	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/router"
 	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/cache"
 	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/util"
 	"github.com/hashicorp/go-hclog"
 )
 
@@ -12,4 +13,5 @@ type RouteParameters struct {
 	Log        hclog.Logger
 	Repository repository.Repository
 	Cache      cache.Cache
+	Config     *util.Config
 }
@@ -0,0 +1,17 @@
+package route
+
+import (
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/controller"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/usecase"
+)
+
+func UserRoute(params *RouteParameters) {
+	usecaseUser := usecase.NewUser(params.Repository, params.Cache, params.Config)
+	controllerUser := controller.NewUser(params.Log, usecaseUser)
+
+	pathApiUser := "/api/user"
+	paramID := params.AppRouter.PathFormat("/%s", "user_id")
+
+	params.AppRouter.Get(pathApiUser+paramID, controllerUser.GetDetailsByUserID)
+	params.AppRouter.Get(pathApiUser, controllerUser.ListSummaries)
+}
@@ -27,6 +27,7 @@ import (
 	cache "github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/cache/redis"
 	repository "github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository/in_memory"
 	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/util"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/watcher"
 	gohandlers "github.com/gorilla/handlers"
 	"github.com/hashicorp/go-hclog"
 )
@@ -94,19 +95,43 @@ func main() {
 	serverAddr := config.ServerAddress
 
 	// create a new router
-	appRouter := router.NewHttpRouter()
+	// the mux router is used because the httprouter does not allow static and
+	// param segments in the same position (/api/order/{id} and /api/order/legacy/...)
+	appRouter := router.NewMuxRouter()
 	routerParameters := &route.RouteParameters{
 		AppRouter:  appRouter,
 		Log:        log,
 		Repository: repository,
 		Cache:      cache,
+		Config:     config,
 	}
 
 	// include the routes
-	route.OrderRoute(routerParameters)
+	usecaseOrder := route.OrderRoute(routerParameters)
+	route.UserRoute(routerParameters)
+	route.ProductRoute(routerParameters)
+	route.ReportRoute(routerParameters)
 	route.SwaggerRoute(appRouter)
 	route.HealthzRoute(routerParameters)
 
+	// watch the inbox directory of the legacy files, the imports share the
+	// usecase of the routes so only one import at a time persists its records
+	ctxInbox, cancelInbox := context.WithCancel(context.Background())
+	defer cancelInbox()
+
+	if config.LegacyInboxDir != "" {
+		legacyInbox, err := watcher.NewLegacyInbox(log, usecaseOrder, config)
+
+		if err != nil {
+			log.Error("Cannot watch the legacy inbox", "error", err)
+			os.Exit(0)
+		}
+
+		go legacyInbox.Run(ctxInbox)
+
+		log.Info(fmt.Sprintf("Legacy inbox %v watched successfuly", config.LegacyInboxDir))
+	}
+
 	// create HTTP handler
 	httpHandler := appRouter.Serve()
 
@@ -155,6 +180,10 @@ func main() {
 	sig := <-chanSignal
 	log.Info(fmt.Sprintf("HTTP server terminate signal %v", sig))
 
+	// stop watching the inbox, the file being imported is imported again at
+	// the next start and replayed when it was already persisted
+	cancelInbox()
+
 	// gracefully shutdown the server, waiting max 30 seconds for current operations to complete
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
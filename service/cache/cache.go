@@ -2,6 +2,8 @@ package cache
 
 type Cache interface {
 	Order() Order
+	User() User
+	Report() Report
 	Check() error
 	Close() error
 }
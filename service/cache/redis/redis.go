@@ -14,6 +14,8 @@ type Redis struct {
 	Client     *redis.Client
 	Expiration time.Duration
 	OrderInst  cache.Order
+	UserInst   cache.User
+	ReportInst cache.Report
 }
 
 func NewRedis(config *util.Config) (cache.Cache, error) {
@@ -43,6 +45,8 @@ func NewRedis(config *util.Config) (cache.Cache, error) {
 	}
 
 	cacheRedis.OrderInst = NewRedisOrder(cacheRedis)
+	cacheRedis.UserInst = NewRedisUser(cacheRedis)
+	cacheRedis.ReportInst = NewRedisReport(cacheRedis)
 
 	return cacheRedis, nil
 }
@@ -59,6 +63,14 @@ func (redis *Redis) Order() cache.Order {
 	return redis.OrderInst
 }
 
+func (redis *Redis) User() cache.User {
+	return redis.UserInst
+}
+
+func (redis *Redis) Report() cache.Report {
+	return redis.ReportInst
+}
+
 func RedisKeyFormat(identifier, fieldName, fieldValue string) string {
 	return fmt.Sprintf("%v:%v:%v", identifier, fieldName, fieldValue)
 }
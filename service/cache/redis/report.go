@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/cache"
+)
+
+type RedisReport struct {
+	Cache *Redis
+}
+
+func NewRedisReport(cache *Redis) cache.Report {
+	return &RedisReport{Cache: cache}
+}
+
+func (redisReport *RedisReport) SetRevenue(version string, modelReportFilter *model.ReportFilter, modelReportsRevenue *model.ReportsRevenue) error {
+	return redisReport.set(redisReportKey("revenue", version, modelReportFilter), modelReportsRevenue)
+}
+
+func (redisReport *RedisReport) GetRevenue(version string, modelReportFilter *model.ReportFilter) (*model.ReportsRevenue, error) {
+	modelReportsRevenue := &model.ReportsRevenue{}
+	err := redisReport.get(redisReportKey("revenue", version, modelReportFilter), modelReportsRevenue)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return modelReportsRevenue, nil
+}
+
+func (redisReport *RedisReport) SetTopUsers(version string, modelReportFilter *model.ReportFilter, modelReportTopUsers *model.ReportTopUsers) error {
+	return redisReport.set(redisReportKey("top-users", version, modelReportFilter), modelReportTopUsers)
+}
+
+func (redisReport *RedisReport) GetTopUsers(version string, modelReportFilter *model.ReportFilter) (*model.ReportTopUsers, error) {
+	modelReportTopUsers := &model.ReportTopUsers{}
+	err := redisReport.get(redisReportKey("top-users", version, modelReportFilter), modelReportTopUsers)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return modelReportTopUsers, nil
+}
+
+func (redisReport *RedisReport) SetTopProducts(version string, modelReportFilter *model.ReportFilter, modelReportTopProducts *model.ReportTopProducts) error {
+	return redisReport.set(redisReportKey("top-products", version, modelReportFilter), modelReportTopProducts)
+}
+
+func (redisReport *RedisReport) GetTopProducts(version string, modelReportFilter *model.ReportFilter) (*model.ReportTopProducts, error) {
+	modelReportTopProducts := &model.ReportTopProducts{}
+	err := redisReport.get(redisReportKey("top-products", version, modelReportFilter), modelReportTopProducts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return modelReportTopProducts, nil
+}
+
+func (redisReport *RedisReport) SetBasket(version string, modelReportFilter *model.ReportFilter, modelReportBasket *model.ReportBasket) error {
+	return redisReport.set(redisReportKey("basket", version, modelReportFilter), modelReportBasket)
+}
+
+func (redisReport *RedisReport) GetBasket(version string, modelReportFilter *model.ReportFilter) (*model.ReportBasket, error) {
+	modelReportBasket := &model.ReportBasket{}
+	err := redisReport.get(redisReportKey("basket", version, modelReportFilter), modelReportBasket)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return modelReportBasket, nil
+}
+
+func (redisReport *RedisReport) set(key string, report interface{}) error {
+	value, err := json.Marshal(report)
+
+	if err != nil {
+		return err
+	}
+
+	return redisReport.Cache.Client.Set(context.Background(), key, value, redisReport.Cache.Expiration).Err()
+}
+
+func (redisReport *RedisReport) get(key string, report interface{}) error {
+	value, err := redisReport.Cache.Client.Get(context.Background(), key).Result()
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal([]byte(value), report)
+}
+
+// redisReportKey includes the version of the dataset and the filter in the key
+// of the report
+func redisReportKey(name string, version string, modelReportFilter *model.ReportFilter) string {
+	return RedisKeyFormat("report", name, fmt.Sprintf("%s:version:%s", modelReportFilter.Key(), version))
+}
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/cache"
+)
+
+type RedisUser struct {
+	Cache *Redis
+}
+
+func NewRedisUser(cache *Redis) cache.User {
+	return &RedisUser{Cache: cache}
+}
+
+func (redisUser *RedisUser) SetDetailsByUserID(version string, modelOrderDetails *model.OrderDetails) error {
+	key := redisUserKey(version, modelOrderDetails.UserID)
+	value, err := json.Marshal(modelOrderDetails)
+
+	if err != nil {
+		return err
+	}
+
+	return redisUser.Cache.Client.Set(context.Background(), key, value, redisUser.Cache.Expiration).Err()
+}
+
+func (redisUser *RedisUser) GetDetailsByUserID(version string, userID int64) (*model.OrderDetails, error) {
+	key := redisUserKey(version, userID)
+	value, err := redisUser.Cache.Client.Get(context.Background(), key).Result()
+
+	if err != nil {
+		return nil, err
+	}
+
+	modelOrderDetails := &model.OrderDetails{}
+	err = json.Unmarshal([]byte(value), modelOrderDetails)
+
+	return modelOrderDetails, err
+}
+
+// redisUserKey includes the version of the dataset in the key of the user
+func redisUserKey(version string, userID int64) string {
+	return RedisKeyFormat("user", "id", fmt.Sprintf("%d:version:%s", userID, version))
+}
@@ -0,0 +1,17 @@
+package cache
+
+import "github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+
+// Report caches the reports by the version of the dataset and the filter of
+// the report, so an import makes the previous entries unreachable until they
+// expire
+type Report interface {
+	SetRevenue(version string, modelReportFilter *model.ReportFilter, modelReportsRevenue *model.ReportsRevenue) error
+	GetRevenue(version string, modelReportFilter *model.ReportFilter) (*model.ReportsRevenue, error)
+	SetTopUsers(version string, modelReportFilter *model.ReportFilter, modelReportTopUsers *model.ReportTopUsers) error
+	GetTopUsers(version string, modelReportFilter *model.ReportFilter) (*model.ReportTopUsers, error)
+	SetTopProducts(version string, modelReportFilter *model.ReportFilter, modelReportTopProducts *model.ReportTopProducts) error
+	GetTopProducts(version string, modelReportFilter *model.ReportFilter) (*model.ReportTopProducts, error)
+	SetBasket(version string, modelReportFilter *model.ReportFilter, modelReportBasket *model.ReportBasket) error
+	GetBasket(version string, modelReportFilter *model.ReportFilter) (*model.ReportBasket, error)
+}
@@ -0,0 +1,10 @@
+package cache
+
+import "github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+
+// User caches the orders of the users by the version of the dataset, so an
+// import makes the previous entries unreachable until they expire
+type User interface {
+	SetDetailsByUserID(version string, modelOrderDetails *model.OrderDetails) error
+	GetDetailsByUserID(version string, userID int64) (*model.OrderDetails, error)
+}
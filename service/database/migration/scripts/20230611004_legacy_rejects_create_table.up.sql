@@ -0,0 +1,6 @@
+CREATE TABLE legacy_rejects (
+    "id" bigserial PRIMARY KEY,
+    "line" bigint NOT NULL,
+    "message" text NOT NULL,
+    "record" text NOT NULL
+);
@@ -0,0 +1 @@
+ALTER TABLE legacy_rejects DROP COLUMN IF EXISTS "file";
@@ -0,0 +1 @@
+ALTER TABLE legacy_rejects ADD COLUMN "file" text NOT NULL DEFAULT '';
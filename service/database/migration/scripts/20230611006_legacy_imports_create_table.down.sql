@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS legacy_imports_orders_product;
+DROP TABLE IF EXISTS legacy_imports_orders;
+DROP TABLE IF EXISTS legacy_imports_users;
+DROP TABLE IF EXISTS legacy_imports;
@@ -0,0 +1,53 @@
+CREATE TABLE legacy_imports (
+    "id" bigserial PRIMARY KEY,
+    "imported_at" timestamptz NOT NULL,
+    "file_name" text NOT NULL,
+    "checksum" varchar(64) NOT NULL,
+    "mode" varchar(10) NOT NULL,
+    "users" integer NOT NULL,
+    "orders" integer NOT NULL,
+    "products" integer NOT NULL,
+    "accepted" integer NOT NULL,
+    "rejected" integer NOT NULL,
+    "requested_by" text NOT NULL,
+    "live" boolean NOT NULL
+);
+
+CREATE TABLE legacy_imports_users (
+    "import_id" bigint NOT NULL,
+    "id" bigint NOT NULL,
+    "name" varchar(45) NOT NULL,
+    CONSTRAINT fk_import
+        FOREIGN KEY(import_id) 
+	        REFERENCES legacy_imports(id)
+	        ON DELETE CASCADE
+);
+
+CREATE INDEX "idx_legacy_imports_users_import_id" ON legacy_imports_users (import_id);
+
+CREATE TABLE legacy_imports_orders (
+    "import_id" bigint NOT NULL,
+    "id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "buy_date" date NOT NULL,
+    "total" real NOT NULL,
+    CONSTRAINT fk_import
+        FOREIGN KEY(import_id) 
+	        REFERENCES legacy_imports(id)
+	        ON DELETE CASCADE
+);
+
+CREATE INDEX "idx_legacy_imports_orders_import_id" ON legacy_imports_orders (import_id);
+
+CREATE TABLE legacy_imports_orders_product (
+    "import_id" bigint NOT NULL,
+    "order_id" bigint NOT NULL,
+    "product_id" bigint NOT NULL,
+    "product_value" real NOT NULL,
+    CONSTRAINT fk_import
+        FOREIGN KEY(import_id) 
+	        REFERENCES legacy_imports(id)
+	        ON DELETE CASCADE
+);
+
+CREATE INDEX "idx_legacy_imports_orders_product_import_id" ON legacy_imports_orders_product (import_id);
@@ -0,0 +1 @@
+ALTER TABLE legacy_rejects ADD COLUMN "lines" jsonb;
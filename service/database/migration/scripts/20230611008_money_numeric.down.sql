@@ -0,0 +1,4 @@
+ALTER TABLE legacy_imports_orders ALTER COLUMN "total" TYPE real;
+ALTER TABLE legacy_imports_orders_product ALTER COLUMN "product_value" TYPE real;
+ALTER TABLE orders ALTER COLUMN "total" TYPE real;
+ALTER TABLE orders_product ALTER COLUMN "product_value" TYPE real;
@@ -0,0 +1,8 @@
+ALTER TABLE orders_product ALTER COLUMN "product_value" TYPE numeric(12,2) USING round("product_value"::numeric, 2);
+ALTER TABLE orders ALTER COLUMN "total" TYPE numeric(12,2) USING round("total"::numeric, 2);
+ALTER TABLE legacy_imports_orders_product ALTER COLUMN "product_value" TYPE numeric(12,2) USING round("product_value"::numeric, 2);
+ALTER TABLE legacy_imports_orders ALTER COLUMN "total" TYPE numeric(12,2) USING round("total"::numeric, 2);
+
+-- the totals stored as real may have lost cents, so they are summed again
+UPDATE orders o SET "total" = COALESCE((SELECT SUM(op.product_value) FROM orders_product op WHERE op.order_id = o.id), 0);
+UPDATE legacy_imports_orders o SET "total" = COALESCE((SELECT SUM(op.product_value) FROM legacy_imports_orders_product op WHERE op.import_id = o.import_id AND op.order_id = o.id), 0);
@@ -0,0 +1 @@
+ALTER TABLE legacy_imports DROP COLUMN IF EXISTS "idempotency_key";
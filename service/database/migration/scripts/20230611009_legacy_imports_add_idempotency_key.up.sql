@@ -0,0 +1 @@
+ALTER TABLE legacy_imports ADD COLUMN "idempotency_key" text NOT NULL DEFAULT '';
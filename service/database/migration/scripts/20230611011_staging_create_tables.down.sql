@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS staging_legacy_imports;
+DROP TABLE IF EXISTS staging_legacy_rejects;
+DROP TABLE IF EXISTS staging_orders_product;
+DROP TABLE IF EXISTS staging_orders;
+DROP TABLE IF EXISTS staging_users;
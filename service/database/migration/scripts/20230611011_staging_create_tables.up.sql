@@ -0,0 +1,6 @@
+-- the staged dataset has the same structure of the live one until promoted
+CREATE TABLE staging_users (LIKE users INCLUDING ALL);
+CREATE TABLE staging_orders (LIKE orders INCLUDING ALL);
+CREATE TABLE staging_orders_product (LIKE orders_product INCLUDING ALL);
+CREATE TABLE staging_legacy_rejects (LIKE legacy_rejects INCLUDING ALL);
+CREATE TABLE staging_legacy_imports (LIKE legacy_imports INCLUDING ALL);
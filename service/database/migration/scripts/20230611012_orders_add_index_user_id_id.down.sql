@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "idx_staging_user_id_id";
+DROP INDEX IF EXISTS "idx_user_id_id";
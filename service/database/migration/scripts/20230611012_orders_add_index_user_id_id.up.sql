@@ -0,0 +1,3 @@
+-- keyset pagination of the orders sorted by user and order
+CREATE INDEX "idx_user_id_id" ON orders (user_id, id);
+CREATE INDEX "idx_staging_user_id_id" ON staging_orders (user_id, id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "idx_staging_product_id";
+DROP INDEX IF EXISTS "idx_product_id";
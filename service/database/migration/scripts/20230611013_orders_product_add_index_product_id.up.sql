@@ -0,0 +1,3 @@
+-- orders of a product and its summary
+CREATE INDEX "idx_product_id" ON orders_product (product_id);
+CREATE INDEX "idx_staging_product_id" ON staging_orders_product (product_id);
@@ -0,0 +1,17 @@
+-- the rejects of each import are restored with its dataset
+CREATE TABLE legacy_imports_rejects (
+    "import_id" bigint NOT NULL,
+    "id" bigint NOT NULL,
+    "file" text NOT NULL DEFAULT '',
+    "line" bigint NOT NULL,
+    "message" text NOT NULL,
+    "record" text NOT NULL,
+    "lines" jsonb,
+    "errors" jsonb,
+    CONSTRAINT fk_import
+        FOREIGN KEY(import_id) 
+	        REFERENCES legacy_imports(id)
+	        ON DELETE CASCADE
+);
+
+CREATE INDEX "idx_legacy_imports_rejects_import_id" ON legacy_imports_rejects (import_id);
@@ -0,0 +1,2 @@
+ALTER TABLE staging_legacy_imports DROP COLUMN IF EXISTS "base_id";
+ALTER TABLE legacy_imports DROP COLUMN IF EXISTS "base_id";
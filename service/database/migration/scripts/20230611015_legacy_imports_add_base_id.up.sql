@@ -0,0 +1,4 @@
+-- a merge keeps only the merged records and is restored over the import it
+-- was merged into, null when the import keeps the whole dataset
+ALTER TABLE legacy_imports ADD COLUMN "base_id" bigint;
+ALTER TABLE staging_legacy_imports ADD COLUMN "base_id" bigint;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "idx_legacy_imports_live";
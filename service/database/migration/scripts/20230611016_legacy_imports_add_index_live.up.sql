@@ -0,0 +1,2 @@
+-- live import read on every read of the cache of the users and the reports
+CREATE INDEX "idx_legacy_imports_live" ON legacy_imports (id) WHERE live;
@@ -0,0 +1,2 @@
+ALTER TABLE staging_legacy_imports DROP COLUMN IF EXISTS "restorable";
+ALTER TABLE legacy_imports DROP COLUMN IF EXISTS "restorable";
@@ -0,0 +1,4 @@
+-- the records of the import are copied to the history only when it is
+-- restorable, the imports before this column kept their copy
+ALTER TABLE legacy_imports ADD COLUMN "restorable" boolean NOT NULL DEFAULT true;
+ALTER TABLE staging_legacy_imports ADD COLUMN "restorable" boolean NOT NULL DEFAULT true;
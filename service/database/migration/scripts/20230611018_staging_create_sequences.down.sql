@@ -0,0 +1,10 @@
+ALTER TABLE staging_legacy_imports ALTER COLUMN "id" SET DEFAULT nextval('legacy_imports_id_seq');
+DROP SEQUENCE IF EXISTS staging_legacy_imports_id_seq;
+ALTER TABLE staging_legacy_rejects ALTER COLUMN "id" SET DEFAULT nextval('legacy_rejects_id_seq');
+DROP SEQUENCE IF EXISTS staging_legacy_rejects_id_seq;
+ALTER TABLE staging_orders_product ALTER COLUMN "id" SET DEFAULT nextval('orders_product_id_seq');
+DROP SEQUENCE IF EXISTS staging_orders_product_id_seq;
+ALTER TABLE staging_orders ALTER COLUMN "id" SET DEFAULT nextval('orders_id_seq');
+DROP SEQUENCE IF EXISTS staging_orders_id_seq;
+ALTER TABLE staging_users ALTER COLUMN "id" SET DEFAULT nextval('users_id_seq');
+DROP SEQUENCE IF EXISTS staging_users_id_seq;
@@ -0,0 +1,13 @@
+-- LIKE ... INCLUDING ALL copies the default of the id, so the staging tables
+-- used the sequences of the live tables and a staged import took an ID of the
+-- history that is discarded when it is promoted
+CREATE SEQUENCE staging_users_id_seq OWNED BY staging_users.id;
+ALTER TABLE staging_users ALTER COLUMN "id" SET DEFAULT nextval('staging_users_id_seq');
+CREATE SEQUENCE staging_orders_id_seq OWNED BY staging_orders.id;
+ALTER TABLE staging_orders ALTER COLUMN "id" SET DEFAULT nextval('staging_orders_id_seq');
+CREATE SEQUENCE staging_orders_product_id_seq OWNED BY staging_orders_product.id;
+ALTER TABLE staging_orders_product ALTER COLUMN "id" SET DEFAULT nextval('staging_orders_product_id_seq');
+CREATE SEQUENCE staging_legacy_rejects_id_seq OWNED BY staging_legacy_rejects.id;
+ALTER TABLE staging_legacy_rejects ALTER COLUMN "id" SET DEFAULT nextval('staging_legacy_rejects_id_seq');
+CREATE SEQUENCE staging_legacy_imports_id_seq OWNED BY staging_legacy_imports.id;
+ALTER TABLE staging_legacy_imports ALTER COLUMN "id" SET DEFAULT nextval('staging_legacy_imports_id_seq');
@@ -5,7 +5,10 @@ import (
 	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/util"
 )
 
-type InMemory struct{}
+type InMemory struct {
+	// the repository of the staged dataset instead of the live one
+	staging bool
+}
 
 func NewInMemory(config *util.Config) (repository.Repository, error) {
 	return &InMemory{}, nil
@@ -20,5 +23,37 @@ func (inMemory *InMemory) Close() error {
 }
 
 func (inMemory *InMemory) Order() repository.Order {
+	if inMemory.staging {
+		return &InMemoryOrder{store: orderStaging}
+	}
+
 	return NewOrder()
 }
+
+func (inMemory *InMemory) User() repository.User {
+	if inMemory.staging {
+		return &InMemoryUser{store: orderStaging}
+	}
+
+	return NewUser()
+}
+
+func (inMemory *InMemory) Product() repository.Product {
+	if inMemory.staging {
+		return &InMemoryProduct{store: orderStaging}
+	}
+
+	return NewProduct()
+}
+
+func (inMemory *InMemory) Report() repository.Report {
+	if inMemory.staging {
+		return &InMemoryReport{store: orderStaging}
+	}
+
+	return NewReport()
+}
+
+func (inMemory *InMemory) Staging() repository.Repository {
+	return &InMemory{staging: true}
+}
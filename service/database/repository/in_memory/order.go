@@ -1,63 +1,527 @@
 package repository
 
 import (
+	"sort"
+	"strings"
+	"sync"
+
 	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
 	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
 )
 
 var (
-	orderModelUsers          = model.Users{}
-	orderModelOrders         = model.Orders{}
-	orderModelOrdersProducts = model.OrdersProducts{}
-	orderMapUsers            = make(map[int64]int)
-	orderMapOrders           = make(map[int64]int)
-	orderMapOrdersProducts   = make(map[int64][]int)
+	orderLive               = newOrderStore()
+	orderStaging            = newOrderStore()
+	orderModelLegacyImports = model.LegacyImports{}
+	// dataset of each import of the history indexed by the import ID
+	orderLegacyImportDatasets = make(map[int64]orderDataset)
+	orderMutex                sync.RWMutex
 )
 
-type InMemoryOrder struct{}
+// orderDataset is the dataset resulting from an import, kept to be restored
+// with its rejects. The dataset of a merge keeps only the merged records and
+// is restored over the dataset of the import it was merged into.
+type orderDataset struct {
+	users          model.Users
+	orders         model.Orders
+	ordersProducts model.OrdersProducts
+	legacyRejects  model.LegacyRejects
+	// import the merge was merged into, zero when the dataset is whole
+	baseID int64
+}
+
+// orderStore is a dataset with its indexes, the live one is queried by the
+// API and the staged one until it is promoted
+type orderStore struct {
+	users             model.Users
+	orders            model.Orders
+	ordersProducts    model.OrdersProducts
+	mapUsers          map[int64]int
+	mapOrders         map[int64]int
+	mapOrdersProducts map[int64][]int
+	// indexes of the orders sorted by user and order, followed by the pages
+	ordersSorted []int
+	// indexes of the users sorted by ID, followed by the pages of the users
+	usersSorted []int
+	// indexes of the orders of each user in the sort by user and order
+	mapUsersOrders map[int64][]int
+	// indexes of the products of the orders by product ID
+	mapProductsOrdersProducts map[int64][]int
+	legacyRejects             model.LegacyRejects
+	// import of the staged dataset, nil when nothing is staged
+	legacyImport *model.LegacyImport
+}
+
+func newOrderStore() *orderStore {
+	return &orderStore{
+		users:                     model.Users{},
+		orders:                    model.Orders{},
+		ordersProducts:            model.OrdersProducts{},
+		mapUsers:                  make(map[int64]int),
+		mapOrders:                 make(map[int64]int),
+		mapOrdersProducts:         make(map[int64][]int),
+		mapUsersOrders:            make(map[int64][]int),
+		mapProductsOrdersProducts: make(map[int64][]int),
+		legacyRejects:             model.LegacyRejects{},
+	}
+}
+
+type InMemoryOrder struct {
+	store *orderStore
+}
 
 func NewOrder() repository.Order {
-	return &InMemoryOrder{}
+	return &InMemoryOrder{store: orderLive}
+}
+
+func (inMemoryOrder *InMemoryOrder) LegacyBulkInsert(modelLegacyImport *model.LegacyImport, legacyDataset repository.LegacyDataset) error {
+	dataset, err := orderDatasetLoad(legacyDataset)
+
+	if err != nil {
+		return err
+	}
+
+	orderMutex.Lock()
+	defer orderMutex.Unlock()
+
+	inMemoryOrder.store.setDataset(dataset.users, dataset.orders, dataset.ordersProducts)
+	inMemoryOrder.store.legacyRejects = dataset.legacyRejects
+	inMemoryOrder.legacyImportInsert(modelLegacyImport, dataset)
+
+	return nil
+}
+
+func (inMemoryOrder *InMemoryOrder) LegacyBulkUpsert(modelLegacyImport *model.LegacyImport, legacyDataset repository.LegacyDataset) ([]int64, error) {
+	dataset, err := orderDatasetLoad(legacyDataset)
+
+	if err != nil {
+		return nil, err
+	}
+
+	orderMutex.Lock()
+	defer orderMutex.Unlock()
+
+	store := inMemoryOrder.store
+
+	merged := orderDatasetUpsert(orderDataset{users: store.users, orders: store.orders, ordersProducts: store.ordersProducts}, dataset)
+
+	store.setDataset(merged.users, merged.orders, merged.ordersProducts)
+	store.legacyRejects = merged.legacyRejects
+
+	mapUsersUpserted := make(map[int64]bool)
+	mapOrdersUpserted := make(map[int64]bool)
+
+	for _, modelUser := range dataset.users {
+		mapUsersUpserted[modelUser.ID] = true
+	}
+
+	for _, modelOrder := range dataset.orders {
+		mapOrdersUpserted[modelOrder.ID] = true
+	}
+
+	// the history keeps the merged orders with the recalculated totals
+	dataset.orders = model.Orders{}
+	dataset.baseID = orderLegacyImportLiveID()
+
+	// without a live import the whole dataset is kept
+	if dataset.baseID == 0 {
+		dataset = merged
+	}
+
+	orderIDs := []int64{}
+
+	for _, modelOrder := range store.orders {
+		if mapOrdersUpserted[modelOrder.ID] {
+			dataset.orders = append(dataset.orders, modelOrder)
+		}
+
+		if mapOrdersUpserted[modelOrder.ID] || mapUsersUpserted[modelOrder.UserID] {
+			orderIDs = append(orderIDs, modelOrder.ID)
+		}
+	}
+
+	inMemoryOrder.legacyImportInsert(modelLegacyImport, dataset)
+
+	return orderIDs, nil
+}
+
+func (inMemoryOrder *InMemoryOrder) ListLegacyRejects() (*model.LegacyRejects, error) {
+	orderMutex.RLock()
+	defer orderMutex.RUnlock()
+
+	if len(inMemoryOrder.store.legacyRejects) == 0 {
+		return nil, repository.ErrNotFound{Message: "not found"}
+	}
+
+	modelLegacyRejects := append(model.LegacyRejects{}, inMemoryOrder.store.legacyRejects...)
+
+	return &modelLegacyRejects, nil
+}
+
+func (*InMemoryOrder) ListLegacyImports() (*model.LegacyImports, error) {
+	orderMutex.RLock()
+	defer orderMutex.RUnlock()
+
+	if len(orderModelLegacyImports) == 0 {
+		return nil, repository.ErrNotFound{Message: "not found"}
+	}
+
+	modelLegacyImports := model.LegacyImports{}
+
+	// the most recent import first
+	for index := len(orderModelLegacyImports) - 1; index >= 0; index-- {
+		modelLegacyImports = append(modelLegacyImports, orderModelLegacyImports[index])
+	}
+
+	return &modelLegacyImports, nil
+}
+
+func (*InMemoryOrder) GetLegacyImportLive() (*model.LegacyImport, error) {
+	orderMutex.RLock()
+	defer orderMutex.RUnlock()
+
+	for _, modelLegacyImport := range orderModelLegacyImports {
+		if modelLegacyImport.Live {
+			return &modelLegacyImport, nil
+		}
+	}
+
+	return nil, repository.ErrNotFound{Message: "not found"}
+}
+
+func (*InMemoryOrder) LegacyImportRestore(importID int64) (*model.LegacyImport, error) {
+	orderMutex.Lock()
+	defer orderMutex.Unlock()
+
+	datasets, ok := orderLegacyImportChain(importID)
+
+	if !ok {
+		return nil, repository.ErrNotFound{Message: "not found"}
+	}
+
+	dataset := orderDataset{}
+
+	for _, datasetImport := range datasets {
+		dataset = orderDatasetUpsert(dataset, datasetImport)
+	}
+
+	orderLive.setDataset(dataset.users, dataset.orders, dataset.ordersProducts)
+	orderLive.legacyRejects = dataset.legacyRejects
+
+	var modelLegacyImport model.LegacyImport
+
+	for index := range orderModelLegacyImports {
+		orderModelLegacyImports[index].Live = orderModelLegacyImports[index].ID == importID
+
+		if orderModelLegacyImports[index].Live {
+			modelLegacyImport = orderModelLegacyImports[index]
+		}
+	}
+
+	return &modelLegacyImport, nil
+}
+
+// LegacyImportsPrune removes from the history the imports older than the last
+// size imports, the live import and the imports needed to restore the merges
+// kept are always kept
+func (*InMemoryOrder) LegacyImportsPrune(size int) error {
+	orderMutex.Lock()
+	defer orderMutex.Unlock()
+
+	modelLegacyImports := model.LegacyImports{}
+
+	mapKept := make(map[int64]bool)
+
+	for index, modelLegacyImport := range orderModelLegacyImports {
+		if modelLegacyImport.Live || index >= len(orderModelLegacyImports)-size {
+			// a merge kept needs the imports it was merged into
+			for importID := modelLegacyImport.ID; importID != 0 && !mapKept[importID]; importID = orderLegacyImportDatasets[importID].baseID {
+				mapKept[importID] = true
+			}
+		}
+	}
+
+	for _, modelLegacyImport := range orderModelLegacyImports {
+		if mapKept[modelLegacyImport.ID] {
+			modelLegacyImports = append(modelLegacyImports, modelLegacyImport)
+		} else {
+			delete(orderLegacyImportDatasets, modelLegacyImport.ID)
+		}
+	}
+
+	orderModelLegacyImports = modelLegacyImports
+
+	return nil
+}
+
+// LegacyStagePromote makes the staged dataset and its rejects live, including
+// its import in the history, and empties the staging
+func (*InMemoryOrder) LegacyStagePromote() (*model.LegacyImport, error) {
+	orderMutex.Lock()
+	defer orderMutex.Unlock()
+
+	if orderStaging.legacyImport == nil {
+		return nil, repository.ErrNotFound{Message: "not found"}
+	}
+
+	modelLegacyImport := *orderStaging.legacyImport
+
+	orderLive.setDataset(orderStaging.users, orderStaging.orders, orderStaging.ordersProducts)
+	orderLive.legacyRejects = orderStaging.legacyRejects
+	orderLegacyImportInsert(&modelLegacyImport, orderDataset{users: orderLive.users, orders: orderLive.orders, ordersProducts: orderLive.ordersProducts, legacyRejects: orderLive.legacyRejects})
+
+	*orderStaging = *newOrderStore()
+
+	return &modelLegacyImport, nil
+}
+
+// legacyImportInsert includes the import of the live dataset in the history,
+// the import of the staged dataset is kept apart until it is promoted
+func (inMemoryOrder *InMemoryOrder) legacyImportInsert(modelLegacyImport *model.LegacyImport, dataset orderDataset) {
+	if inMemoryOrder.store == orderStaging {
+		modelLegacyImportStaged := *modelLegacyImport
+		orderStaging.legacyImport = &modelLegacyImportStaged
+
+		return
+	}
+
+	orderLegacyImportInsert(modelLegacyImport, dataset)
+}
+
+// orderLegacyImportInsert includes the import in the history as the live one,
+// keeping the dataset of the import to be restored when it is restorable
+func orderLegacyImportInsert(modelLegacyImport *model.LegacyImport, dataset orderDataset) {
+	modelLegacyImport.ID = 1
+	modelLegacyImport.Live = true
+
+	if len(orderModelLegacyImports) > 0 {
+		modelLegacyImport.ID = orderModelLegacyImports[len(orderModelLegacyImports)-1].ID + 1
+	}
+
+	for index := range orderModelLegacyImports {
+		// the merged records alone do not restore the dataset
+		if orderModelLegacyImports[index].ID == dataset.baseID && !orderModelLegacyImports[index].Restorable {
+			modelLegacyImport.Restorable = false
+		}
+
+		orderModelLegacyImports[index].Live = false
+	}
+
+	orderModelLegacyImports = append(orderModelLegacyImports, *modelLegacyImport)
+
+	if modelLegacyImport.Restorable {
+		orderLegacyImportDatasets[modelLegacyImport.ID] = dataset
+	}
+}
+
+// orderLegacyImportChain returns the datasets needed to restore the import,
+// from the whole dataset up to the import, following the imports each merge
+// was merged into
+func orderLegacyImportChain(importID int64) ([]orderDataset, bool) {
+	datasets := []orderDataset{}
+
+	for ; importID != 0; importID = datasets[0].baseID {
+		dataset, ok := orderLegacyImportDatasets[importID]
+
+		if !ok {
+			return nil, false
+		}
+
+		datasets = append([]orderDataset{dataset}, datasets...)
+	}
+
+	return datasets, true
 }
 
-func (*InMemoryOrder) LegacyBulkInsert(modelUsers *model.Users, modelOrders *model.Orders, modelOrdersProducts *model.OrdersProducts) error {
-	orderModelUsers = *modelUsers
-	orderModelOrders = *modelOrders
-	orderModelOrdersProducts = *modelOrdersProducts
+// orderLegacyImportLiveID returns the ID of the live import, zero when there
+// is no live import
+func orderLegacyImportLiveID() int64 {
+	for _, modelLegacyImport := range orderModelLegacyImports {
+		if modelLegacyImport.Live {
+			return modelLegacyImport.ID
+		}
+	}
+
+	return 0
+}
 
+// orderDatasetUpsert returns the dataset with the records of the merge
+// upserted, the products of an upserted order are replaced by the merged ones
+// and its total is recalculated. The rejects are the ones of the merge.
+func orderDatasetUpsert(dataset orderDataset, merge orderDataset) orderDataset {
+	mapUsers := make(map[int64]int)
+	mapOrders := make(map[int64]int)
+
+	users := append(model.Users{}, dataset.users...)
+	orders := append(model.Orders{}, dataset.orders...)
+	ordersProducts := model.OrdersProducts{}
+
+	for userIndex, modelUser := range users {
+		mapUsers[modelUser.ID] = userIndex
+	}
+
+	for orderIndex, modelOrder := range orders {
+		mapOrders[modelOrder.ID] = orderIndex
+	}
+
+	for _, modelUser := range merge.users {
+		if userIndex, ok := mapUsers[modelUser.ID]; ok {
+			users[userIndex] = modelUser
+		} else {
+			mapUsers[modelUser.ID] = len(users)
+			users = append(users, modelUser)
+		}
+	}
+
+	// totals of the upserted orders
+	mapOrdersUpserted := make(map[int64]model.Money)
+
+	for _, modelOrder := range merge.orders {
+		mapOrdersUpserted[modelOrder.ID] = 0
+	}
+
+	for _, modelOrderProduct := range merge.ordersProducts {
+		mapOrdersUpserted[modelOrderProduct.OrderID] += modelOrderProduct.ProductValue
+	}
+
+	for _, modelOrder := range merge.orders {
+		modelOrder.Total = mapOrdersUpserted[modelOrder.ID]
+
+		if orderIndex, ok := mapOrders[modelOrder.ID]; ok {
+			orders[orderIndex] = modelOrder
+		} else {
+			mapOrders[modelOrder.ID] = len(orders)
+			orders = append(orders, modelOrder)
+		}
+	}
+
+	for _, modelOrderProduct := range dataset.ordersProducts {
+		if _, ok := mapOrdersUpserted[modelOrderProduct.OrderID]; !ok {
+			ordersProducts = append(ordersProducts, modelOrderProduct)
+		}
+	}
+
+	ordersProducts = append(ordersProducts, merge.ordersProducts...)
+
+	return orderDataset{
+		users:          users,
+		orders:         orders,
+		ordersProducts: ordersProducts,
+		legacyRejects:  merge.legacyRejects,
+	}
+}
+
+// orderDatasetLoad reads all the chunks of the imported dataset
+func orderDatasetLoad(legacyDataset repository.LegacyDataset) (orderDataset, error) {
+	dataset := orderDataset{
+		users:          model.Users{},
+		orders:         model.Orders{},
+		ordersProducts: model.OrdersProducts{},
+		legacyRejects:  model.LegacyRejects{},
+	}
+
+	err := legacyDataset.Users(func(modelUsers *model.Users) error {
+		dataset.users = append(dataset.users, *modelUsers...)
+		return nil
+	})
+
+	if err == nil {
+		err = legacyDataset.Orders(func(modelOrders *model.Orders) error {
+			dataset.orders = append(dataset.orders, *modelOrders...)
+			return nil
+		})
+	}
+
+	if err == nil {
+		err = legacyDataset.OrdersProducts(func(modelOrdersProducts *model.OrdersProducts) error {
+			dataset.ordersProducts = append(dataset.ordersProducts, *modelOrdersProducts...)
+			return nil
+		})
+	}
+
+	if err == nil {
+		err = legacyDataset.Rejects(func(modelLegacyRejects *model.LegacyRejects) error {
+			dataset.legacyRejects = append(dataset.legacyRejects, *modelLegacyRejects...)
+			return nil
+		})
+	}
+
+	return dataset, err
+}
+
+// setDataset replaces the dataset of the store and rebuilds its indexes
+func (store *orderStore) setDataset(modelUsers model.Users, modelOrders model.Orders, modelOrdersProducts model.OrdersProducts) {
 	mapUsers := make(map[int64]int)
 	mapOrders := make(map[int64]int)
 	mapOrdersProducts := make(map[int64][]int)
+	mapProductsOrdersProducts := make(map[int64][]int)
 
 	pos := 0
 
-	for pos < len(orderModelUsers) {
-		mapUsers[orderModelUsers[pos].ID] = pos
-		mapOrders[orderModelOrders[pos].ID] = pos
-		mapOrdersProducts[orderModelOrdersProducts[pos].OrderID] = append(mapOrdersProducts[orderModelOrdersProducts[pos].OrderID], pos)
+	for pos < len(modelUsers) || pos < len(modelOrders) || pos < len(modelOrdersProducts) {
+		if pos < len(modelUsers) {
+			mapUsers[modelUsers[pos].ID] = pos
+		}
+
+		if pos < len(modelOrders) {
+			mapOrders[modelOrders[pos].ID] = pos
+		}
+
+		if pos < len(modelOrdersProducts) {
+			mapOrdersProducts[modelOrdersProducts[pos].OrderID] = append(mapOrdersProducts[modelOrdersProducts[pos].OrderID], pos)
+			mapProductsOrdersProducts[modelOrdersProducts[pos].ProductID] = append(mapProductsOrdersProducts[modelOrdersProducts[pos].ProductID], pos)
+		}
+
 		pos++
 	}
 
-	for pos < len(orderModelOrders) {
-		mapOrders[orderModelOrders[pos].ID] = pos
-		mapOrdersProducts[orderModelOrdersProducts[pos].OrderID] = append(mapOrdersProducts[orderModelOrdersProducts[pos].OrderID], pos)
-		pos++
+	store.users = modelUsers
+	store.orders = modelOrders
+	store.ordersProducts = modelOrdersProducts
+	store.mapUsers = mapUsers
+	store.mapOrders = mapOrders
+	store.mapOrdersProducts = mapOrdersProducts
+	store.mapProductsOrdersProducts = mapProductsOrdersProducts
+
+	ordersSorted := make([]int, len(modelOrders))
+
+	for orderIndex := range ordersSorted {
+		ordersSorted[orderIndex] = orderIndex
 	}
 
-	for pos < len(orderModelOrdersProducts) {
-		mapOrdersProducts[orderModelOrdersProducts[pos].OrderID] = append(mapOrdersProducts[orderModelOrdersProducts[pos].OrderID], pos)
-		pos++
+	sort.Slice(ordersSorted, func(i, j int) bool {
+		modelOrderI, modelOrderJ := &modelOrders[ordersSorted[i]], &modelOrders[ordersSorted[j]]
+
+		return modelOrderI.UserID < modelOrderJ.UserID || (modelOrderI.UserID == modelOrderJ.UserID && modelOrderI.ID < modelOrderJ.ID)
+	})
+
+	mapUsersOrders := make(map[int64][]int)
+
+	for _, orderIndex := range ordersSorted {
+		mapUsersOrders[modelOrders[orderIndex].UserID] = append(mapUsersOrders[modelOrders[orderIndex].UserID], orderIndex)
 	}
 
-	orderMapUsers = mapUsers
-	orderMapOrders = mapOrders
-	orderMapOrdersProducts = mapOrdersProducts
+	store.ordersSorted = ordersSorted
+	store.mapUsersOrders = mapUsersOrders
 
-	return nil
+	usersSorted := make([]int, len(modelUsers))
+
+	for userIndex := range usersSorted {
+		usersSorted[userIndex] = userIndex
+	}
+
+	sort.Slice(usersSorted, func(i, j int) bool {
+		return modelUsers[usersSorted[i]].ID < modelUsers[usersSorted[j]].ID
+	})
+
+	store.usersSorted = usersSorted
 }
 
 func (inMemoryOrder *InMemoryOrder) GetDetailsByOrderID(orderID int64) (*model.OrderDetails, error) {
-	orderIndex, ok := orderMapOrders[orderID]
+	orderMutex.RLock()
+	defer orderMutex.RUnlock()
+
+	orderIndex, ok := inMemoryOrder.store.mapOrders[orderID]
 
 	if !ok {
 		return nil, repository.ErrNotFound{Message: "not found"}
@@ -66,55 +530,187 @@ func (inMemoryOrder *InMemoryOrder) GetDetailsByOrderID(orderID int64) (*model.O
 	modelOrdersDetails := model.OrdersDetails{}
 	mapOrdersDetails := make(map[int64]int)
 
-	inMemoryOrder.convertToDetails(&modelOrdersDetails, mapOrdersDetails, &orderModelOrders[orderIndex])
+	inMemoryOrder.convertToDetails(&modelOrdersDetails, mapOrdersDetails, &inMemoryOrder.store.orders[orderIndex])
 
 	return &(modelOrdersDetails)[0], nil
 }
 
-func (inMemoryOrder *InMemoryOrder) ListDetailsByRangeBuyDate(modelOrderRangeBuyDate *model.OrderRangeBuyDate) (*model.OrdersDetails, error) {
-	orderRangeBuyDateFrom := modelOrderRangeBuyDate.From.Format("2006-01-02")
-	orderRangeBuyDateTo := modelOrderRangeBuyDate.To.Format("2006-01-02")
+func (inMemoryOrder *InMemoryOrder) ListDetails(modelOrderFilter *model.OrderFilter, modelOrderPage *model.OrderPage) (*model.OrdersDetailsPage, error) {
+	orderMutex.RLock()
+	defer orderMutex.RUnlock()
 
-	modelOrdersDetails := model.OrdersDetails{}
-	mapOrdersDetails := make(map[int64]int)
+	ordersSorted := inMemoryOrder.store.ordersIndexed(modelOrderFilter)
+
+	return inMemoryOrder.listDetailsPage(ordersSorted, modelOrderPage, newOrderFilterAccept(inMemoryOrder.store, modelOrderFilter))
+}
 
-	for _, modelOrder := range orderModelOrders {
-		if modelOrder.BuyDate >= orderRangeBuyDateFrom && modelOrder.BuyDate <= orderRangeBuyDateTo {
-			inMemoryOrder.convertToDetails(&modelOrdersDetails, mapOrdersDetails, &modelOrder)
+// ordersIndexed returns the indexes of the candidate orders of the filter in
+// the sort by user and order, taken from the index of the IDs informed
+// instead of all the orders
+func (store *orderStore) ordersIndexed(modelOrderFilter *model.OrderFilter) []int {
+	ordersIndexed := []int{}
+
+	switch {
+	case len(modelOrderFilter.OrderIDs) > 0:
+		for _, orderID := range modelOrderFilter.OrderIDs {
+			if orderIndex, ok := store.mapOrders[orderID]; ok {
+				ordersIndexed = append(ordersIndexed, orderIndex)
+			}
+		}
+	case len(modelOrderFilter.UserIDs) > 0:
+		for _, userID := range modelOrderFilter.UserIDs {
+			ordersIndexed = append(ordersIndexed, store.mapUsersOrders[userID]...)
 		}
+	case modelOrderFilter.ProductID != nil:
+		for _, orderProductIndex := range store.mapProductsOrdersProducts[*modelOrderFilter.ProductID] {
+			ordersIndexed = append(ordersIndexed, store.mapOrders[store.ordersProducts[orderProductIndex].OrderID])
+		}
+	default:
+		return store.ordersSorted
 	}
 
-	if len(modelOrdersDetails) == 0 {
-		return nil, repository.ErrNotFound{Message: "not found"}
+	sort.Slice(ordersIndexed, func(i, j int) bool {
+		modelOrderI, modelOrderJ := &store.orders[ordersIndexed[i]], &store.orders[ordersIndexed[j]]
+
+		return modelOrderI.UserID < modelOrderJ.UserID || (modelOrderI.UserID == modelOrderJ.UserID && modelOrderI.ID < modelOrderJ.ID)
+	})
+
+	// the IDs informed more than once and the orders with the product more
+	// than once are kept once
+	ordersUnique := ordersIndexed[:0]
+
+	for pos, orderIndex := range ordersIndexed {
+		if pos == 0 || orderIndex != ordersIndexed[pos-1] {
+			ordersUnique = append(ordersUnique, orderIndex)
+		}
 	}
 
-	return &modelOrdersDetails, nil
+	return ordersUnique
 }
 
-func (inMemoryOrder *InMemoryOrder) ListDetails() (*model.OrdersDetails, error) {
-	modelOrdersDetails := model.OrdersDetails{}
+// newOrderFilterAccept returns the check of all the filters on an order
+func newOrderFilterAccept(store *orderStore, modelOrderFilter *model.OrderFilter) func(modelOrder *model.Order) bool {
+	var buyDateFrom, buyDateTo string
+
+	if modelOrderFilter.BuyDate != nil {
+		buyDateFrom = modelOrderFilter.BuyDate.From.Format("2006-01-02")
+		buyDateTo = modelOrderFilter.BuyDate.To.Format("2006-01-02")
+	}
+
+	mapUserIDs := make(map[int64]bool)
+
+	for _, userID := range modelOrderFilter.UserIDs {
+		mapUserIDs[userID] = true
+	}
+
+	mapOrderIDs := make(map[int64]bool)
+
+	for _, orderID := range modelOrderFilter.OrderIDs {
+		mapOrderIDs[orderID] = true
+	}
+
+	name := strings.ToLower(modelOrderFilter.Name)
+
+	return func(modelOrder *model.Order) bool {
+		if modelOrderFilter.BuyDate != nil && (modelOrder.BuyDate < buyDateFrom || modelOrder.BuyDate > buyDateTo) {
+			return false
+		}
+
+		if len(mapUserIDs) > 0 && !mapUserIDs[modelOrder.UserID] {
+			return false
+		}
+
+		if len(mapOrderIDs) > 0 && !mapOrderIDs[modelOrder.ID] {
+			return false
+		}
+
+		if (modelOrderFilter.MinTotal != nil && modelOrder.Total < *modelOrderFilter.MinTotal) || (modelOrderFilter.MaxTotal != nil && modelOrder.Total > *modelOrderFilter.MaxTotal) {
+			return false
+		}
+
+		if name != "" && !strings.Contains(strings.ToLower(store.users[store.mapUsers[modelOrder.UserID]].Name), name) {
+			return false
+		}
+
+		if !modelOrderFilter.ProductFiltered() {
+			return true
+		}
+
+		for _, orderProductIndex := range store.mapOrdersProducts[modelOrder.ID] {
+			modelOrderProduct := &store.ordersProducts[orderProductIndex]
+
+			if (modelOrderFilter.ProductID == nil || modelOrderProduct.ProductID == *modelOrderFilter.ProductID) &&
+				(modelOrderFilter.MinValue == nil || modelOrderProduct.ProductValue >= *modelOrderFilter.MinValue) &&
+				(modelOrderFilter.MaxValue == nil || modelOrderProduct.ProductValue <= *modelOrderFilter.MaxValue) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// listDetailsPage groups the orders accepted by the filter from the position
+// of the cursor, following the indexes of the orders sorted by user and order
+func (inMemoryOrder *InMemoryOrder) listDetailsPage(ordersSorted []int, modelOrderPage *model.OrderPage, accept func(modelOrder *model.Order) bool) (*model.OrdersDetailsPage, error) {
+	store := inMemoryOrder.store
+
+	start := 0
+
+	if modelOrderCursor := modelOrderPage.Cursor; modelOrderCursor != nil {
+		start = sort.Search(len(ordersSorted), func(i int) bool {
+			return orderAfter(&store.orders[ordersSorted[i]], modelOrderCursor)
+		})
+	}
+
+	modelOrdersDetailsPage := &model.OrdersDetailsPage{OrdersDetails: model.OrdersDetails{}}
 	mapOrdersDetails := make(map[int64]int)
+	var modelOrderLast *model.Order
+	count := 0
 
-	for _, modelOrder := range orderModelOrders {
-		inMemoryOrder.convertToDetails(&modelOrdersDetails, mapOrdersDetails, &modelOrder)
+	for _, orderIndex := range ordersSorted[start:] {
+		modelOrder := &store.orders[orderIndex]
+
+		if !accept(modelOrder) {
+			continue
+		}
+
+		// there is a next page only when another order is accepted
+		if modelOrderPage.Limit > 0 && count == modelOrderPage.Limit {
+			modelOrdersDetailsPage.Next = &model.OrderCursor{UserID: modelOrderLast.UserID, OrderID: modelOrderLast.ID}
+			break
+		}
+
+		inMemoryOrder.convertToDetails(&modelOrdersDetailsPage.OrdersDetails, mapOrdersDetails, modelOrder)
+
+		modelOrderLast = modelOrder
+		count++
 	}
 
-	if len(modelOrdersDetails) == 0 {
+	if count == 0 {
 		return nil, repository.ErrNotFound{Message: "not found"}
 	}
 
-	return &modelOrdersDetails, nil
+	return modelOrdersDetailsPage, nil
 }
 
-func (*InMemoryOrder) convertToDetails(modelOrdersDetails *model.OrdersDetails, mapOrdersDetails map[int64]int, modelOrder *model.Order) {
-	userIndex := orderMapUsers[modelOrder.UserID]
+// orderAfter tells if the order comes after the cursor in the sort by user
+// and order
+func orderAfter(modelOrder *model.Order, modelOrderCursor *model.OrderCursor) bool {
+	return modelOrder.UserID > modelOrderCursor.UserID || (modelOrder.UserID == modelOrderCursor.UserID && modelOrder.ID > modelOrderCursor.OrderID)
+}
+
+func (inMemoryOrder *InMemoryOrder) convertToDetails(modelOrdersDetails *model.OrdersDetails, mapOrdersDetails map[int64]int, modelOrder *model.Order) {
+	store := inMemoryOrder.store
+
+	userIndex := store.mapUsers[modelOrder.UserID]
 
-	modelUser := &orderModelUsers[userIndex]
+	modelUser := &store.users[userIndex]
 
 	modelOrderDetailsProducts := []model.OrderDetailsProduct{}
 
-	for _, orderProductIndex := range orderMapOrdersProducts[modelOrder.ID] {
-		modelOrderProduct := orderModelOrdersProducts[orderProductIndex]
+	for _, orderProductIndex := range store.mapOrdersProducts[modelOrder.ID] {
+		modelOrderProduct := store.ordersProducts[orderProductIndex]
 		modelOrderDetailsProducts = append(modelOrderDetailsProducts, model.OrderDetailsProduct{
 			ID:    modelOrderProduct.ProductID,
 			Value: modelOrderProduct.ProductValue,
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+)
+
+// InMemoryProduct queries the products of the same dataset of the orders by
+// the index of the products of the orders built with the dataset
+type InMemoryProduct struct {
+	store *orderStore
+}
+
+func NewProduct() repository.Product {
+	return &InMemoryProduct{store: orderLive}
+}
+
+func (inMemoryProduct *InMemoryProduct) GetSummaryByProductID(productID int64) (*model.ProductSummary, error) {
+	orderMutex.RLock()
+	defer orderMutex.RUnlock()
+
+	store := inMemoryProduct.store
+
+	ordersProducts := store.mapProductsOrdersProducts[productID]
+
+	if len(ordersProducts) == 0 {
+		return nil, repository.ErrNotFound{Message: "not found"}
+	}
+
+	modelProductSummary := &model.ProductSummary{ProductID: productID}
+	mapBuyers := make(map[int64]bool)
+	var total model.Money
+
+	for _, orderProductIndex := range ordersProducts {
+		modelOrderProduct := &store.ordersProducts[orderProductIndex]
+		modelOrder := &store.orders[store.mapOrders[modelOrderProduct.OrderID]]
+
+		if modelProductSummary.TimesSold == 0 {
+			modelProductSummary.MinValue = modelOrderProduct.ProductValue
+			modelProductSummary.MaxValue = modelOrderProduct.ProductValue
+			modelProductSummary.FirstSaleDate = modelOrder.BuyDate
+			modelProductSummary.LastSaleDate = modelOrder.BuyDate
+		}
+
+		if modelOrderProduct.ProductValue < modelProductSummary.MinValue {
+			modelProductSummary.MinValue = modelOrderProduct.ProductValue
+		}
+
+		if modelOrderProduct.ProductValue > modelProductSummary.MaxValue {
+			modelProductSummary.MaxValue = modelOrderProduct.ProductValue
+		}
+
+		if modelOrder.BuyDate < modelProductSummary.FirstSaleDate {
+			modelProductSummary.FirstSaleDate = modelOrder.BuyDate
+		}
+
+		if modelOrder.BuyDate > modelProductSummary.LastSaleDate {
+			modelProductSummary.LastSaleDate = modelOrder.BuyDate
+		}
+
+		mapBuyers[modelOrder.UserID] = true
+		total += modelOrderProduct.ProductValue
+		modelProductSummary.TimesSold++
+	}
+
+	modelProductSummary.Buyers = len(mapBuyers)
+	modelProductSummary.AvgValue = total.Average(modelProductSummary.TimesSold)
+
+	return modelProductSummary, nil
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"sort"
+	"time"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+)
+
+// InMemoryReport aggregates the orders of the same dataset with a pass over
+// the orders of the period
+type InMemoryReport struct {
+	store *orderStore
+}
+
+func NewReport() repository.Report {
+	return &InMemoryReport{store: orderLive}
+}
+
+func (inMemoryReport *InMemoryReport) ListRevenue(modelReportFilter *model.ReportFilter) (*model.ReportsRevenue, error) {
+	orderMutex.RLock()
+	defer orderMutex.RUnlock()
+
+	modelReportsRevenue := model.ReportsRevenue{}
+	mapPeriods := make(map[string]int)
+
+	inMemoryReport.store.ordersEach(modelReportFilter, func(modelOrder *model.Order) {
+		period := reportPeriod(modelOrder.BuyDate, modelReportFilter.Period)
+
+		periodIndex, ok := mapPeriods[period]
+
+		if !ok {
+			modelReportsRevenue = append(modelReportsRevenue, model.ReportRevenue{Period: period})
+			periodIndex = len(modelReportsRevenue) - 1
+			mapPeriods[period] = periodIndex
+		}
+
+		modelReportsRevenue[periodIndex].Orders++
+		modelReportsRevenue[periodIndex].Revenue += modelOrder.Total
+	})
+
+	if len(modelReportsRevenue) == 0 {
+		return nil, repository.ErrNotFound{Message: "not found"}
+	}
+
+	sort.Slice(modelReportsRevenue, func(i, j int) bool {
+		return modelReportsRevenue[i].Period < modelReportsRevenue[j].Period
+	})
+
+	return &modelReportsRevenue, nil
+}
+
+func (inMemoryReport *InMemoryReport) ListTopUsers(modelReportFilter *model.ReportFilter) (*model.ReportTopUsers, error) {
+	orderMutex.RLock()
+	defer orderMutex.RUnlock()
+
+	store := inMemoryReport.store
+
+	modelReportTopUsers := model.ReportTopUsers{}
+	mapUsers := make(map[int64]int)
+
+	store.ordersEach(modelReportFilter, func(modelOrder *model.Order) {
+		userIndex, ok := mapUsers[modelOrder.UserID]
+
+		if !ok {
+			modelReportTopUsers = append(modelReportTopUsers, model.ReportTopUser{
+				UserID:   modelOrder.UserID,
+				UserName: store.users[store.mapUsers[modelOrder.UserID]].Name,
+			})
+			userIndex = len(modelReportTopUsers) - 1
+			mapUsers[modelOrder.UserID] = userIndex
+		}
+
+		modelReportTopUsers[userIndex].Orders++
+		modelReportTopUsers[userIndex].Total += modelOrder.Total
+	})
+
+	if len(modelReportTopUsers) == 0 {
+		return nil, repository.ErrNotFound{Message: "not found"}
+	}
+
+	sort.Slice(modelReportTopUsers, func(i, j int) bool {
+		modelReportTopUserI, modelReportTopUserJ := &modelReportTopUsers[i], &modelReportTopUsers[j]
+
+		return modelReportTopUserI.Total > modelReportTopUserJ.Total ||
+			(modelReportTopUserI.Total == modelReportTopUserJ.Total && modelReportTopUserI.UserID < modelReportTopUserJ.UserID)
+	})
+
+	if modelReportFilter.Size > 0 && len(modelReportTopUsers) > modelReportFilter.Size {
+		modelReportTopUsers = modelReportTopUsers[:modelReportFilter.Size]
+	}
+
+	return &modelReportTopUsers, nil
+}
+
+func (inMemoryReport *InMemoryReport) ListTopProducts(modelReportFilter *model.ReportFilter) (*model.ReportTopProducts, error) {
+	orderMutex.RLock()
+	defer orderMutex.RUnlock()
+
+	store := inMemoryReport.store
+
+	modelReportTopProducts := model.ReportTopProducts{}
+	mapProducts := make(map[int64]int)
+
+	store.ordersEach(modelReportFilter, func(modelOrder *model.Order) {
+		for _, orderProductIndex := range store.mapOrdersProducts[modelOrder.ID] {
+			modelOrderProduct := &store.ordersProducts[orderProductIndex]
+
+			productIndex, ok := mapProducts[modelOrderProduct.ProductID]
+
+			if !ok {
+				modelReportTopProducts = append(modelReportTopProducts, model.ReportTopProduct{ProductID: modelOrderProduct.ProductID})
+				productIndex = len(modelReportTopProducts) - 1
+				mapProducts[modelOrderProduct.ProductID] = productIndex
+			}
+
+			modelReportTopProducts[productIndex].TimesSold++
+			modelReportTopProducts[productIndex].Revenue += modelOrderProduct.ProductValue
+		}
+	})
+
+	if len(modelReportTopProducts) == 0 {
+		return nil, repository.ErrNotFound{Message: "not found"}
+	}
+
+	sort.Slice(modelReportTopProducts, func(i, j int) bool {
+		modelReportTopProductI, modelReportTopProductJ := &modelReportTopProducts[i], &modelReportTopProducts[j]
+
+		return modelReportTopProductI.Revenue > modelReportTopProductJ.Revenue ||
+			(modelReportTopProductI.Revenue == modelReportTopProductJ.Revenue && modelReportTopProductI.ProductID < modelReportTopProductJ.ProductID)
+	})
+
+	if modelReportFilter.Size > 0 && len(modelReportTopProducts) > modelReportFilter.Size {
+		modelReportTopProducts = modelReportTopProducts[:modelReportFilter.Size]
+	}
+
+	return &modelReportTopProducts, nil
+}
+
+func (inMemoryReport *InMemoryReport) GetBasket(modelReportFilter *model.ReportFilter) (*model.ReportBasket, error) {
+	orderMutex.RLock()
+	defer orderMutex.RUnlock()
+
+	store := inMemoryReport.store
+
+	orders, products := 0, 0
+	var revenue model.Money
+
+	store.ordersEach(modelReportFilter, func(modelOrder *model.Order) {
+		orders++
+		products += len(store.mapOrdersProducts[modelOrder.ID])
+		revenue += modelOrder.Total
+	})
+
+	if orders == 0 {
+		return nil, repository.ErrNotFound{Message: "not found"}
+	}
+
+	return model.NewReportBasket(orders, products, revenue), nil
+}
+
+// ordersEach visits the orders of the period of the report
+func (store *orderStore) ordersEach(modelReportFilter *model.ReportFilter, visit func(modelOrder *model.Order)) {
+	buyDateFrom, buyDateTo := "", ""
+
+	if modelReportFilter.BuyDate != nil {
+		buyDateFrom = modelReportFilter.BuyDate.From.Format("2006-01-02")
+		buyDateTo = modelReportFilter.BuyDate.To.Format("2006-01-02")
+	}
+
+	for orderIndex := range store.orders {
+		modelOrder := &store.orders[orderIndex]
+
+		if modelReportFilter.BuyDate != nil && (modelOrder.BuyDate < buyDateFrom || modelOrder.BuyDate > buyDateTo) {
+			continue
+		}
+
+		visit(modelOrder)
+	}
+}
+
+// reportPeriod returns the first day of the period of the buy date, the
+// weeks start on monday as the date_trunc of the database
+func reportPeriod(buyDate string, period string) string {
+	date, err := time.Parse("2006-01-02", buyDate)
+
+	if err != nil {
+		return buyDate
+	}
+
+	switch period {
+	case model.ReportPeriodWeek:
+		date = date.AddDate(0, 0, -(int(date.Weekday())+6)%7)
+	case model.ReportPeriodMonth:
+		date = time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	return date.Format("2006-01-02")
+}
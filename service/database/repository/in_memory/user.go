@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+)
+
+// InMemoryUser queries the users of the same dataset of the orders
+type InMemoryUser struct {
+	store *orderStore
+}
+
+func NewUser() repository.User {
+	return &InMemoryUser{store: orderLive}
+}
+
+// GetDetailsByUserID returns all the orders of the user, none when the orders
+// of the user were moved to another user by a merge
+func (inMemoryUser *InMemoryUser) GetDetailsByUserID(userID int64) (*model.OrderDetails, error) {
+	orderMutex.RLock()
+	defer orderMutex.RUnlock()
+
+	store := inMemoryUser.store
+
+	userIndex, ok := store.mapUsers[userID]
+
+	if !ok {
+		return nil, repository.ErrNotFound{Message: "not found"}
+	}
+
+	modelOrdersDetails := model.OrdersDetails{}
+	mapOrdersDetails := make(map[int64]int)
+	inMemoryOrder := &InMemoryOrder{store: store}
+
+	for _, orderIndex := range store.mapUsersOrders[userID] {
+		inMemoryOrder.convertToDetails(&modelOrdersDetails, mapOrdersDetails, &store.orders[orderIndex])
+	}
+
+	if len(modelOrdersDetails) == 0 {
+		return &model.OrderDetails{
+			UserID:   userID,
+			UserName: store.users[userIndex].Name,
+			Orders:   []model.OrderDetailsOrder{},
+		}, nil
+	}
+
+	return &modelOrdersDetails[0], nil
+}
+
+func (inMemoryUser *InMemoryUser) ListSummaries(modelUserFilter *model.UserFilter, modelUserPage *model.UserPage) (*model.UserSummariesPage, error) {
+	orderMutex.RLock()
+	defer orderMutex.RUnlock()
+
+	store := inMemoryUser.store
+
+	start := 0
+
+	if modelUserCursor := modelUserPage.Cursor; modelUserCursor != nil {
+		start = sort.Search(len(store.usersSorted), func(i int) bool {
+			return store.users[store.usersSorted[i]].ID > modelUserCursor.UserID
+		})
+	}
+
+	name := strings.ToLower(modelUserFilter.Name)
+
+	modelUserSummariesPage := &model.UserSummariesPage{UserSummaries: model.UserSummaries{}}
+
+	for _, userIndex := range store.usersSorted[start:] {
+		modelUser := &store.users[userIndex]
+
+		if name != "" && !strings.Contains(strings.ToLower(modelUser.Name), name) {
+			continue
+		}
+
+		// there is a next page only when another user is accepted
+		if modelUserPage.Limit > 0 && len(modelUserSummariesPage.UserSummaries) == modelUserPage.Limit {
+			modelUserSummaryLast := modelUserSummariesPage.UserSummaries[len(modelUserSummariesPage.UserSummaries)-1]
+			modelUserSummariesPage.Next = &model.UserCursor{UserID: modelUserSummaryLast.UserID}
+			break
+		}
+
+		modelUserSummariesPage.UserSummaries = append(modelUserSummariesPage.UserSummaries, store.userSummary(modelUser))
+	}
+
+	if len(modelUserSummariesPage.UserSummaries) == 0 {
+		return nil, repository.ErrNotFound{Message: "not found"}
+	}
+
+	return modelUserSummariesPage, nil
+}
+
+// userSummary aggregates the orders of the user
+func (store *orderStore) userSummary(modelUser *model.User) model.UserSummary {
+	modelUserSummary := model.UserSummary{
+		UserID:   modelUser.ID,
+		UserName: modelUser.Name,
+	}
+
+	for _, orderIndex := range store.mapUsersOrders[modelUser.ID] {
+		modelOrder := &store.orders[orderIndex]
+
+		if modelUserSummary.Orders == 0 || modelOrder.BuyDate < modelUserSummary.FirstBuyDate {
+			modelUserSummary.FirstBuyDate = modelOrder.BuyDate
+		}
+
+		if modelOrder.BuyDate > modelUserSummary.LastBuyDate {
+			modelUserSummary.LastBuyDate = modelOrder.BuyDate
+		}
+
+		modelUserSummary.Orders++
+		modelUserSummary.Total += modelOrder.Total
+	}
+
+	return modelUserSummary
+}
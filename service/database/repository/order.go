@@ -3,8 +3,24 @@ package repository
 import "github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
 
 type Order interface {
-	LegacyBulkInsert(modelUsers *model.Users, modelOrders *model.Orders, modelOrdersProducts *model.OrdersProducts) error
+	LegacyBulkInsert(modelLegacyImport *model.LegacyImport, legacyDataset LegacyDataset) error
+	LegacyBulkUpsert(modelLegacyImport *model.LegacyImport, legacyDataset LegacyDataset) ([]int64, error)
 	GetDetailsByOrderID(orderID int64) (*model.OrderDetails, error)
-	ListDetailsByRangeBuyDate(modelOrderRangeBuyDate *model.OrderRangeBuyDate) (*model.OrdersDetails, error)
-	ListDetails() (*model.OrdersDetails, error)
+	ListDetails(modelOrderFilter *model.OrderFilter, modelOrderPage *model.OrderPage) (*model.OrdersDetailsPage, error)
+	ListLegacyRejects() (*model.LegacyRejects, error)
+	ListLegacyImports() (*model.LegacyImports, error)
+	GetLegacyImportLive() (*model.LegacyImport, error)
+	LegacyImportRestore(importID int64) (*model.LegacyImport, error)
+	LegacyImportsPrune(size int) error
+	LegacyStagePromote() (*model.LegacyImport, error)
+}
+
+// LegacyDataset provides the records of an import in chunks, so the whole
+// file is never in memory. Each method calls persist once for each chunk and
+// stops at the first error returned.
+type LegacyDataset interface {
+	Users(persist func(modelUsers *model.Users) error) error
+	Orders(persist func(modelOrders *model.Orders) error) error
+	OrdersProducts(persist func(modelOrdersProducts *model.OrdersProducts) error) error
+	Rejects(persist func(modelLegacyRejects *model.LegacyRejects) error) error
 }
@@ -2,7 +2,9 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
 	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
@@ -17,49 +19,143 @@ const (
 	queryOrderDetails = `SELECT
 			o.id, o.buy_date, o.total, o.user_id, u.name, op.product_id, op.product_value
 		FROM
-			orders o
+			%[1]sorders o
 		LEFT JOIN
-			users u ON u.id = o.user_id
+			%[1]susers u ON u.id = o.user_id
 		LEFT JOIN
-			orders_product op ON op.order_id = o.id 
-		%s
+			%[1]sorders_product op ON op.order_id = o.id 
+		%[2]s
 		ORDER BY
 			o.user_id, o.id`
+
+	// the orders of the page are selected before the join with the products,
+	// in the sort of the index on (user_id, id) followed by the cursor
+	queryOrderDetailsPage = `SELECT
+			o.id, o.buy_date, o.total, o.user_id, u.name, op.product_id, op.product_value
+		FROM
+			(SELECT
+				id, buy_date, total, user_id
+			FROM
+				%[1]sorders
+			%[2]s
+			ORDER BY
+				user_id, id
+			LIMIT $%[3]d) o
+		LEFT JOIN
+			%[1]susers u ON u.id = o.user_id
+		LEFT JOIN
+			%[1]sorders_product op ON op.order_id = o.id
+		ORDER BY
+			o.user_id, o.id`
+
+	queryLegacyImports = `SELECT
+			id, imported_at, file_name, checksum, mode, users, orders, products, accepted, rejected, requested_by, idempotency_key, live, restorable
+		FROM
+			%[1]slegacy_imports
+		%[2]s`
 )
 
 func NewOrder(repository *Postgres) repository.Order {
 	return &PostgresOrder{Repository: repository}
 }
 
-func (postgresOrder *PostgresOrder) ListDetails() (*model.OrdersDetails, error) {
-	query := fmt.Sprintf(queryOrderDetails, "")
+func (postgresOrder *PostgresOrder) ListDetails(modelOrderFilter *model.OrderFilter, modelOrderPage *model.OrderPage) (*model.OrdersDetailsPage, error) {
+	conditions, args := postgresOrder.orderFilterConditions(modelOrderFilter)
 
-	rows, err := postgresOrder.Repository.Conn.Query(query)
+	return postgresOrder.listDetailsPage(modelOrderPage, conditions, args)
+}
 
-	if err != nil {
-		return nil, err
+// orderFilterConditions returns the conditions of the filters informed on the
+// orders selected by the page, the values are always passed as args
+func (postgresOrder *PostgresOrder) orderFilterConditions(modelOrderFilter *model.OrderFilter) ([]string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
 	}
 
-	defer rows.Close()
+	if modelOrderFilter.BuyDate != nil {
+		conditions = append(conditions, fmt.Sprintf("buy_date BETWEEN %s AND %s", arg(modelOrderFilter.BuyDate.From), arg(modelOrderFilter.BuyDate.To)))
+	}
 
-	modelOrdersDetails, err := postgresOrder.convertQueryResultToOrdersDetails(rows)
+	if len(modelOrderFilter.UserIDs) > 0 {
+		conditions = append(conditions, fmt.Sprintf("user_id = ANY(%s)", arg(pq.Array(modelOrderFilter.UserIDs))))
+	}
 
-	// repository error not found
-	if err != nil {
-		if err.Error() == sql.ErrNoRows.Error() {
-			err = repository.ErrNotFound{Message: err.Error()}
+	if len(modelOrderFilter.OrderIDs) > 0 {
+		conditions = append(conditions, fmt.Sprintf("id = ANY(%s)", arg(pq.Array(modelOrderFilter.OrderIDs))))
+	}
+
+	if modelOrderFilter.MinTotal != nil {
+		conditions = append(conditions, fmt.Sprintf("total >= %s", arg(*modelOrderFilter.MinTotal)))
+	}
+
+	if modelOrderFilter.MaxTotal != nil {
+		conditions = append(conditions, fmt.Sprintf("total <= %s", arg(*modelOrderFilter.MaxTotal)))
+	}
+
+	if modelOrderFilter.Name != "" {
+		conditions = append(conditions, fmt.Sprintf("user_id IN (SELECT u.id FROM %susers u WHERE u.name ILIKE %s)",
+			postgresOrder.Repository.TablePrefix, arg("%"+likeEscape(modelOrderFilter.Name)+"%")))
+	}
+
+	// the product and the range of value are checked on the same product
+	if modelOrderFilter.ProductFiltered() {
+		productConditions := []string{fmt.Sprintf("op.order_id = %sorders.id", postgresOrder.Repository.TablePrefix)}
+
+		if modelOrderFilter.ProductID != nil {
+			productConditions = append(productConditions, fmt.Sprintf("op.product_id = %s", arg(*modelOrderFilter.ProductID)))
 		}
-	} else if len(*modelOrdersDetails) == 0 {
-		err = repository.ErrNotFound{Message: sql.ErrNoRows.Error()}
+
+		if modelOrderFilter.MinValue != nil {
+			productConditions = append(productConditions, fmt.Sprintf("op.product_value >= %s", arg(*modelOrderFilter.MinValue)))
+		}
+
+		if modelOrderFilter.MaxValue != nil {
+			productConditions = append(productConditions, fmt.Sprintf("op.product_value <= %s", arg(*modelOrderFilter.MaxValue)))
+		}
+
+		conditions = append(conditions, fmt.Sprintf("EXISTS (SELECT 1 FROM %sorders_product op WHERE %s)",
+			postgresOrder.Repository.TablePrefix, strings.Join(productConditions, " AND ")))
 	}
 
-	return modelOrdersDetails, err
+	return conditions, args
+}
+
+// likeEscape escapes the wildcards of the LIKE patterns, so the text is
+// matched as it is
+func likeEscape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(text)
 }
 
-func (postgresOrder *PostgresOrder) ListDetailsByRangeBuyDate(modelOrderRangeBuyDate *model.OrderRangeBuyDate) (*model.OrdersDetails, error) {
-	query := fmt.Sprintf(queryOrderDetails, " WHERE o.buy_date BETWEEN $1 AND $2 ")
+// listDetailsPage selects the orders of the page with keyset pagination, the
+// conditions use the args from $1 and the cursor and the limit follow them
+func (postgresOrder *PostgresOrder) listDetailsPage(modelOrderPage *model.OrderPage, conditions []string, args []interface{}) (*model.OrdersDetailsPage, error) {
+	if modelOrderCursor := modelOrderPage.Cursor; modelOrderCursor != nil {
+		args = append(args, modelOrderCursor.UserID, modelOrderCursor.OrderID)
+		conditions = append(conditions, fmt.Sprintf("(user_id, id) > ($%d, $%d)", len(args)-1, len(args)))
+	}
+
+	where := ""
+
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// one order beyond the limit tells there is a next page, NULL is no limit
+	var limit interface{}
+
+	if modelOrderPage.Limit > 0 {
+		limit = modelOrderPage.Limit + 1
+	}
 
-	rows, err := postgresOrder.Repository.Conn.Query(query, modelOrderRangeBuyDate.From, modelOrderRangeBuyDate.To)
+	args = append(args, limit)
+
+	query := fmt.Sprintf(queryOrderDetailsPage, postgresOrder.Repository.TablePrefix, where, len(args))
+
+	rows, err := postgresOrder.Repository.Conn.Query(query, args...)
 
 	if err != nil {
 		return nil, err
@@ -78,11 +174,48 @@ func (postgresOrder *PostgresOrder) ListDetailsByRangeBuyDate(modelOrderRangeBuy
 		err = repository.ErrNotFound{Message: sql.ErrNoRows.Error()}
 	}
 
-	return modelOrdersDetails, err
+	if err != nil {
+		return nil, err
+	}
+
+	modelOrdersDetailsPage := &model.OrdersDetailsPage{OrdersDetails: *modelOrdersDetails}
+
+	if modelOrderPage.Limit > 0 {
+		modelOrdersDetailsPage.Next = ordersDetailsTrim(&modelOrdersDetailsPage.OrdersDetails, modelOrderPage.Limit)
+	}
+
+	return modelOrdersDetailsPage, nil
+}
+
+// ordersDetailsTrim removes the order selected beyond the limit of the page
+// and returns the cursor of the next page, nil when there is no such order
+func ordersDetailsTrim(modelOrdersDetails *model.OrdersDetails, limit int) *model.OrderCursor {
+	count := 0
+
+	for _, modelOrderDetails := range *modelOrdersDetails {
+		count += len(modelOrderDetails.Orders)
+	}
+
+	if count <= limit {
+		return nil
+	}
+
+	modelOrderDetails := &(*modelOrdersDetails)[len(*modelOrdersDetails)-1]
+	modelOrderDetails.Orders = modelOrderDetails.Orders[:len(modelOrderDetails.Orders)-1]
+
+	if len(modelOrderDetails.Orders) == 0 {
+		*modelOrdersDetails = (*modelOrdersDetails)[:len(*modelOrdersDetails)-1]
+		modelOrderDetails = &(*modelOrdersDetails)[len(*modelOrdersDetails)-1]
+	}
+
+	return &model.OrderCursor{
+		UserID:  modelOrderDetails.UserID,
+		OrderID: modelOrderDetails.Orders[len(modelOrderDetails.Orders)-1].OrderID,
+	}
 }
 
 func (postgresOrder *PostgresOrder) GetDetailsByOrderID(orderID int64) (*model.OrderDetails, error) {
-	query := fmt.Sprintf(queryOrderDetails, " WHERE o.id = $1 ")
+	query := fmt.Sprintf(queryOrderDetails, postgresOrder.Repository.TablePrefix, " WHERE o.id = $1 ")
 
 	rows, err := postgresOrder.Repository.Conn.Query(query, orderID)
 
@@ -110,25 +243,39 @@ func (postgresOrder *PostgresOrder) GetDetailsByOrderID(orderID int64) (*model.O
 	return &(*modelOrdersDetails)[0], nil
 }
 
-func (postgresOrder *PostgresOrder) LegacyBulkInsert(modelUsers *model.Users, modelOrders *model.Orders, modelOrdersProducts *model.OrdersProducts) error {
+func (postgresOrder *PostgresOrder) LegacyBulkInsert(modelLegacyImport *model.LegacyImport, legacyDataset repository.LegacyDataset) error {
 	tx, err := postgresOrder.Repository.Conn.Begin()
 
 	if err != nil {
 		return err
 	}
 
-	err = postgresOrder.legacyClearAll(tx)
+	err = postgresOrder.legacyClearAll(tx, postgresOrder.Repository.TablePrefix)
+
+	if err == nil {
+		err = legacyDataset.Users(func(modelUsers *model.Users) error {
+			return postgresOrder.legacyUserBulkInsert(modelUsers, tx)
+		})
+	}
+
+	if err == nil {
+		err = legacyDataset.Orders(func(modelOrders *model.Orders) error {
+			return postgresOrder.legacyOrderBulkInsert(modelOrders, tx)
+		})
+	}
 
 	if err == nil {
-		err = postgresOrder.legacyUserBulkInsert(modelUsers, tx)
+		err = legacyDataset.OrdersProducts(func(modelOrdersProducts *model.OrdersProducts) error {
+			return postgresOrder.legacyOrderProductBulkInsert(modelOrdersProducts, tx)
+		})
 	}
 
 	if err == nil {
-		err = postgresOrder.legacyOrderBulkInsert(modelOrders, tx)
+		err = postgresOrder.legacyRejectsReplace(legacyDataset, tx)
 	}
 
 	if err == nil {
-		err = postgresOrder.legacyOrderProductBulkInsert(modelOrdersProducts, tx)
+		err = postgresOrder.legacyImportInsert(modelLegacyImport, nil, nil, tx)
 	}
 
 	if err != nil {
@@ -147,112 +294,267 @@ func (postgresOrder *PostgresOrder) LegacyBulkInsert(modelUsers *model.Users, mo
 	return err
 }
 
-func (postgresOrder *PostgresOrder) legacyClearAll(tx *sql.Tx) error {
-	query := `TRUNCATE TABLE orders_product CASCADE;
-		TRUNCATE TABLE orders CASCADE;
-		TRUNCATE TABLE users CASCADE;`
+func (postgresOrder *PostgresOrder) LegacyBulkUpsert(modelLegacyImport *model.LegacyImport, legacyDataset repository.LegacyDataset) ([]int64, error) {
+	userIDs := []int64{}
+	orderIDs := []int64{}
 
-	_, err := tx.Exec(query)
+	tx, err := postgresOrder.Repository.Conn.Begin()
 
-	return err
-}
+	if err != nil {
+		return nil, err
+	}
 
-func (postgresOrder *PostgresOrder) legacyUserBulkInsert(modelUsers *model.Users, tx *sql.Tx) error {
-	for _, modelUser := range *modelUsers {
-		// userExists, err := postgresOrder.legacyUserCheckExistsByID(modelUser.ID)
+	err = legacyDataset.Users(func(modelUsers *model.Users) error {
+		for _, modelUser := range *modelUsers {
+			userIDs = append(userIDs, modelUser.ID)
+		}
 
-		// if err != nil {
-		// 	return err
-		// }
+		return postgresOrder.legacyUserBulkUpsert(modelUsers, tx)
+	})
 
-		// if userExists {
-		// 	continue
-		// }
+	if err == nil {
+		err = legacyDataset.Orders(func(modelOrders *model.Orders) error {
+			chunkOrderIDs := []int64{}
 
-		err := postgresOrder.legacyUserInser(&modelUser, tx)
+			for _, modelOrder := range *modelOrders {
+				chunkOrderIDs = append(chunkOrderIDs, modelOrder.ID)
+			}
 
-		if err != nil {
-			return err
+			orderIDs = append(orderIDs, chunkOrderIDs...)
+
+			err := postgresOrder.legacyOrderBulkUpsert(modelOrders, tx)
+
+			if err != nil {
+				return err
+			}
+
+			return postgresOrder.legacyOrderProductDeleteByOrderIDs(chunkOrderIDs, tx)
+		})
+	}
+
+	if err == nil {
+		err = legacyDataset.OrdersProducts(func(modelOrdersProducts *model.OrdersProducts) error {
+			return postgresOrder.legacyOrderProductBulkInsert(modelOrdersProducts, tx)
+		})
+	}
+
+	if err == nil {
+		err = postgresOrder.legacyOrderTotalRecalculate(orderIDs, tx)
+	}
+
+	var affectedOrderIDs []int64
+
+	if err == nil {
+		affectedOrderIDs, err = postgresOrder.legacyOrderAffectedIDs(orderIDs, userIDs, tx)
+	}
+
+	if err == nil {
+		err = postgresOrder.legacyRejectsReplace(legacyDataset, tx)
+	}
+
+	if err == nil {
+		err = postgresOrder.legacyImportInsert(modelLegacyImport, userIDs, orderIDs, tx)
+	}
+
+	if err != nil {
+		tx.Rollback()
+	} else {
+		err = tx.Commit()
+	}
+
+	// repository error duplicate key
+	if errPQ, ok := err.(*pq.Error); ok {
+		if errPQ.Code == "23505" {
+			err = repository.ErrDuplicateKey{Message: errPQ.Detail}
 		}
 	}
 
-	return nil
+	if err != nil {
+		return nil, err
+	}
+
+	return affectedOrderIDs, nil
 }
 
-func (postgresOrder *PostgresOrder) legacyUserCheckExistsByID(id int64) (exists bool, err error) {
+// legacyUserBulkUpsert upserts a chunk of users with a single statement
+func (postgresOrder *PostgresOrder) legacyUserBulkUpsert(modelUsers *model.Users, tx *sql.Tx) error {
+	query :=
+		`INSERT INTO 
+			%[1]susers
+			(id, name)
+		SELECT
+			*
+		FROM
+			unnest($1::bigint[], $2::text[])
+		ON CONFLICT (id) DO UPDATE SET
+			name = EXCLUDED.name;`
+
+	ids := make([]int64, len(*modelUsers))
+	names := make([]string, len(*modelUsers))
+
+	for index, modelUser := range *modelUsers {
+		ids[index] = modelUser.ID
+		names[index] = modelUser.Name
+	}
+
+	_, err := tx.Exec(fmt.Sprintf(query, postgresOrder.Repository.TablePrefix), pq.Array(ids), pq.Array(names))
+
+	return err
+}
+
+// legacyOrderBulkUpsert upserts a chunk of orders with a single statement
+func (postgresOrder *PostgresOrder) legacyOrderBulkUpsert(modelOrders *model.Orders, tx *sql.Tx) error {
+	query :=
+		`INSERT INTO 
+			%[1]sorders
+			(id, user_id, buy_date, total)
+		SELECT
+			*
+		FROM
+			unnest($1::bigint[], $2::bigint[], $3::date[], $4::numeric[])
+		ON CONFLICT (id) DO UPDATE SET
+			user_id = EXCLUDED.user_id,
+			buy_date = EXCLUDED.buy_date;`
+
+	ids := make([]int64, len(*modelOrders))
+	userIDs := make([]int64, len(*modelOrders))
+	buyDates := make([]string, len(*modelOrders))
+	totals := make([]string, len(*modelOrders))
+
+	for index, modelOrder := range *modelOrders {
+		ids[index] = modelOrder.ID
+		userIDs[index] = modelOrder.UserID
+		buyDates[index] = modelOrder.BuyDate
+		totals[index] = modelOrder.Total.String()
+	}
+
+	_, err := tx.Exec(fmt.Sprintf(query, postgresOrder.Repository.TablePrefix), pq.Array(ids), pq.Array(userIDs), pq.Array(buyDates), pq.Array(totals))
+
+	return err
+}
+
+// legacyOrderProductDeleteByOrderIDs removes the products of the upserted orders
+// because they are replaced by the imported ones
+func (postgresOrder *PostgresOrder) legacyOrderProductDeleteByOrderIDs(orderIDs []int64, tx *sql.Tx) error {
+	query :=
+		`DELETE FROM 
+			%[1]sorders_product 
+		WHERE 
+			order_id = ANY($1);`
+
+	_, err := tx.Exec(fmt.Sprintf(query, postgresOrder.Repository.TablePrefix), pq.Array(orderIDs))
+
+	return err
+}
+
+func (postgresOrder *PostgresOrder) legacyOrderTotalRecalculate(orderIDs []int64, tx *sql.Tx) error {
+	query :=
+		`UPDATE 
+			%[1]sorders o
+		SET 
+			total = COALESCE((SELECT SUM(op.product_value) FROM %[1]sorders_product op WHERE op.order_id = o.id), 0)
+		WHERE
+			o.id = ANY($1);`
+
+	_, err := tx.Exec(fmt.Sprintf(query, postgresOrder.Repository.TablePrefix), pq.Array(orderIDs))
+
+	return err
+}
+
+// legacyOrderAffectedIDs returns the upserted orders and the orders of the upserted users
+func (postgresOrder *PostgresOrder) legacyOrderAffectedIDs(orderIDs, userIDs []int64, tx *sql.Tx) ([]int64, error) {
 	query :=
 		`SELECT 
 			id 
 		FROM 
-			users 
+			%[1]sorders 
 		WHERE 
-			id = $1;`
+			id = ANY($1) OR user_id = ANY($2);`
 
-	rows, err := postgresOrder.Repository.Conn.Query(query, id)
+	rows, err := tx.Query(fmt.Sprintf(query, postgresOrder.Repository.TablePrefix), pq.Array(orderIDs), pq.Array(userIDs))
 
 	if err != nil {
-		return
+		return nil, err
 	}
 
 	defer rows.Close()
 
+	affectedOrderIDs := []int64{}
+
 	for rows.Next() {
-		var userIDFound int64
+		var orderID int64
 
-		err = rows.Scan(&userIDFound)
+		err = rows.Scan(&orderID)
 
 		if err != nil {
-			return
+			return nil, err
 		}
 
-		exists = (userIDFound == id)
+		affectedOrderIDs = append(affectedOrderIDs, orderID)
 	}
 
-	return
+	return affectedOrderIDs, rows.Err()
 }
 
-func (*PostgresOrder) legacyUserInser(modelUser *model.User, tx *sql.Tx) error {
-	query :=
-		`INSERT INTO 
-			users
-			(id, name)
-		VALUES
-			($1, $2);`
+// legacyClearAll removes the dataset of the tables with the prefix
+func (postgresOrder *PostgresOrder) legacyClearAll(tx *sql.Tx, tablePrefix string) error {
+	query := `TRUNCATE TABLE %[1]sorders_product CASCADE;
+		TRUNCATE TABLE %[1]sorders CASCADE;
+		TRUNCATE TABLE %[1]susers CASCADE;`
 
-	_, err := tx.Exec(
-		query,
-		modelUser.ID,
-		modelUser.Name,
-	)
+	_, err := tx.Exec(fmt.Sprintf(query, tablePrefix))
 
 	return err
 }
 
-func (postgresOrder *PostgresOrder) legacyOrderBulkInsert(modelOrders *model.Orders, tx *sql.Tx) (err error) {
-	// var orderExists bool
+func (postgresOrder *PostgresOrder) legacyUserBulkInsert(modelUsers *model.Users, tx *sql.Tx) error {
+	return postgresOrder.legacyCopy(tx, postgresOrder.Repository.TablePrefix+"users", []string{"id", "name"}, len(*modelUsers), func(index int) []interface{} {
+		modelUser := &(*modelUsers)[index]
 
-	for _, modelOrder := range *modelOrders {
-		// orderExists, err = postgresOrder.legacyOrderCheckExistsByID(modelOrder.ID)
+		return []interface{}{modelUser.ID, modelUser.Name}
+	})
+}
 
-		// if err != nil {
-		// 	return err
-		// }
+func (postgresOrder *PostgresOrder) legacyUserCheckExistsByID(id int64) (exists bool, err error) {
+	query :=
+		`SELECT 
+			id 
+		FROM 
+			users 
+		WHERE 
+			id = $1;`
+
+	rows, err := postgresOrder.Repository.Conn.Query(query, id)
 
-		// if orderExists {
-		// 	err = postgresOrder.legacyOrderUpdate(&modelOrder, tx)
-		// } else {
-		// 	err = postgresOrder.legacyOrderInsert(&modelOrder, tx)
-		// }
-		err = postgresOrder.legacyOrderInsert(&modelOrder, tx)
+	if err != nil {
+		return
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var userIDFound int64
+
+		err = rows.Scan(&userIDFound)
 
 		if err != nil {
 			return
 		}
+
+		exists = (userIDFound == id)
 	}
 
 	return
 }
 
+func (postgresOrder *PostgresOrder) legacyOrderBulkInsert(modelOrders *model.Orders, tx *sql.Tx) error {
+	return postgresOrder.legacyCopy(tx, postgresOrder.Repository.TablePrefix+"orders", []string{"id", "user_id", "buy_date", "total"}, len(*modelOrders), func(index int) []interface{} {
+		modelOrder := &(*modelOrders)[index]
+
+		return []interface{}{modelOrder.ID, modelOrder.UserID, modelOrder.BuyDate, modelOrder.Total}
+	})
+}
+
 func (postgresOrder *PostgresOrder) legacyOrderCheckExistsByID(id int64) (exists bool, err error) {
 	query :=
 		`SELECT 
@@ -285,25 +587,6 @@ func (postgresOrder *PostgresOrder) legacyOrderCheckExistsByID(id int64) (exists
 	return
 }
 
-func (*PostgresOrder) legacyOrderInsert(modelOrder *model.Order, tx *sql.Tx) error {
-	query :=
-		`INSERT INTO 
-			orders
-			(id, user_id, buy_date, total)
-		VALUES
-			($1, $2, $3, $4);`
-
-	_, err := tx.Exec(
-		query,
-		modelOrder.ID,
-		modelOrder.UserID,
-		modelOrder.BuyDate,
-		modelOrder.Total,
-	)
-
-	return err
-}
-
 func (*PostgresOrder) legacyOrderUpdate(modelOrder *model.Order, tx *sql.Tx) error {
 	query :=
 		`UPDATE 
@@ -322,36 +605,578 @@ func (*PostgresOrder) legacyOrderUpdate(modelOrder *model.Order, tx *sql.Tx) err
 	return err
 }
 
-func (postgresOrder *PostgresOrder) legacyOrderProductBulkInsert(modelOrdersProducts *model.OrdersProducts, tx *sql.Tx) (err error) {
-	for _, modelOrderProduct := range *modelOrdersProducts {
-		err = postgresOrder.legacyOrderProductInsert(&modelOrderProduct, tx)
+func (postgresOrder *PostgresOrder) legacyOrderProductBulkInsert(modelOrdersProducts *model.OrdersProducts, tx *sql.Tx) error {
+	return postgresOrder.legacyCopy(tx, postgresOrder.Repository.TablePrefix+"orders_product", []string{"order_id", "product_id", "product_value"}, len(*modelOrdersProducts), func(index int) []interface{} {
+		modelOrderProduct := &(*modelOrdersProducts)[index]
+
+		return []interface{}{modelOrderProduct.OrderID, modelOrderProduct.ProductID, modelOrderProduct.ProductValue}
+	})
+}
+
+// legacyCopy persists a chunk of rows with the COPY command, which is much
+// faster than an INSERT by row for the large files
+func (*PostgresOrder) legacyCopy(tx *sql.Tx, table string, columns []string, rows int, row func(index int) []interface{}) error {
+	stmt, err := tx.Prepare(pq.CopyIn(table, columns...))
+
+	if err != nil {
+		return err
+	}
+
+	for index := 0; index < rows && err == nil; index++ {
+		_, err = stmt.Exec(row(index)...)
+	}
+
+	// the rows buffered are sent by the Exec without arguments
+	if err == nil {
+		_, err = stmt.Exec()
+	}
+
+	if err != nil {
+		stmt.Close()
+		return err
+	}
+
+	return stmt.Close()
+}
+
+// legacyRejectsReplace replaces the rejects in the transaction of the dataset,
+// copying them in chunks, so only one chunk of the rejects is in memory at a
+// time
+func (postgresOrder *PostgresOrder) legacyRejectsReplace(legacyDataset repository.LegacyDataset, tx *sql.Tx) error {
+	_, err := tx.Exec(fmt.Sprintf(`DELETE FROM %[1]slegacy_rejects;`, postgresOrder.Repository.TablePrefix))
+
+	if err != nil {
+		return err
+	}
+
+	return legacyDataset.Rejects(func(modelLegacyRejects *model.LegacyRejects) error {
+		return postgresOrder.legacyRejectBulkInsert(modelLegacyRejects, tx)
+	})
+}
+
+func (postgresOrder *PostgresOrder) ListLegacyRejects() (*model.LegacyRejects, error) {
+	query :=
+		`SELECT 
+			file, line, message, record, lines, errors
+		FROM 
+			%[1]slegacy_rejects
+		ORDER BY
+			id`
+
+	rows, err := postgresOrder.Repository.Conn.Query(fmt.Sprintf(query, postgresOrder.Repository.TablePrefix))
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	modelLegacyRejects := model.LegacyRejects{}
+
+	for rows.Next() {
+		modelLegacyReject := model.LegacyReject{}
+
+		var lines []byte
+		var errors []byte
+
+		err = rows.Scan(
+			&modelLegacyReject.File,
+			&modelLegacyReject.Line,
+			&modelLegacyReject.Message,
+			&modelLegacyReject.Record,
+			&lines,
+			&errors,
+		)
+
+		if err == nil && len(lines) > 0 {
+			err = json.Unmarshal(lines, &modelLegacyReject.Lines)
+		}
+
+		if err == nil && len(errors) > 0 {
+			err = json.Unmarshal(errors, &modelLegacyReject.Errors)
+		}
 
 		if err != nil {
-			return
+			return nil, err
 		}
+
+		modelLegacyRejects = append(modelLegacyRejects, modelLegacyReject)
 	}
 
-	return
+	// repository error not found
+	if len(modelLegacyRejects) == 0 {
+		return nil, repository.ErrNotFound{Message: sql.ErrNoRows.Error()}
+	}
+
+	return &modelLegacyRejects, nil
+}
+
+// legacyImportInsert includes the import of the live dataset in the history,
+// the import of the staged dataset is kept apart until it is promoted
+func (postgresOrder *PostgresOrder) legacyImportInsert(modelLegacyImport *model.LegacyImport, userIDs, orderIDs []int64, tx *sql.Tx) error {
+	if postgresOrder.Repository.TablePrefix != "" {
+		return postgresOrder.legacyStageImportInsert(modelLegacyImport, tx)
+	}
+
+	return postgresOrder.legacyImportHistoryInsert(modelLegacyImport, userIDs, orderIDs, tx)
 }
 
-func (*PostgresOrder) legacyOrderProductInsert(modelOrderProduct *model.OrderProduct, tx *sql.Tx) error {
+// legacyImportHistoryInsert includes the import in the history as the live
+// one and, when it is restorable, keeps a copy of its records and of the
+// current rejects to be restored. The copy of a replace writes the whole
+// dataset a second time. A merge keeps only the merged users and orders and is
+// restored over the live import it was merged into, nil IDs keep the whole
+// dataset.
+func (*PostgresOrder) legacyImportHistoryInsert(modelLegacyImport *model.LegacyImport, userIDs, orderIDs []int64, tx *sql.Tx) error {
+	var baseID sql.NullInt64
+
+	if orderIDs != nil {
+		var baseRestorable bool
+
+		err := tx.QueryRow(`SELECT id, restorable FROM legacy_imports WHERE live;`).Scan(&baseID, &baseRestorable)
+
+		// without a live import the whole dataset is kept
+		if err == sql.ErrNoRows {
+			userIDs, orderIDs = nil, nil
+		} else if err != nil {
+			return err
+		}
+
+		// the merged records alone do not restore the dataset
+		if baseID.Valid && !baseRestorable {
+			modelLegacyImport.Restorable = false
+		}
+	}
+
+	_, err := tx.Exec(`UPDATE legacy_imports SET live = false WHERE live;`)
+
+	if err != nil {
+		return err
+	}
+
 	query :=
 		`INSERT INTO 
-			orders_product
-			(order_id, product_id, product_value)
+			legacy_imports
+			(imported_at, file_name, checksum, mode, users, orders, products, accepted, rejected, requested_by, idempotency_key, live, base_id, restorable)
 		VALUES
-			($1, $2, $3);`
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, true, $12, $13)
+		RETURNING
+			id;`
 
-	_, err := tx.Exec(
+	err = tx.QueryRow(
 		query,
-		modelOrderProduct.OrderID,
-		modelOrderProduct.ProductID,
-		modelOrderProduct.ProductValue,
+		modelLegacyImport.ImportedAt,
+		modelLegacyImport.FileName,
+		modelLegacyImport.Checksum,
+		modelLegacyImport.Mode,
+		modelLegacyImport.Result.Users,
+		modelLegacyImport.Result.Orders,
+		modelLegacyImport.Result.Products,
+		modelLegacyImport.Result.Accepted,
+		modelLegacyImport.Result.Rejected,
+		modelLegacyImport.RequestedBy,
+		modelLegacyImport.IdempotencyKey,
+		baseID,
+		modelLegacyImport.Restorable,
+	).Scan(&modelLegacyImport.ID)
+
+	if err != nil {
+		return err
+	}
+
+	modelLegacyImport.Live = true
+
+	if !modelLegacyImport.Restorable {
+		return nil
+	}
+
+	// the statements are executed one by one because they use parameters
+	statements := []struct {
+		query string
+		ids   []int64
+	}{
+		{`INSERT INTO legacy_imports_users (import_id, id, name)
+			SELECT $1, id, name FROM users WHERE $2::bigint[] IS NULL OR id = ANY($2);`, userIDs},
+		{`INSERT INTO legacy_imports_orders (import_id, id, user_id, buy_date, total)
+			SELECT $1, id, user_id, buy_date, total FROM orders WHERE $2::bigint[] IS NULL OR id = ANY($2);`, orderIDs},
+		{`INSERT INTO legacy_imports_orders_product (import_id, order_id, product_id, product_value)
+			SELECT $1, order_id, product_id, product_value FROM orders_product WHERE $2::bigint[] IS NULL OR order_id = ANY($2);`, orderIDs},
+	}
+
+	for _, statement := range statements {
+		_, err = tx.Exec(statement.query, modelLegacyImport.ID, pq.Array(statement.ids))
+
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(`INSERT INTO legacy_imports_rejects (import_id, id, file, line, message, record, lines, errors)
+		SELECT $1, id, file, line, message, record, lines, errors FROM legacy_rejects;`, modelLegacyImport.ID)
+
+	return err
+}
+
+func (postgresOrder *PostgresOrder) legacyRejectBulkInsert(modelLegacyRejects *model.LegacyRejects, tx *sql.Tx) error {
+	columns := []string{"file", "line", "message", "record", "lines", "errors"}
+
+	return postgresOrder.legacyCopy(tx, postgresOrder.Repository.TablePrefix+"legacy_rejects", columns, len(*modelLegacyRejects), func(index int) []interface{} {
+		modelLegacyReject := &(*modelLegacyRejects)[index]
+
+		// the json columns are copied as text, null when empty
+		var lines, errors interface{}
+
+		if len(modelLegacyReject.Lines) > 0 {
+			value, _ := json.Marshal(modelLegacyReject.Lines)
+			lines = string(value)
+		}
+
+		if len(modelLegacyReject.Errors) > 0 {
+			value, _ := json.Marshal(modelLegacyReject.Errors)
+			errors = string(value)
+		}
+
+		return []interface{}{modelLegacyReject.File, modelLegacyReject.Line, modelLegacyReject.Message, modelLegacyReject.Record, lines, errors}
+	})
+}
+
+func (postgresOrder *PostgresOrder) ListLegacyImports() (*model.LegacyImports, error) {
+	query := fmt.Sprintf(queryLegacyImports, "", "ORDER BY id DESC")
+
+	rows, err := postgresOrder.Repository.Conn.Query(query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	modelLegacyImports := model.LegacyImports{}
+
+	for rows.Next() {
+		modelLegacyImport, err := postgresOrder.convertQueryResultToLegacyImport(rows)
+
+		if err != nil {
+			return nil, err
+		}
+
+		modelLegacyImports = append(modelLegacyImports, *modelLegacyImport)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// repository error not found
+	if len(modelLegacyImports) == 0 {
+		return nil, repository.ErrNotFound{Message: sql.ErrNoRows.Error()}
+	}
+
+	return &modelLegacyImports, nil
+}
+
+// GetLegacyImportLive reads only the row of the live import, it is called on
+// every read of the cache
+func (postgresOrder *PostgresOrder) GetLegacyImportLive() (*model.LegacyImport, error) {
+	query := fmt.Sprintf(queryLegacyImports, "", "WHERE live LIMIT 1")
+
+	modelLegacyImport, err := postgresOrder.convertQueryResultToLegacyImport(postgresOrder.Repository.Conn.QueryRow(query))
+
+	// repository error not found
+	if err == sql.ErrNoRows {
+		return nil, repository.ErrNotFound{Message: err.Error()}
+	}
+
+	return modelLegacyImport, err
+}
+
+// LegacyImportRestore replaces the current dataset and its rejects by the ones
+// of the import in a single transaction, making it the live import. A merge
+// keeps only the merged records, so the imports it was merged into are
+// restored before it. An import without the copy of its records is not found.
+func (postgresOrder *PostgresOrder) LegacyImportRestore(importID int64) (*model.LegacyImport, error) {
+	tx, err := postgresOrder.Repository.Conn.Begin()
+
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(queryLegacyImports, "", "WHERE id = $1 AND restorable FOR UPDATE")
+
+	modelLegacyImport, err := postgresOrder.convertQueryResultToLegacyImport(tx.QueryRow(query, importID))
+
+	if err == sql.ErrNoRows {
+		tx.Rollback()
+		return nil, repository.ErrNotFound{Message: err.Error()}
+	}
+
+	var importIDs []int64
+
+	if err == nil {
+		importIDs, err = postgresOrder.legacyImportChain(importID, tx)
+	}
+
+	if err == nil {
+		err = postgresOrder.legacyClearAll(tx, "")
+	}
+
+	// the records of each import are upserted in the order of the imports
+	statements := []string{
+		`INSERT INTO users (id, name)
+			SELECT id, name FROM legacy_imports_users WHERE import_id = $1
+		ON CONFLICT (id) DO UPDATE SET
+			name = EXCLUDED.name;`,
+		`INSERT INTO orders (id, user_id, buy_date, total)
+			SELECT id, user_id, buy_date, total FROM legacy_imports_orders WHERE import_id = $1
+		ON CONFLICT (id) DO UPDATE SET
+			user_id = EXCLUDED.user_id,
+			buy_date = EXCLUDED.buy_date,
+			total = EXCLUDED.total;`,
+		`DELETE FROM orders_product
+			WHERE order_id IN (SELECT id FROM legacy_imports_orders WHERE import_id = $1);`,
+		`INSERT INTO orders_product (order_id, product_id, product_value)
+			SELECT order_id, product_id, product_value FROM legacy_imports_orders_product WHERE import_id = $1;`,
+	}
+
+	for _, chainImportID := range importIDs {
+		for _, statement := range statements {
+			if err != nil {
+				break
+			}
+
+			_, err = tx.Exec(statement, chainImportID)
+		}
+	}
+
+	if err == nil {
+		_, err = tx.Exec(`DELETE FROM legacy_rejects;`)
+	}
+
+	statements = []string{
+		`INSERT INTO legacy_rejects (file, line, message, record, lines, errors)
+			SELECT file, line, message, record, lines, errors FROM legacy_imports_rejects WHERE import_id = $1 ORDER BY id;`,
+		`UPDATE legacy_imports SET live = (id = $1);`,
+	}
+
+	for _, statement := range statements {
+		if err != nil {
+			break
+		}
+
+		_, err = tx.Exec(statement, importID)
+	}
+
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Commit()
+
+	if err != nil {
+		return nil, err
+	}
+
+	modelLegacyImport.Live = true
+
+	return modelLegacyImport, nil
+}
+
+// legacyImportChain returns the IDs of the imports needed to restore the
+// import, from the whole dataset up to the import, following the imports each
+// merge was merged into
+func (*PostgresOrder) legacyImportChain(importID int64, tx *sql.Tx) ([]int64, error) {
+	query :=
+		`WITH RECURSIVE chain AS (
+			SELECT id, base_id FROM legacy_imports WHERE id = $1
+			UNION ALL
+			SELECT i.id, i.base_id FROM legacy_imports i JOIN chain c ON i.id = c.base_id
+		)
+		SELECT 
+			id 
+		FROM 
+			chain 
+		ORDER BY 
+			id;`
+
+	rows, err := tx.Query(query, importID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	importIDs := []int64{}
+
+	for rows.Next() {
+		var chainImportID int64
+
+		err = rows.Scan(&chainImportID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		importIDs = append(importIDs, chainImportID)
+	}
+
+	return importIDs, rows.Err()
+}
+
+// LegacyImportsPrune removes from the history the imports older than the last
+// size imports, the live import and the imports the merges kept were merged
+// into are always kept
+func (postgresOrder *PostgresOrder) LegacyImportsPrune(size int) error {
+	query :=
+		`WITH RECURSIVE kept AS (
+			SELECT id, base_id FROM legacy_imports WHERE live OR id IN (SELECT id FROM legacy_imports ORDER BY id DESC LIMIT $1)
+			UNION
+			SELECT i.id, i.base_id FROM legacy_imports i JOIN kept k ON i.id = k.base_id
+		)
+		DELETE FROM 
+			legacy_imports 
+		WHERE 
+			id NOT IN (SELECT id FROM kept);`
+
+	_, err := postgresOrder.Repository.Conn.Exec(query, size)
+
+	return err
+}
+
+// legacyStageImportInsert replaces the import of the staged dataset
+func (postgresOrder *PostgresOrder) legacyStageImportInsert(modelLegacyImport *model.LegacyImport, tx *sql.Tx) error {
+	_, err := tx.Exec(fmt.Sprintf(`DELETE FROM %[1]slegacy_imports;`, postgresTablePrefixStaging))
+
+	if err != nil {
+		return err
+	}
+
+	query :=
+		`INSERT INTO 
+			%[1]slegacy_imports
+			(imported_at, file_name, checksum, mode, users, orders, products, accepted, rejected, requested_by, idempotency_key, live, restorable)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, false, $12);`
+
+	_, err = tx.Exec(
+		fmt.Sprintf(query, postgresTablePrefixStaging),
+		modelLegacyImport.ImportedAt,
+		modelLegacyImport.FileName,
+		modelLegacyImport.Checksum,
+		modelLegacyImport.Mode,
+		modelLegacyImport.Result.Users,
+		modelLegacyImport.Result.Orders,
+		modelLegacyImport.Result.Products,
+		modelLegacyImport.Result.Accepted,
+		modelLegacyImport.Result.Rejected,
+		modelLegacyImport.RequestedBy,
+		modelLegacyImport.IdempotencyKey,
+		modelLegacyImport.Restorable,
 	)
 
 	return err
 }
 
+// LegacyStagePromote replaces the live dataset and its rejects by the staged
+// ones in a single transaction, including the staged import in the history as
+// the live one, and empties the staging
+func (postgresOrder *PostgresOrder) LegacyStagePromote() (*model.LegacyImport, error) {
+	tx, err := postgresOrder.Repository.Conn.Begin()
+
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(queryLegacyImports, postgresTablePrefixStaging, "FOR UPDATE")
+
+	modelLegacyImport, err := postgresOrder.convertQueryResultToLegacyImport(tx.QueryRow(query))
+
+	if err == sql.ErrNoRows {
+		tx.Rollback()
+		return nil, repository.ErrNotFound{Message: err.Error()}
+	}
+
+	if err == nil {
+		err = postgresOrder.legacyClearAll(tx, "")
+	}
+
+	statements := []string{
+		`INSERT INTO users (id, name)
+			SELECT id, name FROM %[1]susers;`,
+		`INSERT INTO orders (id, user_id, buy_date, total)
+			SELECT id, user_id, buy_date, total FROM %[1]sorders;`,
+		`INSERT INTO orders_product (order_id, product_id, product_value)
+			SELECT order_id, product_id, product_value FROM %[1]sorders_product ORDER BY id;`,
+		`DELETE FROM legacy_rejects;`,
+		`INSERT INTO legacy_rejects (file, line, message, record, lines, errors)
+			SELECT file, line, message, record, lines, errors FROM %[1]slegacy_rejects ORDER BY id;`,
+	}
+
+	for _, statement := range statements {
+		if err != nil {
+			break
+		}
+
+		_, err = tx.Exec(fmt.Sprintf(statement, postgresTablePrefixStaging))
+	}
+
+	if err == nil {
+		err = postgresOrder.legacyImportHistoryInsert(modelLegacyImport, nil, nil, tx)
+	}
+
+	if err == nil {
+		err = postgresOrder.legacyClearAll(tx, postgresTablePrefixStaging)
+	}
+
+	if err == nil {
+		_, err = tx.Exec(fmt.Sprintf(`DELETE FROM %[1]slegacy_rejects;
+			DELETE FROM %[1]slegacy_imports;`, postgresTablePrefixStaging))
+	}
+
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Commit()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return modelLegacyImport, nil
+}
+
+func (*PostgresOrder) convertQueryResultToLegacyImport(row interface{ Scan(dest ...any) error }) (*model.LegacyImport, error) {
+	modelLegacyImport := &model.LegacyImport{}
+
+	err := row.Scan(
+		&modelLegacyImport.ID,
+		&modelLegacyImport.ImportedAt,
+		&modelLegacyImport.FileName,
+		&modelLegacyImport.Checksum,
+		&modelLegacyImport.Mode,
+		&modelLegacyImport.Result.Users,
+		&modelLegacyImport.Result.Orders,
+		&modelLegacyImport.Result.Products,
+		&modelLegacyImport.Result.Accepted,
+		&modelLegacyImport.Result.Rejected,
+		&modelLegacyImport.RequestedBy,
+		&modelLegacyImport.IdempotencyKey,
+		&modelLegacyImport.Live,
+		&modelLegacyImport.Restorable,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	modelLegacyImport.ImportedAt = modelLegacyImport.ImportedAt.UTC()
+
+	return modelLegacyImport, nil
+}
+
 func (*PostgresOrder) convertQueryResultToOrdersDetails(rows *sql.Rows) (*model.OrdersDetails, error) {
 	modelOrdersDetails := model.OrdersDetails{}
 	userIndex := -1
@@ -9,8 +9,13 @@ import (
 	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/util"
 )
 
+// prefix of the tables of the staged dataset
+const postgresTablePrefixStaging = "staging_"
+
 type Postgres struct {
 	Conn *sql.DB
+	// prefix of the tables of the dataset, empty for the live dataset
+	TablePrefix string
 }
 
 func NewPostgres(config *util.Config) (repository.Repository, error) {
@@ -44,3 +49,22 @@ func (postgres *Postgres) Close() error {
 func (postgres *Postgres) Order() repository.Order {
 	return NewOrder(postgres)
 }
+
+func (postgres *Postgres) User() repository.User {
+	return NewUser(postgres)
+}
+
+func (postgres *Postgres) Product() repository.Product {
+	return NewProduct(postgres)
+}
+
+func (postgres *Postgres) Report() repository.Report {
+	return NewReport(postgres)
+}
+
+func (postgres *Postgres) Staging() repository.Repository {
+	return &Postgres{
+		Conn:        postgres.Conn,
+		TablePrefix: postgresTablePrefixStaging,
+	}
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+)
+
+// PostgresProduct queries the products of the same dataset of the orders
+type PostgresProduct struct {
+	Repository *Postgres
+}
+
+const (
+	// the products of the orders are selected by the index on product_id, no
+	// row is returned when the product was not sold
+	queryProductSummary = `SELECT
+			COUNT(*), COUNT(DISTINCT o.user_id), MIN(op.product_value), MAX(op.product_value), ROUND(AVG(op.product_value), 2), MIN(o.buy_date), MAX(o.buy_date)
+		FROM
+			%[1]sorders_product op
+		JOIN
+			%[1]sorders o ON o.id = op.order_id
+		WHERE
+			op.product_id = $1
+		HAVING
+			COUNT(*) > 0`
+)
+
+func NewProduct(repository *Postgres) repository.Product {
+	return &PostgresProduct{Repository: repository}
+}
+
+func (postgresProduct *PostgresProduct) GetSummaryByProductID(productID int64) (*model.ProductSummary, error) {
+	query := fmt.Sprintf(queryProductSummary, postgresProduct.Repository.TablePrefix)
+
+	modelProductSummary := &model.ProductSummary{ProductID: productID}
+
+	var firstSaleDate, lastSaleDate sql.NullTime
+
+	err := postgresProduct.Repository.Conn.QueryRow(query, productID).Scan(
+		&modelProductSummary.TimesSold,
+		&modelProductSummary.Buyers,
+		&modelProductSummary.MinValue,
+		&modelProductSummary.MaxValue,
+		&modelProductSummary.AvgValue,
+		&firstSaleDate,
+		&lastSaleDate,
+	)
+
+	// repository error not found
+	if err == sql.ErrNoRows {
+		return nil, repository.ErrNotFound{Message: err.Error()}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	modelProductSummary.FirstSaleDate = firstSaleDate.Time.Format("2006-01-02")
+	modelProductSummary.LastSaleDate = lastSaleDate.Time.Format("2006-01-02")
+
+	return modelProductSummary, nil
+}
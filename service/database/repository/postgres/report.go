@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+)
+
+// PostgresReport aggregates the orders of the same dataset with GROUP BY
+type PostgresReport struct {
+	Repository *Postgres
+}
+
+const (
+	// the unit of date_trunc comes from reportPeriodUnits, never from the
+	// request
+	queryReportRevenue = `SELECT
+			date_trunc('%[3]s', o.buy_date)::date, COUNT(*), SUM(o.total)
+		FROM
+			%[1]sorders o
+		%[2]s
+		GROUP BY
+			1
+		ORDER BY
+			1`
+
+	queryReportTopUsers = `SELECT
+			o.user_id, u.name, COUNT(*), SUM(o.total)
+		FROM
+			%[1]sorders o
+		JOIN
+			%[1]susers u ON u.id = o.user_id
+		%[2]s
+		GROUP BY
+			o.user_id, u.name
+		ORDER BY
+			SUM(o.total) DESC, o.user_id
+		LIMIT $%[3]d`
+
+	queryReportTopProducts = `SELECT
+			op.product_id, COUNT(*), SUM(op.product_value)
+		FROM
+			%[1]sorders_product op
+		JOIN
+			%[1]sorders o ON o.id = op.order_id
+		%[2]s
+		GROUP BY
+			op.product_id
+		ORDER BY
+			SUM(op.product_value) DESC, op.product_id
+		LIMIT $%[3]d`
+
+	queryReportBasket = `SELECT
+			COUNT(*),
+			COALESCE(SUM(o.total), 0),
+			(SELECT COUNT(*) FROM %[1]sorders_product op JOIN %[1]sorders o ON o.id = op.order_id %[2]s)
+		FROM
+			%[1]sorders o
+		%[2]s`
+)
+
+var reportPeriodUnits = map[string]string{
+	model.ReportPeriodDay:   "day",
+	model.ReportPeriodWeek:  "week",
+	model.ReportPeriodMonth: "month",
+}
+
+func NewReport(repository *Postgres) repository.Report {
+	return &PostgresReport{Repository: repository}
+}
+
+func (postgresReport *PostgresReport) ListRevenue(modelReportFilter *model.ReportFilter) (*model.ReportsRevenue, error) {
+	unit, ok := reportPeriodUnits[modelReportFilter.Period]
+
+	if !ok {
+		unit = reportPeriodUnits[model.ReportPeriodDay]
+	}
+
+	where, args := reportFilterWhere(modelReportFilter)
+
+	query := fmt.Sprintf(queryReportRevenue, postgresReport.Repository.TablePrefix, where, unit)
+
+	rows, err := postgresReport.Repository.Conn.Query(query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	modelReportsRevenue := model.ReportsRevenue{}
+
+	for rows.Next() {
+		modelReportRevenue := model.ReportRevenue{}
+
+		var period time.Time
+
+		err = rows.Scan(&period, &modelReportRevenue.Orders, &modelReportRevenue.Revenue)
+
+		if err != nil {
+			return nil, err
+		}
+
+		modelReportRevenue.Period = period.Format("2006-01-02")
+
+		modelReportsRevenue = append(modelReportsRevenue, modelReportRevenue)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// repository error not found
+	if len(modelReportsRevenue) == 0 {
+		return nil, repository.ErrNotFound{Message: sql.ErrNoRows.Error()}
+	}
+
+	return &modelReportsRevenue, nil
+}
+
+func (postgresReport *PostgresReport) ListTopUsers(modelReportFilter *model.ReportFilter) (*model.ReportTopUsers, error) {
+	where, args := reportFilterWhere(modelReportFilter)
+
+	args = append(args, reportFilterLimit(modelReportFilter))
+
+	query := fmt.Sprintf(queryReportTopUsers, postgresReport.Repository.TablePrefix, where, len(args))
+
+	rows, err := postgresReport.Repository.Conn.Query(query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	modelReportTopUsers := model.ReportTopUsers{}
+
+	for rows.Next() {
+		modelReportTopUser := model.ReportTopUser{}
+
+		err = rows.Scan(
+			&modelReportTopUser.UserID,
+			&modelReportTopUser.UserName,
+			&modelReportTopUser.Orders,
+			&modelReportTopUser.Total,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		modelReportTopUsers = append(modelReportTopUsers, modelReportTopUser)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// repository error not found
+	if len(modelReportTopUsers) == 0 {
+		return nil, repository.ErrNotFound{Message: sql.ErrNoRows.Error()}
+	}
+
+	return &modelReportTopUsers, nil
+}
+
+func (postgresReport *PostgresReport) ListTopProducts(modelReportFilter *model.ReportFilter) (*model.ReportTopProducts, error) {
+	where, args := reportFilterWhere(modelReportFilter)
+
+	args = append(args, reportFilterLimit(modelReportFilter))
+
+	query := fmt.Sprintf(queryReportTopProducts, postgresReport.Repository.TablePrefix, where, len(args))
+
+	rows, err := postgresReport.Repository.Conn.Query(query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	modelReportTopProducts := model.ReportTopProducts{}
+
+	for rows.Next() {
+		modelReportTopProduct := model.ReportTopProduct{}
+
+		err = rows.Scan(
+			&modelReportTopProduct.ProductID,
+			&modelReportTopProduct.TimesSold,
+			&modelReportTopProduct.Revenue,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		modelReportTopProducts = append(modelReportTopProducts, modelReportTopProduct)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// repository error not found
+	if len(modelReportTopProducts) == 0 {
+		return nil, repository.ErrNotFound{Message: sql.ErrNoRows.Error()}
+	}
+
+	return &modelReportTopProducts, nil
+}
+
+func (postgresReport *PostgresReport) GetBasket(modelReportFilter *model.ReportFilter) (*model.ReportBasket, error) {
+	where, args := reportFilterWhere(modelReportFilter)
+
+	query := fmt.Sprintf(queryReportBasket, postgresReport.Repository.TablePrefix, where)
+
+	orders, products := 0, 0
+	var revenue model.Money
+
+	err := postgresReport.Repository.Conn.QueryRow(query, args...).Scan(&orders, &revenue, &products)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// repository error not found
+	if orders == 0 {
+		return nil, repository.ErrNotFound{Message: sql.ErrNoRows.Error()}
+	}
+
+	return model.NewReportBasket(orders, products, revenue), nil
+}
+
+// reportFilterWhere returns the condition of the period of the buy date on the
+// orders aliased as o, the dates are always passed as args from $1
+func reportFilterWhere(modelReportFilter *model.ReportFilter) (string, []interface{}) {
+	if modelReportFilter.BuyDate == nil {
+		return "", []interface{}{}
+	}
+
+	return "WHERE o.buy_date BETWEEN $1 AND $2", []interface{}{modelReportFilter.BuyDate.From, modelReportFilter.BuyDate.To}
+}
+
+// reportFilterLimit returns the limit of the top reports, NULL is no limit
+func reportFilterLimit(modelReportFilter *model.ReportFilter) interface{} {
+	if modelReportFilter.Size > 0 {
+		return modelReportFilter.Size
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+)
+
+// PostgresUser queries the users of the same dataset of the orders
+type PostgresUser struct {
+	Repository *Postgres
+}
+
+const (
+	// the users of the page are selected before the join with the orders, in
+	// the sort of the primary key followed by the cursor
+	queryUserSummariesPage = `SELECT
+			u.id, u.name, COUNT(o.id), MIN(o.buy_date), MAX(o.buy_date), COALESCE(SUM(o.total), 0)
+		FROM
+			(SELECT
+				id, name
+			FROM
+				%[1]susers
+			%[2]s
+			ORDER BY
+				id
+			LIMIT $%[3]d) u
+		LEFT JOIN
+			%[1]sorders o ON o.user_id = u.id
+		GROUP BY
+			u.id, u.name
+		ORDER BY
+			u.id`
+)
+
+func NewUser(repository *Postgres) repository.User {
+	return &PostgresUser{Repository: repository}
+}
+
+// GetDetailsByUserID returns all the orders of the user, none when the orders
+// of the user were moved to another user by a merge
+func (postgresUser *PostgresUser) GetDetailsByUserID(userID int64) (*model.OrderDetails, error) {
+	postgresOrder := &PostgresOrder{Repository: postgresUser.Repository}
+
+	query := fmt.Sprintf(queryOrderDetails, postgresUser.Repository.TablePrefix, " WHERE o.user_id = $1 ")
+
+	rows, err := postgresUser.Repository.Conn.Query(query, userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	modelOrdersDetails, err := postgresOrder.convertQueryResultToOrdersDetails(rows)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(*modelOrdersDetails) > 0 {
+		return &(*modelOrdersDetails)[0], nil
+	}
+
+	modelOrderDetails := &model.OrderDetails{
+		UserID: userID,
+		Orders: []model.OrderDetailsOrder{},
+	}
+
+	query = fmt.Sprintf("SELECT name FROM %susers WHERE id = $1", postgresUser.Repository.TablePrefix)
+
+	err = postgresUser.Repository.Conn.QueryRow(query, userID).Scan(&modelOrderDetails.UserName)
+
+	// repository error not found
+	if err == sql.ErrNoRows {
+		return nil, repository.ErrNotFound{Message: err.Error()}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return modelOrderDetails, nil
+}
+
+// ListSummaries selects the users of the page with keyset pagination and
+// aggregates their orders
+func (postgresUser *PostgresUser) ListSummaries(modelUserFilter *model.UserFilter, modelUserPage *model.UserPage) (*model.UserSummariesPage, error) {
+	conditions := []string{}
+	args := []interface{}{}
+
+	if modelUserFilter.Name != "" {
+		args = append(args, "%"+likeEscape(modelUserFilter.Name)+"%")
+		conditions = append(conditions, fmt.Sprintf("name ILIKE $%d", len(args)))
+	}
+
+	if modelUserCursor := modelUserPage.Cursor; modelUserCursor != nil {
+		args = append(args, modelUserCursor.UserID)
+		conditions = append(conditions, fmt.Sprintf("id > $%d", len(args)))
+	}
+
+	where := ""
+
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// one user beyond the limit tells there is a next page, NULL is no limit
+	var limit interface{}
+
+	if modelUserPage.Limit > 0 {
+		limit = modelUserPage.Limit + 1
+	}
+
+	args = append(args, limit)
+
+	query := fmt.Sprintf(queryUserSummariesPage, postgresUser.Repository.TablePrefix, where, len(args))
+
+	rows, err := postgresUser.Repository.Conn.Query(query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	modelUserSummaries, err := postgresUser.convertQueryResultToUserSummaries(rows)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// repository error not found
+	if len(*modelUserSummaries) == 0 {
+		return nil, repository.ErrNotFound{Message: sql.ErrNoRows.Error()}
+	}
+
+	modelUserSummariesPage := &model.UserSummariesPage{UserSummaries: *modelUserSummaries}
+
+	if modelUserPage.Limit > 0 && len(modelUserSummariesPage.UserSummaries) > modelUserPage.Limit {
+		modelUserSummariesPage.UserSummaries = modelUserSummariesPage.UserSummaries[:modelUserPage.Limit]
+		modelUserSummariesPage.Next = &model.UserCursor{UserID: modelUserSummariesPage.UserSummaries[modelUserPage.Limit-1].UserID}
+	}
+
+	return modelUserSummariesPage, nil
+}
+
+func (*PostgresUser) convertQueryResultToUserSummaries(rows *sql.Rows) (*model.UserSummaries, error) {
+	modelUserSummaries := model.UserSummaries{}
+
+	for rows.Next() {
+		modelUserSummary := model.UserSummary{}
+
+		var firstBuyDate, lastBuyDate sql.NullTime
+
+		err := rows.Scan(
+			&modelUserSummary.UserID,
+			&modelUserSummary.UserName,
+			&modelUserSummary.Orders,
+			&firstBuyDate,
+			&lastBuyDate,
+			&modelUserSummary.Total,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		// the user has no orders when the dates are null
+		if firstBuyDate.Valid {
+			modelUserSummary.FirstBuyDate = firstBuyDate.Time.Format("2006-01-02")
+			modelUserSummary.LastBuyDate = lastBuyDate.Time.Format("2006-01-02")
+		}
+
+		modelUserSummaries = append(modelUserSummaries, modelUserSummary)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &modelUserSummaries, nil
+}
@@ -0,0 +1,7 @@
+package repository
+
+import "github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+
+type Product interface {
+	GetSummaryByProductID(productID int64) (*model.ProductSummary, error)
+}
@@ -0,0 +1,10 @@
+package repository
+
+import "github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+
+type Report interface {
+	ListRevenue(modelReportFilter *model.ReportFilter) (*model.ReportsRevenue, error)
+	ListTopUsers(modelReportFilter *model.ReportFilter) (*model.ReportTopUsers, error)
+	ListTopProducts(modelReportFilter *model.ReportFilter) (*model.ReportTopProducts, error)
+	GetBasket(modelReportFilter *model.ReportFilter) (*model.ReportBasket, error)
+}
@@ -2,6 +2,12 @@ package repository
 
 type Repository interface {
 	Order() Order
+	User() User
+	Product() Product
+	Report() Report
+	// Staging returns the repository of the staged dataset, queried with the
+	// same methods of the live one until it is promoted
+	Staging() Repository
 	Check() error
 	Close() error
 }
@@ -0,0 +1,8 @@
+package repository
+
+import "github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+
+type User interface {
+	GetDetailsByUserID(userID int64) (*model.OrderDetails, error)
+	ListSummaries(modelUserFilter *model.UserFilter, modelUserPage *model.UserPage) (*model.UserSummariesPage, error)
+}
@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+)
+
+// datasetVersionEmpty is the version of the dataset when nothing was imported
+const datasetVersionEmpty = "0"
+
+// datasetVersion returns the version of the dataset that keys the cache of the
+// users and the reports, it changes on every import, restore and promotion.
+// The ID of the live import is combined with its time, since the IDs of the
+// in-memory repository restart with the process and the cache outlives it.
+func datasetVersion(datasetRepository repository.Repository) (string, error) {
+	modelLegacyImport, err := datasetRepository.Order().GetLegacyImportLive()
+
+	if err != nil {
+		if _, ok := err.(repository.ErrNotFound); ok {
+			return datasetVersionEmpty, nil
+		}
+
+		return "", err
+	}
+
+	return fmt.Sprintf("%d.%d", modelLegacyImport.ID, modelLegacyImport.ImportedAt.UnixMicro()), nil
+}
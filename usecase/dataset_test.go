@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	mock_repository "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+)
+
+func TestDatasetVersion(t *testing.T) {
+	importedAt := time.Date(2021, 3, 8, 10, 30, 0, 0, time.UTC)
+
+	type test struct {
+		name             string
+		mockLegacyImport *model.LegacyImport
+		mockError        error
+		wantVersion      string
+		wantError        error
+	}
+
+	tests := []test{
+		{
+			name:        "RepositoryError",
+			mockError:   errors.New("Repository Error"),
+			wantVersion: "",
+			wantError:   errors.New("Repository Error"),
+		},
+		{
+			name:        "EmptySuccess",
+			mockError:   repository.ErrNotFound{Message: "not found"},
+			wantVersion: datasetVersionEmpty,
+			wantError:   nil,
+		},
+		{
+			name:             "Success",
+			mockLegacyImport: &model.LegacyImport{ID: 1, ImportedAt: importedAt, Live: true},
+			wantVersion:      "1.1615199400000000",
+			wantError:        nil,
+		},
+		{
+			// the IDs of the in-memory repository restart with the process
+			name:             "SameIDAfterRestartSuccess",
+			mockLegacyImport: &model.LegacyImport{ID: 1, ImportedAt: importedAt.Add(time.Hour), Live: true},
+			wantVersion:      "1.1615203000000000",
+			wantError:        nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepository := new(mock_repository.MockRepository)
+			mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+			mockRepositoryOrder.On("GetLegacyImportLive").Return(tt.mockLegacyImport, tt.mockError)
+			mockRepository.On("Order").Return(mockRepositoryOrder)
+
+			version, err := datasetVersion(mockRepository)
+
+			if !reflect.DeepEqual(err, tt.wantError) {
+				t.Errorf("datasetVersion() got error = %v, want = %v.", err, tt.wantError)
+			}
+
+			if version != tt.wantVersion {
+				t.Errorf("datasetVersion() got version = %v, want = %v.", version, tt.wantVersion)
+			}
+		})
+	}
+}
@@ -1,12 +1,9 @@
 package usecase
 
 import (
-	"bufio"
-	"encoding/json"
-	"errors"
+	"context"
 	"fmt"
 	"io"
-	"log"
 	"strconv"
 	"strings"
 	"time"
@@ -18,46 +15,98 @@ import (
 )
 
 var (
-	OrderBuyDateMin                            = time.Date(1900, 01, 01, 00, 00, 00, 000, time.UTC)
-	OrderBuyDateMax                            = time.Now().UTC()
-	OrderErrorMessageRecordSize                = "Record size not equal 95"
+	OrderErrorMessageModeInvalid               = fmt.Sprintf("The param mode is invalid, the allowed values are %v and %v", model.LegacyImportModeReplace, model.LegacyImportModeMerge)
+	OrderErrorMessageLayoutInvalid             = "The param layout is invalid"
+	OrderErrorMessageRecordSize                = "Record size not equal %d"
 	OrderErrorMessageUserIDInvalid             = "UserID invalid"
 	OrderErrorMessageUserNameInvalid           = "UserName invalid"
 	OrderErrorMessageOrderIDInvalid            = "OrderID invalid"
 	OrderErrorMessageProductIDInvalid          = "ProductID invalid"
 	OrderErrorMessageProductValueInvalid       = "ProductValue invalid"
 	OrderErrorMessageBuyDateInvalid            = "BuyDate invalid"
-	OrderErrorMessageBuyDateBetween            = fmt.Sprintf("BuyDate value is not between %v and %v", OrderBuyDateMin.Format("2006-01-02"), OrderBuyDateMax.Format("2006-01-02"))
+	OrderErrorMessageBuyDateBetween            = "BuyDate value is not between %v and %v"
 	OrderRangeBuyDateErrorMessageFromEmpty     = "The param from is empty"
 	OrderRangeBuyDateErrorMessageFromInvalid   = "The param from is invalid"
-	OrderRangeBuyDateErrorMessageFromBetween   = fmt.Sprintf("The param from value is not between %v and %v", OrderBuyDateMin.Format("2006-01-02"), OrderBuyDateMax.Format("2006-01-02"))
+	OrderRangeBuyDateErrorMessageFromBetween   = "The param from value is not between %v and %v of the rule " + model.ValidationRuleBuyDateWindow
 	OrderRangeBuyDateErrorMessageToEmpty       = "The param to is empty"
 	OrderRangeBuyDateErrorMessageToInvalid     = "The param to is invalid"
-	OrderRangeBuyDateErrorMessageToBetween     = fmt.Sprintf("The param to value is not between %v and %v", OrderBuyDateMin.Format("2006-01-02"), OrderBuyDateMax.Format("2006-01-02"))
+	OrderRangeBuyDateErrorMessageToBetween     = "The param to value is not between %v and %v of the rule " + model.ValidationRuleBuyDateWindow
 	OrderRangeBuyDateErrorMessageToSmallerFrom = "The param to is smaller the param from"
-	OrderRangeBuyDateErrorMessageRangeError    = "the range is greater than 31 days"
+	OrderRangeBuyDateErrorMessageRangeError    = "the range is greater than %d days of the rule " + model.ValidationRuleRangeMaxDays
+	OrderPageErrorMessageLimitInvalid          = "The param limit is invalid"
+	OrderPageErrorMessageLimitBetween          = "The param limit is not between 1 and %d"
+	OrderPageErrorMessageCursorInvalid         = "The param cursor is invalid"
+	OrderFilterErrorMessageParamInvalid        = "The param %s is invalid"
+	OrderFilterErrorMessageMaxTotalSmallerMin  = "The param max_total is smaller the param min_total"
+	OrderFilterErrorMessageMaxValueSmallerMin  = "The param max_value is smaller the param min_value"
 )
 
 type Order interface {
-	LegacyImport(file io.Reader, hasHeader bool) (*model.LegacyImportResult, error)
+	LegacyImport(file io.Reader, modelLegacyImportOptions *model.LegacyImportOptions) (*model.LegacyImportResult, error)
 	GetDetailsByOrderID(orderID int64) (*model.OrderDetails, error)
-	ListDetailsByRangeBuyDate(modelOrderRangeBuyDate *model.OrderRangeBuyDate) (*model.OrdersDetails, error)
-	ListDetails() (*model.OrdersDetails, error)
+	ListDetails(modelOrderFilter *model.OrderFilter, modelOrderPage *model.OrderPage) (*model.OrdersDetailsPage, error)
+	ListLegacyRejects() (*model.LegacyRejects, error)
+	LegacyImportAsync(file io.Reader, modelLegacyImportOptions *model.LegacyImportOptions) (*model.LegacyImportJob, error)
+	GetLegacyImportJob(jobID string) (*model.LegacyImportJob, error)
+	CancelLegacyImportJob(jobID string) (*model.LegacyImportJob, error)
+	LegacyValidate(file io.Reader, modelLegacyImportOptions *model.LegacyImportOptions) (*model.LegacyValidateResult, error)
+	ListLegacyImports() (*model.LegacyImports, error)
+	LegacyImportRestore(importID int64) (*model.LegacyImport, error)
+	CreateLegacyUpload(modelLegacyUpload *model.LegacyUpload) (*model.LegacyUpload, error)
+	GetLegacyUpload(uploadID string) (*model.LegacyUpload, error)
+	LegacyUploadChunk(uploadID string, offset int64, chunk io.Reader) (*model.LegacyUpload, error)
+	DeleteLegacyUpload(uploadID string) error
+	LegacyUploadImport(uploadID string, modelLegacyImportOptions *model.LegacyImportOptions) (*model.LegacyImportResult, error)
+	LegacyUploadImportAsync(uploadID string, modelLegacyImportOptions *model.LegacyImportOptions) (*model.LegacyImportJob, error)
+	LegacyStagePromote() (*model.LegacyImport, error)
 }
 
 type UseCaseOrder struct {
 	Repository repository.Repository
 	Cache      cache.Cache
+	Config     *util.Config
+	// layouts of the legacy records indexed by name
+	legacyLayouts map[string]*model.LegacyLayout
+	// serializes the persistence of the imports
+	legacyImportLock chan struct{}
+	legacyImportJobs *legacyImportJobs
+	legacyUploads    *legacyUploads
+	validationPolicy *orderValidationPolicy
+	// queries the staged dataset, which is not cached
+	staging bool
 }
 
-func NewOrder(repository repository.Repository, cache cache.Cache) Order {
+func NewOrder(repository repository.Repository, cache cache.Cache, config *util.Config) Order {
+	legacyLayouts := map[string]*model.LegacyLayout{
+		model.LegacyLayoutNameDefault: &model.LegacyLayoutDefault,
+	}
+
+	for index := range config.LegacyLayouts {
+		legacyLayouts[config.LegacyLayouts[index].Name] = &config.LegacyLayouts[index]
+	}
+
 	return &UseCaseOrder{
-		Repository: repository,
-		Cache:      cache,
+		Repository:       repository,
+		Cache:            cache,
+		Config:           config,
+		legacyLayouts:    legacyLayouts,
+		legacyImportLock: make(chan struct{}, 1),
+		legacyImportJobs: newLegacyImportJobs(),
+		legacyUploads:    newLegacyUploads(),
+		validationPolicy: newOrderValidationPolicy(config.ValidationPolicy),
 	}
 }
 
+// validation evaluates the validation policy at the moment of the request
+func (usecaseOrder *UseCaseOrder) validation() *orderValidation {
+	return usecaseOrder.validationPolicy.at(time.Now().UTC())
+}
+
 func (usecaseOrder *UseCaseOrder) GetDetailsByOrderID(orderID int64) (*model.OrderDetails, error) {
+	if usecaseOrder.staging {
+		return usecaseOrder.Repository.Order().GetDetailsByOrderID(orderID)
+	}
+
 	modelOrdersDetails, err := usecaseOrder.Cache.Order().GetDetailsByOrderID(orderID)
 
 	if err == nil {
@@ -73,137 +122,360 @@ func (usecaseOrder *UseCaseOrder) GetDetailsByOrderID(orderID int64) (*model.Ord
 	return modelOrdersDetails, err
 }
 
-func (usecaseOrder *UseCaseOrder) ListDetails() (*model.OrdersDetails, error) {
-	return usecaseOrder.Repository.Order().ListDetails()
+func (usecaseOrder *UseCaseOrder) ListDetails(modelOrderFilter *model.OrderFilter, modelOrderPage *model.OrderPage) (*model.OrdersDetailsPage, error) {
+	err := usecaseOrder.orderFilterValidate(modelOrderFilter)
+
+	if err == nil {
+		err = usecaseOrder.orderPageValidate(modelOrderPage)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return usecaseOrder.Repository.Order().ListDetails(modelOrderFilter, modelOrderPage)
+}
+
+// orderFilterValidate checks the period against the validation policy and
+// that the ranges informed are not inverted
+func (usecaseOrder *UseCaseOrder) orderFilterValidate(modelOrderFilter *model.OrderFilter) error {
+	if modelOrderFilter.BuyDate != nil {
+		err := orderRangeBuyDateValidate(modelOrderFilter.BuyDate, usecaseOrder.validation())
+
+		if err != nil {
+			return err
+		}
+	}
+
+	messages := []string{}
+
+	if modelOrderFilter.MinTotal != nil && modelOrderFilter.MaxTotal != nil && *modelOrderFilter.MaxTotal < *modelOrderFilter.MinTotal {
+		messages = append(messages, OrderFilterErrorMessageMaxTotalSmallerMin)
+	}
+
+	if modelOrderFilter.MinValue != nil && modelOrderFilter.MaxValue != nil && *modelOrderFilter.MaxValue < *modelOrderFilter.MinValue {
+		messages = append(messages, OrderFilterErrorMessageMaxValueSmallerMin)
+	}
+
+	if len(messages) > 0 {
+		return ErrParamValidate{Message: strings.Join(messages, ";")}
+	}
+
+	return nil
+}
+
+func (usecaseOrder *UseCaseOrder) ListLegacyRejects() (*model.LegacyRejects, error) {
+	return usecaseOrder.Repository.Order().ListLegacyRejects()
+}
+
+// orderPageValidate limits the page to the maximum size configured
+func (usecaseOrder *UseCaseOrder) orderPageValidate(modelOrderPage *model.OrderPage) error {
+	return pageLimitValidate(&modelOrderPage.Limit, usecaseOrder.Config.OrderPageMaxSize)
 }
 
-func (usecaseOrder *UseCaseOrder) ListDetailsByRangeBuyDate(modelOrderRangeBuyDate *model.OrderRangeBuyDate) (*model.OrdersDetails, error) {
-	err := OrderRangeBuyDateValidate(modelOrderRangeBuyDate)
+// pageLimitValidate limits the page to the maximum size, which is also the
+// size of the page when the limit is not informed, unlimited when zero
+func pageLimitValidate(limit *int, pageMaxSize int) error {
+	if *limit < 0 || (pageMaxSize > 0 && *limit > pageMaxSize) {
+		return ErrParamValidate{Message: fmt.Sprintf(OrderPageErrorMessageLimitBetween, pageMaxSize)}
+	}
+
+	if *limit == 0 {
+		*limit = pageMaxSize
+	}
+
+	return nil
+}
+
+func (usecaseOrder *UseCaseOrder) LegacyImport(file io.Reader, modelLegacyImportOptions *model.LegacyImportOptions) (*model.LegacyImportResult, error) {
+	return usecaseOrder.legacyImport(context.Background(), file, modelLegacyImportOptions, legacyImportProgressNone{})
+}
+
+func (usecaseOrder *UseCaseOrder) legacyImport(ctx context.Context, file io.Reader, modelLegacyImportOptions *model.LegacyImportOptions, progress legacyImportProgress) (*model.LegacyImportResult, error) {
+	modelLegacyLayout, err := usecaseOrder.legacyImportOptionsValidate(modelLegacyImportOptions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	file, err = usecaseOrder.legacyFileSizeLimit(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	file, checksum := newLegacyChecksum(file)
+
+	// the staged dataset is replaced even by a file already imported
+	stage := modelLegacyImportOptions.Target == model.LegacyImportTargetStage
+
+	// a retry with the key of an import already persisted is not even parsed
+	if modelLegacyImportResult, err := usecaseOrder.legacyImportReplay(modelLegacyImportOptions.IdempotencyKey, ""); !stage && (modelLegacyImportResult != nil || err != nil) {
+		return modelLegacyImportResult, err
+	}
+
+	dataset, err := usecaseOrder.legacyParse(ctx, file, modelLegacyImportOptions, modelLegacyLayout, progress)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer dataset.close()
+
+	if !dataset.accepted(modelLegacyImportOptions) {
+		errRecordValidate := dataset.errRecordValidate()
+
+		progress.RecordsError(errRecordValidate)
+
+		return nil, errRecordValidate
+	}
+
+	// only one import at a time can persist its records
+	select {
+	case usecaseOrder.legacyImportLock <- struct{}{}:
+		defer func() { <-usecaseOrder.legacyImportLock }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	err = progress.Persisting(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	modelLegacyImport := &model.LegacyImport{
+		ImportedAt:     time.Now().UTC(),
+		FileName:       modelLegacyImportOptions.FileName,
+		Mode:           modelLegacyImportOptions.Mode,
+		Result:         *dataset.result(),
+		RequestedBy:    modelLegacyImportOptions.RequestedBy,
+		IdempotencyKey: modelLegacyImportOptions.IdempotencyKey,
+		Restorable:     usecaseOrder.Config.LegacyImportHistoryRecords,
+	}
+
+	modelLegacyImport.Checksum, err = checksum()
 
 	if err != nil {
 		return nil, err
 	}
 
-	return usecaseOrder.Repository.Order().ListDetailsByRangeBuyDate(modelOrderRangeBuyDate)
+	// checked again holding the lock, a concurrent retry may have been persisted
+	if modelLegacyImportResult, err := usecaseOrder.legacyImportReplay(modelLegacyImport.IdempotencyKey, modelLegacyImport.Checksum); !stage && (modelLegacyImportResult != nil || err != nil) {
+		return modelLegacyImportResult, err
+	}
+
+	// the rejects are persisted with the records of the dataset
+	if stage {
+		err = usecaseOrder.legacyStage(modelLegacyImport, dataset)
+	} else if modelLegacyImportOptions.Mode == model.LegacyImportModeMerge {
+		err = usecaseOrder.legacyMerge(modelLegacyImport, dataset)
+	} else {
+		err = usecaseOrder.legacyReplace(modelLegacyImport, dataset)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !stage {
+		usecaseOrder.legacyImportsPrune()
+	}
+
+	progress.Persisted(dataset.lines)
+
+	modelLegacyImportResult := dataset.result()
+	modelLegacyImportResult.ImportID = modelLegacyImport.ID
+
+	return modelLegacyImportResult, nil
 }
 
-func (usecaseOrder *UseCaseOrder) LegacyImport(file io.Reader, hasHeader bool) (*model.LegacyImportResult, error) {
-	scanner := bufio.NewScanner(file)
+// LegacyValidate runs the same parsing and aggregation of the import without
+// persisting the records or touching the cache.
+func (usecaseOrder *UseCaseOrder) LegacyValidate(file io.Reader, modelLegacyImportOptions *model.LegacyImportOptions) (*model.LegacyValidateResult, error) {
+	modelLegacyLayout, err := usecaseOrder.legacyImportOptionsValidate(modelLegacyImportOptions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	file, err = usecaseOrder.legacyFileSizeLimit(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	dataset, err := usecaseOrder.legacyParse(context.Background(), file, modelLegacyImportOptions, modelLegacyLayout, legacyImportProgressNone{})
 
-	if hasHeader {
-		scanner.Scan()
+	if err != nil {
+		return nil, err
 	}
 
-	linesCount := 0
-	modelUsers := model.Users{}
-	modelOrders := model.Orders{}
-	modelOrdersProducts := model.OrdersProducts{}
+	defer dataset.close()
 
-	modelLegacyRecordsError := model.LegacyRecordsError{}
-	mapUsers := make(map[int64]int)
-	mapOrders := make(map[int64]int)
+	errRecordValidate := dataset.errRecordValidate()
 
-	for scanner.Scan() {
-		linesCount++
-		record := scanner.Text()
+	modelLegacyValidateResult := &model.LegacyValidateResult{
+		Valid:           dataset.accepted(modelLegacyImportOptions),
+		Result:          *dataset.result(),
+		Errors:          errRecordValidate.RecordsError,
+		ErrorsTotal:     errRecordValidate.Total,
+		ErrorsTruncated: errRecordValidate.Truncated,
+	}
 
-		modelLegacy, err := recordToLegacy(record)
+	return modelLegacyValidateResult, nil
+}
 
-		if err != nil {
-			modelLegacyRecordsError = append(modelLegacyRecordsError, model.LegacyRecordError{Line: int64(linesCount), Message: err.Error()})
-			continue
-		}
+func (usecaseOrder *UseCaseOrder) legacyParse(ctx context.Context, file io.Reader, modelLegacyImportOptions *model.LegacyImportOptions, modelLegacyLayout *model.LegacyLayout, progress legacyImportProgress) (*legacyDataset, error) {
+	reader, release, err := usecaseOrder.newLegacyFileReader(file, modelLegacyImportOptions, modelLegacyLayout)
 
-		legacyUser(modelLegacy, &modelUsers, mapUsers)
-		legacyOrder(modelLegacy, &modelOrders, mapOrders)
-		legacyProduct(modelLegacy, &modelOrdersProducts)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := scanner.Err(); err != nil {
+	defer release()
+
+	dataset, err := usecaseOrder.newLegacyDataset()
+
+	if err != nil {
 		return nil, err
 	}
 
-	if len(modelLegacyRecordsError) > 0 {
-		jsonBytes, _ := json.Marshal(modelLegacyRecordsError)
-		return nil, ErrRecordValidate{Message: string(jsonBytes)}
+	conflicts := newLegacyConflicts()
+
+	err = dataset.read(ctx, reader, modelLegacyImportOptions, modelLegacyLayout, conflicts, progress)
+
+	if err == nil {
+		err = dataset.aggregate(modelLegacyImportOptions, modelLegacyLayout, conflicts)
+	}
+
+	if err != nil {
+		dataset.close()
+		return nil, err
 	}
 
+	return dataset, nil
+}
+
+// legacyReplace discards the current dataset and persists the imported one,
+// so the whole cache is cleared.
+func (usecaseOrder *UseCaseOrder) legacyReplace(modelLegacyImport *model.LegacyImport, dataset *legacyDataset) error {
 	usecaseOrder.Cache.Order().ClearAll()
 
-	err := usecaseOrder.Repository.Order().LegacyBulkInsert(&modelUsers, &modelOrders, &modelOrdersProducts)
+	return usecaseOrder.Repository.Order().LegacyBulkInsert(modelLegacyImport, dataset)
+}
+
+// legacyMerge upserts the imported records into the current dataset and
+// invalidates only the cache keys of the orders affected by the upsert, the
+// upserted orders and the orders of the upserted users. The whole cache is
+// cleared when a key can not be removed, a key left behind would keep the
+// details replaced by the merge.
+func (usecaseOrder *UseCaseOrder) legacyMerge(modelLegacyImport *model.LegacyImport, dataset *legacyDataset) error {
+	orderIDs, err := usecaseOrder.Repository.Order().LegacyBulkUpsert(modelLegacyImport, dataset)
 
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	modelLegacyImportResult := &model.LegacyImportResult{
-		Users:    len(modelUsers),
-		Orders:   len(modelOrders),
-		Products: len(modelOrdersProducts),
+	for _, orderID := range orderIDs {
+		err = usecaseOrder.Cache.Order().DelDetailsByOrderID(orderID)
+
+		if err != nil {
+			usecaseOrder.Cache.Order().ClearAll()
+			break
+		}
 	}
-	return modelLegacyImportResult, err
+
+	return nil
 }
 
-func recordToLegacy(record string) (*model.Legacy, error) {
-	if len(record) != 95 {
-		return nil, errors.New(OrderErrorMessageRecordSize)
+// recordToLegacyRecord extracts the fields of a fixed-width record
+func recordToLegacyRecord(record string, modelLegacyLayout *model.LegacyLayout) (*model.LegacyRecord, error) {
+	fixedWidthRecord := newLegacyFixedWidthRecord(record)
+
+	if fixedWidthRecord.width() != modelLegacyLayout.Size {
+		return nil, newLegacyRecordError(OrderErrorCodeRecordSize, fmt.Sprintf(OrderErrorMessageRecordSize, modelLegacyLayout.Size))
 	}
 
 	modelRecordLegacy := &model.LegacyRecord{
-		UserID:       record[0:10],
-		UserName:     strings.TrimSpace(record[10:55]),
-		OrderID:      record[55:65],
-		ProductID:    record[65:75],
-		ProductValue: strings.TrimSpace(record[75:87]),
-		BuyDate:      record[87:95],
+		UserID:       legacyFieldValue(fixedWidthRecord, modelLegacyLayout.Field(model.LegacyFieldUserID)),
+		UserName:     legacyFieldValue(fixedWidthRecord, modelLegacyLayout.Field(model.LegacyFieldUserName)),
+		OrderID:      legacyFieldValue(fixedWidthRecord, modelLegacyLayout.Field(model.LegacyFieldOrderID)),
+		ProductID:    legacyFieldValue(fixedWidthRecord, modelLegacyLayout.Field(model.LegacyFieldProductID)),
+		ProductValue: legacyFieldValue(fixedWidthRecord, modelLegacyLayout.Field(model.LegacyFieldProductValue)),
+		BuyDate:      legacyFieldValue(fixedWidthRecord, modelLegacyLayout.Field(model.LegacyFieldBuyDate)),
 	}
 
+	return modelRecordLegacy, nil
+}
+
+// legacyRecordToLegacy validates and converts the fields of a record of any
+// format, the values are checked against the validation policy
+func legacyRecordToLegacy(modelRecordLegacy *model.LegacyRecord, modelLegacyLayout *model.LegacyLayout, validation *orderValidation) (*model.Legacy, error) {
 	modelLegacy := &model.Legacy{}
-	errMessages := []string{}
+	errs := legacyRecordErrors{}
 	var err error
 
+	fieldError := func(code string, message string, field string, value string) {
+		errs = append(errs, model.LegacyFieldError{Code: code, Field: field, Value: value, Message: message})
+	}
+
+	ruleError := func(code string, message string, rule string, field string, value string) {
+		errs = append(errs, model.LegacyFieldError{Code: code, Field: field, Value: value, Rule: rule, Message: message})
+	}
+
+	idRuleError := func(code string, label string, rule string, field string, value string) {
+		ruleError(code, fmt.Sprintf(OrderErrorMessageRuleViolated, label, rule), rule, field, value)
+	}
+
 	modelLegacy.UserID, err = strconv.ParseInt(modelRecordLegacy.UserID, 10, 64)
 
 	if err != nil {
-		errMessages = append(errMessages, OrderErrorMessageUserIDInvalid)
+		fieldError(OrderErrorCodeUserIDInvalid, OrderErrorMessageUserIDInvalid, model.LegacyFieldUserID, modelRecordLegacy.UserID)
+	} else if !validation.id(model.ValidationRuleUserIDRange, modelLegacy.UserID) {
+		idRuleError(OrderErrorCodeUserIDOutOfRange, "UserID", model.ValidationRuleUserIDRange, model.LegacyFieldUserID, modelRecordLegacy.UserID)
 	}
 
 	modelLegacy.UserName = util.FormatTitle(modelRecordLegacy.UserName)
 
-	if len(modelLegacy.UserName) < 2 {
-		errMessages = append(errMessages, OrderErrorMessageUserNameInvalid)
+	if rule := validation.userName(modelLegacy.UserName); rule != "" {
+		ruleError(OrderErrorCodeUserNameInvalid, OrderErrorMessageUserNameInvalid, rule, model.LegacyFieldUserName, modelRecordLegacy.UserName)
 	}
 
 	modelLegacy.OrderID, err = strconv.ParseInt(modelRecordLegacy.OrderID, 10, 64)
 
 	if err != nil {
-		errMessages = append(errMessages, OrderErrorMessageOrderIDInvalid)
+		fieldError(OrderErrorCodeOrderIDInvalid, OrderErrorMessageOrderIDInvalid, model.LegacyFieldOrderID, modelRecordLegacy.OrderID)
+	} else if !validation.id(model.ValidationRuleOrderIDRange, modelLegacy.OrderID) {
+		idRuleError(OrderErrorCodeOrderIDOutOfRange, "OrderID", model.ValidationRuleOrderIDRange, model.LegacyFieldOrderID, modelRecordLegacy.OrderID)
 	}
 
 	modelLegacy.ProductID, err = strconv.ParseInt(modelRecordLegacy.ProductID, 10, 64)
 
 	if err != nil {
-		errMessages = append(errMessages, OrderErrorMessageProductIDInvalid)
+		fieldError(OrderErrorCodeProductIDInvalid, OrderErrorMessageProductIDInvalid, model.LegacyFieldProductID, modelRecordLegacy.ProductID)
+	} else if !validation.id(model.ValidationRuleProductIDRange, modelLegacy.ProductID) {
+		idRuleError(OrderErrorCodeProductIDOutOfRange, "ProductID", model.ValidationRuleProductIDRange, model.LegacyFieldProductID, modelRecordLegacy.ProductID)
 	}
 
-	modelLegacy.ProductValue, err = strconv.ParseFloat(modelRecordLegacy.ProductValue, 64)
+	modelLegacy.ProductValue, err = legacyFieldDecimal(modelRecordLegacy.ProductValue, modelLegacyLayout.Field(model.LegacyFieldProductValue))
 
 	if err != nil {
-		errMessages = append(errMessages, OrderErrorMessageProductValueInvalid)
+		fieldError(OrderErrorCodeProductValueInvalid, OrderErrorMessageProductValueInvalid, model.LegacyFieldProductValue, modelRecordLegacy.ProductValue)
+	} else if !validation.productValue(modelLegacy.ProductValue) {
+		idRuleError(OrderErrorCodeProductValueOutOfRange, "ProductValue", model.ValidationRuleProductValueRange, model.LegacyFieldProductValue, modelRecordLegacy.ProductValue)
 	}
 
-	buyDate, err := time.Parse("20060102", modelRecordLegacy.BuyDate)
+	buyDate, err := time.Parse(legacyFieldDateFormat(modelLegacyLayout.Field(model.LegacyFieldBuyDate)), modelRecordLegacy.BuyDate)
 
 	if err != nil {
-		errMessages = append(errMessages, OrderErrorMessageBuyDateInvalid)
-	} else {
-		if buyDate.Before(OrderBuyDateMin) || buyDate.After(OrderBuyDateMax) {
-			errMessages = append(errMessages, OrderErrorMessageBuyDateBetween)
-		}
+		fieldError(OrderErrorCodeBuyDateInvalid, OrderErrorMessageBuyDateInvalid, model.LegacyFieldBuyDate, modelRecordLegacy.BuyDate)
+	} else if !validation.buyDate(buyDate) {
+		ruleError(OrderErrorCodeBuyDateBetween, validation.buyDateMessage(OrderErrorMessageBuyDateBetween), model.ValidationRuleBuyDateWindow, model.LegacyFieldBuyDate, modelRecordLegacy.BuyDate)
 	}
 
-	if len(errMessages) > 0 {
-		return nil, errors.New(strings.Join(errMessages, ";"))
+	if len(errs) > 0 {
+		return nil, errs
 	}
 
 	modelLegacy.BuyDate = buyDate.Format("2006-01-02")
@@ -212,6 +484,60 @@ func recordToLegacy(record string) (*model.Legacy, error) {
 	return modelLegacy, nil
 }
 
+// legacyFieldValue extracts the field of the fixed-width record removing its pad characters
+func legacyFieldValue(record *legacyFixedWidthRecord, field *model.LegacyLayoutField) string {
+	return legacyFieldTrim(record.field(field), field)
+}
+
+func legacyFieldTrim(value string, field *model.LegacyLayoutField) string {
+	pad := field.Pad
+
+	if pad == "" {
+		pad = " "
+	}
+
+	switch field.Trim {
+	case model.LegacyFieldTrimLeft:
+		value = strings.TrimLeft(value, pad)
+	case model.LegacyFieldTrimRight:
+		value = strings.TrimRight(value, pad)
+	case model.LegacyFieldTrimBoth:
+		value = strings.Trim(value, pad)
+	}
+
+	// a numeric field filled with zeros is trimmed to empty
+	if value == "" && pad == "0" {
+		value = "0"
+	}
+
+	return value
+}
+
+func legacyFieldDecimal(value string, field *model.LegacyLayoutField) (model.Money, error) {
+	// the implied decimal places are separated by a dot before the conversion
+	if field.Decimals > 0 && !strings.Contains(value, ".") {
+		if _, err := strconv.ParseUint(value, 10, 64); err != nil {
+			return 0, err
+		}
+
+		if len(value) <= field.Decimals {
+			value = strings.Repeat("0", field.Decimals-len(value)+1) + value
+		}
+
+		value = value[:len(value)-field.Decimals] + "." + value[len(value)-field.Decimals:]
+	}
+
+	return model.ParseMoney(value)
+}
+
+func legacyFieldDateFormat(field *model.LegacyLayoutField) string {
+	if field.Format == "" {
+		return "20060102"
+	}
+
+	return field.Format
+}
+
 func legacyUser(modelLegacy *model.Legacy, modelUsers *model.Users, mapUsers map[int64]int) {
 	index, ok := mapUsers[modelLegacy.UserID]
 
@@ -242,40 +568,110 @@ func legacyOrder(modelLegacy *model.Legacy, modelOrders *model.Orders, mapOrders
 		index = len(*modelOrders) - 1
 		mapOrders[modelLegacy.OrderID] = index
 	} else {
-		if (*modelOrders)[index].UserID != modelLegacy.UserID {
-			log.Fatal("UserID divergent")
-		}
-
-		(*modelOrders)[index].Total =
-			util.MathRoundPrecision((*modelOrders)[index].Total+modelLegacy.ProductValue, 2)
+		(*modelOrders)[index].Total += modelLegacy.ProductValue
 	}
 
 	return
 }
 
-func legacyProduct(modelLegacy *model.Legacy, modelOrdersProducts *model.OrdersProducts) {
-	*modelOrdersProducts = append(*modelOrdersProducts, model.OrderProduct{
+func legacyProduct(modelLegacy *model.Legacy) *model.OrderProduct {
+	return &model.OrderProduct{
 		OrderID:      modelLegacy.OrderID,
 		ProductID:    modelLegacy.ProductID,
 		ProductValue: modelLegacy.ProductValue,
-	})
+	}
+}
+
+// legacyImportOptionsValidate validates the options and returns the layout selected
+func (usecaseOrder *UseCaseOrder) legacyImportOptionsValidate(modelLegacyImportOptions *model.LegacyImportOptions) (*model.LegacyLayout, error) {
+	err := LegacyImportOptionsValidate(modelLegacyImportOptions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	modelLegacyLayout, ok := usecaseOrder.legacyLayouts[modelLegacyImportOptions.Layout]
+
+	if !ok {
+		return nil, ErrParamValidate{Message: OrderErrorMessageLayoutInvalid}
+	}
+
+	err = legacyControlOptionsValidate(modelLegacyImportOptions, modelLegacyLayout)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return modelLegacyLayout, nil
+}
+
+func LegacyImportOptionsValidate(modelLegacyImportOptions *model.LegacyImportOptions) error {
+	switch modelLegacyImportOptions.Mode {
+	case "":
+		modelLegacyImportOptions.Mode = model.LegacyImportModeReplace
+	case model.LegacyImportModeReplace, model.LegacyImportModeMerge:
+	default:
+		return ErrParamValidate{Message: OrderErrorMessageModeInvalid}
+	}
+
+	switch modelLegacyImportOptions.Target {
+	case "":
+		modelLegacyImportOptions.Target = model.LegacyImportTargetLive
+	case model.LegacyImportTargetLive:
+	case model.LegacyImportTargetStage:
+		if modelLegacyImportOptions.Mode != model.LegacyImportModeReplace {
+			return ErrParamValidate{Message: OrderErrorMessageTargetStageMode}
+		}
+	default:
+		return ErrParamValidate{Message: OrderErrorMessageTargetInvalid}
+	}
+
+	switch modelLegacyImportOptions.Format {
+	case "":
+		modelLegacyImportOptions.Format = model.LegacyImportFormatFixedWidth
+		// the header and the trailer are only read in the fixed-width format
+		modelLegacyImportOptions.FormatByFileName = !modelLegacyImportOptions.HasHeader && !modelLegacyImportOptions.HasTrailer
+	case model.LegacyImportFormatFixedWidth, model.LegacyImportFormatCSV, model.LegacyImportFormatNDJSON:
+	default:
+		return ErrParamValidate{Message: OrderErrorMessageFormatInvalid}
+	}
+
+	switch modelLegacyImportOptions.Encoding {
+	case "":
+		modelLegacyImportOptions.Encoding = model.LegacyImportEncodingAuto
+	case model.LegacyImportEncodingAuto, model.LegacyImportEncodingUTF8, model.LegacyImportEncodingLatin1, model.LegacyImportEncodingWindows1252:
+	default:
+		return ErrParamValidate{Message: OrderErrorMessageEncodingInvalid}
+	}
+
+	switch modelLegacyImportOptions.Compression {
+	case "", model.LegacyImportCompressionGzip, model.LegacyImportCompressionZip:
+	default:
+		return ErrParamValidate{Message: OrderErrorMessageCompressionInvalid}
+	}
+
+	if modelLegacyImportOptions.Layout == "" {
+		modelLegacyImportOptions.Layout = model.LegacyLayoutNameDefault
+	}
+
+	return nil
 }
 
-func OrderRangeBuyDateValidate(modelOrderRangeBuyDate *model.OrderRangeBuyDate) error {
+// orderRangeBuyDateValidate checks the range of the query against the
+// validation policy evaluated at the request
+func orderRangeBuyDateValidate(modelOrderRangeBuyDate *model.OrderRangeBuyDate, validation *orderValidation) error {
 	messages := []string{}
 
 	if modelOrderRangeBuyDate.From.IsZero() {
 		messages = append(messages, OrderRangeBuyDateErrorMessageFromEmpty)
-	} else if modelOrderRangeBuyDate.From.Before(OrderBuyDateMin) ||
-		modelOrderRangeBuyDate.From.After(OrderBuyDateMax) {
-		messages = append(messages, OrderRangeBuyDateErrorMessageFromBetween)
+	} else if !validation.buyDate(modelOrderRangeBuyDate.From) {
+		messages = append(messages, validation.buyDateMessage(OrderRangeBuyDateErrorMessageFromBetween))
 	}
 
 	if modelOrderRangeBuyDate.To.IsZero() {
 		messages = append(messages, OrderRangeBuyDateErrorMessageToEmpty)
-	} else if modelOrderRangeBuyDate.To.Before(OrderBuyDateMin) ||
-		modelOrderRangeBuyDate.To.After(OrderBuyDateMax) {
-		messages = append(messages, OrderRangeBuyDateErrorMessageToBetween)
+	} else if !validation.buyDate(modelOrderRangeBuyDate.To) {
+		messages = append(messages, validation.buyDateMessage(OrderRangeBuyDateErrorMessageToBetween))
 	}
 
 	if len(messages) > 0 {
@@ -286,8 +682,8 @@ func OrderRangeBuyDateValidate(modelOrderRangeBuyDate *model.OrderRangeBuyDate)
 
 	if dateDiffDays < 0 {
 		messages = append(messages, OrderRangeBuyDateErrorMessageToSmallerFrom)
-	} else if dateDiffDays > 31 {
-		messages = append(messages, OrderRangeBuyDateErrorMessageRangeError)
+	} else if !validation.rangeDays(dateDiffDays) {
+		messages = append(messages, fmt.Sprintf(OrderRangeBuyDateErrorMessageRangeError, validation.policy.RangeMaxDays))
 	}
 
 	if len(messages) > 0 {
@@ -0,0 +1,300 @@
+package usecase
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+)
+
+var (
+	OrderErrorMessageCompressionInvalid    = fmt.Sprintf("The param compression is invalid, the allowed values are %v and %v", model.LegacyImportCompressionGzip, model.LegacyImportCompressionZip)
+	OrderErrorMessageDecompress            = "Error decompressing file: %v"
+	OrderErrorMessageDecompressedSizeLimit = "The decompressed content exceeds the limit of %d bytes"
+	OrderErrorMessageArchiveWithoutFile    = "The zip archive does not contain any file"
+	OrderErrorMessageFileSizeLimit         = "The file exceeds the limit of %d bytes"
+	OrderErrorMessageFileRead              = "Error reading file: %v"
+	OrderErrorMessageFormFieldAfterFile    = "The field %s of the form must be sent before the file"
+	legacyImportFormatsByExtension         = map[string]string{
+		".csv":    model.LegacyImportFormatCSV,
+		".ndjson": model.LegacyImportFormatNDJSON,
+		".jsonl":  model.LegacyImportFormatNDJSON,
+	}
+)
+
+// newLegacyFileReader creates the reader of the uploaded file, decompressing it
+// when needed, and returns the function to release the resources used.
+func (usecaseOrder *UseCaseOrder) newLegacyFileReader(file io.Reader, modelLegacyImportOptions *model.LegacyImportOptions, modelLegacyLayout *model.LegacyLayout) (legacyReader, func(), error) {
+	switch modelLegacyImportOptions.Compression {
+	case model.LegacyImportCompressionGzip:
+		return usecaseOrder.newLegacyReaderGzip(file, modelLegacyImportOptions, modelLegacyLayout)
+	case model.LegacyImportCompressionZip:
+		return usecaseOrder.newLegacyReaderZip(file, modelLegacyImportOptions, modelLegacyLayout)
+	default:
+		return usecaseOrder.newLegacyReader(file, modelLegacyImportOptions, modelLegacyLayout), func() {}, nil
+	}
+}
+
+func (usecaseOrder *UseCaseOrder) newLegacyReaderGzip(file io.Reader, modelLegacyImportOptions *model.LegacyImportOptions, modelLegacyLayout *model.LegacyLayout) (legacyReader, func(), error) {
+	gzipReader, err := gzip.NewReader(file)
+
+	if err != nil {
+		return nil, nil, ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageDecompress, err)}
+	}
+
+	fileName := gzipReader.Name
+
+	if fileName == "" {
+		fileName = strings.TrimSuffix(modelLegacyImportOptions.FileName, ".gz")
+	}
+
+	reader := usecaseOrder.newLegacyReader(
+		usecaseOrder.newLegacyLimitReader(gzipReader, new(int64)),
+		legacyImportOptionsByFileName(modelLegacyImportOptions, fileName),
+		modelLegacyLayout,
+	)
+
+	return reader, func() { gzipReader.Close() }, nil
+}
+
+func (usecaseOrder *UseCaseOrder) newLegacyReaderZip(file io.Reader, modelLegacyImportOptions *model.LegacyImportOptions, modelLegacyLayout *model.LegacyLayout) (legacyReader, func(), error) {
+	// the zip directory is at the end of the archive, so it is read from a file
+	fileZip, ok := file.(interface {
+		io.ReaderAt
+		io.Seeker
+	})
+
+	release := func() {}
+
+	if !ok {
+		fileTemp, err := os.CreateTemp(usecaseOrder.Config.LegacyImportTempDir, "legacy-import-*.zip")
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		release = func() {
+			fileTemp.Close()
+			os.Remove(fileTemp.Name())
+		}
+
+		if _, err := io.Copy(fileTemp, file); err != nil {
+			release()
+			return nil, nil, err
+		}
+
+		fileZip = fileTemp
+	}
+
+	size, err := fileZip.Seek(0, io.SeekEnd)
+
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+
+	zipReader, err := zip.NewReader(fileZip, size)
+
+	if err != nil {
+		release()
+		return nil, nil, ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageDecompress, err)}
+	}
+
+	files := []*zip.File{}
+
+	for _, zipFile := range zipReader.File {
+		if !zipFile.FileInfo().IsDir() {
+			files = append(files, zipFile)
+		}
+	}
+
+	if len(files) == 0 {
+		release()
+		return nil, nil, ErrFileValidate{Message: OrderErrorMessageArchiveWithoutFile}
+	}
+
+	reader := &legacyReaderZip{
+		usecaseOrder: usecaseOrder,
+		files:        files,
+		options:      modelLegacyImportOptions,
+		layout:       modelLegacyLayout,
+		decompressed: new(int64),
+	}
+
+	return reader, func() { reader.close(); release() }, nil
+}
+
+// legacyReaderZip reads all the files of the archive as a single dataset
+type legacyReaderZip struct {
+	usecaseOrder *UseCaseOrder
+	files        []*zip.File
+	options      *model.LegacyImportOptions
+	layout       *model.LegacyLayout
+	// bytes decompressed of all the files
+	decompressed *int64
+	current      legacyReader
+	currentFile  io.ReadCloser
+}
+
+func (reader *legacyReaderZip) Read() (*legacyReaderRecord, error) {
+	for {
+		if reader.current == nil {
+			if len(reader.files) == 0 {
+				return nil, io.EOF
+			}
+
+			currentFile, err := reader.files[0].Open()
+
+			if err != nil {
+				return nil, ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageDecompress, err)}
+			}
+
+			reader.currentFile = currentFile
+			reader.current = reader.usecaseOrder.newLegacyReader(
+				reader.usecaseOrder.newLegacyLimitReader(currentFile, reader.decompressed),
+				legacyImportOptionsByFileName(reader.options, reader.files[0].Name),
+				reader.layout,
+			)
+		}
+
+		modelLegacyReaderRecord, err := reader.current.Read()
+
+		if err == io.EOF {
+			reader.close()
+			reader.files = reader.files[1:]
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		modelLegacyReaderRecord.file = reader.files[0].Name
+
+		return modelLegacyReaderRecord, nil
+	}
+}
+
+func (reader *legacyReaderZip) close() {
+	if reader.currentFile != nil {
+		reader.currentFile.Close()
+	}
+
+	reader.current = nil
+	reader.currentFile = nil
+}
+
+// legacyImportOptionsByFileName returns the options with the format
+// identified by the extension of the decompressed file when the format was
+// not informed, the fixed-width format is used when the extension is unknown.
+func legacyImportOptionsByFileName(modelLegacyImportOptions *model.LegacyImportOptions, fileName string) *model.LegacyImportOptions {
+	modelLegacyImportOptionsFile := *modelLegacyImportOptions
+	modelLegacyImportOptionsFile.FileName = fileName
+
+	if !modelLegacyImportOptions.FormatByFileName {
+		return &modelLegacyImportOptionsFile
+	}
+
+	modelLegacyImportOptionsFile.Format = model.LegacyImportFormatFixedWidth
+
+	if format, ok := legacyImportFormatsByExtension[strings.ToLower(path.Ext(fileName))]; ok {
+		modelLegacyImportOptionsFile.Format = format
+	}
+
+	return &modelLegacyImportOptionsFile
+}
+
+// legacyLimitReader fails the reading of the decompressed content when it
+// exceeds the configured limit, protecting the import against zip bombs.
+type legacyLimitReader struct {
+	reader io.Reader
+	limit  int64
+	// bytes read, shared by the files of the same archive
+	read *int64
+}
+
+func (usecaseOrder *UseCaseOrder) newLegacyLimitReader(reader io.Reader, read *int64) *legacyLimitReader {
+	return &legacyLimitReader{
+		reader: reader,
+		limit:  usecaseOrder.Config.LegacyImportMaxDecompressedSize,
+		read:   read,
+	}
+}
+
+func (reader *legacyLimitReader) Read(p []byte) (int, error) {
+	n, err := reader.reader.Read(p)
+
+	*reader.read += int64(n)
+
+	if reader.limit > 0 && *reader.read > reader.limit {
+		return 0, ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageDecompressedSizeLimit, reader.limit)}
+	}
+
+	if err != nil && err != io.EOF {
+		return n, ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageDecompress, err)}
+	}
+
+	return n, err
+}
+
+// legacyFileSizeLimit fails the import of a file larger than the configured
+// limit, the size of a seekable file is checked before reading it.
+func (usecaseOrder *UseCaseOrder) legacyFileSizeLimit(file io.Reader) (io.Reader, error) {
+	limit := usecaseOrder.Config.LegacyImportMaxSize
+
+	if limit <= 0 {
+		return file, nil
+	}
+
+	fileSeeker, ok := file.(io.Seeker)
+
+	if !ok {
+		return &legacySizeLimitReader{reader: file, limit: limit}, nil
+	}
+
+	offset, err := fileSeeker.Seek(0, io.SeekCurrent)
+
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := fileSeeker.Seek(0, io.SeekEnd)
+
+	if err == nil {
+		_, err = fileSeeker.Seek(offset, io.SeekStart)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if size-offset > limit {
+		return nil, ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageFileSizeLimit, limit)}
+	}
+
+	return file, nil
+}
+
+// legacySizeLimitReader fails the reading of a file streamed larger than the limit
+type legacySizeLimitReader struct {
+	reader io.Reader
+	limit  int64
+	read   int64
+}
+
+func (reader *legacySizeLimitReader) Read(p []byte) (int, error) {
+	n, err := reader.reader.Read(p)
+
+	reader.read += int64(n)
+
+	if reader.read > reader.limit {
+		return 0, ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageFileSizeLimit, reader.limit)}
+	}
+
+	return n, err
+}
@@ -0,0 +1,252 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	mock_cache "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/cache"
+	mock_repository "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/util"
+)
+
+func TestOrderLegacyArchive(t *testing.T) {
+	recordFixedWidth := "0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308"
+	recordFixedWidthInvalid := "000000007x                              Palmer Prosacco00000007530000000003     1836.7420210308"
+
+	type test struct {
+		name             string
+		inputFile        func() io.Reader
+		inputCompression string
+		inputFormat      string
+		inputFileName    string
+		inputMaxSize     int64
+		wantResult       *model.LegacyValidateResult
+		wantError        error
+	}
+
+	tests := []test{
+		{
+			name: "CompressionInvalidError",
+			inputFile: func() io.Reader {
+				return bytes.NewBufferString("")
+			},
+			inputCompression: "rar",
+			wantResult:       nil,
+			wantError:        ErrParamValidate{Message: OrderErrorMessageCompressionInvalid},
+		},
+		{
+			name: "GzipInvalidError",
+			inputFile: func() io.Reader {
+				return bytes.NewBufferString(recordFixedWidth)
+			},
+			inputCompression: model.LegacyImportCompressionGzip,
+			wantResult:       nil,
+			wantError:        ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageDecompress, gzip.ErrHeader)},
+		},
+		{
+			name: "GzipSizeLimitError",
+			inputFile: func() io.Reader {
+				return testOrderLegacyGzip(t, strings.Repeat(recordFixedWidth+"\n", 1000))
+			},
+			inputCompression: model.LegacyImportCompressionGzip,
+			inputMaxSize:     1000,
+			wantResult:       nil,
+			wantError:        ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageDecompressedSizeLimit, 1000)},
+		},
+		{
+			name: "Gzip",
+			inputFile: func() io.Reader {
+				return testOrderLegacyGzip(t, strings.Join([]string{
+					"user_id,user_name,order_id,product_id,product_value,buy_date",
+					"70,Palmer Prosacco,753,3,1836.74,20210308",
+					"7x,Palmer Prosacco,753,3,1836.74,20210308",
+				}, "\n"))
+			},
+			inputCompression: model.LegacyImportCompressionGzip,
+			inputFileName:    "data.csv.gz",
+			wantResult: &model.LegacyValidateResult{
+				Valid: false,
+				Result: model.LegacyImportResult{
+					Users:    1,
+					Orders:   1,
+					Products: 1,
+					Accepted: 1,
+					Rejected: 1,
+				},
+				Errors: model.LegacyRecordsError{
+					{
+						Line:    3,
+						Message: OrderErrorMessageUserIDInvalid,
+						Errors: []model.LegacyFieldError{
+							{Code: OrderErrorCodeUserIDInvalid, Field: model.LegacyFieldUserID, Value: "7x", Message: OrderErrorMessageUserIDInvalid},
+						},
+					},
+				},
+				ErrorsTotal: 1,
+			},
+			wantError: nil,
+		},
+		{
+			// the format informed is kept for any name of the decompressed file
+			name: "GzipFormatCSV",
+			inputFile: func() io.Reader {
+				return testOrderLegacyGzip(t, strings.Join([]string{
+					"user_id,user_name,order_id,product_id,product_value,buy_date",
+					"70,Palmer Prosacco,753,3,1836.74,20210308",
+				}, "\n"))
+			},
+			inputCompression: model.LegacyImportCompressionGzip,
+			inputFormat:      model.LegacyImportFormatCSV,
+			inputFileName:    "data.txt.gz",
+			wantResult: &model.LegacyValidateResult{
+				Valid: true,
+				Result: model.LegacyImportResult{
+					Users:    1,
+					Orders:   1,
+					Products: 1,
+					Accepted: 1,
+				},
+				Errors: model.LegacyRecordsError{},
+			},
+			wantError: nil,
+		},
+		{
+			name: "ZipWithoutFileError",
+			inputFile: func() io.Reader {
+				return testOrderLegacyZip(t, []testOrderLegacyZipFile{})
+			},
+			inputCompression: model.LegacyImportCompressionZip,
+			wantResult:       nil,
+			wantError:        ErrFileValidate{Message: OrderErrorMessageArchiveWithoutFile},
+		},
+		{
+			name: "ZipSizeLimitError",
+			inputFile: func() io.Reader {
+				return testOrderLegacyZip(t, []testOrderLegacyZipFile{
+					{name: "data_1.txt", content: strings.Repeat(recordFixedWidth+"\n", 6)},
+					{name: "data_2.txt", content: strings.Repeat(recordFixedWidth+"\n", 6)},
+				})
+			},
+			inputCompression: model.LegacyImportCompressionZip,
+			inputMaxSize:     1000,
+			wantResult:       nil,
+			wantError:        ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageDecompressedSizeLimit, 1000)},
+		},
+		{
+			name: "Zip",
+			inputFile: func() io.Reader {
+				return testOrderLegacyZip(t, []testOrderLegacyZipFile{
+					{name: "data_1.txt", content: strings.Join([]string{recordFixedWidth, recordFixedWidthInvalid}, "\n")},
+					{name: "partner/", content: ""},
+					{name: "partner/data_2.ndjson", content: strings.Join([]string{
+						`{"user_id": 75, "user_name": "Bobbie Batz", "order_id": 798, "product_id": 2, "product_value": 1578.57, "buy_date": "20211116"}`,
+						`{"user_id": 75`,
+					}, "\n")},
+				})
+			},
+			inputCompression: model.LegacyImportCompressionZip,
+			wantResult: &model.LegacyValidateResult{
+				Valid: false,
+				Result: model.LegacyImportResult{
+					Users:    2,
+					Orders:   2,
+					Products: 2,
+					Accepted: 2,
+					Rejected: 2,
+				},
+				Errors: model.LegacyRecordsError{
+					{
+						File:    "data_1.txt",
+						Line:    2,
+						Message: OrderErrorMessageUserIDInvalid,
+						Errors: []model.LegacyFieldError{
+							{Code: OrderErrorCodeUserIDInvalid, Field: model.LegacyFieldUserID, ColumnStart: 1, ColumnEnd: 10, Value: "000000007x", Message: OrderErrorMessageUserIDInvalid},
+						},
+					},
+					{
+						File:    "partner/data_2.ndjson",
+						Line:    2,
+						Message: OrderErrorMessageRecordJSONInvalid,
+						Errors: []model.LegacyFieldError{
+							{Code: OrderErrorCodeRecordJSONInvalid, Message: OrderErrorMessageRecordJSONInvalid},
+						},
+					},
+				},
+				ErrorsTotal: 2,
+			},
+			wantError: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepository := new(mock_repository.MockRepository)
+			mockCache := new(mock_cache.MockCache)
+
+			usecaseOrder := NewOrder(mockRepository, mockCache, &util.Config{LegacyImportMaxDecompressedSize: tt.inputMaxSize})
+
+			modelLegacyImportOptions := &model.LegacyImportOptions{
+				Compression: tt.inputCompression,
+				Format:      tt.inputFormat,
+				FileName:    tt.inputFileName,
+			}
+
+			modelLegacyValidateResult, err := usecaseOrder.LegacyValidate(tt.inputFile(), modelLegacyImportOptions)
+
+			if !reflect.DeepEqual(err, tt.wantError) {
+				t.Errorf("LegacyValidate() got error = %v, want = %v.", err, tt.wantError)
+			}
+
+			if !reflect.DeepEqual(modelLegacyValidateResult, tt.wantResult) {
+				t.Errorf("LegacyValidate() got result = %v, want = %v.", modelLegacyValidateResult, tt.wantResult)
+			}
+		})
+	}
+}
+
+func testOrderLegacyGzip(t *testing.T, content string) io.Reader {
+	buffer := &bytes.Buffer{}
+	writer := gzip.NewWriter(buffer)
+
+	if _, err := writer.Write([]byte(content)); err != nil {
+		t.Fatalf("Failed to write gzip content: %v", err)
+	}
+
+	writer.Close()
+
+	return buffer
+}
+
+type testOrderLegacyZipFile struct {
+	name    string
+	content string
+}
+
+// testOrderLegacyZip returns a buffer, so the archive is copied to a temporary file
+func testOrderLegacyZip(t *testing.T, files []testOrderLegacyZipFile) io.Reader {
+	buffer := &bytes.Buffer{}
+	writer := zip.NewWriter(buffer)
+
+	for _, file := range files {
+		fileWriter, err := writer.Create(file.name)
+
+		if err == nil {
+			_, err = fileWriter.Write([]byte(file.content))
+		}
+
+		if err != nil {
+			t.Fatalf("Failed to write zip content: %v", err)
+		}
+	}
+
+	writer.Close()
+
+	return buffer
+}
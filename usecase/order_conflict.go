@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"strconv"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+)
+
+var (
+	OrderErrorMessageOrderUserDivergent    = "OrderID belongs to more than one UserID"
+	OrderErrorMessageUserNameDivergent     = "UserID has more than one UserName"
+	OrderErrorMessageOrderProductDuplicate = "OrderID, ProductID and ProductValue duplicated"
+)
+
+type legacyOrderProductKey struct {
+	orderID      int64
+	productID    int64
+	productValue model.Money
+}
+
+// hash returns a compact key of the product, two products with the same hash
+// are compared by the whole key before being considered duplicated
+func (key legacyOrderProductKey) hash() uint64 {
+	var bytes [24]byte
+
+	binary.LittleEndian.PutUint64(bytes[0:], uint64(key.orderID))
+	binary.LittleEndian.PutUint64(bytes[8:], uint64(key.productID))
+	binary.LittleEndian.PutUint64(bytes[16:], uint64(key.productValue))
+
+	hash := fnv.New64a()
+	hash.Write(bytes[:])
+
+	return hash.Sum64()
+}
+
+func newLegacyOrderProductKey(modelLegacy *model.Legacy) legacyOrderProductKey {
+	return legacyOrderProductKey{modelLegacy.OrderID, modelLegacy.ProductID, modelLegacy.ProductValue}
+}
+
+// legacyConflicts validates the records between them. All the records of an
+// order with more than one user and of a user with more than one name are in
+// conflict, because it is not possible to know which one is right, while only
+// the repetitions of a duplicated product are in conflict.
+//
+// The records are not kept in memory, so they are visited twice: index finds
+// the keys in conflict and collect gathers the lines of those keys only.
+type legacyConflicts struct {
+	// user of each order and the lines of the orders with more than one user
+	ordersUser      map[int64]int64
+	ordersDivergent map[int64][]model.LegacyRecordLine
+	// name of each user and the lines of the users with more than one name
+	usersName      map[int64]string
+	usersDivergent map[int64][]model.LegacyRecordLine
+	// hash of each product and the lines of the products with a repeated hash
+	productsHash      map[uint64]bool
+	productsDuplicate map[legacyOrderProductKey][]model.LegacyRecordLine
+}
+
+func newLegacyConflicts() *legacyConflicts {
+	return &legacyConflicts{
+		ordersUser:        make(map[int64]int64),
+		ordersDivergent:   make(map[int64][]model.LegacyRecordLine),
+		usersName:         make(map[int64]string),
+		usersDivergent:    make(map[int64][]model.LegacyRecordLine),
+		productsHash:      make(map[uint64]bool),
+		productsDuplicate: make(map[legacyOrderProductKey][]model.LegacyRecordLine),
+	}
+}
+
+// index visits a valid record of the file for the first time
+func (conflicts *legacyConflicts) index(modelLegacy *model.Legacy) {
+	if userID, ok := conflicts.ordersUser[modelLegacy.OrderID]; !ok {
+		conflicts.ordersUser[modelLegacy.OrderID] = modelLegacy.UserID
+	} else if userID != modelLegacy.UserID {
+		conflicts.ordersDivergent[modelLegacy.OrderID] = nil
+	}
+
+	if userName, ok := conflicts.usersName[modelLegacy.UserID]; !ok {
+		conflicts.usersName[modelLegacy.UserID] = modelLegacy.UserName
+	} else if userName != modelLegacy.UserName {
+		conflicts.usersDivergent[modelLegacy.UserID] = nil
+	}
+
+	productHash := newLegacyOrderProductKey(modelLegacy).hash()
+
+	_, repeated := conflicts.productsHash[productHash]
+	conflicts.productsHash[productHash] = repeated
+}
+
+// indexed releases the indexes of the first visit, only the keys in conflict
+// are needed to collect the lines
+func (conflicts *legacyConflicts) indexed() {
+	for productHash, repeated := range conflicts.productsHash {
+		if !repeated {
+			delete(conflicts.productsHash, productHash)
+		}
+	}
+
+	conflicts.ordersUser = nil
+	conflicts.usersName = nil
+}
+
+// collect visits a valid record of the file for the second time, in the
+// same order of the first one
+func (conflicts *legacyConflicts) collect(line model.LegacyRecordLine, modelLegacy *model.Legacy) {
+	if lines, ok := conflicts.ordersDivergent[modelLegacy.OrderID]; ok {
+		conflicts.ordersDivergent[modelLegacy.OrderID] = append(lines, line)
+	}
+
+	if lines, ok := conflicts.usersDivergent[modelLegacy.UserID]; ok {
+		conflicts.usersDivergent[modelLegacy.UserID] = append(lines, line)
+	}
+
+	productKey := newLegacyOrderProductKey(modelLegacy)
+
+	if conflicts.productsHash[productKey.hash()] {
+		conflicts.productsDuplicate[productKey] = append(conflicts.productsDuplicate[productKey], line)
+	}
+}
+
+// check returns the error of a record in conflict and the lines in conflict
+// with it, including its own line. The divergent order has priority over the
+// divergent user and both over the duplicated product.
+func (conflicts *legacyConflicts) check(line model.LegacyRecordLine, modelLegacy *model.Legacy) ([]model.LegacyRecordLine, legacyRecordErrors) {
+	if lines, ok := conflicts.ordersDivergent[modelLegacy.OrderID]; ok {
+		return lines, legacyRecordErrors{{
+			Code:    OrderErrorCodeOrderUserDivergent,
+			Field:   model.LegacyFieldUserID,
+			Value:   strconv.FormatInt(modelLegacy.UserID, 10),
+			Message: OrderErrorMessageOrderUserDivergent,
+		}}
+	}
+
+	if lines, ok := conflicts.usersDivergent[modelLegacy.UserID]; ok {
+		return lines, legacyRecordErrors{{
+			Code:    OrderErrorCodeUserNameDivergent,
+			Field:   model.LegacyFieldUserName,
+			Value:   modelLegacy.UserName,
+			Message: OrderErrorMessageUserNameDivergent,
+		}}
+	}
+
+	// the first occurrence of a duplicated product is kept
+	if lines := conflicts.productsDuplicate[newLegacyOrderProductKey(modelLegacy)]; len(lines) > 1 && lines[0] != line {
+		return lines, newLegacyRecordError(OrderErrorCodeOrderProductDuplicate, OrderErrorMessageOrderProductDuplicate)
+	}
+
+	return nil, nil
+}
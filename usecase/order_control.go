@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+)
+
+var (
+	OrderErrorMessageControlFormat        = fmt.Sprintf("The params has_header and has_trailer are only allowed with the %v format", model.LegacyImportFormatFixedWidth)
+	OrderErrorMessageLayoutWithoutTrailer = "The layout %v has no trailer record"
+	OrderErrorMessageHeaderNotFound       = "The header record was not found"
+	OrderErrorMessageHeaderInvalid        = "The header record is invalid: %v"
+	OrderErrorMessageTrailerNotFound      = "The trailer record was not found"
+	OrderErrorMessageTrailerInvalid       = "The trailer record is invalid: %v"
+	OrderErrorMessageTrailerRecordCount   = "The trailer record count %d does not match the %d records of the file"
+	OrderErrorMessageTrailerControlTotal  = "The trailer control total %v does not match the total %v of the records of the file"
+)
+
+// legacyControlOptionsValidate checks the header and trailer options against
+// the format and the layout of the records
+func legacyControlOptionsValidate(modelLegacyImportOptions *model.LegacyImportOptions, modelLegacyLayout *model.LegacyLayout) error {
+	if !modelLegacyImportOptions.HasHeader && !modelLegacyImportOptions.HasTrailer {
+		return nil
+	}
+
+	if modelLegacyImportOptions.Format != model.LegacyImportFormatFixedWidth {
+		return ErrParamValidate{Message: OrderErrorMessageControlFormat}
+	}
+
+	if modelLegacyImportOptions.HasTrailer && len(modelLegacyLayout.Trailer) == 0 {
+		return ErrParamValidate{Message: fmt.Sprintf(OrderErrorMessageLayoutWithoutTrailer, modelLegacyLayout.Name)}
+	}
+
+	return nil
+}
+
+// legacyControlRecord validates the fields of a header or trailer record
+// and returns their values by name, the names of the invalid fields are
+// returned in the error
+func legacyControlRecord(record string, fields []model.LegacyLayoutField) (map[string]string, error) {
+	values := make(map[string]string)
+	invalid := []string{}
+
+	fixedWidthRecord := newLegacyFixedWidthRecord(record)
+
+	for index := range fields {
+		field := &fields[index]
+
+		if fixedWidthRecord.width() < field.Start-1+field.Length {
+			invalid = append(invalid, field.Name)
+			continue
+		}
+
+		value := legacyFieldValue(fixedWidthRecord, field)
+
+		var err error
+
+		switch field.Type {
+		case model.LegacyFieldTypeInt:
+			_, err = strconv.ParseInt(value, 10, 64)
+		case model.LegacyFieldTypeDecimal:
+			_, err = legacyFieldDecimal(value, field)
+		case model.LegacyFieldTypeDate:
+			_, err = time.Parse(legacyFieldDateFormat(field), value)
+		default:
+			if value == "" {
+				err = errors.New("empty")
+			}
+		}
+
+		if err != nil {
+			invalid = append(invalid, field.Name)
+			continue
+		}
+
+		values[field.Name] = value
+	}
+
+	if len(invalid) > 0 {
+		return nil, errors.New(strings.Join(invalid, ","))
+	}
+
+	return values, nil
+}
+
+func legacyHeaderValidate(record string, modelLegacyLayout *model.LegacyLayout) error {
+	_, err := legacyControlRecord(record, modelLegacyLayout.Header)
+
+	if err != nil {
+		return ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageHeaderInvalid, err)}
+	}
+
+	return nil
+}
+
+// legacyTrailer is the control totals of the records read from the file
+type legacyTrailer struct {
+	layout *model.LegacyLayout
+	// records between the header and the trailer, including the invalid ones
+	records int64
+	// sum of the product values of the records, the invalid values are not summed
+	total model.Money
+}
+
+func (trailer *legacyTrailer) add(modelLegacyRecord *model.LegacyRecord) {
+	trailer.records++
+
+	if modelLegacyRecord == nil {
+		return
+	}
+
+	value, err := legacyFieldDecimal(modelLegacyRecord.ProductValue, trailer.layout.Field(model.LegacyFieldProductValue))
+
+	if err == nil {
+		trailer.total += value
+	}
+}
+
+// validate compares the trailer record with the records read from the file
+func (trailer *legacyTrailer) validate(record string) error {
+	values, err := legacyControlRecord(record, trailer.layout.Trailer)
+
+	if err != nil {
+		return ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageTrailerInvalid, err)}
+	}
+
+	recordCount, _ := strconv.ParseInt(values[model.LegacyFieldRecordCount], 10, 64)
+
+	if recordCount != trailer.records {
+		return ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageTrailerRecordCount, recordCount, trailer.records)}
+	}
+
+	controlTotal, _ := legacyFieldDecimal(values[model.LegacyFieldControlTotal], legacyLayoutTrailerField(trailer.layout, model.LegacyFieldControlTotal))
+
+	if controlTotal != trailer.total {
+		return ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageTrailerControlTotal, controlTotal, trailer.total)}
+	}
+
+	return nil
+}
+
+func legacyLayoutTrailerField(modelLegacyLayout *model.LegacyLayout, name string) *model.LegacyLayoutField {
+	for index := range modelLegacyLayout.Trailer {
+		if modelLegacyLayout.Trailer[index].Name == name {
+			return &modelLegacyLayout.Trailer[index]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	mock_cache "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/cache"
+	mock_repository "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/util"
+)
+
+func TestOrderLegacyControl(t *testing.T) {
+	header := "20230611" + fmt.Sprintf("%-87s", "LEGACY ORDERS")
+	records := []string{
+		"0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308",
+		"0000000075                                  Bobbie Batz00000007980000000002     1578.5720211116",
+	}
+	trailer := "0000000002             3415.31"
+
+	file := func(lines ...string) string {
+		return strings.Join(lines, "\n")
+	}
+
+	resultValid := &model.LegacyValidateResult{
+		Valid: true,
+		Result: model.LegacyImportResult{
+			Users:    2,
+			Orders:   2,
+			Products: 2,
+			Accepted: 2,
+		},
+		Errors: model.LegacyRecordsError{},
+	}
+
+	type test struct {
+		name       string
+		inputFile  string
+		inputCfg   util.Config
+		inputOpts  model.LegacyImportOptions
+		wantResult *model.LegacyValidateResult
+		wantError  error
+	}
+
+	tests := []test{
+		{
+			name:       "FormatInvalidError",
+			inputFile:  "",
+			inputOpts:  model.LegacyImportOptions{Format: model.LegacyImportFormatCSV, HasHeader: true},
+			wantResult: nil,
+			wantError:  ErrParamValidate{Message: OrderErrorMessageControlFormat},
+		},
+		{
+			name:      "LayoutWithoutTrailerError",
+			inputFile: "",
+			inputCfg: util.Config{LegacyLayouts: model.LegacyLayouts{
+				{Name: "orders", Size: model.LegacyLayoutDefault.Size, Fields: model.LegacyLayoutDefault.Fields},
+			}},
+			inputOpts:  model.LegacyImportOptions{Layout: "orders", HasTrailer: true},
+			wantResult: nil,
+			wantError:  ErrParamValidate{Message: fmt.Sprintf(OrderErrorMessageLayoutWithoutTrailer, "orders")},
+		},
+		{
+			name:       "HeaderNotFoundError",
+			inputFile:  "",
+			inputOpts:  model.LegacyImportOptions{HasHeader: true},
+			wantResult: nil,
+			wantError:  ErrFileValidate{Message: OrderErrorMessageHeaderNotFound},
+		},
+		{
+			name:       "HeaderInvalidError",
+			inputFile:  file(append([]string{"2023061X"}, records...)...),
+			inputOpts:  model.LegacyImportOptions{HasHeader: true},
+			wantResult: nil,
+			wantError:  ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageHeaderInvalid, "generation_date,source")},
+		},
+		{
+			name:       "HeaderValid",
+			inputFile:  file(append([]string{header}, records...)...),
+			inputOpts:  model.LegacyImportOptions{HasHeader: true},
+			wantResult: resultValid,
+			wantError:  nil,
+		},
+		{
+			name:      "HeaderSkipped",
+			inputFile: file(append([]string{"    USERID                                     USERNAME   ORDERID PRODUCTIDPRODUCTVALUE BUYDATE"}, records...)...),
+			inputCfg: util.Config{LegacyLayouts: model.LegacyLayouts{
+				{Name: "orders", Size: model.LegacyLayoutDefault.Size, Fields: model.LegacyLayoutDefault.Fields},
+			}},
+			inputOpts:  model.LegacyImportOptions{Layout: "orders", HasHeader: true},
+			wantResult: resultValid,
+			wantError:  nil,
+		},
+		{
+			name:       "TrailerNotFoundError",
+			inputFile:  "",
+			inputOpts:  model.LegacyImportOptions{HasTrailer: true},
+			wantResult: nil,
+			wantError:  ErrFileValidate{Message: OrderErrorMessageTrailerNotFound},
+		},
+		{
+			name:       "TrailerInvalidError",
+			inputFile:  file(append(records, "000000000X")...),
+			inputOpts:  model.LegacyImportOptions{HasTrailer: true},
+			wantResult: nil,
+			wantError:  ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageTrailerInvalid, "record_count,control_total")},
+		},
+		{
+			name:       "TrailerRecordCountError",
+			inputFile:  file(append(records, "0000000003             3415.31")...),
+			inputOpts:  model.LegacyImportOptions{HasTrailer: true},
+			wantResult: nil,
+			wantError:  ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageTrailerRecordCount, 3, 2)},
+		},
+		{
+			name:       "TrailerControlTotalError",
+			inputFile:  file(append(records, "0000000002             3415.32")...),
+			inputOpts:  model.LegacyImportOptions{HasTrailer: true},
+			wantResult: nil,
+			wantError:  ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageTrailerControlTotal, "3415.32", "3415.31")},
+		},
+		{
+			name:       "HeaderTrailerValid",
+			inputFile:  file(append(append([]string{header}, records...), trailer)...),
+			inputOpts:  model.LegacyImportOptions{HasHeader: true, HasTrailer: true},
+			wantResult: resultValid,
+			wantError:  nil,
+		},
+		{
+			// the rejected records are part of the control totals of the file
+			name: "TrailerWithRejectedRecord",
+			inputFile: file(
+				records[0],
+				"000000007x                                  Bobbie Batz00000007980000000002     1578.5720211116",
+				trailer,
+			),
+			inputOpts: model.LegacyImportOptions{HasTrailer: true, Lenient: true},
+			wantResult: &model.LegacyValidateResult{
+				Valid: true,
+				Result: model.LegacyImportResult{
+					Users:    1,
+					Orders:   1,
+					Products: 1,
+					Accepted: 1,
+					Rejected: 1,
+				},
+				Errors: model.LegacyRecordsError{
+					{
+						Line:    2,
+						Message: OrderErrorMessageUserIDInvalid,
+						Errors: []model.LegacyFieldError{
+							{Code: OrderErrorCodeUserIDInvalid, Field: model.LegacyFieldUserID, ColumnStart: 1, ColumnEnd: 10, Value: "000000007x", Message: OrderErrorMessageUserIDInvalid},
+						},
+					},
+				},
+				ErrorsTotal: 1,
+			},
+			wantError: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecaseOrder := NewOrder(new(mock_repository.MockRepository), new(mock_cache.MockCache), &tt.inputCfg)
+
+			modelLegacyValidateResult, err := usecaseOrder.LegacyValidate(bytes.NewBufferString(tt.inputFile), &tt.inputOpts)
+
+			if !reflect.DeepEqual(err, tt.wantError) {
+				t.Errorf("LegacyValidate() got error = %v, want = %v.", err, tt.wantError)
+			}
+
+			if !reflect.DeepEqual(modelLegacyValidateResult, tt.wantResult) {
+				t.Errorf("LegacyValidate() got result = %v, want = %v.", modelLegacyValidateResult, tt.wantResult)
+			}
+		})
+	}
+}
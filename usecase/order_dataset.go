@@ -0,0 +1,451 @@
+package usecase
+
+import (
+	"bufio"
+	"context"
+	"encoding/gob"
+	"io"
+	"os"
+	"runtime"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+)
+
+// legacyDataset holds the records aggregated from a legacy file. Only the
+// users, the orders and the indexes of the validation between the records are
+// kept in memory, the records, the products and the rejects are spilled to
+// temporary files so the size of the file does not limit the import.
+type legacyDataset struct {
+	lines    int64
+	users    model.Users
+	orders   model.Orders
+	products int
+	// first records with error, limited by maxErrors
+	recordsError    model.LegacyRecordsError
+	recordsRejected int
+	// number of errors of the fields found and kept in recordsError
+	errorsTotal    int
+	errorsKept     int
+	maxErrors      int
+	records        *legacySpill
+	ordersProducts *legacySpill
+	// rejected records quarantined by the lenient mode
+	rejects *legacySpill
+	// number of records of each chunk persisted
+	chunkSize int
+	// number of goroutines parsing the records
+	parseWorkers int
+	// validation policy evaluated when the import started
+	validation *orderValidation
+}
+
+// legacySpillRecord is a record of the file spilled while the file is read,
+// waiting for the validation between the records
+type legacySpillRecord struct {
+	File   string
+	Line   int64
+	Raw    string
+	Legacy *model.Legacy
+	Errors legacyRecordErrors
+	// the errors of the record have the positions of the fields
+	FixedWidth bool
+}
+
+func (record *legacySpillRecord) line() model.LegacyRecordLine {
+	return model.LegacyRecordLine{File: record.File, Line: record.Line}
+}
+
+func (usecaseOrder *UseCaseOrder) newLegacyDataset() (*legacyDataset, error) {
+	parseWorkers := usecaseOrder.Config.LegacyImportParseWorkers
+
+	if parseWorkers <= 0 {
+		parseWorkers = runtime.NumCPU()
+	}
+
+	records, err := newLegacySpill(usecaseOrder.Config.LegacyImportTempDir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ordersProducts, err := newLegacySpill(usecaseOrder.Config.LegacyImportTempDir)
+
+	if err != nil {
+		records.close()
+		return nil, err
+	}
+
+	rejects, err := newLegacySpill(usecaseOrder.Config.LegacyImportTempDir)
+
+	if err != nil {
+		records.close()
+		ordersProducts.close()
+		return nil, err
+	}
+
+	return &legacyDataset{
+		users:          model.Users{},
+		orders:         model.Orders{},
+		recordsError:   model.LegacyRecordsError{},
+		records:        records,
+		ordersProducts: ordersProducts,
+		rejects:        rejects,
+		chunkSize:      usecaseOrder.Config.LegacyImportChunkSize,
+		parseWorkers:   parseWorkers,
+		maxErrors:      usecaseOrder.Config.LegacyImportMaxErrors,
+		validation:     usecaseOrder.validation(),
+	}, nil
+}
+
+// close removes the temporary files of the dataset
+func (dataset *legacyDataset) close() {
+	dataset.records.close()
+	dataset.ordersProducts.close()
+	dataset.rejects.close()
+}
+
+// read spills the records of the file and indexes the valid ones to find the
+// records in conflict
+func (dataset *legacyDataset) read(ctx context.Context, reader legacyReader, modelLegacyImportOptions *model.LegacyImportOptions, modelLegacyLayout *model.LegacyLayout, conflicts *legacyConflicts, progress legacyImportProgress) error {
+	parser := newLegacyParser(reader, dataset.parseWorkers, func(readerRecord *legacyReaderRecord) *legacySpillRecord {
+		return newLegacySpillRecord(readerRecord, modelLegacyImportOptions, modelLegacyLayout, dataset.validation)
+	})
+	defer parser.close()
+
+	for {
+		record, err := parser.Read()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		dataset.lines++
+
+		if dataset.lines%legacyImportProgressLines == 0 {
+			progress.Parsed(dataset.lines)
+
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+
+		if record.Legacy != nil {
+			conflicts.index(record.Legacy)
+		}
+
+		if err := dataset.records.write(record); err != nil {
+			return err
+		}
+	}
+
+	progress.Parsed(dataset.lines)
+
+	return nil
+}
+
+// newLegacySpillRecord validates and converts a record of the file, it does
+// not depend on the other records so it can be called concurrently
+func newLegacySpillRecord(modelLegacyReaderRecord *legacyReaderRecord, modelLegacyImportOptions *model.LegacyImportOptions, modelLegacyLayout *model.LegacyLayout, validation *orderValidation) *legacySpillRecord {
+	record := &legacySpillRecord{
+		File:       modelLegacyReaderRecord.file,
+		Line:       modelLegacyReaderRecord.line,
+		FixedWidth: modelLegacyReaderRecord.fixedWidth,
+	}
+
+	err := modelLegacyReaderRecord.err
+
+	if err == nil {
+		record.Legacy, err = legacyRecordToLegacy(modelLegacyReaderRecord.record, modelLegacyLayout, validation)
+	}
+
+	if err != nil {
+		record.Errors = newLegacyRecordErrors(err)
+	}
+
+	// the content is kept only to quarantine the rejected records
+	if modelLegacyImportOptions.Lenient {
+		record.Raw = modelLegacyReaderRecord.raw
+	}
+
+	return record
+}
+
+// aggregate visits the spilled records twice, first to collect the lines of
+// the records in conflict and then to aggregate the valid records
+func (dataset *legacyDataset) aggregate(modelLegacyImportOptions *model.LegacyImportOptions, modelLegacyLayout *model.LegacyLayout, conflicts *legacyConflicts) error {
+	conflicts.indexed()
+
+	err := legacySpillEach(dataset.records, func(record *legacySpillRecord) error {
+		if record.Legacy != nil {
+			conflicts.collect(record.line(), record.Legacy)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	mapUsers := make(map[int64]int)
+	mapOrders := make(map[int64]int)
+
+	err = legacySpillEach(dataset.records, func(record *legacySpillRecord) error {
+		errs := record.Errors
+		var lines []model.LegacyRecordLine
+
+		if record.Legacy != nil {
+			lines, errs = conflicts.check(record.line(), record.Legacy)
+		}
+
+		if len(errs) > 0 {
+			if record.FixedWidth {
+				errs = errs.columns(modelLegacyLayout)
+			}
+
+			return dataset.recordError(record, errs, lines, modelLegacyImportOptions)
+		}
+
+		legacyUser(record.Legacy, &dataset.users, mapUsers)
+		legacyOrder(record.Legacy, &dataset.orders, mapOrders)
+
+		dataset.products++
+
+		return dataset.ordersProducts.write(legacyProduct(record.Legacy))
+	})
+
+	if err != nil {
+		return err
+	}
+
+	// the records are not needed anymore, only the products are persisted
+	dataset.records.close()
+
+	return nil
+}
+
+// recordError keeps the error of a record, only the first errors up to the
+// max number of errors are kept while all the rejected records are
+// quarantined
+func (dataset *legacyDataset) recordError(record *legacySpillRecord, errs legacyRecordErrors, lines []model.LegacyRecordLine, modelLegacyImportOptions *model.LegacyImportOptions) error {
+	modelLegacyRecordError := model.LegacyRecordError{
+		File:    record.File,
+		Line:    record.Line,
+		Message: errs.Error(),
+		Lines:   lines,
+		Errors:  errs,
+	}
+
+	dataset.recordsRejected++
+	dataset.errorsTotal += len(errs)
+
+	if modelLegacyImportOptions.Lenient {
+		err := dataset.rejects.write(&model.LegacyReject{LegacyRecordError: modelLegacyRecordError, Record: record.Raw})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if dataset.maxErrors > 0 {
+		available := dataset.maxErrors - dataset.errorsKept
+
+		if available <= 0 {
+			return nil
+		}
+
+		if len(modelLegacyRecordError.Errors) > available {
+			modelLegacyRecordError.Errors = modelLegacyRecordError.Errors[:available]
+		}
+	}
+
+	dataset.errorsKept += len(modelLegacyRecordError.Errors)
+	dataset.recordsError = append(dataset.recordsError, modelLegacyRecordError)
+
+	return nil
+}
+
+// errRecordValidate returns the error of the dataset not accepted
+func (dataset *legacyDataset) errRecordValidate() ErrRecordValidate {
+	return ErrRecordValidate{
+		Message:      OrderErrorMessageRecordValidate,
+		RecordsError: dataset.recordsError,
+		Total:        dataset.errorsTotal,
+		Truncated:    dataset.errorsTotal > dataset.errorsKept,
+	}
+}
+
+// accepted reports whether the dataset can be persisted, the lenient mode
+// only rejects the file when there is no valid record.
+func (dataset *legacyDataset) accepted(modelLegacyImportOptions *model.LegacyImportOptions) bool {
+	if dataset.recordsRejected == 0 {
+		return true
+	}
+
+	return modelLegacyImportOptions.Lenient && dataset.products > 0
+}
+
+func (dataset *legacyDataset) result() *model.LegacyImportResult {
+	return &model.LegacyImportResult{
+		Users:    len(dataset.users),
+		Orders:   len(dataset.orders),
+		Products: dataset.products,
+		Accepted: dataset.products,
+		Rejected: dataset.recordsRejected,
+	}
+}
+
+// Users provides the users to the repository in chunks
+func (dataset *legacyDataset) Users(persist func(modelUsers *model.Users) error) error {
+	return legacyChunks(len(dataset.users), dataset.chunkSize, func(start, end int) error {
+		modelUsers := dataset.users[start:end]
+
+		return persist(&modelUsers)
+	})
+}
+
+// Orders provides the orders to the repository in chunks
+func (dataset *legacyDataset) Orders(persist func(modelOrders *model.Orders) error) error {
+	return legacyChunks(len(dataset.orders), dataset.chunkSize, func(start, end int) error {
+		modelOrders := dataset.orders[start:end]
+
+		return persist(&modelOrders)
+	})
+}
+
+// OrdersProducts provides the products to the repository in chunks read
+// from the temporary file, so only one chunk is in memory at a time
+func (dataset *legacyDataset) OrdersProducts(persist func(modelOrdersProducts *model.OrdersProducts) error) error {
+	return legacySpillChunks(dataset.ordersProducts, dataset.chunkSize, func(chunk []model.OrderProduct) error {
+		modelOrdersProducts := model.OrdersProducts(chunk)
+
+		return persist(&modelOrdersProducts)
+	})
+}
+
+// Rejects provides the rejected records to the repository in chunks read
+// from the temporary file, there are no rejects without the lenient mode
+func (dataset *legacyDataset) Rejects(persist func(modelLegacyRejects *model.LegacyRejects) error) error {
+	return legacySpillChunks(dataset.rejects, dataset.chunkSize, func(chunk []model.LegacyReject) error {
+		modelLegacyRejects := model.LegacyRejects(chunk)
+
+		return persist(&modelLegacyRejects)
+	})
+}
+
+// legacySpillChunks calls persist with each chunk of the values of the spill,
+// a size of zero results in a single chunk
+func legacySpillChunks[T any](spill *legacySpill, size int, persist func(chunk []T) error) error {
+	chunk := []T{}
+
+	err := legacySpillEach(spill, func(value *T) error {
+		chunk = append(chunk, *value)
+
+		if size <= 0 || len(chunk) < size {
+			return nil
+		}
+
+		err := persist(chunk)
+		chunk = make([]T, 0, size)
+
+		return err
+	})
+
+	if err != nil || len(chunk) == 0 {
+		return err
+	}
+
+	return persist(chunk)
+}
+
+// legacyChunks calls persist with the bounds of each chunk of the records,
+// a size of zero results in a single chunk
+func legacyChunks(records int, size int, persist func(start, end int) error) error {
+	if size <= 0 {
+		size = records
+	}
+
+	for start := 0; start < records; start += size {
+		end := start + size
+
+		if end > records {
+			end = records
+		}
+
+		if err := persist(start, end); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// legacySpill keeps values of the same type in a temporary file, out of the
+// memory, to be read again in the same order they were written
+type legacySpill struct {
+	file    *os.File
+	writer  *bufio.Writer
+	encoder *gob.Encoder
+}
+
+func newLegacySpill(dir string) (*legacySpill, error) {
+	file, err := os.CreateTemp(dir, "legacy-import-*.spill")
+
+	if err != nil {
+		return nil, err
+	}
+
+	writer := bufio.NewWriter(file)
+
+	return &legacySpill{
+		file:    file,
+		writer:  writer,
+		encoder: gob.NewEncoder(writer),
+	}, nil
+}
+
+func (spill *legacySpill) write(value interface{}) error {
+	return spill.encoder.Encode(value)
+}
+
+// legacySpillEach reads from the start all the values written to the spill
+func legacySpillEach[T any](spill *legacySpill, visit func(value *T) error) error {
+	if err := spill.writer.Flush(); err != nil {
+		return err
+	}
+
+	if _, err := spill.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	decoder := gob.NewDecoder(bufio.NewReader(spill.file))
+
+	for {
+		// a new value for each one, the zero fields are not decoded
+		value := new(T)
+
+		err := decoder.Decode(value)
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := visit(value); err != nil {
+			return err
+		}
+	}
+}
+
+// close removes the temporary file, it can be called more than once
+func (spill *legacySpill) close() {
+	spill.file.Close()
+	os.Remove(spill.file.Name())
+}
@@ -0,0 +1,243 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	mock_cache "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/cache"
+	mock_repository "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/util"
+)
+
+func TestOrderLegacySizeLimit(t *testing.T) {
+	recordFixedWidth := "0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308"
+	content := strings.Repeat(recordFixedWidth+"\n", 2)
+
+	type test struct {
+		name       string
+		inputFile  func() io.Reader
+		inputCfg   util.Config
+		wantResult *model.LegacyValidateResult
+		wantError  error
+	}
+
+	tests := []test{
+		{
+			name: "FileSizeLimitError",
+			inputFile: func() io.Reader {
+				return strings.NewReader(content)
+			},
+			inputCfg:   util.Config{LegacyImportMaxSize: 100},
+			wantResult: nil,
+			wantError:  ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageFileSizeLimit, 100)},
+		},
+		{
+			name: "FileSizeLimitStreamError",
+			inputFile: func() io.Reader {
+				return bytes.NewBufferString(content)
+			},
+			inputCfg:   util.Config{LegacyImportMaxSize: 100},
+			wantResult: nil,
+			wantError:  ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageFileSizeLimit, 100)},
+		},
+		{
+			name: "RecordSizeLimitError",
+			inputFile: func() io.Reader {
+				return bytes.NewBufferString(recordFixedWidth + "\n" + strings.Repeat(" ", 200))
+			},
+			inputCfg:   util.Config{LegacyImportMaxRecordSize: 100},
+			wantResult: nil,
+			wantError:  ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageRecordSizeLimit, 2, 100)},
+		},
+		{
+			name: "RecordLargerThanScannerBuffer",
+			inputFile: func() io.Reader {
+				return bytes.NewBufferString(recordFixedWidth + "\n" + strings.Repeat("0", 100<<10))
+			},
+			inputCfg: util.Config{LegacyImportMaxSize: 1 << 20, LegacyImportMaxRecordSize: 1 << 20},
+			wantResult: &model.LegacyValidateResult{
+				Valid: false,
+				Result: model.LegacyImportResult{
+					Users:    1,
+					Orders:   1,
+					Products: 1,
+					Accepted: 1,
+					Rejected: 1,
+				},
+				Errors: model.LegacyRecordsError{
+					{
+						Line:    2,
+						Message: fmt.Sprintf(OrderErrorMessageRecordSize, 95),
+						Errors: []model.LegacyFieldError{
+							{Code: OrderErrorCodeRecordSize, Message: fmt.Sprintf(OrderErrorMessageRecordSize, 95)},
+						},
+					},
+				},
+				ErrorsTotal: 1,
+			},
+			wantError: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepository := new(mock_repository.MockRepository)
+			mockCache := new(mock_cache.MockCache)
+
+			usecaseOrder := NewOrder(mockRepository, mockCache, &tt.inputCfg)
+
+			modelLegacyValidateResult, err := usecaseOrder.LegacyValidate(tt.inputFile(), &model.LegacyImportOptions{})
+
+			if !reflect.DeepEqual(err, tt.wantError) {
+				t.Errorf("LegacyValidate() got error = %v, want = %v.", err, tt.wantError)
+			}
+
+			if !reflect.DeepEqual(modelLegacyValidateResult, tt.wantResult) {
+				t.Errorf("LegacyValidate() got result = %v, want = %v.", modelLegacyValidateResult, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestOrderLegacyDatasetChunks(t *testing.T) {
+	lines := []string{
+		"0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308",
+		"0000000075                                  Bobbie Batz00000007980000000002     1578.5720211116",
+		"0000000049                               Ken Wintheiser00000005230000000003      586.7420210903",
+		"0000000070                              Palmer Prosacco00000007530000000004      100.0020210308",
+		"0000000075                                  Bobbie Batz00000007980000000001      200.0020211116",
+	}
+
+	tempDir := t.TempDir()
+
+	usecaseOrder := NewOrder(new(mock_repository.MockRepository), new(mock_cache.MockCache), &util.Config{
+		LegacyImportChunkSize: 2,
+		LegacyImportTempDir:   tempDir,
+	}).(*UseCaseOrder)
+
+	modelLegacyImportOptions := &model.LegacyImportOptions{}
+	modelLegacyLayout, _ := usecaseOrder.legacyImportOptionsValidate(modelLegacyImportOptions)
+
+	dataset, err := usecaseOrder.legacyParse(context.Background(), bytes.NewBufferString(strings.Join(lines, "\n")), modelLegacyImportOptions, modelLegacyLayout, legacyImportProgressNone{})
+
+	if err != nil {
+		t.Fatalf("legacyParse() got error = %v.", err)
+	}
+
+	chunks := []int{}
+	modelUsers := model.Users{}
+
+	dataset.Users(func(chunk *model.Users) error {
+		chunks = append(chunks, len(*chunk))
+		modelUsers = append(modelUsers, *chunk...)
+		return nil
+	})
+
+	dataset.Orders(func(chunk *model.Orders) error {
+		chunks = append(chunks, len(*chunk))
+		return nil
+	})
+
+	modelOrdersProducts := model.OrdersProducts{}
+
+	dataset.OrdersProducts(func(chunk *model.OrdersProducts) error {
+		chunks = append(chunks, len(*chunk))
+		modelOrdersProducts = append(modelOrdersProducts, *chunk...)
+		return nil
+	})
+
+	// users, orders and products
+	wantChunks := []int{2, 1, 2, 1, 2, 2, 1}
+
+	if !reflect.DeepEqual(chunks, wantChunks) {
+		t.Errorf("legacyDataset got chunks = %v, want = %v.", chunks, wantChunks)
+	}
+
+	wantUsers := model.Users{{ID: 70, Name: "Palmer Prosacco"}, {ID: 75, Name: "Bobbie Batz"}, {ID: 49, Name: "Ken Wintheiser"}}
+
+	if !reflect.DeepEqual(modelUsers, wantUsers) {
+		t.Errorf("legacyDataset got users = %v, want = %v.", modelUsers, wantUsers)
+	}
+
+	wantOrdersProducts := model.OrdersProducts{
+		{OrderID: 753, ProductID: 3, ProductValue: 183674},
+		{OrderID: 798, ProductID: 2, ProductValue: 157857},
+		{OrderID: 523, ProductID: 3, ProductValue: 58674},
+		{OrderID: 753, ProductID: 4, ProductValue: 10000},
+		{OrderID: 798, ProductID: 1, ProductValue: 20000},
+	}
+
+	if !reflect.DeepEqual(modelOrdersProducts, wantOrdersProducts) {
+		t.Errorf("legacyDataset got products = %v, want = %v.", modelOrdersProducts, wantOrdersProducts)
+	}
+
+	dataset.close()
+
+	if files, _ := os.ReadDir(tempDir); len(files) > 0 {
+		t.Errorf("legacyDataset got temporary files = %v after close.", len(files))
+	}
+}
+
+func TestOrderLegacyDatasetRejectsChunks(t *testing.T) {
+	lines := []string{
+		"0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308",
+		"00000000x5                                  Bobbie Batz00000007980000000002     1578.5720211116",
+		"0000000049                               Ken Wintheiser00000005230000000003      586.7420211308",
+		"0000000070                              Palmer Prosacco00000007530000000004      100.0020210308",
+		"0000000075                                  Bobbie Batz000000079800000000x1      200.0020211116",
+	}
+
+	tempDir := t.TempDir()
+
+	usecaseOrder := NewOrder(new(mock_repository.MockRepository), new(mock_cache.MockCache), &util.Config{
+		LegacyImportChunkSize: 2,
+		LegacyImportTempDir:   tempDir,
+	}).(*UseCaseOrder)
+
+	modelLegacyImportOptions := &model.LegacyImportOptions{Lenient: true}
+	modelLegacyLayout, _ := usecaseOrder.legacyImportOptionsValidate(modelLegacyImportOptions)
+
+	dataset, err := usecaseOrder.legacyParse(context.Background(), bytes.NewBufferString(strings.Join(lines, "\n")), modelLegacyImportOptions, modelLegacyLayout, legacyImportProgressNone{})
+
+	if err != nil {
+		t.Fatalf("legacyParse() got error = %v.", err)
+	}
+
+	chunks := []int{}
+	records := []string{}
+
+	dataset.Rejects(func(chunk *model.LegacyRejects) error {
+		chunks = append(chunks, len(*chunk))
+
+		for _, modelLegacyReject := range *chunk {
+			records = append(records, fmt.Sprintf("%d:%v", modelLegacyReject.Line, modelLegacyReject.Record))
+		}
+
+		return nil
+	})
+
+	wantChunks := []int{2, 1}
+
+	if !reflect.DeepEqual(chunks, wantChunks) {
+		t.Errorf("legacyDataset got chunks = %v, want = %v.", chunks, wantChunks)
+	}
+
+	wantRecords := []string{"2:" + lines[1], "3:" + lines[2], "5:" + lines[4]}
+
+	if !reflect.DeepEqual(records, wantRecords) {
+		t.Errorf("legacyDataset got rejects = %v, want = %v.", records, wantRecords)
+	}
+
+	dataset.close()
+
+	if files, _ := os.ReadDir(tempDir); len(files) > 0 {
+		t.Errorf("legacyDataset got temporary files = %v after close.", len(files))
+	}
+}
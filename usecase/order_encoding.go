@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"golang.org/x/text/encoding/charmap"
+)
+
+var OrderErrorMessageEncodingInvalid = fmt.Sprintf("The param encoding is invalid, the allowed values are %v, %v, %v and %v", model.LegacyImportEncodingAuto, model.LegacyImportEncodingUTF8, model.LegacyImportEncodingLatin1, model.LegacyImportEncodingWindows1252)
+
+var legacyUTF8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// legacyCharmaps are the single-byte encodings of the legacy files
+var legacyCharmaps = map[string]*charmap.Charmap{
+	model.LegacyImportEncodingLatin1:      charmap.ISO8859_1,
+	model.LegacyImportEncodingWindows1252: charmap.Windows1252,
+}
+
+// legacyDecoder transcodes the file to UTF-8 line by line, so the readers of
+// all the formats receive UTF-8. With the auto encoding each line that is not
+// valid UTF-8 is decoded as Windows-1252, a superset of the printable
+// characters of Latin-1. The UTF-8 BOM is removed.
+type legacyDecoder struct {
+	reader   *bufio.Reader
+	encoding string
+	started  bool
+	// encoding of the current line, decided by its first chunk, nil when the
+	// line is UTF-8
+	lineCharmap *charmap.Charmap
+	lineStart   bool
+	buffer      []byte
+	pending     []byte
+	err         error
+}
+
+func newLegacyDecoder(file io.Reader, encoding string) *legacyDecoder {
+	if encoding == "" {
+		encoding = model.LegacyImportEncodingAuto
+	}
+
+	return &legacyDecoder{
+		reader:    bufio.NewReader(file),
+		encoding:  encoding,
+		lineStart: true,
+	}
+}
+
+func (decoder *legacyDecoder) Read(p []byte) (int, error) {
+	for len(decoder.pending) == 0 {
+		if decoder.err != nil {
+			return 0, decoder.err
+		}
+
+		if !decoder.started {
+			decoder.started = true
+			decoder.skipBOM()
+		}
+
+		chunk, err := decoder.reader.ReadSlice('\n')
+
+		if err == bufio.ErrBufferFull {
+			err = nil
+		}
+
+		decoder.err = err
+
+		if len(chunk) == 0 {
+			continue
+		}
+
+		if decoder.lineStart {
+			decoder.lineCharmap = decoder.charmap(chunk)
+		}
+
+		decoder.lineStart = chunk[len(chunk)-1] == '\n'
+
+		// the chunk is only valid until the next read of the file
+		decoder.buffer = decoder.buffer[:0]
+
+		if decoder.lineCharmap != nil {
+			decoder.buffer = legacyDecode(decoder.buffer, chunk, decoder.lineCharmap)
+		} else {
+			decoder.buffer = append(decoder.buffer, chunk...)
+		}
+
+		decoder.pending = decoder.buffer
+	}
+
+	n := copy(p, decoder.pending)
+	decoder.pending = decoder.pending[n:]
+
+	return n, nil
+}
+
+// skipBOM removes the BOM of a UTF-8 file, a file with BOM is UTF-8 even
+// with the auto encoding
+func (decoder *legacyDecoder) skipBOM() {
+	if _, ok := legacyCharmaps[decoder.encoding]; ok {
+		return
+	}
+
+	if bom, _ := decoder.reader.Peek(len(legacyUTF8BOM)); bytes.Equal(bom, legacyUTF8BOM) {
+		decoder.reader.Discard(len(legacyUTF8BOM))
+		decoder.encoding = model.LegacyImportEncodingUTF8
+	}
+}
+
+func (decoder *legacyDecoder) charmap(chunk []byte) *charmap.Charmap {
+	switch decoder.encoding {
+	case model.LegacyImportEncodingUTF8:
+		return nil
+	case model.LegacyImportEncodingAuto:
+		if legacyValidUTF8(chunk) {
+			return nil
+		}
+
+		return charmap.Windows1252
+	default:
+		return legacyCharmaps[decoder.encoding]
+	}
+}
+
+// legacyValidUTF8 reports whether the chunk is valid UTF-8, a character
+// incomplete at the end of the chunk is completed by the next one
+func legacyValidUTF8(chunk []byte) bool {
+	for index := len(chunk) - 1; index >= 0 && index >= len(chunk)-utf8.UTFMax; index-- {
+		if utf8.RuneStart(chunk[index]) {
+			if !utf8.FullRune(chunk[index:]) {
+				chunk = chunk[:index]
+			}
+
+			break
+		}
+	}
+
+	return utf8.Valid(chunk)
+}
+
+func legacyDecode(buffer []byte, chunk []byte, modelCharmap *charmap.Charmap) []byte {
+	for _, value := range chunk {
+		buffer = utf8.AppendRune(buffer, modelCharmap.DecodeByte(value))
+	}
+
+	return buffer
+}
+
+// legacyFixedWidthRecord is a fixed-width record measured in characters, the
+// bytes of the single-byte encodings of the legacy files
+type legacyFixedWidthRecord struct {
+	record string
+	// characters of the record, nil when all of them are ASCII
+	runes []rune
+}
+
+func newLegacyFixedWidthRecord(record string) *legacyFixedWidthRecord {
+	modelLegacyFixedWidthRecord := &legacyFixedWidthRecord{record: record}
+
+	if utf8.RuneCountInString(record) != len(record) {
+		modelLegacyFixedWidthRecord.runes = []rune(record)
+	}
+
+	return modelLegacyFixedWidthRecord
+}
+
+func (record *legacyFixedWidthRecord) width() int {
+	if record.runes == nil {
+		return len(record.record)
+	}
+
+	return len(record.runes)
+}
+
+func (record *legacyFixedWidthRecord) field(field *model.LegacyLayoutField) string {
+	start := field.Start - 1
+	end := start + field.Length
+
+	if record.runes == nil {
+		return record.record[start:end]
+	}
+
+	return string(record.runes[start:end])
+}
@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	mock_cache "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/cache"
+	mock_repository "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/util"
+)
+
+func TestOrderLegacyEncoding(t *testing.T) {
+	// the name is padded to the width of the field in characters of the encoding
+	recordFixedWidth := func(userID int64, userName string) string {
+		return fmt.Sprintf("%010d%45s%010d%010d%12s%s", userID, userName, 753+userID, 3, "1836.74", "20210308")
+	}
+
+	type test struct {
+		name        string
+		inputFile   string
+		inputFormat string
+		inputCoding string
+		wantUsers   model.Users
+		wantErrors  model.LegacyRecordsError
+		wantError   error
+	}
+
+	tests := []test{
+		{
+			name:        "EncodingInvalidError",
+			inputFile:   recordFixedWidth(70, "Palmer Prosacco"),
+			inputCoding: "ascii",
+			wantError:   ErrParamValidate{Message: OrderErrorMessageEncodingInvalid},
+		},
+		{
+			name:        "Latin1",
+			inputFile:   recordFixedWidth(70, "Jos\xe9 Concei\xe7\xe3o"),
+			inputCoding: model.LegacyImportEncodingLatin1,
+			wantUsers:   model.Users{{ID: 70, Name: "José Conceição"}},
+			wantErrors:  model.LegacyRecordsError{},
+		},
+		{
+			name:        "Windows1252Auto",
+			inputFile:   strings.Join([]string{recordFixedWidth(70, "Jos\xe9 Concei\xe7\xe3o"), recordFixedWidth(75, "Bobbie Batz")}, "\n"),
+			inputCoding: "",
+			wantUsers:   model.Users{{ID: 70, Name: "José Conceição"}, {ID: 75, Name: "Bobbie Batz"}},
+			wantErrors:  model.LegacyRecordsError{},
+		},
+		{
+			name:        "UTF8Auto",
+			inputFile:   recordFixedWidth(70, "José Conceição"),
+			inputCoding: model.LegacyImportEncodingAuto,
+			wantUsers:   model.Users{{ID: 70, Name: "José Conceição"}},
+			wantErrors:  model.LegacyRecordsError{},
+		},
+		{
+			name:        "UTF8BOMCRLFBlankLines",
+			inputFile:   "\xef\xbb\xbf" + recordFixedWidth(70, "José Conceição") + "\r\n" + recordFixedWidth(75, "Bobbie Batz") + "\r\n\r\n   \r\n\n",
+			inputCoding: model.LegacyImportEncodingUTF8,
+			wantUsers:   model.Users{{ID: 70, Name: "José Conceição"}, {ID: 75, Name: "Bobbie Batz"}},
+			wantErrors:  model.LegacyRecordsError{},
+		},
+		{
+			// only the blank lines at the end of the file are ignored
+			name:        "BlankLinesBetweenRecords",
+			inputFile:   strings.Join([]string{recordFixedWidth(70, "Palmer Prosacco"), "", recordFixedWidth(75, "Bobbie Batz"), "", ""}, "\n"),
+			inputCoding: "",
+			wantUsers:   model.Users{{ID: 70, Name: "Palmer Prosacco"}, {ID: 75, Name: "Bobbie Batz"}},
+			wantErrors: model.LegacyRecordsError{
+				{
+					Line:    2,
+					Message: fmt.Sprintf(OrderErrorMessageRecordSize, 95),
+					Errors: []model.LegacyFieldError{
+						{Code: OrderErrorCodeRecordSize, Message: fmt.Sprintf(OrderErrorMessageRecordSize, 95)},
+					},
+				},
+			},
+		},
+		{
+			name:        "CSVLatin1Auto",
+			inputFile:   "user_id,user_name,order_id,product_id,product_value,buy_date\r\n70,Jos\xe9 Concei\xe7\xe3o,753,3,1836.74,20210308\r\n",
+			inputFormat: model.LegacyImportFormatCSV,
+			inputCoding: "",
+			wantUsers:   model.Users{{ID: 70, Name: "José Conceição"}},
+			wantErrors:  model.LegacyRecordsError{},
+		},
+		{
+			name:        "NDJSONBOMAuto",
+			inputFile:   "\xef\xbb\xbf" + `{"user_id": 70, "user_name": "José Conceição", "order_id": 753, "product_id": 3, "product_value": 1836.74, "buy_date": "20210308"}` + "\r\n\r\n",
+			inputFormat: model.LegacyImportFormatNDJSON,
+			inputCoding: "",
+			wantUsers:   model.Users{{ID: 70, Name: "José Conceição"}},
+			wantErrors:  model.LegacyRecordsError{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecaseOrder := NewOrder(new(mock_repository.MockRepository), new(mock_cache.MockCache), &util.Config{}).(*UseCaseOrder)
+
+			modelLegacyImportOptions := &model.LegacyImportOptions{Format: tt.inputFormat, Encoding: tt.inputCoding, Lenient: true}
+			modelLegacyLayout, err := usecaseOrder.legacyImportOptionsValidate(modelLegacyImportOptions)
+
+			if err != nil {
+				if !reflect.DeepEqual(err, tt.wantError) {
+					t.Errorf("legacyImportOptionsValidate() got error = %v, want = %v.", err, tt.wantError)
+				}
+
+				return
+			}
+
+			dataset, err := usecaseOrder.legacyParse(context.Background(), bytes.NewBufferString(tt.inputFile), modelLegacyImportOptions, modelLegacyLayout, legacyImportProgressNone{})
+
+			if !reflect.DeepEqual(err, tt.wantError) {
+				t.Fatalf("legacyParse() got error = %v, want = %v.", err, tt.wantError)
+			}
+
+			defer dataset.close()
+
+			modelUsers := model.Users{}
+
+			dataset.Users(func(chunk *model.Users) error {
+				modelUsers = append(modelUsers, *chunk...)
+				return nil
+			})
+
+			if !reflect.DeepEqual(modelUsers, tt.wantUsers) {
+				t.Errorf("legacyParse() got users = %v, want = %v.", modelUsers, tt.wantUsers)
+			}
+
+			if !reflect.DeepEqual(dataset.recordsError, tt.wantErrors) {
+				t.Errorf("legacyParse() got errors = %v, want = %v.", dataset.recordsError, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestOrderLegacyDecoderChunks(t *testing.T) {
+	// lines larger than the buffer of the decoder are decoded in chunks
+	line := strings.Repeat("\xe7", 10000) + strings.Repeat("ç", 10000)
+
+	decoder := newLegacyDecoder(bytes.NewBufferString(line+"\n"+line), model.LegacyImportEncodingAuto)
+
+	buffer := new(bytes.Buffer)
+
+	if _, err := buffer.ReadFrom(decoder); err != nil {
+		t.Fatalf("legacyDecoder.Read() got error = %v.", err)
+	}
+
+	// the first chunk of the line decides its encoding
+	want := strings.Repeat("ç", 10000) + strings.Repeat("Ã§", 10000)
+
+	if got := buffer.String(); got != want+"\n"+want {
+		t.Errorf("legacyDecoder.Read() got %v bytes, want = %v bytes.", len(got), len(want+"\n"+want))
+	}
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"strings"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+)
+
+// codes of the errors of the records, stable to be handled by the clients
+// while the messages may change
+const (
+	OrderErrorCodeRecordInvalid          = "RECORD_INVALID"
+	OrderErrorCodeRecordSize             = "RECORD_SIZE_INVALID"
+	OrderErrorCodeRecordColumns          = "RECORD_COLUMNS_INVALID"
+	OrderErrorCodeRecordCSVInvalid       = "RECORD_CSV_INVALID"
+	OrderErrorCodeRecordJSONInvalid      = "RECORD_JSON_INVALID"
+	OrderErrorCodeColumnNotFound         = "COLUMN_NOT_FOUND"
+	OrderErrorCodeUserIDInvalid          = "USER_ID_INVALID"
+	OrderErrorCodeUserNameInvalid        = "USER_NAME_INVALID"
+	OrderErrorCodeOrderIDInvalid         = "ORDER_ID_INVALID"
+	OrderErrorCodeProductIDInvalid       = "PRODUCT_ID_INVALID"
+	OrderErrorCodeProductValueInvalid    = "PRODUCT_VALUE_INVALID"
+	OrderErrorCodeBuyDateInvalid         = "BUY_DATE_INVALID"
+	OrderErrorCodeBuyDateBetween         = "BUY_DATE_OUT_OF_RANGE"
+	OrderErrorCodeUserIDOutOfRange       = "USER_ID_OUT_OF_RANGE"
+	OrderErrorCodeOrderIDOutOfRange      = "ORDER_ID_OUT_OF_RANGE"
+	OrderErrorCodeProductIDOutOfRange    = "PRODUCT_ID_OUT_OF_RANGE"
+	OrderErrorCodeProductValueOutOfRange = "PRODUCT_VALUE_OUT_OF_RANGE"
+	OrderErrorCodeOrderUserDivergent     = "ORDER_USER_DIVERGENT"
+	OrderErrorCodeUserNameDivergent      = "USER_NAME_DIVERGENT"
+	OrderErrorCodeOrderProductDuplicate  = "ORDER_PRODUCT_DUPLICATE"
+)
+
+var OrderErrorMessageRecordValidate = "Error validating the file records"
+
+// legacyRecordErrors is the error of a record with one entry for each field
+// in error. Its message is the messages of the entries joined, the same
+// message of the record before the errors were structured.
+type legacyRecordErrors []model.LegacyFieldError
+
+func (errs legacyRecordErrors) Error() string {
+	messages := make([]string, len(errs))
+
+	for index, err := range errs {
+		messages[index] = err.Message
+	}
+
+	return strings.Join(messages, ";")
+}
+
+// newLegacyRecordError returns the error of the whole record
+func newLegacyRecordError(code string, message string) legacyRecordErrors {
+	return legacyRecordErrors{{Code: code, Message: message}}
+}
+
+// newLegacyRecordErrors converts an error of the record to the structured
+// one, an unknown error is an error of the whole record
+func newLegacyRecordErrors(err error) legacyRecordErrors {
+	if errs, ok := err.(legacyRecordErrors); ok {
+		return errs
+	}
+
+	return newLegacyRecordError(OrderErrorCodeRecordInvalid, err.Error())
+}
+
+// columns fills the positions of the fields in the fixed-width record, the
+// other formats do not have positions
+func (errs legacyRecordErrors) columns(modelLegacyLayout *model.LegacyLayout) legacyRecordErrors {
+	for index := range errs {
+		if errs[index].Field == "" {
+			continue
+		}
+
+		if field := modelLegacyLayout.Field(errs[index].Field); field != nil {
+			errs[index].ColumnStart = field.Start
+			errs[index].ColumnEnd = field.Start + field.Length - 1
+		}
+	}
+
+	return errs
+}
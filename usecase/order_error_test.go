@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	mock_cache "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/cache"
+	mock_repository "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/util"
+)
+
+func TestOrderLegacyErrorsLimit(t *testing.T) {
+	lines := []string{
+		"0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308",
+		"000000007x                              Palmer Prosacco00000007530000000003     1836.7420210308",
+		"000000007x                              Palmer Prosacco000000075x0000000003     1836.7420210308",
+		"0000000070                              Palmer Prosacco0000000753000000000x     1836.7420210308",
+	}
+
+	errorUserID := model.LegacyFieldError{Code: OrderErrorCodeUserIDInvalid, Field: model.LegacyFieldUserID, ColumnStart: 1, ColumnEnd: 10, Value: "000000007x", Message: OrderErrorMessageUserIDInvalid}
+	errorOrderID := model.LegacyFieldError{Code: OrderErrorCodeOrderIDInvalid, Field: model.LegacyFieldOrderID, ColumnStart: 56, ColumnEnd: 65, Value: "000000075x", Message: OrderErrorMessageOrderIDInvalid}
+	errorProductID := model.LegacyFieldError{Code: OrderErrorCodeProductIDInvalid, Field: model.LegacyFieldProductID, ColumnStart: 66, ColumnEnd: 75, Value: "000000000x", Message: OrderErrorMessageProductIDInvalid}
+
+	messageUserIDOrderID := OrderErrorMessageUserIDInvalid + ";" + OrderErrorMessageOrderIDInvalid
+
+	type test struct {
+		name      string
+		inputCfg  util.Config
+		wantTotal int
+		// the errors are truncated when there are more than the limit
+		wantTruncated bool
+		wantErrors    model.LegacyRecordsError
+	}
+
+	tests := []test{
+		{
+			name:          "Unlimited",
+			inputCfg:      util.Config{},
+			wantTotal:     4,
+			wantTruncated: false,
+			wantErrors: model.LegacyRecordsError{
+				{Line: 2, Message: OrderErrorMessageUserIDInvalid, Errors: []model.LegacyFieldError{errorUserID}},
+				{Line: 3, Message: messageUserIDOrderID, Errors: []model.LegacyFieldError{errorUserID, errorOrderID}},
+				{Line: 4, Message: OrderErrorMessageProductIDInvalid, Errors: []model.LegacyFieldError{errorProductID}},
+			},
+		},
+		{
+			name:          "Limit",
+			inputCfg:      util.Config{LegacyImportMaxErrors: 2},
+			wantTotal:     4,
+			wantTruncated: true,
+			wantErrors: model.LegacyRecordsError{
+				{Line: 2, Message: OrderErrorMessageUserIDInvalid, Errors: []model.LegacyFieldError{errorUserID}},
+				{Line: 3, Message: messageUserIDOrderID, Errors: []model.LegacyFieldError{errorUserID}},
+			},
+		},
+		{
+			name:          "LimitNotReached",
+			inputCfg:      util.Config{LegacyImportMaxErrors: 4},
+			wantTotal:     4,
+			wantTruncated: false,
+			wantErrors: model.LegacyRecordsError{
+				{Line: 2, Message: OrderErrorMessageUserIDInvalid, Errors: []model.LegacyFieldError{errorUserID}},
+				{Line: 3, Message: messageUserIDOrderID, Errors: []model.LegacyFieldError{errorUserID, errorOrderID}},
+				{Line: 4, Message: OrderErrorMessageProductIDInvalid, Errors: []model.LegacyFieldError{errorProductID}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecaseOrder := NewOrder(new(mock_repository.MockRepository), new(mock_cache.MockCache), &tt.inputCfg)
+
+			modelLegacyValidateResult, err := usecaseOrder.LegacyValidate(bytes.NewBufferString(strings.Join(lines, "\n")), &model.LegacyImportOptions{Lenient: true})
+
+			if err != nil {
+				t.Fatalf("LegacyValidate() got error = %v.", err)
+			}
+
+			if modelLegacyValidateResult.ErrorsTotal != tt.wantTotal {
+				t.Errorf("LegacyValidate() got errors total = %v, want = %v.", modelLegacyValidateResult.ErrorsTotal, tt.wantTotal)
+			}
+
+			if modelLegacyValidateResult.ErrorsTruncated != tt.wantTruncated {
+				t.Errorf("LegacyValidate() got errors truncated = %v, want = %v.", modelLegacyValidateResult.ErrorsTruncated, tt.wantTruncated)
+			}
+
+			if !reflect.DeepEqual(modelLegacyValidateResult.Errors, tt.wantErrors) {
+				t.Errorf("LegacyValidate() got errors = %v, want = %v.", modelLegacyValidateResult.Errors, tt.wantErrors)
+			}
+
+			// all the rejected records are counted even when their errors are not kept
+			if modelLegacyValidateResult.Result.Rejected != 3 {
+				t.Errorf("LegacyValidate() got rejected = %v, want = %v.", modelLegacyValidateResult.Result.Rejected, 3)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+)
+
+var OrderErrorMessageImportNotRestorable = "Import without its records kept in the history, it can not be restored"
+
+func (usecaseOrder *UseCaseOrder) ListLegacyImports() (*model.LegacyImports, error) {
+	return usecaseOrder.Repository.Order().ListLegacyImports()
+}
+
+// LegacyImportRestore makes the dataset of a previous import live again, it
+// waits for the import in progress to finish persisting its records. An import
+// without its records kept in the history is a conflict.
+func (usecaseOrder *UseCaseOrder) LegacyImportRestore(importID int64) (*model.LegacyImport, error) {
+	usecaseOrder.legacyImportLock <- struct{}{}
+	defer func() { <-usecaseOrder.legacyImportLock }()
+
+	modelLegacyImports, err := usecaseOrder.Repository.Order().ListLegacyImports()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, modelLegacyImport := range *modelLegacyImports {
+		if modelLegacyImport.ID == importID && !modelLegacyImport.Restorable {
+			return nil, ErrConflict{Message: OrderErrorMessageImportNotRestorable}
+		}
+	}
+
+	modelLegacyImport, err := usecaseOrder.Repository.Order().LegacyImportRestore(importID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	usecaseOrder.Cache.Order().ClearAll()
+
+	return modelLegacyImport, nil
+}
+
+// legacyImportReplay returns the result of the import of the current dataset
+// with the same idempotency key or checksum, nil when the file was not
+// imported yet. The current dataset is built by the live import and, when it
+// is a merge, by the imports before it up to the last replace.
+func (usecaseOrder *UseCaseOrder) legacyImportReplay(idempotencyKey string, checksum string) (*model.LegacyImportResult, error) {
+	if idempotencyKey == "" && checksum == "" {
+		return nil, nil
+	}
+
+	modelLegacyImports, err := usecaseOrder.Repository.Order().ListLegacyImports()
+
+	if _, ok := err.(repository.ErrNotFound); ok {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	live := false
+
+	// the imports are listed from the most recent to the oldest
+	for _, modelLegacyImport := range *modelLegacyImports {
+		live = live || modelLegacyImport.Live
+
+		if !live {
+			continue
+		}
+
+		if (idempotencyKey != "" && idempotencyKey == modelLegacyImport.IdempotencyKey) || (checksum != "" && checksum == modelLegacyImport.Checksum) {
+			modelLegacyImportResult := modelLegacyImport.Result
+			modelLegacyImportResult.ImportID = modelLegacyImport.ID
+			modelLegacyImportResult.Replayed = true
+
+			return &modelLegacyImportResult, nil
+		}
+
+		if modelLegacyImport.Mode != model.LegacyImportModeMerge {
+			break
+		}
+	}
+
+	return nil, nil
+}
+
+// legacyImportsPrune keeps only the configured number of imports in the
+// history, a failure does not affect the import already persisted.
+func (usecaseOrder *UseCaseOrder) legacyImportsPrune() {
+	if usecaseOrder.Config.LegacyImportHistorySize > 0 {
+		usecaseOrder.Repository.Order().LegacyImportsPrune(usecaseOrder.Config.LegacyImportHistorySize)
+	}
+}
+
+// newLegacyChecksum returns the reader of the uploaded file and the function
+// that returns its SHA-256 once the file has been parsed.
+func newLegacyChecksum(file io.Reader) (io.Reader, func() (string, error)) {
+	hash := sha256.New()
+
+	// a seekable file is hashed before the parse, so the zip archive keeps
+	// reading it directly instead of copying it to a temporary file
+	if fileSeeker, ok := file.(io.ReadSeeker); ok {
+		_, err := io.Copy(hash, fileSeeker)
+
+		if err == nil {
+			_, err = fileSeeker.Seek(0, io.SeekStart)
+		}
+
+		checksum := hex.EncodeToString(hash.Sum(nil))
+
+		return file, func() (string, error) { return checksum, err }
+	}
+
+	reader := io.TeeReader(file, hash)
+
+	return reader, func() (string, error) {
+		// the content not read by the parse is also part of the file
+		_, err := io.Copy(io.Discard, reader)
+
+		return hex.EncodeToString(hash.Sum(nil)), err
+	}
+}
@@ -0,0 +1,256 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	mock_cache "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/cache"
+	mock_repository "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/util"
+)
+
+func TestOrderLegacyImportRestore(t *testing.T) {
+	modelLegacyImport := &model.LegacyImport{ID: 1, Mode: model.LegacyImportModeReplace, Live: true, Restorable: true}
+
+	type test struct {
+		name         string
+		want         *model.LegacyImport
+		wantError    error
+		wantClearAll bool
+		mockOn       func(*mock_repository.MockRepository, *mock_cache.MockCacheOrder)
+	}
+
+	tests := []test{
+		{
+			name:      "NotFoundError",
+			wantError: repository.ErrNotFound{Message: "not found"},
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCacheOrder *mock_cache.MockCacheOrder) {
+				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("ListLegacyImports").Return(&model.LegacyImports{{ID: 2, Live: true, Restorable: true}}, nil)
+				mockRepositoryOrder.On("LegacyImportRestore").Return(nil, repository.ErrNotFound{Message: "not found"})
+				mockRepository.On("Order").Return(mockRepositoryOrder)
+			},
+		},
+		{
+			name:      "HistoryEmptyError",
+			wantError: repository.ErrNotFound{Message: "not found"},
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCacheOrder *mock_cache.MockCacheOrder) {
+				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("ListLegacyImports").Return(nil, repository.ErrNotFound{Message: "not found"})
+				mockRepository.On("Order").Return(mockRepositoryOrder)
+			},
+		},
+		{
+			name:      "NotRestorableError",
+			wantError: ErrConflict{Message: OrderErrorMessageImportNotRestorable},
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCacheOrder *mock_cache.MockCacheOrder) {
+				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("ListLegacyImports").Return(&model.LegacyImports{{ID: 2, Live: true}, {ID: 1}}, nil)
+				mockRepository.On("Order").Return(mockRepositoryOrder)
+			},
+		},
+		{
+			name:         "Success",
+			want:         modelLegacyImport,
+			wantClearAll: true,
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCacheOrder *mock_cache.MockCacheOrder) {
+				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("ListLegacyImports").Return(&model.LegacyImports{{ID: 2, Live: true, Restorable: true}, {ID: 1, Restorable: true}}, nil)
+				mockRepositoryOrder.On("LegacyImportRestore").Return(modelLegacyImport, nil)
+				mockRepository.On("Order").Return(mockRepositoryOrder)
+
+				mockCacheOrder.On("ClearAll").Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepository := new(mock_repository.MockRepository)
+			mockCache := new(mock_cache.MockCache)
+			mockCacheOrder := new(mock_cache.MockCacheOrder)
+			mockCache.On("Order").Return(mockCacheOrder)
+
+			tt.mockOn(mockRepository, mockCacheOrder)
+
+			usecaseOrder := NewOrder(mockRepository, mockCache, &util.Config{})
+
+			got, err := usecaseOrder.LegacyImportRestore(1)
+
+			if !reflect.DeepEqual(err, tt.wantError) {
+				t.Errorf("LegacyImportRestore() got error = %v, want = %v.", err, tt.wantError)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LegacyImportRestore() got = %v, want = %v.", got, tt.want)
+			}
+
+			if tt.wantClearAll {
+				mockCacheOrder.AssertCalled(t, "ClearAll")
+			} else {
+				mockCacheOrder.AssertNotCalled(t, "ClearAll")
+			}
+		})
+	}
+}
+
+func TestOrderLegacyChecksum(t *testing.T) {
+	content := "0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308"
+	want := "50d82e99dd25daaa5c9232c96a74f7c1cb5db3916c32ce8517852f40e99cb539"
+
+	type test struct {
+		name      string
+		inputFile io.Reader
+		// bytes read by the parse before the checksum
+		inputRead int
+	}
+
+	tests := []test{
+		{
+			name:      "Seekable",
+			inputFile: strings.NewReader(content),
+			inputRead: 10,
+		},
+		{
+			name:      "NotSeekable",
+			inputFile: bytes.NewBufferString(content),
+			inputRead: 10,
+		},
+		{
+			name:      "NotSeekableReadAll",
+			inputFile: bytes.NewBufferString(content),
+			inputRead: len(content),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, checksum := newLegacyChecksum(tt.inputFile)
+
+			read := make([]byte, tt.inputRead)
+
+			if _, err := io.ReadFull(file, read); err != nil {
+				t.Fatalf("newLegacyChecksum() got read error = %v.", err)
+			}
+
+			if string(read) != content[:tt.inputRead] {
+				t.Errorf("newLegacyChecksum() got read = %v, want = %v.", string(read), content[:tt.inputRead])
+			}
+
+			got, err := checksum()
+
+			if err != nil {
+				t.Fatalf("newLegacyChecksum() got error = %v.", err)
+			}
+
+			if got != want {
+				t.Errorf("newLegacyChecksum() got = %v, want = %v.", got, want)
+			}
+		})
+	}
+}
+
+func TestOrderLegacyImportReplay(t *testing.T) {
+	content := "0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308"
+	checksum := "50d82e99dd25daaa5c9232c96a74f7c1cb5db3916c32ce8517852f40e99cb539"
+
+	modelLegacyImportResult := model.LegacyImportResult{Users: 1, Orders: 1, Products: 1, Accepted: 1}
+
+	type test struct {
+		name            string
+		inputKey        string
+		inputImports    *model.LegacyImports
+		inputImportsErr error
+		want            *model.LegacyImportResult
+		wantError       error
+		wantImport      bool
+	}
+
+	tests := []test{
+		{
+			name:            "ListLegacyImportsError",
+			inputImportsErr: errors.New("ListLegacyImports Error"),
+			wantError:       errors.New("ListLegacyImports Error"),
+		},
+		{
+			name:            "FirstImport",
+			inputImportsErr: repository.ErrNotFound{Message: "not found"},
+			want:            &modelLegacyImportResult,
+			wantImport:      true,
+		},
+		{
+			name:     "IdempotencyKeyReplay",
+			inputKey: "key-1",
+			inputImports: &model.LegacyImports{
+				{ID: 2, Checksum: "other", Mode: model.LegacyImportModeReplace, IdempotencyKey: "key-1", Result: modelLegacyImportResult, Live: true},
+			},
+			want: &model.LegacyImportResult{ImportID: 2, Users: 1, Orders: 1, Products: 1, Accepted: 1, Replayed: true},
+		},
+		{
+			name: "ChecksumReplay",
+			inputImports: &model.LegacyImports{
+				{ID: 2, Checksum: checksum, Mode: model.LegacyImportModeReplace, Result: modelLegacyImportResult, Live: true},
+			},
+			want: &model.LegacyImportResult{ImportID: 2, Users: 1, Orders: 1, Products: 1, Accepted: 1, Replayed: true},
+		},
+		{
+			name: "MergedChecksumReplay",
+			inputImports: &model.LegacyImports{
+				{ID: 3, Checksum: "other", Mode: model.LegacyImportModeMerge, Live: true},
+				{ID: 2, Checksum: checksum, Mode: model.LegacyImportModeReplace, Result: modelLegacyImportResult},
+			},
+			want: &model.LegacyImportResult{ImportID: 2, Users: 1, Orders: 1, Products: 1, Accepted: 1, Replayed: true},
+		},
+		{
+			name: "NotCurrentDatasetImport",
+			inputImports: &model.LegacyImports{
+				{ID: 3, Checksum: checksum, Mode: model.LegacyImportModeReplace},
+				{ID: 2, Checksum: "other", Mode: model.LegacyImportModeReplace, Live: true},
+				{ID: 1, Checksum: checksum, Mode: model.LegacyImportModeReplace},
+			},
+			want:       &modelLegacyImportResult,
+			wantImport: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepository := new(mock_repository.MockRepository)
+			mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+			mockRepositoryOrder.On("ListLegacyImports").Return(tt.inputImports, tt.inputImportsErr)
+			mockRepositoryOrder.On("LegacyBulkInsert").Return(nil)
+			mockRepository.On("Order").Return(mockRepositoryOrder)
+
+			mockCache := new(mock_cache.MockCache)
+			mockCacheOrder := new(mock_cache.MockCacheOrder)
+			mockCacheOrder.On("ClearAll").Return(nil)
+			mockCache.On("Order").Return(mockCacheOrder)
+
+			usecaseOrder := NewOrder(mockRepository, mockCache, &util.Config{})
+
+			got, err := usecaseOrder.LegacyImport(strings.NewReader(content), &model.LegacyImportOptions{IdempotencyKey: tt.inputKey})
+
+			if !reflect.DeepEqual(err, tt.wantError) {
+				t.Errorf("LegacyImport() got error = %v, want = %v.", err, tt.wantError)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LegacyImport() got = %v, want = %v.", got, tt.want)
+			}
+
+			if tt.wantImport {
+				mockRepositoryOrder.AssertCalled(t, "LegacyBulkInsert")
+				mockCacheOrder.AssertCalled(t, "ClearAll")
+			} else {
+				mockRepositoryOrder.AssertNotCalled(t, "LegacyBulkInsert")
+				mockCacheOrder.AssertNotCalled(t, "ClearAll")
+			}
+		})
+	}
+}
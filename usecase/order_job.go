@@ -0,0 +1,284 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/google/uuid"
+)
+
+// number of lines parsed between each progress update
+const legacyImportProgressLines = 1000
+
+var (
+	OrderLegacyImportJobRetention      = 24 * time.Hour
+	OrderErrorMessageJobNotFound       = "Job not found"
+	OrderErrorMessageJobFinished       = "Job already finished"
+	OrderErrorMessageJobPersisting     = "Job already persisting the records, it can not be canceled"
+	OrderErrorMessageJobRecordValidate = "Error validating the file records"
+	OrderErrorMessageJobCanceled       = "Import canceled"
+)
+
+type legacyImportProgress interface {
+	Parsed(lines int64)
+	// Persisting is called before the first record is persisted, the import
+	// can not be canceled from then on
+	Persisting(ctx context.Context) error
+	Persisted(lines int64)
+	RecordsError(errRecordValidate ErrRecordValidate)
+}
+
+type legacyImportProgressNone struct{}
+
+func (legacyImportProgressNone) Parsed(lines int64) {}
+
+func (legacyImportProgressNone) Persisting(ctx context.Context) error { return nil }
+
+func (legacyImportProgressNone) Persisted(lines int64) {}
+
+func (legacyImportProgressNone) RecordsError(errRecordValidate ErrRecordValidate) {}
+
+type legacyImportJob struct {
+	mutex  sync.Mutex
+	job    model.LegacyImportJob
+	cancel context.CancelFunc
+}
+
+func (job *legacyImportJob) Parsed(lines int64) {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	job.job.LinesParsed = lines
+}
+
+// Persisting switches the job to persisting unless it was canceled, in the same
+// lock of the cancellation so a cancel accepted is never ignored
+func (job *legacyImportJob) Persisting(ctx context.Context) error {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	job.job.State = model.LegacyImportJobStatePersisting
+
+	return nil
+}
+
+func (job *legacyImportJob) Persisted(lines int64) {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	job.job.LinesPersisted = lines
+}
+
+func (job *legacyImportJob) RecordsError(errRecordValidate ErrRecordValidate) {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	job.job.Errors = errRecordValidate.RecordsError
+	job.job.ErrorsTotal = errRecordValidate.Total
+	job.job.ErrorsTruncated = errRecordValidate.Truncated
+}
+
+func (job *legacyImportJob) start() {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	job.job.State = model.LegacyImportJobStateRunning
+}
+
+func (job *legacyImportJob) finish(modelLegacyImportResult *model.LegacyImportResult, err error) {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	finishedAt := time.Now().UTC()
+	job.job.FinishedAt = &finishedAt
+
+	if err == nil {
+		job.job.State = model.LegacyImportJobStateSucceeded
+		job.job.Result = modelLegacyImportResult
+		return
+	}
+
+	if errors.Is(err, context.Canceled) {
+		job.job.State = model.LegacyImportJobStateCanceled
+		job.job.Message = OrderErrorMessageJobCanceled
+		return
+	}
+
+	job.job.State = model.LegacyImportJobStateFailed
+
+	if _, ok := err.(ErrRecordValidate); ok {
+		job.job.Message = OrderErrorMessageJobRecordValidate
+	} else {
+		job.job.Message = err.Error()
+	}
+}
+
+// requestCancel cancels the context of the job while it has not started to
+// persist its records
+func (job *legacyImportJob) requestCancel() error {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	if job.job.Finished() {
+		return ErrConflict{Message: OrderErrorMessageJobFinished}
+	}
+
+	if job.job.State == model.LegacyImportJobStatePersisting {
+		return ErrConflict{Message: OrderErrorMessageJobPersisting}
+	}
+
+	job.cancel()
+
+	return nil
+}
+
+func (job *legacyImportJob) snapshot() *model.LegacyImportJob {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	modelLegacyImportJob := job.job
+
+	return &modelLegacyImportJob
+}
+
+type legacyImportJobs struct {
+	mutex sync.Mutex
+	jobs  map[string]*legacyImportJob
+}
+
+func newLegacyImportJobs() *legacyImportJobs {
+	return &legacyImportJobs{
+		jobs: make(map[string]*legacyImportJob),
+	}
+}
+
+// add includes the job and discards the jobs finished before the retention period
+func (jobs *legacyImportJobs) add(job *legacyImportJob) {
+	jobs.mutex.Lock()
+	defer jobs.mutex.Unlock()
+
+	retentionLimit := time.Now().UTC().Add(-OrderLegacyImportJobRetention)
+
+	for jobID, jobStored := range jobs.jobs {
+		modelLegacyImportJob := jobStored.snapshot()
+
+		if modelLegacyImportJob.Finished() && modelLegacyImportJob.FinishedAt.Before(retentionLimit) {
+			delete(jobs.jobs, jobID)
+		}
+	}
+
+	jobs.jobs[job.job.ID] = job
+}
+
+func (jobs *legacyImportJobs) get(jobID string) (*legacyImportJob, bool) {
+	jobs.mutex.Lock()
+	defer jobs.mutex.Unlock()
+
+	job, ok := jobs.jobs[jobID]
+
+	return job, ok
+}
+
+func (usecaseOrder *UseCaseOrder) LegacyImportAsync(file io.Reader, modelLegacyImportOptions *model.LegacyImportOptions) (*model.LegacyImportJob, error) {
+	_, err := usecaseOrder.legacyImportOptionsValidate(modelLegacyImportOptions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	file, err = usecaseOrder.legacyFileSizeLimit(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the file is copied because the import runs after the end of the request that uploaded it
+	fileTemp, err := os.CreateTemp(usecaseOrder.Config.LegacyImportTempDir, "legacy-import-*.txt")
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = io.Copy(fileTemp, file)
+
+	if err == nil {
+		_, err = fileTemp.Seek(0, io.SeekStart)
+	}
+
+	if err != nil {
+		fileTemp.Close()
+		os.Remove(fileTemp.Name())
+		return nil, err
+	}
+
+	return usecaseOrder.legacyImportJobStart(fileTemp, modelLegacyImportOptions), nil
+}
+
+// legacyImportJobStart imports the temporary file in background, the file is
+// removed when the import finishes
+func (usecaseOrder *UseCaseOrder) legacyImportJobStart(fileTemp *os.File, modelLegacyImportOptions *model.LegacyImportOptions) *model.LegacyImportJob {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	job := &legacyImportJob{
+		job: model.LegacyImportJob{
+			ID:        uuid.New().String(),
+			State:     model.LegacyImportJobStatePending,
+			CreatedAt: time.Now().UTC(),
+		},
+		cancel: cancel,
+	}
+
+	usecaseOrder.legacyImportJobs.add(job)
+
+	go func() {
+		defer cancel()
+		defer os.Remove(fileTemp.Name())
+		defer fileTemp.Close()
+
+		job.start()
+
+		modelLegacyImportResult, err := usecaseOrder.legacyImport(ctx, fileTemp, modelLegacyImportOptions, job)
+
+		job.finish(modelLegacyImportResult, err)
+	}()
+
+	return job.snapshot()
+}
+
+func (usecaseOrder *UseCaseOrder) GetLegacyImportJob(jobID string) (*model.LegacyImportJob, error) {
+	job, ok := usecaseOrder.legacyImportJobs.get(jobID)
+
+	if !ok {
+		return nil, ErrNotFound{Message: OrderErrorMessageJobNotFound}
+	}
+
+	return job.snapshot(), nil
+}
+
+// CancelLegacyImportJob requests the cancellation of the job, an import that
+// has already started to persist its records is not interrupted and its
+// cancellation is refused.
+func (usecaseOrder *UseCaseOrder) CancelLegacyImportJob(jobID string) (*model.LegacyImportJob, error) {
+	job, ok := usecaseOrder.legacyImportJobs.get(jobID)
+
+	if !ok {
+		return nil, ErrNotFound{Message: OrderErrorMessageJobNotFound}
+	}
+
+	err := job.requestCancel()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return job.snapshot(), nil
+}
@@ -0,0 +1,286 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	mock_cache "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/cache"
+	mock_repository "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/util"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOrderLegacyImportAsync(t *testing.T) {
+	type test struct {
+		name       string
+		inputFile  func() io.Reader
+		inputMode  string
+		wantState  string
+		wantResult *model.LegacyImportResult
+		wantErrors model.LegacyRecordsError
+		// total of the errors, including the ones not kept
+		wantErrorsTotal int
+		wantMessage     string
+		wantError       error
+		mockOn          func(*mock_repository.MockRepository, *mock_cache.MockCache)
+	}
+
+	tests := []test{
+		{
+			name: "ModeInvalidError",
+			inputFile: func() io.Reader {
+				return bytes.NewBufferString("")
+			},
+			inputMode: "append",
+			wantError: ErrParamValidate{Message: OrderErrorMessageModeInvalid},
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+				return
+			},
+		},
+		{
+			name: "RecordValidateFailed",
+			inputFile: func() io.Reader {
+				lines := []string{
+					"0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308",
+					"000000007x                              Palmer Prosacco00000007530000000003     1836.7420210308",
+				}
+
+				return bytes.NewBufferString(strings.Join(lines, "\n"))
+			},
+			wantState: model.LegacyImportJobStateFailed,
+			wantErrors: model.LegacyRecordsError{
+				{
+					Line:    2,
+					Message: OrderErrorMessageUserIDInvalid,
+					Errors: []model.LegacyFieldError{
+						{Code: OrderErrorCodeUserIDInvalid, Field: model.LegacyFieldUserID, ColumnStart: 1, ColumnEnd: 10, Value: "000000007x", Message: OrderErrorMessageUserIDInvalid},
+					},
+				},
+			},
+			wantErrorsTotal: 1,
+			wantMessage:     OrderErrorMessageJobRecordValidate,
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+				return
+			},
+		},
+		{
+			name: "RepositoryFailed",
+			inputFile: func() io.Reader {
+				lines := []string{
+					"0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308",
+				}
+
+				return bytes.NewBufferString(strings.Join(lines, "\n"))
+			},
+			wantState:   model.LegacyImportJobStateFailed,
+			wantMessage: "LegacyBulkInsert Error",
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("ListLegacyImports").Return(nil, repository.ErrNotFound{Message: "not found"})
+				mockRepositoryOrder.On("LegacyBulkInsert").Return(errors.New("LegacyBulkInsert Error"))
+				mockRepository.On("Order").Return(mockRepositoryOrder)
+
+				mockCacheOrder := new(mock_cache.MockCacheOrder)
+				mockCacheOrder.On("ClearAll").Return(nil)
+				mockCache.On("Order").Return(mockCacheOrder)
+			},
+		},
+		{
+			name: "Succeeded",
+			inputFile: func() io.Reader {
+				lines := []string{
+					"0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308",
+					"0000000075                                  Bobbie Batz00000007980000000002     1578.5720211116",
+				}
+
+				return bytes.NewBufferString(strings.Join(lines, "\n"))
+			},
+			wantState: model.LegacyImportJobStateSucceeded,
+			wantResult: &model.LegacyImportResult{
+				Users:    2,
+				Orders:   2,
+				Products: 2,
+				Accepted: 2,
+			},
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("ListLegacyImports").Return(nil, repository.ErrNotFound{Message: "not found"})
+				mockRepositoryOrder.On("LegacyBulkInsert").Return(nil)
+				mockRepository.On("Order").Return(mockRepositoryOrder)
+
+				mockCacheOrder := new(mock_cache.MockCacheOrder)
+				mockCacheOrder.On("ClearAll").Return(nil)
+				mockCache.On("Order").Return(mockCacheOrder)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepository := new(mock_repository.MockRepository)
+			mockCache := new(mock_cache.MockCache)
+
+			tt.mockOn(mockRepository, mockCache)
+
+			usecaseOrder := NewOrder(mockRepository, mockCache, &util.Config{})
+
+			modelLegacyImportJob, err := usecaseOrder.LegacyImportAsync(tt.inputFile(), &model.LegacyImportOptions{Mode: tt.inputMode})
+
+			if !reflect.DeepEqual(err, tt.wantError) {
+				t.Fatalf("LegacyImportAsync() got error = %v, want = %v.", err, tt.wantError)
+			}
+
+			if err != nil {
+				return
+			}
+
+			modelLegacyImportJob = testOrderLegacyImportJobWait(t, usecaseOrder, modelLegacyImportJob.ID)
+
+			if modelLegacyImportJob.State != tt.wantState {
+				t.Errorf("LegacyImportAsync() got state = %v, want = %v.", modelLegacyImportJob.State, tt.wantState)
+			}
+
+			if !reflect.DeepEqual(modelLegacyImportJob.Result, tt.wantResult) {
+				t.Errorf("LegacyImportAsync() got result = %v, want = %v.", modelLegacyImportJob.Result, tt.wantResult)
+			}
+
+			if !reflect.DeepEqual(modelLegacyImportJob.Errors, tt.wantErrors) {
+				t.Errorf("LegacyImportAsync() got errors = %v, want = %v.", modelLegacyImportJob.Errors, tt.wantErrors)
+			}
+
+			if modelLegacyImportJob.ErrorsTotal != tt.wantErrorsTotal {
+				t.Errorf("LegacyImportAsync() got errors total = %v, want = %v.", modelLegacyImportJob.ErrorsTotal, tt.wantErrorsTotal)
+			}
+
+			if modelLegacyImportJob.Message != tt.wantMessage {
+				t.Errorf("LegacyImportAsync() got message = %v, want = %v.", modelLegacyImportJob.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestOrderCancelLegacyImportJob(t *testing.T) {
+	mockRepository := new(mock_repository.MockRepository)
+	mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+	mockRepositoryOrder.On("ListLegacyImports").Return(nil, repository.ErrNotFound{Message: "not found"})
+	mockRepositoryOrder.On("LegacyBulkInsert").Return(nil)
+	mockRepository.On("Order").Return(mockRepositoryOrder)
+
+	mockCache := new(mock_cache.MockCache)
+	mockCacheOrder := new(mock_cache.MockCacheOrder)
+	mockCacheOrder.On("ClearAll").Return(nil)
+	mockCache.On("Order").Return(mockCacheOrder)
+
+	usecaseOrder := NewOrder(mockRepository, mockCache, &util.Config{})
+
+	_, err := usecaseOrder.CancelLegacyImportJob("unknown")
+
+	if !reflect.DeepEqual(err, ErrNotFound{Message: OrderErrorMessageJobNotFound}) {
+		t.Errorf("CancelLegacyImportJob() got error = %v, want = %v.", err, ErrNotFound{Message: OrderErrorMessageJobNotFound})
+	}
+
+	// the import can not persist while the lock is held, so the job stays cancellable
+	usecaseOrder.(*UseCaseOrder).legacyImportLock <- struct{}{}
+
+	file := bytes.NewBufferString("0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308")
+
+	modelLegacyImportJob, err := usecaseOrder.LegacyImportAsync(file, &model.LegacyImportOptions{})
+
+	if err != nil {
+		t.Fatalf("LegacyImportAsync() got error = %v.", err)
+	}
+
+	_, err = usecaseOrder.CancelLegacyImportJob(modelLegacyImportJob.ID)
+
+	if err != nil {
+		t.Errorf("CancelLegacyImportJob() got error = %v.", err)
+	}
+
+	modelLegacyImportJob = testOrderLegacyImportJobWait(t, usecaseOrder, modelLegacyImportJob.ID)
+
+	<-usecaseOrder.(*UseCaseOrder).legacyImportLock
+
+	if modelLegacyImportJob.State != model.LegacyImportJobStateCanceled {
+		t.Errorf("CancelLegacyImportJob() got state = %v, want = %v.", modelLegacyImportJob.State, model.LegacyImportJobStateCanceled)
+	}
+
+	_, err = usecaseOrder.CancelLegacyImportJob(modelLegacyImportJob.ID)
+
+	if !reflect.DeepEqual(err, ErrConflict{Message: OrderErrorMessageJobFinished}) {
+		t.Errorf("CancelLegacyImportJob() got error = %v, want = %v.", err, ErrConflict{Message: OrderErrorMessageJobFinished})
+	}
+}
+
+func TestOrderCancelLegacyImportJobPersisting(t *testing.T) {
+	persisting := make(chan struct{})
+	persisted := make(chan struct{})
+
+	mockRepository := new(mock_repository.MockRepository)
+	mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+	mockRepositoryOrder.On("ListLegacyImports").Return(nil, repository.ErrNotFound{Message: "not found"})
+	mockRepositoryOrder.On("LegacyBulkInsert").Run(func(mock.Arguments) {
+		close(persisting)
+		<-persisted
+	}).Return(nil)
+	mockRepository.On("Order").Return(mockRepositoryOrder)
+
+	mockCache := new(mock_cache.MockCache)
+	mockCacheOrder := new(mock_cache.MockCacheOrder)
+	mockCacheOrder.On("ClearAll").Return(nil)
+	mockCache.On("Order").Return(mockCacheOrder)
+
+	usecaseOrder := NewOrder(mockRepository, mockCache, &util.Config{})
+
+	file := bytes.NewBufferString("0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308")
+
+	modelLegacyImportJob, err := usecaseOrder.LegacyImportAsync(file, &model.LegacyImportOptions{})
+
+	if err != nil {
+		t.Fatalf("LegacyImportAsync() got error = %v.", err)
+	}
+
+	<-persisting
+
+	_, err = usecaseOrder.CancelLegacyImportJob(modelLegacyImportJob.ID)
+
+	if !reflect.DeepEqual(err, ErrConflict{Message: OrderErrorMessageJobPersisting}) {
+		t.Errorf("CancelLegacyImportJob() got error = %v, want = %v.", err, ErrConflict{Message: OrderErrorMessageJobPersisting})
+	}
+
+	close(persisted)
+
+	modelLegacyImportJob = testOrderLegacyImportJobWait(t, usecaseOrder, modelLegacyImportJob.ID)
+
+	if modelLegacyImportJob.State != model.LegacyImportJobStateSucceeded {
+		t.Errorf("CancelLegacyImportJob() got state = %v, want = %v.", modelLegacyImportJob.State, model.LegacyImportJobStateSucceeded)
+	}
+}
+
+func testOrderLegacyImportJobWait(t *testing.T, usecaseOrder Order, jobID string) *model.LegacyImportJob {
+	timeout := time.After(5 * time.Second)
+
+	for {
+		modelLegacyImportJob, err := usecaseOrder.GetLegacyImportJob(jobID)
+
+		if err != nil {
+			t.Fatalf("GetLegacyImportJob() got error = %v.", err)
+		}
+
+		if modelLegacyImportJob.Finished() {
+			return modelLegacyImportJob
+		}
+
+		select {
+		case <-timeout:
+			t.Fatalf("GetLegacyImportJob() job %v not finished", jobID)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
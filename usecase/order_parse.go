@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"io"
+	"sync"
+)
+
+// legacyParseBatchSize is the number of records parsed by a worker at a time
+const legacyParseBatchSize = 1000
+
+// legacyParser converts the records read from the file to the records
+// spilled by the dataset. With more than one worker the records are read in
+// batches parsed concurrently and returned in the order of the file, so the
+// result, including the lines of the errors, is the same of the sequential
+// parsing.
+type legacyParser struct {
+	reader legacyReader
+	parse  func(readerRecord *legacyReaderRecord) *legacySpillRecord
+	// batches in the order of the file, nil on the sequential parsing
+	batches chan *legacyParseBatch
+	batch   *legacyParseBatch
+	index   int
+	stop    chan struct{}
+	stopped sync.WaitGroup
+}
+
+type legacyParseBatch struct {
+	readerRecords []*legacyReaderRecord
+	records       []*legacySpillRecord
+	// error reading the file after the records of the batch
+	err error
+	// closed when the records of the batch were parsed
+	parsed chan struct{}
+}
+
+func newLegacyParser(reader legacyReader, workers int, parse func(readerRecord *legacyReaderRecord) *legacySpillRecord) *legacyParser {
+	parser := &legacyParser{
+		reader: reader,
+		parse:  parse,
+	}
+
+	if workers <= 1 {
+		return parser
+	}
+
+	// the batches waiting to be merged are limited to keep the memory bounded
+	parser.batches = make(chan *legacyParseBatch, workers)
+	parser.stop = make(chan struct{})
+
+	work := make(chan *legacyParseBatch)
+
+	parser.stopped.Add(workers + 1)
+
+	go parser.produce(work)
+
+	for worker := 0; worker < workers; worker++ {
+		go parser.work(work)
+	}
+
+	return parser
+}
+
+// Read returns io.EOF when there are no more records
+func (parser *legacyParser) Read() (*legacySpillRecord, error) {
+	if parser.batches == nil {
+		readerRecord, err := parser.reader.Read()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return parser.parse(readerRecord), nil
+	}
+
+	for {
+		if parser.batch != nil {
+			if parser.index < len(parser.batch.records) {
+				record := parser.batch.records[parser.index]
+				parser.batch.records[parser.index] = nil
+				parser.index++
+
+				return record, nil
+			}
+
+			if parser.batch.err != nil {
+				return nil, parser.batch.err
+			}
+		}
+
+		batch, ok := <-parser.batches
+
+		if !ok {
+			return nil, io.EOF
+		}
+
+		<-batch.parsed
+
+		parser.batch = batch
+		parser.index = 0
+	}
+}
+
+// close stops the reading of the file and waits for the workers, it must be
+// called when the records are not read until the end
+func (parser *legacyParser) close() {
+	if parser.batches == nil {
+		return
+	}
+
+	close(parser.stop)
+	parser.stopped.Wait()
+}
+
+// produce reads the batches of records, sending each one to be parsed by a
+// worker and to be merged in the order of the file
+func (parser *legacyParser) produce(work chan<- *legacyParseBatch) {
+	defer parser.stopped.Done()
+	defer close(parser.batches)
+	defer close(work)
+
+	for {
+		batch := &legacyParseBatch{
+			readerRecords: make([]*legacyReaderRecord, 0, legacyParseBatchSize),
+			parsed:        make(chan struct{}),
+		}
+
+		eof := false
+
+		for len(batch.readerRecords) < legacyParseBatchSize && batch.err == nil && !eof {
+			readerRecord, err := parser.reader.Read()
+
+			switch err {
+			case nil:
+				batch.readerRecords = append(batch.readerRecords, readerRecord)
+			case io.EOF:
+				eof = true
+			default:
+				batch.err = err
+			}
+		}
+
+		select {
+		case work <- batch:
+		case <-parser.stop:
+			return
+		}
+
+		select {
+		case parser.batches <- batch:
+		case <-parser.stop:
+			return
+		}
+
+		if eof || batch.err != nil {
+			return
+		}
+	}
+}
+
+func (parser *legacyParser) work(work <-chan *legacyParseBatch) {
+	defer parser.stopped.Done()
+
+	for batch := range work {
+		batch.records = make([]*legacySpillRecord, len(batch.readerRecords))
+
+		for index, readerRecord := range batch.readerRecords {
+			batch.records[index] = parser.parse(readerRecord)
+		}
+
+		batch.readerRecords = nil
+
+		close(batch.parsed)
+	}
+}
@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	mock_cache "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/cache"
+	mock_repository "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/util"
+)
+
+func TestOrderLegacyParseWorkers(t *testing.T) {
+	data, err := os.ReadFile("../data_1.txt")
+
+	if err != nil {
+		t.Fatalf("ReadFile() got error = %v.", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+
+	// invalid records spread over more than one batch
+	lines[10] = strings.Replace(lines[10], "2021", "20X1", 1)
+	lines[legacyParseBatchSize+10] = "invalid"
+	lines = append(lines, lines[legacyParseBatchSize*2])
+
+	content := strings.Join(lines, "\n")
+
+	type test struct {
+		name      string
+		inputFile string
+		inputCfg  util.Config
+	}
+
+	tests := []test{
+		{
+			name:      "Records",
+			inputFile: content,
+		},
+		{
+			name:      "RecordSizeLimitError",
+			inputFile: content + "\n" + strings.Repeat(" ", 200) + "\n" + content,
+			inputCfg:  util.Config{LegacyImportMaxRecordSize: 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validate := func(workers int) (*model.LegacyValidateResult, error) {
+				cfg := tt.inputCfg
+				cfg.LegacyImportParseWorkers = workers
+
+				usecaseOrder := NewOrder(new(mock_repository.MockRepository), new(mock_cache.MockCache), &cfg)
+
+				return usecaseOrder.LegacyValidate(bytes.NewBufferString(tt.inputFile), &model.LegacyImportOptions{Lenient: true})
+			}
+
+			want, wantErr := validate(1)
+
+			for _, workers := range []int{2, 8} {
+				got, err := validate(workers)
+
+				if !reflect.DeepEqual(err, wantErr) {
+					t.Errorf("LegacyValidate() with %d workers got error = %v, want = %v.", workers, err, wantErr)
+				}
+
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("LegacyValidate() with %d workers got result = %v, want = %v.", workers, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestOrderLegacyParserClose(t *testing.T) {
+	content := strings.Repeat("0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308\n", legacyParseBatchSize*10)
+
+	usecaseOrder := NewOrder(new(mock_repository.MockRepository), new(mock_cache.MockCache), &util.Config{}).(*UseCaseOrder)
+
+	modelLegacyImportOptions := &model.LegacyImportOptions{}
+	modelLegacyLayout, _ := usecaseOrder.legacyImportOptionsValidate(modelLegacyImportOptions)
+
+	reader := usecaseOrder.newLegacyReader(strings.NewReader(content), modelLegacyImportOptions, modelLegacyLayout)
+
+	parser := newLegacyParser(reader, 4, func(readerRecord *legacyReaderRecord) *legacySpillRecord {
+		return &legacySpillRecord{Line: readerRecord.line}
+	})
+
+	for line := int64(1); line <= 10; line++ {
+		record, err := parser.Read()
+
+		if err != nil {
+			t.Fatalf("legacyParser.Read() got error = %v.", err)
+		}
+
+		if record.Line != line {
+			t.Errorf("legacyParser.Read() got line = %v, want = %v.", record.Line, line)
+		}
+	}
+
+	// the reading stops before the end of the file without blocking
+	parser.close()
+}
+
+func BenchmarkOrderLegacyParse(b *testing.B) {
+	for _, file := range []string{"data_1.txt", "data_2.txt"} {
+		data, err := os.ReadFile("../" + file)
+
+		if err != nil {
+			b.Fatalf("ReadFile() got error = %v.", err)
+		}
+
+		for _, workers := range []int{1, 0} {
+			name := fmt.Sprintf("%s/Workers%d", file, workers)
+
+			if workers == 0 {
+				name = file + "/WorkersCPU"
+			}
+
+			b.Run(name, func(b *testing.B) {
+				usecaseOrder := NewOrder(new(mock_repository.MockRepository), new(mock_cache.MockCache), &util.Config{
+					LegacyImportParseWorkers: workers,
+					LegacyImportTempDir:      b.TempDir(),
+				})
+
+				b.SetBytes(int64(len(data)))
+				b.ResetTimer()
+
+				for i := 0; i < b.N; i++ {
+					if _, err := usecaseOrder.LegacyValidate(bytes.NewReader(data), &model.LegacyImportOptions{}); err != nil {
+						b.Fatalf("LegacyValidate() got error = %v.", err)
+					}
+				}
+			})
+		}
+	}
+}
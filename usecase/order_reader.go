@@ -0,0 +1,530 @@
+package usecase
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+)
+
+var (
+	OrderErrorMessageFormatInvalid     = fmt.Sprintf("The param format is invalid, the allowed values are %v, %v and %v", model.LegacyImportFormatFixedWidth, model.LegacyImportFormatCSV, model.LegacyImportFormatNDJSON)
+	OrderErrorMessageColumnNotFound    = "Column not found: %v"
+	OrderErrorMessageRecordColumns     = "Record columns not equal %d"
+	OrderErrorMessageRecordCSVInvalid  = "Record is not a valid CSV line"
+	OrderErrorMessageRecordJSONInvalid = "Record is not a valid JSON object"
+	OrderErrorMessageRecordSizeLimit   = "The record of the line %d exceeds the limit of %d bytes"
+)
+
+// legacyReaderRecord is a record read from the legacy file
+type legacyReaderRecord struct {
+	// file of the zip archive where the record is
+	file string
+	// line of the file where the record starts
+	line int64
+	// content of the record, kept to quarantine it when rejected
+	raw    string
+	record *model.LegacyRecord
+	// error of the record, the reading of the file continues
+	err error
+	// the fields of the record are in positions of the layout
+	fixedWidth bool
+}
+
+// legacyReader reads the records of the legacy file in one of the supported
+// formats, so all of them share the validation and aggregation of the import.
+type legacyReader interface {
+	// Read returns io.EOF when there are no more records
+	Read() (*legacyReaderRecord, error)
+}
+
+func (usecaseOrder *UseCaseOrder) newLegacyReader(file io.Reader, modelLegacyImportOptions *model.LegacyImportOptions, modelLegacyLayout *model.LegacyLayout) legacyReader {
+	maxRecordSize := usecaseOrder.Config.LegacyImportMaxRecordSize
+
+	file = newLegacyDecoder(file, modelLegacyImportOptions.Encoding)
+
+	switch modelLegacyImportOptions.Format {
+	case model.LegacyImportFormatCSV:
+		return newLegacyReaderCSV(file, maxRecordSize, modelLegacyLayout)
+	case model.LegacyImportFormatNDJSON:
+		return newLegacyReaderNDJSON(file, maxRecordSize, modelLegacyLayout)
+	default:
+		return newLegacyReaderFixedWidth(file, maxRecordSize, modelLegacyImportOptions, modelLegacyLayout)
+	}
+}
+
+// legacyScanner reads the lines of the file accepting lines up to the
+// configured size instead of the 64KB of the bufio.Scanner
+type legacyScanner struct {
+	*bufio.Scanner
+	maxRecordSize int
+}
+
+func newLegacyScanner(file io.Reader, maxRecordSize int) *legacyScanner {
+	scanner := bufio.NewScanner(file)
+
+	if maxRecordSize <= 0 {
+		maxRecordSize = bufio.MaxScanTokenSize
+	}
+
+	// the limit is the larger of the max and the capacity of the buffer
+	bufferSize := 4096
+
+	if bufferSize > maxRecordSize {
+		bufferSize = maxRecordSize
+	}
+
+	// the line break is not part of the record
+	scanner.Buffer(make([]byte, 0, bufferSize), maxRecordSize+len("\r\n"))
+
+	return &legacyScanner{Scanner: scanner, maxRecordSize: maxRecordSize}
+}
+
+// errAt reports a line longer than the limit as an error of the file, the
+// reading can not continue because the end of the line was not found
+func (scanner *legacyScanner) errAt(line int64) error {
+	err := scanner.Scanner.Err()
+
+	if errors.Is(err, bufio.ErrTooLong) {
+		return ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageRecordSizeLimit, line+1, scanner.maxRecordSize)}
+	}
+
+	return err
+}
+
+type legacyReaderFixedWidth struct {
+	scanner *legacyScanner
+	layout  *model.LegacyLayout
+	line    int64
+	// lines read from the file after the header, ahead of the records
+	scanned int64
+	// the header is read and validated before the first record
+	hasHeader bool
+	// control totals compared with the trailer, nil when the file has no trailer
+	trailer *legacyTrailer
+	// line read ahead to identify the trailer, the last line of the file
+	next     string
+	nextRead bool
+	// blank lines read ahead followed by the line pending, the blank lines at
+	// the end of the file are ignored
+	blanks  int64
+	pending *string
+}
+
+func newLegacyReaderFixedWidth(file io.Reader, maxRecordSize int, modelLegacyImportOptions *model.LegacyImportOptions, modelLegacyLayout *model.LegacyLayout) *legacyReaderFixedWidth {
+	reader := &legacyReaderFixedWidth{
+		scanner:   newLegacyScanner(file, maxRecordSize),
+		layout:    modelLegacyLayout,
+		hasHeader: modelLegacyImportOptions.HasHeader,
+	}
+
+	if modelLegacyImportOptions.HasTrailer {
+		reader.trailer = &legacyTrailer{layout: modelLegacyLayout}
+	}
+
+	return reader
+}
+
+func (reader *legacyReaderFixedWidth) Read() (*legacyReaderRecord, error) {
+	if reader.hasHeader {
+		reader.hasHeader = false
+
+		if err := reader.readHeader(); err != nil {
+			return nil, err
+		}
+	}
+
+	raw, err := reader.scan()
+
+	if err != nil {
+		return nil, err
+	}
+
+	reader.line++
+
+	modelLegacyRecord, err := recordToLegacyRecord(raw, reader.layout)
+
+	if reader.trailer != nil {
+		reader.trailer.add(modelLegacyRecord)
+	}
+
+	return &legacyReaderRecord{line: reader.line, raw: raw, record: modelLegacyRecord, err: err, fixedWidth: true}, nil
+}
+
+// readHeader validates the header, the header of a layout without header
+// fields is only skipped
+func (reader *legacyReaderFixedWidth) readHeader() error {
+	if !reader.scanner.Scan() {
+		if err := reader.scanner.errAt(0); err != nil {
+			return err
+		}
+
+		return ErrFileValidate{Message: OrderErrorMessageHeaderNotFound}
+	}
+
+	return legacyHeaderValidate(reader.scanner.Text(), reader.layout)
+}
+
+// scan returns the next record, with a trailer the last line of the file is
+// validated as the trailer instead of being returned
+func (reader *legacyReaderFixedWidth) scan() (string, error) {
+	if reader.trailer == nil {
+		return reader.scanLine(io.EOF)
+	}
+
+	if !reader.nextRead {
+		next, err := reader.scanLine(ErrFileValidate{Message: OrderErrorMessageTrailerNotFound})
+
+		if err != nil {
+			return "", err
+		}
+
+		reader.next = next
+		reader.nextRead = true
+	}
+
+	next, err := reader.scanLine(io.EOF)
+
+	if err == io.EOF {
+		if err := reader.trailer.validate(reader.next); err != nil {
+			return "", err
+		}
+
+		return "", io.EOF
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	raw := reader.next
+	reader.next = next
+
+	return raw, nil
+}
+
+// scanLine returns the next line of the file or errEOF at the end of the
+// file, the blank lines at the end of the file are ignored
+func (reader *legacyReaderFixedWidth) scanLine(errEOF error) (string, error) {
+	if reader.blanks > 0 {
+		reader.blanks--
+		return "", nil
+	}
+
+	if reader.pending != nil {
+		line := *reader.pending
+		reader.pending = nil
+
+		return line, nil
+	}
+
+	blanks := int64(0)
+
+	for reader.scanner.Scan() {
+		reader.scanned++
+		line := reader.scanner.Text()
+
+		if strings.TrimSpace(line) != "" {
+			if blanks == 0 {
+				return line, nil
+			}
+
+			// the blank lines are records because they are not at the end
+			reader.blanks = blanks - 1
+			reader.pending = &line
+
+			return "", nil
+		}
+
+		blanks++
+	}
+
+	if err := reader.scanner.errAt(reader.scanned); err != nil {
+		return "", err
+	}
+
+	return "", errEOF
+}
+
+// legacyReaderCSV reads a CSV file whose first line names the columns
+type legacyReaderCSV struct {
+	reader  *csv.Reader
+	input   *legacyCSVInput
+	layout  *model.LegacyLayout
+	started bool
+	// index of the column of each field
+	columns map[string]int
+	// number of columns of the header
+	size int
+}
+
+// legacyCSVInput keeps the bytes read by the csv.Reader that were not returned
+// as a record yet, so the record is quarantined as it is in the file. The
+// csv.Reader reads the whole record before returning it, the bytes kept are
+// limited to stop the reading of a record larger than the limit.
+type legacyCSVInput struct {
+	file          io.Reader
+	maxRecordSize int
+	// bytes read after the offset of the input
+	buffer []byte
+	offset int64
+	// line where the record being read starts
+	line int64
+}
+
+func (input *legacyCSVInput) Read(p []byte) (int, error) {
+	// the csv.Reader reads ahead up to the size of its buffer
+	if len(input.buffer) > input.maxRecordSize+len("\r\n")+4096 {
+		return 0, ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageRecordSizeLimit, input.line, input.maxRecordSize)}
+	}
+
+	n, err := input.file.Read(p)
+
+	input.buffer = append(input.buffer, p[:n]...)
+
+	return n, err
+}
+
+// record returns the bytes of the record ended at the offset and discards
+// them, the blank lines skipped by the csv.Reader and the line break are not
+// part of the record
+func (input *legacyCSVInput) record(offset int64) (string, error) {
+	size := offset - input.offset
+	content := string(input.buffer[:size])
+
+	input.buffer = input.buffer[size:]
+	input.offset = offset
+
+	record := strings.TrimLeft(content, "\r\n")
+	input.line += int64(strings.Count(content[:len(content)-len(record)], "\n"))
+
+	record = strings.TrimRight(record, "\r\n")
+
+	if len(record) > input.maxRecordSize {
+		return "", ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageRecordSizeLimit, input.line, input.maxRecordSize)}
+	}
+
+	// the line of the next record, the line breaks inside quotes are counted
+	input.line += int64(strings.Count(record, "\n")) + 1
+
+	return record, nil
+}
+
+func newLegacyReaderCSV(file io.Reader, maxRecordSize int, modelLegacyLayout *model.LegacyLayout) *legacyReaderCSV {
+	if maxRecordSize <= 0 {
+		maxRecordSize = bufio.MaxScanTokenSize
+	}
+
+	input := &legacyCSVInput{file: file, maxRecordSize: maxRecordSize, line: 1}
+
+	reader := csv.NewReader(input)
+	// the number of columns is validated by record to report it as a record error
+	reader.FieldsPerRecord = -1
+
+	return &legacyReaderCSV{
+		reader: reader,
+		input:  input,
+		layout: modelLegacyLayout,
+	}
+}
+
+// read returns the fields and the content of the next record
+func (reader *legacyReaderCSV) read() ([]string, string, error) {
+	fields, err := reader.reader.Read()
+
+	if err != nil && err != io.EOF {
+		if _, ok := err.(*csv.ParseError); !ok {
+			return nil, "", err
+		}
+	}
+
+	raw, errRecord := reader.input.record(reader.reader.InputOffset())
+
+	if errRecord != nil {
+		return nil, "", errRecord
+	}
+
+	return fields, raw, err
+}
+
+func (reader *legacyReaderCSV) Read() (*legacyReaderRecord, error) {
+	if !reader.started {
+		reader.started = true
+
+		modelLegacyReaderRecord, err := reader.readHeader()
+
+		if modelLegacyReaderRecord != nil || err != nil {
+			return modelLegacyReaderRecord, err
+		}
+	}
+
+	if reader.columns == nil {
+		return nil, io.EOF
+	}
+
+	fields, raw, err := reader.read()
+
+	if parseError, ok := err.(*csv.ParseError); ok {
+		return &legacyReaderRecord{line: int64(parseError.StartLine), raw: raw, err: newLegacyRecordError(OrderErrorCodeRecordCSVInvalid, OrderErrorMessageRecordCSVInvalid)}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	line, _ := reader.reader.FieldPos(0)
+
+	modelLegacyReaderRecord := &legacyReaderRecord{line: int64(line), raw: raw}
+
+	if len(fields) != reader.size {
+		modelLegacyReaderRecord.err = newLegacyRecordError(OrderErrorCodeRecordColumns, fmt.Sprintf(OrderErrorMessageRecordColumns, reader.size))
+		return modelLegacyReaderRecord, nil
+	}
+
+	modelLegacyReaderRecord.record = &model.LegacyRecord{
+		UserID:       reader.value(fields, model.LegacyFieldUserID),
+		UserName:     reader.value(fields, model.LegacyFieldUserName),
+		OrderID:      reader.value(fields, model.LegacyFieldOrderID),
+		ProductID:    reader.value(fields, model.LegacyFieldProductID),
+		ProductValue: reader.value(fields, model.LegacyFieldProductValue),
+		BuyDate:      reader.value(fields, model.LegacyFieldBuyDate),
+	}
+
+	return modelLegacyReaderRecord, nil
+}
+
+// readHeader maps the columns of the header to the fields of the layout, the
+// columns not found are reported as an error of the header line and the file
+// is not read anymore.
+func (reader *legacyReaderCSV) readHeader() (*legacyReaderRecord, error) {
+	header, raw, err := reader.read()
+
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+
+	if parseError, ok := err.(*csv.ParseError); ok {
+		return &legacyReaderRecord{line: int64(parseError.StartLine), raw: raw, err: newLegacyRecordError(OrderErrorCodeRecordCSVInvalid, OrderErrorMessageRecordCSVInvalid)}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	headerColumns := make(map[string]int)
+
+	for index, column := range header {
+		headerColumns[strings.TrimSpace(column)] = index
+	}
+
+	columns := make(map[string]int)
+	columnsNotFound := []string{}
+
+	for _, field := range reader.layout.Fields {
+		index, ok := headerColumns[field.ColumnName()]
+
+		if !ok {
+			columnsNotFound = append(columnsNotFound, field.ColumnName())
+			continue
+		}
+
+		columns[field.Name] = index
+	}
+
+	if len(columnsNotFound) > 0 {
+		return &legacyReaderRecord{
+			line: 1,
+			raw:  raw,
+			err:  newLegacyRecordError(OrderErrorCodeColumnNotFound, fmt.Sprintf(OrderErrorMessageColumnNotFound, strings.Join(columnsNotFound, ","))),
+		}, nil
+	}
+
+	reader.columns = columns
+	reader.size = len(header)
+
+	return nil, nil
+}
+
+func (reader *legacyReaderCSV) value(fields []string, fieldName string) string {
+	return legacyFieldTrim(fields[reader.columns[fieldName]], reader.layout.Field(fieldName))
+}
+
+// legacyReaderNDJSON reads a file with one JSON object per line
+type legacyReaderNDJSON struct {
+	scanner *legacyScanner
+	layout  *model.LegacyLayout
+	line    int64
+}
+
+func newLegacyReaderNDJSON(file io.Reader, maxRecordSize int, modelLegacyLayout *model.LegacyLayout) *legacyReaderNDJSON {
+	return &legacyReaderNDJSON{
+		scanner: newLegacyScanner(file, maxRecordSize),
+		layout:  modelLegacyLayout,
+	}
+}
+
+func (reader *legacyReaderNDJSON) Read() (*legacyReaderRecord, error) {
+	for reader.scanner.Scan() {
+		reader.line++
+		raw := reader.scanner.Text()
+
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+
+		modelLegacyReaderRecord := &legacyReaderRecord{line: reader.line, raw: raw}
+
+		values := map[string]interface{}{}
+
+		decoder := json.NewDecoder(strings.NewReader(raw))
+		decoder.UseNumber()
+
+		if err := decoder.Decode(&values); err != nil {
+			modelLegacyReaderRecord.err = newLegacyRecordError(OrderErrorCodeRecordJSONInvalid, OrderErrorMessageRecordJSONInvalid)
+			return modelLegacyReaderRecord, nil
+		}
+
+		modelLegacyReaderRecord.record = &model.LegacyRecord{
+			UserID:       reader.value(values, model.LegacyFieldUserID),
+			UserName:     reader.value(values, model.LegacyFieldUserName),
+			OrderID:      reader.value(values, model.LegacyFieldOrderID),
+			ProductID:    reader.value(values, model.LegacyFieldProductID),
+			ProductValue: reader.value(values, model.LegacyFieldProductValue),
+			BuyDate:      reader.value(values, model.LegacyFieldBuyDate),
+		}
+
+		return modelLegacyReaderRecord, nil
+	}
+
+	if err := reader.scanner.errAt(reader.line); err != nil {
+		return nil, err
+	}
+
+	return nil, io.EOF
+}
+
+// value converts the JSON value of the field to text, a missing key results
+// in an empty value that fails the field validation
+func (reader *legacyReaderNDJSON) value(values map[string]interface{}, fieldName string) string {
+	field := reader.layout.Field(fieldName)
+
+	var value string
+
+	switch jsonValue := values[field.ColumnName()].(type) {
+	case nil:
+		value = ""
+	case string:
+		value = jsonValue
+	case json.Number:
+		value = jsonValue.String()
+	default:
+		value = fmt.Sprint(jsonValue)
+	}
+
+	return legacyFieldTrim(value, field)
+}
@@ -0,0 +1,275 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	mock_cache "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/cache"
+	mock_repository "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/util"
+)
+
+func TestOrderLegacyReader(t *testing.T) {
+	type test struct {
+		name        string
+		inputFile   func() io.Reader
+		inputFormat string
+		wantResult  *model.LegacyValidateResult
+		wantError   error
+	}
+
+	tests := []test{
+		{
+			name: "FormatInvalidError",
+			inputFile: func() io.Reader {
+				return bytes.NewBufferString("")
+			},
+			inputFormat: "xml",
+			wantResult:  nil,
+			wantError:   ErrParamValidate{Message: OrderErrorMessageFormatInvalid},
+		},
+		{
+			name: "CSVColumnNotFound",
+			inputFile: func() io.Reader {
+				lines := []string{
+					"user_id,name,order_id,product_id,value,buy_date",
+					"70,Palmer Prosacco,753,3,1836.74,20210308",
+				}
+
+				return bytes.NewBufferString(strings.Join(lines, "\n"))
+			},
+			inputFormat: model.LegacyImportFormatCSV,
+			wantResult: &model.LegacyValidateResult{
+				Valid: false,
+				Result: model.LegacyImportResult{
+					Users:    0,
+					Orders:   0,
+					Products: 0,
+					Accepted: 0,
+					Rejected: 1,
+				},
+				Errors: model.LegacyRecordsError{
+					{
+						Line:    1,
+						Message: fmt.Sprintf(OrderErrorMessageColumnNotFound, "user_name,product_value"),
+						Errors: []model.LegacyFieldError{
+							{Code: OrderErrorCodeColumnNotFound, Message: fmt.Sprintf(OrderErrorMessageColumnNotFound, "user_name,product_value")},
+						},
+					},
+				},
+				ErrorsTotal: 1,
+			},
+			wantError: nil,
+		},
+		{
+			name: "CSV",
+			inputFile: func() io.Reader {
+				lines := []string{
+					"buy_date,user_id,user_name,order_id,product_id,product_value",
+					"20210308,70,Palmer Prosacco,753,3,1836.74",
+					"20210308,7x,Palmer Prosacco,753,3,1836.74",
+					`20211116,75,"Bobbie ""B""`,
+					`Batz",798,2,1578.57`,
+					"20211116,75,Bobbie Batz,798",
+					`20211116,75,Bob"by Batz,798,2,1578.57`,
+					"20211116,76,Bobbie Batz,799,3,10.00",
+				}
+
+				return bytes.NewBufferString(strings.Join(lines, "\n"))
+			},
+			inputFormat: model.LegacyImportFormatCSV,
+			wantResult: &model.LegacyValidateResult{
+				Valid: false,
+				Result: model.LegacyImportResult{
+					Users:    3,
+					Orders:   3,
+					Products: 3,
+					Accepted: 3,
+					Rejected: 3,
+				},
+				Errors: model.LegacyRecordsError{
+					{
+						Line:    3,
+						Message: OrderErrorMessageUserIDInvalid,
+						Errors: []model.LegacyFieldError{
+							{Code: OrderErrorCodeUserIDInvalid, Field: model.LegacyFieldUserID, Value: "7x", Message: OrderErrorMessageUserIDInvalid},
+						},
+					},
+					{
+						Line:    6,
+						Message: fmt.Sprintf(OrderErrorMessageRecordColumns, 6),
+						Errors: []model.LegacyFieldError{
+							{Code: OrderErrorCodeRecordColumns, Message: fmt.Sprintf(OrderErrorMessageRecordColumns, 6)},
+						},
+					},
+					{
+						Line:    7,
+						Message: OrderErrorMessageRecordCSVInvalid,
+						Errors: []model.LegacyFieldError{
+							{Code: OrderErrorCodeRecordCSVInvalid, Message: OrderErrorMessageRecordCSVInvalid},
+						},
+					},
+				},
+				ErrorsTotal: 3,
+			},
+			wantError: nil,
+		},
+		{
+			name: "NDJSON",
+			inputFile: func() io.Reader {
+				lines := []string{
+					`{"user_id": 70, "user_name": "Palmer Prosacco", "order_id": 753, "product_id": 3, "product_value": 1836.74, "buy_date": "20210308"}`,
+					``,
+					`{"user_id": "75", "user_name": "Bobbie Batz", "order_id": "798", "product_id": "2", "product_value": "1578.57", "buy_date": "20211116"}`,
+					`{"user_id": 75, "user_name": "Bobbie Batz", "order_id": 798, "product_id": 2`,
+					`{"user_id": 75, "user_name": "Bobbie Batz", "order_id": 798, "product_id": 2, "buy_date": "20211116"}`,
+				}
+
+				return bytes.NewBufferString(strings.Join(lines, "\n"))
+			},
+			inputFormat: model.LegacyImportFormatNDJSON,
+			wantResult: &model.LegacyValidateResult{
+				Valid: false,
+				Result: model.LegacyImportResult{
+					Users:    2,
+					Orders:   2,
+					Products: 2,
+					Accepted: 2,
+					Rejected: 2,
+				},
+				Errors: model.LegacyRecordsError{
+					{
+						Line:    4,
+						Message: OrderErrorMessageRecordJSONInvalid,
+						Errors: []model.LegacyFieldError{
+							{Code: OrderErrorCodeRecordJSONInvalid, Message: OrderErrorMessageRecordJSONInvalid},
+						},
+					},
+					{
+						Line:    5,
+						Message: OrderErrorMessageProductValueInvalid,
+						Errors: []model.LegacyFieldError{
+							{Code: OrderErrorCodeProductValueInvalid, Field: model.LegacyFieldProductValue, Message: OrderErrorMessageProductValueInvalid},
+						},
+					},
+				},
+				ErrorsTotal: 2,
+			},
+			wantError: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepository := new(mock_repository.MockRepository)
+			mockCache := new(mock_cache.MockCache)
+
+			usecaseOrder := NewOrder(mockRepository, mockCache, &util.Config{})
+
+			modelLegacyValidateResult, err := usecaseOrder.LegacyValidate(tt.inputFile(), &model.LegacyImportOptions{Format: tt.inputFormat})
+
+			if !reflect.DeepEqual(err, tt.wantError) {
+				t.Errorf("LegacyValidate() got error = %v, want = %v.", err, tt.wantError)
+			}
+
+			if !reflect.DeepEqual(modelLegacyValidateResult, tt.wantResult) {
+				t.Errorf("LegacyValidate() got result = %v, want = %v.", modelLegacyValidateResult, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestOrderLegacyReaderCSVRaw(t *testing.T) {
+	header := "buy_date,user_id,user_name,order_id,product_id,product_value"
+
+	type test struct {
+		name      string
+		inputFile string
+		wantLines []int64
+		wantRaws  []string
+		wantError error
+	}
+
+	tests := []test{
+		{
+			name: "Records",
+			inputFile: strings.Join([]string{
+				header,
+				`20210308, 70 ,"Palmer Prosacco",753,3,1836.74`,
+				"",
+				`20211116,75,"Bobbie ""B""`,
+				`Batz",798,2,1578.57`,
+				`20211116,75,Bob"by Batz,798,2,1578.57`,
+				"20211116,76,Bobbie Batz,799,3,10.00\r",
+				"",
+			}, "\n"),
+			wantLines: []int64{2, 4, 6, 7},
+			wantRaws: []string{
+				`20210308, 70 ,"Palmer Prosacco",753,3,1836.74`,
+				"20211116,75,\"Bobbie \"\"B\"\"\nBatz\",798,2,1578.57",
+				`20211116,75,Bob"by Batz,798,2,1578.57`,
+				"20211116,76,Bobbie Batz,799,3,10.00",
+			},
+			wantError: io.EOF,
+		},
+		{
+			name: "RecordSizeLimitError",
+			inputFile: strings.Join([]string{
+				header,
+				"20210308,70,Palmer Prosacco,753,3,1836.74",
+				"",
+				"20210308,70," + strings.Repeat("P", 100) + ",753,3,1836.74",
+			}, "\n"),
+			wantLines: []int64{2},
+			wantRaws:  []string{"20210308,70,Palmer Prosacco,753,3,1836.74"},
+			wantError: ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageRecordSizeLimit, 4, 100)},
+		},
+		{
+			name:      "RecordLargerThanBufferError",
+			inputFile: header + "\n\"" + strings.Repeat("P", 100<<10),
+			wantLines: []int64{},
+			wantRaws:  []string{},
+			wantError: ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageRecordSizeLimit, 2, 100)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := newLegacyReaderCSV(bytes.NewBufferString(tt.inputFile), 100, &model.LegacyLayoutDefault)
+
+			lines, raws := []int64{}, []string{}
+
+			var err error
+
+			for {
+				var modelLegacyReaderRecord *legacyReaderRecord
+
+				modelLegacyReaderRecord, err = reader.Read()
+
+				if err != nil {
+					break
+				}
+
+				lines = append(lines, modelLegacyReaderRecord.line)
+				raws = append(raws, modelLegacyReaderRecord.raw)
+			}
+
+			if !reflect.DeepEqual(err, tt.wantError) {
+				t.Errorf("Read() got error = %v, want = %v.", err, tt.wantError)
+			}
+
+			if !reflect.DeepEqual(lines, tt.wantLines) {
+				t.Errorf("Read() got lines = %v, want = %v.", lines, tt.wantLines)
+			}
+
+			if !reflect.DeepEqual(raws, tt.wantRaws) {
+				t.Errorf("Read() got raws = %q, want = %q.", raws, tt.wantRaws)
+			}
+		})
+	}
+}
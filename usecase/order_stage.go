@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/cache"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/util"
+)
+
+var (
+	OrderErrorMessageTargetInvalid   = fmt.Sprintf("The param target is invalid, the allowed values are %v and %v", model.LegacyImportTargetLive, model.LegacyImportTargetStage)
+	OrderErrorMessageTargetStageMode = fmt.Sprintf("The param target %v only allows the mode %v", model.LegacyImportTargetStage, model.LegacyImportModeReplace)
+)
+
+// NewOrderStaging returns the usecase of the staged dataset, which answers the
+// same queries of the live dataset until it is promoted
+func NewOrderStaging(repository repository.Repository, cache cache.Cache, config *util.Config) Order {
+	usecaseOrder := NewOrder(repository.Staging(), cache, config).(*UseCaseOrder)
+	usecaseOrder.staging = true
+
+	return usecaseOrder
+}
+
+// LegacyStagePromote makes the staged dataset live, it waits for the import in
+// progress to finish persisting its records.
+func (usecaseOrder *UseCaseOrder) LegacyStagePromote() (*model.LegacyImport, error) {
+	usecaseOrder.legacyImportLock <- struct{}{}
+	defer func() { <-usecaseOrder.legacyImportLock }()
+
+	modelLegacyImport, err := usecaseOrder.Repository.Order().LegacyStagePromote()
+
+	if err != nil {
+		return nil, err
+	}
+
+	usecaseOrder.Cache.Order().ClearAll()
+
+	usecaseOrder.legacyImportsPrune()
+
+	return modelLegacyImport, nil
+}
+
+// legacyStage replaces the staged dataset, the live dataset and the cache are
+// not touched until the staged dataset is promoted
+func (usecaseOrder *UseCaseOrder) legacyStage(modelLegacyImport *model.LegacyImport, dataset *legacyDataset) error {
+	return usecaseOrder.Repository.Staging().Order().LegacyBulkInsert(modelLegacyImport, dataset)
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	mock_cache "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/cache"
+	mock_repository "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/util"
+)
+
+func TestOrderLegacyImportStage(t *testing.T) {
+	mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+	mockRepositoryOrder.On("ListLegacyImports").Return(nil, repository.ErrNotFound{Message: "not found"})
+
+	mockRepositoryStagingOrder := new(mock_repository.MockRepositoryOrder)
+	mockRepositoryStagingOrder.On("LegacyBulkInsert").Return(nil)
+
+	mockRepositoryStaging := new(mock_repository.MockRepository)
+	mockRepositoryStaging.On("Order").Return(mockRepositoryStagingOrder)
+
+	mockRepository := new(mock_repository.MockRepository)
+	mockRepository.On("Order").Return(mockRepositoryOrder)
+	mockRepository.On("Staging").Return(mockRepositoryStaging)
+
+	mockCache := new(mock_cache.MockCache)
+	mockCacheOrder := new(mock_cache.MockCacheOrder)
+	mockCache.On("Order").Return(mockCacheOrder)
+
+	usecaseOrder := NewOrder(mockRepository, mockCache, &util.Config{LegacyImportTempDir: t.TempDir()})
+
+	file := bytes.NewBufferString("0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308")
+
+	_, err := usecaseOrder.LegacyImport(file, &model.LegacyImportOptions{Mode: model.LegacyImportModeMerge, Target: model.LegacyImportTargetStage})
+
+	if !reflect.DeepEqual(err, ErrParamValidate{Message: OrderErrorMessageTargetStageMode}) {
+		t.Errorf("LegacyImport() got error = %v, want = %v.", err, ErrParamValidate{Message: OrderErrorMessageTargetStageMode})
+	}
+
+	_, err = usecaseOrder.LegacyImport(file, &model.LegacyImportOptions{Target: "X"})
+
+	if !reflect.DeepEqual(err, ErrParamValidate{Message: OrderErrorMessageTargetInvalid}) {
+		t.Errorf("LegacyImport() got error = %v, want = %v.", err, ErrParamValidate{Message: OrderErrorMessageTargetInvalid})
+	}
+
+	got, err := usecaseOrder.LegacyImport(file, &model.LegacyImportOptions{Target: model.LegacyImportTargetStage})
+
+	if err != nil {
+		t.Fatalf("LegacyImport() got error = %v.", err)
+	}
+
+	if got.Accepted != 1 {
+		t.Errorf("LegacyImport() got accepted = %v, want = 1.", got.Accepted)
+	}
+
+	// the live dataset and the cache are touched only by the promotion
+	mockRepositoryStagingOrder.AssertCalled(t, "LegacyBulkInsert")
+	mockRepositoryOrder.AssertNotCalled(t, "LegacyBulkInsert")
+	mockCacheOrder.AssertNotCalled(t, "ClearAll")
+}
+
+func TestOrderLegacyStagePromote(t *testing.T) {
+	modelLegacyImport := &model.LegacyImport{ID: 1, Mode: model.LegacyImportModeReplace, Live: true}
+
+	type test struct {
+		name         string
+		want         *model.LegacyImport
+		wantError    error
+		wantClearAll bool
+		mockOn       func(*mock_repository.MockRepository, *mock_cache.MockCacheOrder)
+	}
+
+	tests := []test{
+		{
+			name:      "NotFoundError",
+			wantError: repository.ErrNotFound{Message: "not found"},
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCacheOrder *mock_cache.MockCacheOrder) {
+				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("LegacyStagePromote").Return(nil, repository.ErrNotFound{Message: "not found"})
+				mockRepository.On("Order").Return(mockRepositoryOrder)
+			},
+		},
+		{
+			name:         "Success",
+			want:         modelLegacyImport,
+			wantClearAll: true,
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCacheOrder *mock_cache.MockCacheOrder) {
+				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("LegacyStagePromote").Return(modelLegacyImport, nil)
+				mockRepository.On("Order").Return(mockRepositoryOrder)
+
+				mockCacheOrder.On("ClearAll").Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepository := new(mock_repository.MockRepository)
+			mockCache := new(mock_cache.MockCache)
+			mockCacheOrder := new(mock_cache.MockCacheOrder)
+			mockCache.On("Order").Return(mockCacheOrder)
+
+			tt.mockOn(mockRepository, mockCacheOrder)
+
+			usecaseOrder := NewOrder(mockRepository, mockCache, &util.Config{})
+
+			got, err := usecaseOrder.LegacyStagePromote()
+
+			if !reflect.DeepEqual(err, tt.wantError) {
+				t.Errorf("LegacyStagePromote() got error = %v, want = %v.", err, tt.wantError)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LegacyStagePromote() got = %v, want = %v.", got, tt.want)
+			}
+
+			if tt.wantClearAll {
+				mockCacheOrder.AssertCalled(t, "ClearAll")
+			} else {
+				mockCacheOrder.AssertNotCalled(t, "ClearAll")
+			}
+		})
+	}
+}
@@ -2,8 +2,8 @@ package usecase
 
 import (
 	"bytes"
-	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"reflect"
 	"strings"
@@ -13,6 +13,8 @@ import (
 	mock_cache "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/cache"
 	mock_repository "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/repository"
 	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/util"
 )
 
 func TestOrderLegacyImport(t *testing.T) {
@@ -20,6 +22,8 @@ func TestOrderLegacyImport(t *testing.T) {
 		name           string
 		inputFile      func() io.Reader
 		inputHasHeader bool
+		inputMode      string
+		inputLenient   bool
 		wantResult     *model.LegacyImportResult
 		wantError      func() error
 		mockOn         func(*mock_repository.MockRepository, *mock_cache.MockCache)
@@ -39,15 +43,19 @@ func TestOrderLegacyImport(t *testing.T) {
 			inputHasHeader: false,
 			wantResult:     nil,
 			wantError: func() error {
-				jsonBytes, _ := json.Marshal(model.LegacyRecordsError{
-					{
-						Line:    1,
-						Message: OrderErrorMessageRecordSize,
+				return ErrRecordValidate{
+					Message: OrderErrorMessageRecordValidate,
+					RecordsError: model.LegacyRecordsError{
+						{
+							Line:    1,
+							Message: fmt.Sprintf(OrderErrorMessageRecordSize, 95),
+							Errors: []model.LegacyFieldError{
+								{Code: OrderErrorCodeRecordSize, Message: fmt.Sprintf(OrderErrorMessageRecordSize, 95)},
+							},
+						},
 					},
-				})
-
-				err := ErrRecordValidate{Message: string(jsonBytes)}
-				return err
+					Total: 1,
+				}
 			},
 			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
 				return
@@ -66,15 +74,19 @@ func TestOrderLegacyImport(t *testing.T) {
 			inputHasHeader: false,
 			wantResult:     nil,
 			wantError: func() error {
-				jsonBytes, _ := json.Marshal(model.LegacyRecordsError{
-					{
-						Line:    1,
-						Message: OrderErrorMessageUserIDInvalid,
+				return ErrRecordValidate{
+					Message: OrderErrorMessageRecordValidate,
+					RecordsError: model.LegacyRecordsError{
+						{
+							Line:    1,
+							Message: OrderErrorMessageUserIDInvalid,
+							Errors: []model.LegacyFieldError{
+								{Code: OrderErrorCodeUserIDInvalid, Field: model.LegacyFieldUserID, ColumnStart: 1, ColumnEnd: 10, Value: "000000007x", Message: OrderErrorMessageUserIDInvalid},
+							},
+						},
 					},
-				})
-
-				err := ErrRecordValidate{Message: string(jsonBytes)}
-				return err
+					Total: 1,
+				}
 			},
 			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
 				return
@@ -93,15 +105,19 @@ func TestOrderLegacyImport(t *testing.T) {
 			inputHasHeader: false,
 			wantResult:     nil,
 			wantError: func() error {
-				jsonBytes, _ := json.Marshal(model.LegacyRecordsError{
-					{
-						Line:    1,
-						Message: OrderErrorMessageUserNameInvalid,
+				return ErrRecordValidate{
+					Message: OrderErrorMessageRecordValidate,
+					RecordsError: model.LegacyRecordsError{
+						{
+							Line:    1,
+							Message: OrderErrorMessageUserNameInvalid,
+							Errors: []model.LegacyFieldError{
+								{Code: OrderErrorCodeUserNameInvalid, Field: model.LegacyFieldUserName, ColumnStart: 11, ColumnEnd: 55, Value: "o", Rule: model.ValidationRuleUserNameLength, Message: OrderErrorMessageUserNameInvalid},
+							},
+						},
 					},
-				})
-
-				err := ErrRecordValidate{Message: string(jsonBytes)}
-				return err
+					Total: 1,
+				}
 			},
 			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
 				return
@@ -120,15 +136,19 @@ func TestOrderLegacyImport(t *testing.T) {
 			inputHasHeader: false,
 			wantResult:     nil,
 			wantError: func() error {
-				jsonBytes, _ := json.Marshal(model.LegacyRecordsError{
-					{
-						Line:    1,
-						Message: OrderErrorMessageOrderIDInvalid,
+				return ErrRecordValidate{
+					Message: OrderErrorMessageRecordValidate,
+					RecordsError: model.LegacyRecordsError{
+						{
+							Line:    1,
+							Message: OrderErrorMessageOrderIDInvalid,
+							Errors: []model.LegacyFieldError{
+								{Code: OrderErrorCodeOrderIDInvalid, Field: model.LegacyFieldOrderID, ColumnStart: 56, ColumnEnd: 65, Value: "000000075x", Message: OrderErrorMessageOrderIDInvalid},
+							},
+						},
 					},
-				})
-
-				err := ErrRecordValidate{Message: string(jsonBytes)}
-				return err
+					Total: 1,
+				}
 			},
 			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
 				return
@@ -147,15 +167,19 @@ func TestOrderLegacyImport(t *testing.T) {
 			inputHasHeader: false,
 			wantResult:     nil,
 			wantError: func() error {
-				jsonBytes, _ := json.Marshal(model.LegacyRecordsError{
-					{
-						Line:    1,
-						Message: OrderErrorMessageProductIDInvalid,
+				return ErrRecordValidate{
+					Message: OrderErrorMessageRecordValidate,
+					RecordsError: model.LegacyRecordsError{
+						{
+							Line:    1,
+							Message: OrderErrorMessageProductIDInvalid,
+							Errors: []model.LegacyFieldError{
+								{Code: OrderErrorCodeProductIDInvalid, Field: model.LegacyFieldProductID, ColumnStart: 66, ColumnEnd: 75, Value: "000000000x", Message: OrderErrorMessageProductIDInvalid},
+							},
+						},
 					},
-				})
-
-				err := ErrRecordValidate{Message: string(jsonBytes)}
-				return err
+					Total: 1,
+				}
 			},
 			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
 				return
@@ -174,15 +198,19 @@ func TestOrderLegacyImport(t *testing.T) {
 			inputHasHeader: false,
 			wantResult:     nil,
 			wantError: func() error {
-				jsonBytes, _ := json.Marshal(model.LegacyRecordsError{
-					{
-						Line:    1,
-						Message: OrderErrorMessageProductValueInvalid,
+				return ErrRecordValidate{
+					Message: OrderErrorMessageRecordValidate,
+					RecordsError: model.LegacyRecordsError{
+						{
+							Line:    1,
+							Message: OrderErrorMessageProductValueInvalid,
+							Errors: []model.LegacyFieldError{
+								{Code: OrderErrorCodeProductValueInvalid, Field: model.LegacyFieldProductValue, ColumnStart: 76, ColumnEnd: 87, Value: "1836.7x", Message: OrderErrorMessageProductValueInvalid},
+							},
+						},
 					},
-				})
-
-				err := ErrRecordValidate{Message: string(jsonBytes)}
-				return err
+					Total: 1,
+				}
 			},
 			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
 				return
@@ -201,15 +229,19 @@ func TestOrderLegacyImport(t *testing.T) {
 			inputHasHeader: false,
 			wantResult:     nil,
 			wantError: func() error {
-				jsonBytes, _ := json.Marshal(model.LegacyRecordsError{
-					{
-						Line:    1,
-						Message: OrderErrorMessageBuyDateInvalid,
+				return ErrRecordValidate{
+					Message: OrderErrorMessageRecordValidate,
+					RecordsError: model.LegacyRecordsError{
+						{
+							Line:    1,
+							Message: OrderErrorMessageBuyDateInvalid,
+							Errors: []model.LegacyFieldError{
+								{Code: OrderErrorCodeBuyDateInvalid, Field: model.LegacyFieldBuyDate, ColumnStart: 88, ColumnEnd: 95, Value: "20211308", Message: OrderErrorMessageBuyDateInvalid},
+							},
+						},
 					},
-				})
-
-				err := ErrRecordValidate{Message: string(jsonBytes)}
-				return err
+					Total: 1,
+				}
 			},
 			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
 				return
@@ -228,15 +260,21 @@ func TestOrderLegacyImport(t *testing.T) {
 			inputHasHeader: false,
 			wantResult:     nil,
 			wantError: func() error {
-				jsonBytes, _ := json.Marshal(model.LegacyRecordsError{
-					{
-						Line:    1,
-						Message: OrderErrorMessageBuyDateBetween,
-					},
-				})
+				message := newOrderValidationPolicy(model.ValidationPolicy{}).at(time.Now().UTC()).buyDateMessage(OrderErrorMessageBuyDateBetween)
 
-				err := ErrRecordValidate{Message: string(jsonBytes)}
-				return err
+				return ErrRecordValidate{
+					Message: OrderErrorMessageRecordValidate,
+					RecordsError: model.LegacyRecordsError{
+						{
+							Line:    1,
+							Message: message,
+							Errors: []model.LegacyFieldError{
+								{Code: OrderErrorCodeBuyDateBetween, Field: model.LegacyFieldBuyDate, ColumnStart: 88, ColumnEnd: 95, Value: "18990308", Rule: model.ValidationRuleBuyDateWindow, Message: message},
+							},
+						},
+					},
+					Total: 1,
+				}
 			},
 			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
 				return
@@ -270,13 +308,14 @@ func TestOrderLegacyImport(t *testing.T) {
 
 				return bytes.NewBufferString(strings.Join(lines, "\n"))
 			},
-			inputHasHeader: true,
+			inputHasHeader: false,
 			wantResult:     nil,
 			wantError: func() error {
 				return errors.New("LegacyBulkInsert Error")
 			},
 			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
 				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("ListLegacyImports").Return(nil, repository.ErrNotFound{Message: "not found"})
 				mockRepositoryOrder.On("LegacyBulkInsert").Return(errors.New("LegacyBulkInsert Error"))
 				mockRepository.On("Order").Return(mockRepositoryOrder)
 
@@ -318,27 +357,36 @@ func TestOrderLegacyImport(t *testing.T) {
 			inputHasHeader: false,
 			wantResult:     nil,
 			wantError: func() error {
-				jsonBytes, _ := json.Marshal(model.LegacyRecordsError{
-					{
-						Line: 1,
-						Message: strings.Join(
-							[]string{
-								OrderErrorMessageUserIDInvalid,
-								OrderErrorMessageOrderIDInvalid,
-								OrderErrorMessageProductIDInvalid,
-								OrderErrorMessageProductValueInvalid,
-								OrderErrorMessageBuyDateInvalid,
+				return ErrRecordValidate{
+					Message: OrderErrorMessageRecordValidate,
+					RecordsError: model.LegacyRecordsError{
+						{
+							Line: 1,
+							Message: strings.Join(
+								[]string{
+									OrderErrorMessageUserIDInvalid,
+									OrderErrorMessageOrderIDInvalid,
+									OrderErrorMessageProductIDInvalid,
+									OrderErrorMessageProductValueInvalid,
+									OrderErrorMessageBuyDateInvalid,
+								},
+								";",
+							),
+							Errors: []model.LegacyFieldError{
+								{Code: OrderErrorCodeUserIDInvalid, Field: model.LegacyFieldUserID, ColumnStart: 1, ColumnEnd: 10, Value: "    USERID", Message: OrderErrorMessageUserIDInvalid},
+								{Code: OrderErrorCodeOrderIDInvalid, Field: model.LegacyFieldOrderID, ColumnStart: 56, ColumnEnd: 65, Value: "   ORDERID", Message: OrderErrorMessageOrderIDInvalid},
+								{Code: OrderErrorCodeProductIDInvalid, Field: model.LegacyFieldProductID, ColumnStart: 66, ColumnEnd: 75, Value: " PRODUCTID", Message: OrderErrorMessageProductIDInvalid},
+								{Code: OrderErrorCodeProductValueInvalid, Field: model.LegacyFieldProductValue, ColumnStart: 76, ColumnEnd: 87, Value: "PRODUCTVALUE", Message: OrderErrorMessageProductValueInvalid},
+								{Code: OrderErrorCodeBuyDateInvalid, Field: model.LegacyFieldBuyDate, ColumnStart: 88, ColumnEnd: 95, Value: " BUYDATE", Message: OrderErrorMessageBuyDateInvalid},
 							},
-							";",
-						),
+						},
 					},
-				})
-
-				err := ErrRecordValidate{Message: string(jsonBytes)}
-				return err
+					Total: 5,
+				}
 			},
 			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
 				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("ListLegacyImports").Return(nil, repository.ErrNotFound{Message: "not found"})
 				mockRepositoryOrder.On("LegacyBulkInsert").Return(nil)
 				mockRepository.On("Order").Return(mockRepositoryOrder)
 
@@ -351,7 +399,7 @@ func TestOrderLegacyImport(t *testing.T) {
 			name: "HasHeaderSuccess",
 			inputFile: func() io.Reader {
 				lines := []string{
-					"    USERID                                     USERNAME   ORDERID PRODUCTIDPRODUCTVALUE BUYDATE",
+					"20230611LEGACY ORDERS                                                                          ",
 					"0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308",
 					"0000000075                                  Bobbie Batz00000007980000000002     1578.5720211116",
 					"0000000049                               Ken Wintheiser00000005230000000003      586.7420210903",
@@ -382,12 +430,14 @@ func TestOrderLegacyImport(t *testing.T) {
 				Users:    17,
 				Orders:   20,
 				Products: 21,
+				Accepted: 21,
 			},
 			wantError: func() error {
 				return nil
 			},
 			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
 				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("ListLegacyImports").Return(nil, repository.ErrNotFound{Message: "not found"})
 				mockRepositoryOrder.On("LegacyBulkInsert").Return(nil)
 				mockRepository.On("Order").Return(mockRepositoryOrder)
 
@@ -430,12 +480,14 @@ func TestOrderLegacyImport(t *testing.T) {
 				Users:    17,
 				Orders:   20,
 				Products: 21,
+				Accepted: 21,
 			},
 			wantError: func() error {
 				return nil
 			},
 			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
 				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("ListLegacyImports").Return(nil, repository.ErrNotFound{Message: "not found"})
 				mockRepositoryOrder.On("LegacyBulkInsert").Return(nil)
 				mockRepository.On("Order").Return(mockRepositoryOrder)
 
@@ -444,6 +496,250 @@ func TestOrderLegacyImport(t *testing.T) {
 				mockCache.On("Order").Return(mockCacheOrder)
 			},
 		},
+		{
+			name: "ModeInvalidError",
+			inputFile: func() io.Reader {
+				lines := []string{
+					"0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308",
+				}
+
+				return bytes.NewBufferString(strings.Join(lines, "\n"))
+			},
+			inputHasHeader: false,
+			inputMode:      "append",
+			wantResult:     nil,
+			wantError: func() error {
+				return ErrParamValidate{Message: OrderErrorMessageModeInvalid}
+			},
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+				return
+			},
+		},
+		{
+			name: "MergeRepositoryError",
+			inputFile: func() io.Reader {
+				lines := []string{
+					"0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308",
+					"0000000075                                  Bobbie Batz00000007980000000002     1578.5720211116",
+				}
+
+				return bytes.NewBufferString(strings.Join(lines, "\n"))
+			},
+			inputHasHeader: false,
+			inputMode:      model.LegacyImportModeMerge,
+			wantResult:     nil,
+			wantError: func() error {
+				return errors.New("LegacyBulkUpsert Error")
+			},
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("ListLegacyImports").Return(nil, repository.ErrNotFound{Message: "not found"})
+				mockRepositoryOrder.On("LegacyBulkUpsert").Return(nil, errors.New("LegacyBulkUpsert Error"))
+				mockRepository.On("Order").Return(mockRepositoryOrder)
+			},
+		},
+		{
+			name: "MergeSuccess",
+			inputFile: func() io.Reader {
+				lines := []string{
+					"0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308",
+					"0000000075                                  Bobbie Batz00000007980000000002     1578.5720211116",
+					"0000000070                              Palmer Prosacco00000007530000000003     1009.5420210308",
+				}
+
+				return bytes.NewBufferString(strings.Join(lines, "\n"))
+			},
+			inputHasHeader: false,
+			inputMode:      model.LegacyImportModeMerge,
+			wantResult: &model.LegacyImportResult{
+				Users:    2,
+				Orders:   2,
+				Products: 3,
+				Accepted: 3,
+			},
+			wantError: func() error {
+				return nil
+			},
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("ListLegacyImports").Return(nil, repository.ErrNotFound{Message: "not found"})
+				mockRepositoryOrder.On("LegacyBulkUpsert").Return([]int64{753, 798, 812}, nil)
+				mockRepository.On("Order").Return(mockRepositoryOrder)
+
+				mockCacheOrder := new(mock_cache.MockCacheOrder)
+				mockCacheOrder.On("DelDetailsByOrderID").Return(nil).Times(3)
+				mockCache.On("Order").Return(mockCacheOrder)
+			},
+		},
+		{
+			name: "MergeCacheError",
+			inputFile: func() io.Reader {
+				lines := []string{
+					"0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308",
+					"0000000075                                  Bobbie Batz00000007980000000002     1578.5720211116",
+					"0000000070                              Palmer Prosacco00000007530000000003     1009.5420210308",
+				}
+
+				return bytes.NewBufferString(strings.Join(lines, "\n"))
+			},
+			inputHasHeader: false,
+			inputMode:      model.LegacyImportModeMerge,
+			wantResult: &model.LegacyImportResult{
+				Users:    2,
+				Orders:   2,
+				Products: 3,
+				Accepted: 3,
+			},
+			wantError: func() error {
+				return nil
+			},
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("ListLegacyImports").Return(nil, repository.ErrNotFound{Message: "not found"})
+				mockRepositoryOrder.On("LegacyBulkUpsert").Return([]int64{753, 798, 812}, nil)
+				mockRepository.On("Order").Return(mockRepositoryOrder)
+
+				mockCacheOrder := new(mock_cache.MockCacheOrder)
+				mockCacheOrder.On("DelDetailsByOrderID").Return(errors.New("Cache Error")).Once()
+				mockCacheOrder.On("ClearAll").Return(nil).Once()
+				mockCache.On("Order").Return(mockCacheOrder)
+			},
+		},
+		{
+			name: "LenientNoValidRecordError",
+			inputFile: func() io.Reader {
+				lines := []string{
+					"000000007x                              Palmer Prosacco00000007530000000003     1836.7420210308",
+				}
+
+				return bytes.NewBufferString(strings.Join(lines, "\n"))
+			},
+			inputHasHeader: false,
+			inputLenient:   true,
+			wantResult:     nil,
+			wantError: func() error {
+				return ErrRecordValidate{
+					Message: OrderErrorMessageRecordValidate,
+					RecordsError: model.LegacyRecordsError{
+						{
+							Line:    1,
+							Message: OrderErrorMessageUserIDInvalid,
+							Errors: []model.LegacyFieldError{
+								{Code: OrderErrorCodeUserIDInvalid, Field: model.LegacyFieldUserID, ColumnStart: 1, ColumnEnd: 10, Value: "000000007x", Message: OrderErrorMessageUserIDInvalid},
+							},
+						},
+					},
+					Total: 1,
+				}
+			},
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+				return
+			},
+		},
+		{
+			// the rejects are persisted with the dataset
+			name: "LenientRepositoryError",
+			inputFile: func() io.Reader {
+				lines := []string{
+					"0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308",
+					"000000007x                              Palmer Prosacco00000007530000000003     1836.7420210308",
+				}
+
+				return bytes.NewBufferString(strings.Join(lines, "\n"))
+			},
+			inputHasHeader: false,
+			inputLenient:   true,
+			wantResult:     nil,
+			wantError: func() error {
+				return errors.New("LegacyBulkInsert Error")
+			},
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("ListLegacyImports").Return(nil, repository.ErrNotFound{Message: "not found"})
+				mockRepositoryOrder.On("LegacyBulkInsert").Return(errors.New("LegacyBulkInsert Error"))
+				mockRepository.On("Order").Return(mockRepositoryOrder)
+
+				mockCacheOrder := new(mock_cache.MockCacheOrder)
+				mockCacheOrder.On("ClearAll").Return(nil)
+				mockCache.On("Order").Return(mockCacheOrder)
+			},
+		},
+		{
+			name: "LenientSuccess",
+			inputFile: func() io.Reader {
+				lines := []string{
+					"0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308",
+					"000000007x                              Palmer Prosacco00000007530000000003     1836.7420210308",
+					"0000000075                                  Bobbie Batz00000007980000000002     1578.5720211116",
+				}
+
+				return bytes.NewBufferString(strings.Join(lines, "\n"))
+			},
+			inputHasHeader: false,
+			inputLenient:   true,
+			wantResult: &model.LegacyImportResult{
+				Users:    2,
+				Orders:   2,
+				Products: 2,
+				Accepted: 2,
+				Rejected: 1,
+			},
+			wantError: func() error {
+				return nil
+			},
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("ListLegacyImports").Return(nil, repository.ErrNotFound{Message: "not found"})
+				mockRepositoryOrder.On("LegacyBulkInsert").Return(nil)
+				mockRepository.On("Order").Return(mockRepositoryOrder)
+
+				mockCacheOrder := new(mock_cache.MockCacheOrder)
+				mockCacheOrder.On("ClearAll").Return(nil)
+				mockCache.On("Order").Return(mockCacheOrder)
+			},
+		},
+		{
+			name: "OrderUserDivergentError",
+			inputFile: func() io.Reader {
+				lines := []string{
+					"0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308",
+					"0000000075                                  Bobbie Batz00000007530000000002     1578.5720211116",
+				}
+
+				return bytes.NewBufferString(strings.Join(lines, "\n"))
+			},
+			inputHasHeader: false,
+			wantResult:     nil,
+			wantError: func() error {
+				lines := []model.LegacyRecordLine{{Line: 1}, {Line: 2}}
+
+				return ErrRecordValidate{
+					Message: OrderErrorMessageRecordValidate,
+					RecordsError: model.LegacyRecordsError{
+						{
+							Line:    1,
+							Message: OrderErrorMessageOrderUserDivergent,
+							Lines:   lines,
+							Errors: []model.LegacyFieldError{
+								{Code: OrderErrorCodeOrderUserDivergent, Field: model.LegacyFieldUserID, ColumnStart: 1, ColumnEnd: 10, Value: "70", Message: OrderErrorMessageOrderUserDivergent},
+							},
+						},
+						{
+							Line:    2,
+							Message: OrderErrorMessageOrderUserDivergent,
+							Lines:   lines,
+							Errors: []model.LegacyFieldError{
+								{Code: OrderErrorCodeOrderUserDivergent, Field: model.LegacyFieldUserID, ColumnStart: 1, ColumnEnd: 10, Value: "75", Message: OrderErrorMessageOrderUserDivergent},
+							},
+						},
+					},
+					Total: 2,
+				}
+			},
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+				return
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -456,9 +752,9 @@ func TestOrderLegacyImport(t *testing.T) {
 
 			tt.mockOn(mockRepository, mockCache)
 
-			usecaseOrder := NewOrder(mockRepository, mockCache)
+			usecaseOrder := NewOrder(mockRepository, mockCache, &util.Config{})
 
-			modelLegacyImportResult, err := usecaseOrder.LegacyImport(inputFile, tt.inputHasHeader)
+			modelLegacyImportResult, err := usecaseOrder.LegacyImport(inputFile, &model.LegacyImportOptions{HasHeader: tt.inputHasHeader, Mode: tt.inputMode, Lenient: tt.inputLenient})
 
 			if !reflect.DeepEqual(err, wantError) {
 				t.Errorf("Insert() got error = %v, want = %v.", err, wantError)
@@ -471,6 +767,336 @@ func TestOrderLegacyImport(t *testing.T) {
 	}
 }
 
+func TestOrderLegacyValidate(t *testing.T) {
+	config := &util.Config{
+		LegacyLayouts: model.LegacyLayouts{
+			{
+				Name: "compact",
+				Size: 74,
+				Fields: []model.LegacyLayoutField{
+					{Name: model.LegacyFieldUserID, Start: 1, Length: 8, Type: model.LegacyFieldTypeInt},
+					{Name: model.LegacyFieldOrderID, Start: 9, Length: 8, Type: model.LegacyFieldTypeInt},
+					{Name: model.LegacyFieldProductID, Start: 17, Length: 8, Type: model.LegacyFieldTypeInt},
+					{Name: model.LegacyFieldProductValue, Start: 25, Length: 10, Type: model.LegacyFieldTypeDecimal, Trim: model.LegacyFieldTrimLeft, Pad: "0", Decimals: 2},
+					{Name: model.LegacyFieldBuyDate, Start: 35, Length: 10, Type: model.LegacyFieldTypeDate, Format: "02/01/2006"},
+					{Name: model.LegacyFieldUserName, Start: 45, Length: 30, Type: model.LegacyFieldTypeString, Trim: model.LegacyFieldTrimRight},
+				},
+			},
+		},
+	}
+
+	type test struct {
+		name         string
+		inputFile    func() io.Reader
+		inputMode    string
+		inputLayout  string
+		inputLenient bool
+		wantResult   *model.LegacyValidateResult
+		wantError    error
+	}
+
+	tests := []test{
+		{
+			name: "ModeInvalidError",
+			inputFile: func() io.Reader {
+				return bytes.NewBufferString("")
+			},
+			inputMode:  "append",
+			wantResult: nil,
+			wantError:  ErrParamValidate{Message: OrderErrorMessageModeInvalid},
+		},
+		{
+			name: "LayoutInvalidError",
+			inputFile: func() io.Reader {
+				return bytes.NewBufferString("")
+			},
+			inputLayout: "unknown",
+			wantResult:  nil,
+			wantError:   ErrParamValidate{Message: OrderErrorMessageLayoutInvalid},
+		},
+		{
+			name: "LayoutInvalid",
+			inputFile: func() io.Reader {
+				lines := []string{
+					"000000700000075300000003000018367408/03/2021Palmer Prosacco               ",
+					"0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308",
+					"000000700000075300000004000001x00008/03/2021Palmer Prosacco               ",
+				}
+
+				return bytes.NewBufferString(strings.Join(lines, "\n"))
+			},
+			inputLayout: "compact",
+			wantResult: &model.LegacyValidateResult{
+				Valid: false,
+				Result: model.LegacyImportResult{
+					Users:    1,
+					Orders:   1,
+					Products: 1,
+					Accepted: 1,
+					Rejected: 2,
+				},
+				Errors: model.LegacyRecordsError{
+					{
+						Line:    2,
+						Message: fmt.Sprintf(OrderErrorMessageRecordSize, 74),
+						Errors: []model.LegacyFieldError{
+							{Code: OrderErrorCodeRecordSize, Message: fmt.Sprintf(OrderErrorMessageRecordSize, 74)},
+						},
+					},
+					{
+						Line:    3,
+						Message: OrderErrorMessageProductValueInvalid,
+						Errors: []model.LegacyFieldError{
+							{Code: OrderErrorCodeProductValueInvalid, Field: model.LegacyFieldProductValue, ColumnStart: 25, ColumnEnd: 34, Value: "1x000", Message: OrderErrorMessageProductValueInvalid},
+						},
+					},
+				},
+				ErrorsTotal: 2,
+			},
+			wantError: nil,
+		},
+		{
+			name: "LayoutValid",
+			inputFile: func() io.Reader {
+				lines := []string{
+					"000000700000075300000003000018367408/03/2021Palmer Prosacco               ",
+					"000000700000075300000004000000000008/03/2021Palmer Prosacco               ",
+					"000000750000079800000002000015785716/11/2021  Bobbie Batz                 ",
+				}
+
+				return bytes.NewBufferString(strings.Join(lines, "\n"))
+			},
+			inputLayout: "compact",
+			wantResult: &model.LegacyValidateResult{
+				Valid: true,
+				Result: model.LegacyImportResult{
+					Users:    2,
+					Orders:   2,
+					Products: 3,
+					Accepted: 3,
+					Rejected: 0,
+				},
+				Errors: model.LegacyRecordsError{},
+			},
+			wantError: nil,
+		},
+		{
+			name: "Invalid",
+			inputFile: func() io.Reader {
+				lines := []string{
+					"0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308",
+					"000000007x                              Palmer Prosacco00000007530000000003     1836.7420210308",
+					"0000000075                                  Bobbie Batz00000007530000000002     1578.5720211116",
+					"0000000075                                  Bobbie Batz00000007980000000002     1578.5720211116",
+				}
+
+				return bytes.NewBufferString(strings.Join(lines, "\n"))
+			},
+			wantResult: &model.LegacyValidateResult{
+				Valid: false,
+				Result: model.LegacyImportResult{
+					Users:    1,
+					Orders:   1,
+					Products: 1,
+					Accepted: 1,
+					Rejected: 3,
+				},
+				Errors: model.LegacyRecordsError{
+					{
+						Line:    1,
+						Message: OrderErrorMessageOrderUserDivergent,
+						Lines:   []model.LegacyRecordLine{{Line: 1}, {Line: 3}},
+						Errors: []model.LegacyFieldError{
+							{Code: OrderErrorCodeOrderUserDivergent, Field: model.LegacyFieldUserID, ColumnStart: 1, ColumnEnd: 10, Value: "70", Message: OrderErrorMessageOrderUserDivergent},
+						},
+					},
+					{
+						Line:    2,
+						Message: OrderErrorMessageUserIDInvalid,
+						Errors: []model.LegacyFieldError{
+							{Code: OrderErrorCodeUserIDInvalid, Field: model.LegacyFieldUserID, ColumnStart: 1, ColumnEnd: 10, Value: "000000007x", Message: OrderErrorMessageUserIDInvalid},
+						},
+					},
+					{
+						Line:    3,
+						Message: OrderErrorMessageOrderUserDivergent,
+						Lines:   []model.LegacyRecordLine{{Line: 1}, {Line: 3}},
+						Errors: []model.LegacyFieldError{
+							{Code: OrderErrorCodeOrderUserDivergent, Field: model.LegacyFieldUserID, ColumnStart: 1, ColumnEnd: 10, Value: "75", Message: OrderErrorMessageOrderUserDivergent},
+						},
+					},
+				},
+				ErrorsTotal: 3,
+			},
+			wantError: nil,
+		},
+		{
+			name: "UserNameDivergent",
+			inputFile: func() io.Reader {
+				lines := []string{
+					"0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308",
+					"0000000070                                  Bobbie Batz00000007980000000002     1578.5720211116",
+					"0000000075                                  Bobbie Batz00000007990000000002     1578.5720211116",
+				}
+
+				return bytes.NewBufferString(strings.Join(lines, "\n"))
+			},
+			wantResult: &model.LegacyValidateResult{
+				Valid: false,
+				Result: model.LegacyImportResult{
+					Users:    1,
+					Orders:   1,
+					Products: 1,
+					Accepted: 1,
+					Rejected: 2,
+				},
+				Errors: model.LegacyRecordsError{
+					{
+						Line:    1,
+						Message: OrderErrorMessageUserNameDivergent,
+						Lines:   []model.LegacyRecordLine{{Line: 1}, {Line: 2}},
+						Errors: []model.LegacyFieldError{
+							{Code: OrderErrorCodeUserNameDivergent, Field: model.LegacyFieldUserName, ColumnStart: 11, ColumnEnd: 55, Value: "Palmer Prosacco", Message: OrderErrorMessageUserNameDivergent},
+						},
+					},
+					{
+						Line:    2,
+						Message: OrderErrorMessageUserNameDivergent,
+						Lines:   []model.LegacyRecordLine{{Line: 1}, {Line: 2}},
+						Errors: []model.LegacyFieldError{
+							{Code: OrderErrorCodeUserNameDivergent, Field: model.LegacyFieldUserName, ColumnStart: 11, ColumnEnd: 55, Value: "Bobbie Batz", Message: OrderErrorMessageUserNameDivergent},
+						},
+					},
+				},
+				ErrorsTotal: 2,
+			},
+			wantError: nil,
+		},
+		{
+			name: "OrderProductDuplicate",
+			inputFile: func() io.Reader {
+				lines := []string{
+					"0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308",
+					"0000000070                              Palmer Prosacco00000007530000000003     1000.0020210308",
+					"0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308",
+					"0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308",
+				}
+
+				return bytes.NewBufferString(strings.Join(lines, "\n"))
+			},
+			inputLenient: true,
+			wantResult: &model.LegacyValidateResult{
+				Valid: true,
+				Result: model.LegacyImportResult{
+					Users:    1,
+					Orders:   1,
+					Products: 2,
+					Accepted: 2,
+					Rejected: 2,
+				},
+				Errors: model.LegacyRecordsError{
+					{
+						Line:    3,
+						Message: OrderErrorMessageOrderProductDuplicate,
+						Lines:   []model.LegacyRecordLine{{Line: 1}, {Line: 3}, {Line: 4}},
+						Errors: []model.LegacyFieldError{
+							{Code: OrderErrorCodeOrderProductDuplicate, Message: OrderErrorMessageOrderProductDuplicate},
+						},
+					},
+					{
+						Line:    4,
+						Message: OrderErrorMessageOrderProductDuplicate,
+						Lines:   []model.LegacyRecordLine{{Line: 1}, {Line: 3}, {Line: 4}},
+						Errors: []model.LegacyFieldError{
+							{Code: OrderErrorCodeOrderProductDuplicate, Message: OrderErrorMessageOrderProductDuplicate},
+						},
+					},
+				},
+				ErrorsTotal: 2,
+			},
+			wantError: nil,
+		},
+		{
+			name: "LenientValid",
+			inputFile: func() io.Reader {
+				lines := []string{
+					"0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308",
+					"000000007x                              Palmer Prosacco00000007530000000003     1836.7420210308",
+				}
+
+				return bytes.NewBufferString(strings.Join(lines, "\n"))
+			},
+			inputLenient: true,
+			wantResult: &model.LegacyValidateResult{
+				Valid: true,
+				Result: model.LegacyImportResult{
+					Users:    1,
+					Orders:   1,
+					Products: 1,
+					Accepted: 1,
+					Rejected: 1,
+				},
+				Errors: model.LegacyRecordsError{
+					{
+						Line:    2,
+						Message: OrderErrorMessageUserIDInvalid,
+						Errors: []model.LegacyFieldError{
+							{Code: OrderErrorCodeUserIDInvalid, Field: model.LegacyFieldUserID, ColumnStart: 1, ColumnEnd: 10, Value: "000000007x", Message: OrderErrorMessageUserIDInvalid},
+						},
+					},
+				},
+				ErrorsTotal: 1,
+			},
+			wantError: nil,
+		},
+		{
+			name: "Valid",
+			inputFile: func() io.Reader {
+				lines := []string{
+					"0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308",
+					"0000000070                              Palmer Prosacco00000007530000000004      100.0020210308",
+					"0000000075                                  Bobbie Batz00000007980000000002     1578.5720211116",
+				}
+
+				return bytes.NewBufferString(strings.Join(lines, "\n"))
+			},
+			inputMode: model.LegacyImportModeMerge,
+			wantResult: &model.LegacyValidateResult{
+				Valid: true,
+				Result: model.LegacyImportResult{
+					Users:    2,
+					Orders:   2,
+					Products: 3,
+					Accepted: 3,
+					Rejected: 0,
+				},
+				Errors: model.LegacyRecordsError{},
+			},
+			wantError: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the validation must not touch the repository or the cache, any call fails the test
+			mockRepository := new(mock_repository.MockRepository)
+			mockCache := new(mock_cache.MockCache)
+
+			usecaseOrder := NewOrder(mockRepository, mockCache, config)
+
+			modelLegacyValidateResult, err := usecaseOrder.LegacyValidate(tt.inputFile(), &model.LegacyImportOptions{Mode: tt.inputMode, Layout: tt.inputLayout, Lenient: tt.inputLenient})
+
+			if !reflect.DeepEqual(err, tt.wantError) {
+				t.Errorf("LegacyValidate() got error = %v, want = %v.", err, tt.wantError)
+			}
+
+			if !reflect.DeepEqual(modelLegacyValidateResult, tt.wantResult) {
+				t.Errorf("LegacyValidate() got result = %v, want = %v.", modelLegacyValidateResult, tt.wantResult)
+			}
+		})
+	}
+}
+
 func TestOrderGetDetailsByOrderID(t *testing.T) {
 	modelOrderDetails := model.OrderDetails{
 		UserID:   70,
@@ -479,11 +1105,11 @@ func TestOrderGetDetailsByOrderID(t *testing.T) {
 			{
 				OrderID: 753,
 				BuyDate: "2021-03-08",
-				Total:   1836.74,
+				Total:   183674,
 				Products: []model.OrderDetailsProduct{
 					{
 						ID:    3,
-						Value: 1836.74,
+						Value: 183674,
 					},
 				},
 			},
@@ -554,7 +1180,7 @@ func TestOrderGetDetailsByOrderID(t *testing.T) {
 
 			tt.mockOn(mockRepository, mockCache)
 
-			usecaseOrder := NewOrder(mockRepository, mockCache)
+			usecaseOrder := NewOrder(mockRepository, mockCache, &util.Config{})
 
 			modelLegacyImportResult, err := usecaseOrder.GetDetailsByOrderID(tt.inputOrderId)
 
@@ -578,11 +1204,11 @@ func TestOrderListDetails(t *testing.T) {
 				{
 					OrderID: 753,
 					BuyDate: "2021-03-08",
-					Total:   1836.74,
+					Total:   183674,
 					Products: []model.OrderDetailsProduct{
 						{
 							ID:    3,
-							Value: 1836.74,
+							Value: 183674,
 						},
 					},
 				},
@@ -590,16 +1216,32 @@ func TestOrderListDetails(t *testing.T) {
 		},
 	}
 
+	modelOrdersDetailsPage := model.OrdersDetailsPage{
+		OrdersDetails: modelOrdersDetails,
+		Next:          &model.OrderCursor{UserID: 70, OrderID: 753},
+	}
+
 	type test struct {
 		name       string
-		wantResult *model.OrdersDetails
+		inputPage  *model.OrderPage
+		wantResult *model.OrdersDetailsPage
 		wantError  error
 		mockOn     func(*mock_repository.MockRepository, *mock_cache.MockCache)
 	}
 
 	tests := []test{
+		{
+			name:       "ParamLimitBetweenError",
+			inputPage:  &model.OrderPage{Limit: 101},
+			wantResult: nil,
+			wantError:  ErrParamValidate{Message: fmt.Sprintf(OrderPageErrorMessageLimitBetween, 100)},
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+				return
+			},
+		},
 		{
 			name:       "RepositoryError",
+			inputPage:  &model.OrderPage{},
 			wantResult: nil,
 			wantError:  errors.New("Repository Error"),
 			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
@@ -610,11 +1252,12 @@ func TestOrderListDetails(t *testing.T) {
 		},
 		{
 			name:       "Success",
-			wantResult: &modelOrdersDetails,
+			inputPage:  &model.OrderPage{Limit: 1},
+			wantResult: &modelOrdersDetailsPage,
 			wantError:  nil,
 			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
 				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
-				mockRepositoryOrder.On("ListDetails").Return(&modelOrdersDetails, nil)
+				mockRepositoryOrder.On("ListDetails").Return(&modelOrdersDetailsPage, nil)
 				mockRepository.On("Order").Return(mockRepositoryOrder)
 			},
 		},
@@ -627,9 +1270,9 @@ func TestOrderListDetails(t *testing.T) {
 
 			tt.mockOn(mockRepository, mockCache)
 
-			usecaseOrder := NewOrder(mockRepository, mockCache)
+			usecaseOrder := NewOrder(mockRepository, mockCache, &util.Config{OrderPageMaxSize: 100})
 
-			modelLegacyImportResult, err := usecaseOrder.ListDetails()
+			modelLegacyImportResult, err := usecaseOrder.ListDetails(&model.OrderFilter{}, tt.inputPage)
 
 			if !reflect.DeepEqual(err, tt.wantError) {
 				t.Errorf("Insert() got error = %v, want = %v.", err, tt.wantError)
@@ -651,11 +1294,11 @@ func TestOrderListDetailsByRangeBuyDate(t *testing.T) {
 				{
 					OrderID: 753,
 					BuyDate: "2021-03-08",
-					Total:   1836.74,
+					Total:   183674,
 					Products: []model.OrderDetailsProduct{
 						{
 							ID:    3,
-							Value: 1836.74,
+							Value: 183674,
 						},
 					},
 				},
@@ -663,10 +1306,16 @@ func TestOrderListDetailsByRangeBuyDate(t *testing.T) {
 		},
 	}
 
+	modelOrdersDetailsPage := model.OrdersDetailsPage{OrdersDetails: modelOrdersDetails}
+
+	// the dates of the policy are relative to the current date
+	validation := newOrderValidationPolicy(model.ValidationPolicy{}).at(time.Now().UTC())
+	today := validation.buyDateMax
+
 	type test struct {
 		name       string
 		inputParam *model.OrderRangeBuyDate
-		wantResult *model.OrdersDetails
+		wantResult *model.OrdersDetailsPage
 		wantError  error
 		mockOn     func(*mock_repository.MockRepository, *mock_cache.MockCache)
 	}
@@ -674,7 +1323,7 @@ func TestOrderListDetailsByRangeBuyDate(t *testing.T) {
 	tests := []test{
 		{
 			name:       "ParamFromEmptyError",
-			inputParam: &model.OrderRangeBuyDate{From: time.Time{}, To: OrderBuyDateMax},
+			inputParam: &model.OrderRangeBuyDate{From: time.Time{}, To: today},
 			wantResult: nil,
 			wantError:  ErrParamValidate{Message: OrderRangeBuyDateErrorMessageFromEmpty},
 			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
@@ -685,14 +1334,14 @@ func TestOrderListDetailsByRangeBuyDate(t *testing.T) {
 			name:       "ParamFromBetweenError",
 			inputParam: &model.OrderRangeBuyDate{From: OrderBuyDateMin.AddDate(0, 0, -1), To: OrderBuyDateMin},
 			wantResult: nil,
-			wantError:  ErrParamValidate{Message: OrderRangeBuyDateErrorMessageFromBetween},
+			wantError:  ErrParamValidate{Message: validation.buyDateMessage(OrderRangeBuyDateErrorMessageFromBetween)},
 			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
 				return
 			},
 		},
 		{
 			name:       "ParamToEmptyError",
-			inputParam: &model.OrderRangeBuyDate{From: OrderBuyDateMax, To: time.Time{}},
+			inputParam: &model.OrderRangeBuyDate{From: today, To: time.Time{}},
 			wantResult: nil,
 			wantError:  ErrParamValidate{Message: OrderRangeBuyDateErrorMessageToEmpty},
 			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
@@ -701,16 +1350,16 @@ func TestOrderListDetailsByRangeBuyDate(t *testing.T) {
 		},
 		{
 			name:       "ParamToEmptyError",
-			inputParam: &model.OrderRangeBuyDate{From: OrderBuyDateMax, To: OrderBuyDateMax.AddDate(0, 0, 1)},
+			inputParam: &model.OrderRangeBuyDate{From: today, To: today.AddDate(0, 0, 1)},
 			wantResult: nil,
-			wantError:  ErrParamValidate{Message: OrderRangeBuyDateErrorMessageToBetween},
+			wantError:  ErrParamValidate{Message: validation.buyDateMessage(OrderRangeBuyDateErrorMessageToBetween)},
 			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
 				return
 			},
 		},
 		{
 			name:       "ParamToSmallerFromError",
-			inputParam: &model.OrderRangeBuyDate{From: OrderBuyDateMax, To: OrderBuyDateMax.AddDate(0, 0, -1)},
+			inputParam: &model.OrderRangeBuyDate{From: today, To: today.AddDate(0, 0, -1)},
 			wantResult: nil,
 			wantError:  ErrParamValidate{Message: OrderRangeBuyDateErrorMessageToSmallerFrom},
 			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
@@ -719,32 +1368,32 @@ func TestOrderListDetailsByRangeBuyDate(t *testing.T) {
 		},
 		{
 			name:       "RangeError",
-			inputParam: &model.OrderRangeBuyDate{From: OrderBuyDateMax.AddDate(0, 0, -32), To: OrderBuyDateMax},
+			inputParam: &model.OrderRangeBuyDate{From: today.AddDate(0, 0, -32), To: today},
 			wantResult: nil,
-			wantError:  ErrParamValidate{Message: OrderRangeBuyDateErrorMessageRangeError},
+			wantError:  ErrParamValidate{Message: fmt.Sprintf(OrderRangeBuyDateErrorMessageRangeError, 31)},
 			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
 				return
 			},
 		},
 		{
 			name:       "RepositoryError",
-			inputParam: &model.OrderRangeBuyDate{From: OrderBuyDateMax, To: OrderBuyDateMax},
+			inputParam: &model.OrderRangeBuyDate{From: today, To: today},
 			wantResult: nil,
 			wantError:  errors.New("Repository Error"),
 			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
 				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
-				mockRepositoryOrder.On("ListDetailsByRangeBuyDate").Return(nil, errors.New("Repository Error"))
+				mockRepositoryOrder.On("ListDetails").Return(nil, errors.New("Repository Error"))
 				mockRepository.On("Order").Return(mockRepositoryOrder)
 			},
 		},
 		{
 			name:       "Success",
-			inputParam: &model.OrderRangeBuyDate{From: OrderBuyDateMax, To: OrderBuyDateMax},
-			wantResult: &modelOrdersDetails,
+			inputParam: &model.OrderRangeBuyDate{From: today, To: today},
+			wantResult: &modelOrdersDetailsPage,
 			wantError:  nil,
 			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
 				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
-				mockRepositoryOrder.On("ListDetailsByRangeBuyDate").Return(&modelOrdersDetails, nil)
+				mockRepositoryOrder.On("ListDetails").Return(&modelOrdersDetailsPage, nil)
 				mockRepository.On("Order").Return(mockRepositoryOrder)
 			},
 		},
@@ -757,9 +1406,9 @@ func TestOrderListDetailsByRangeBuyDate(t *testing.T) {
 
 			tt.mockOn(mockRepository, mockCache)
 
-			usecaseOrder := NewOrder(mockRepository, mockCache)
+			usecaseOrder := NewOrder(mockRepository, mockCache, &util.Config{})
 
-			modelLegacyImportResult, err := usecaseOrder.ListDetailsByRangeBuyDate(tt.inputParam)
+			modelLegacyImportResult, err := usecaseOrder.ListDetails(&model.OrderFilter{BuyDate: tt.inputParam}, &model.OrderPage{})
 
 			if !reflect.DeepEqual(err, tt.wantError) {
 				t.Errorf("Insert() got error = %v, want = %v.", err, tt.wantError)
@@ -771,3 +1420,38 @@ func TestOrderListDetailsByRangeBuyDate(t *testing.T) {
 		})
 	}
 }
+
+func TestOrderListDetailsFilter(t *testing.T) {
+	moneyMin, moneyMax := model.Money(1000), model.Money(999)
+
+	type test struct {
+		name        string
+		inputFilter *model.OrderFilter
+		wantError   error
+	}
+
+	tests := []test{
+		{
+			name:        "MaxTotalSmallerMinError",
+			inputFilter: &model.OrderFilter{MinTotal: &moneyMin, MaxTotal: &moneyMax},
+			wantError:   ErrParamValidate{Message: OrderFilterErrorMessageMaxTotalSmallerMin},
+		},
+		{
+			name:        "MaxValueSmallerMinError",
+			inputFilter: &model.OrderFilter{MinValue: &moneyMin, MaxValue: &moneyMax, MinTotal: &moneyMax, MaxTotal: &moneyMin},
+			wantError:   ErrParamValidate{Message: OrderFilterErrorMessageMaxValueSmallerMin},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecaseOrder := NewOrder(new(mock_repository.MockRepository), new(mock_cache.MockCache), &util.Config{})
+
+			_, err := usecaseOrder.ListDetails(tt.inputFilter, &model.OrderPage{})
+
+			if !reflect.DeepEqual(err, tt.wantError) {
+				t.Errorf("ListDetails() got error = %v, want = %v.", err, tt.wantError)
+			}
+		})
+	}
+}
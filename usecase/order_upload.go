@@ -0,0 +1,395 @@
+package usecase
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/google/uuid"
+)
+
+// offset of the chunk written after the bytes received
+const OrderLegacyUploadOffsetAppend = int64(-1)
+
+var (
+	OrderLegacyUploadExpiration         = 24 * time.Hour
+	OrderErrorMessageUploadNotFound     = "Upload not found"
+	OrderErrorMessageUploadSizeInvalid  = "The param size is invalid"
+	OrderErrorMessageUploadOffset       = "The chunk starts at the byte %d but only %d bytes were received"
+	OrderErrorMessageUploadSizeExceeded = "The chunk exceeds the size of the upload of %d bytes"
+	OrderErrorMessageUploadIncomplete   = "The upload received %d of its %d bytes"
+)
+
+// size of the buffer writing the chunks in the file of the upload
+const legacyUploadBufferSize = 32 << 10
+
+// legacyUpload is a resumable upload, its chunks are written in a temporary
+// file at the offset informed, so a chunk sent again after a failure
+// overwrites the bytes already received
+type legacyUpload struct {
+	// chunks serializes the chunks of the upload, it is held while the body of
+	// the chunk is read
+	chunks sync.Mutex
+	// mutex guards the upload, removed and the writes in the file, it is never
+	// held while the body of a chunk is read
+	mutex  sync.Mutex
+	upload model.LegacyUpload
+	file   *os.File
+	// the upload was finalized, deleted or expired, no chunk is written after
+	removed bool
+}
+
+func (upload *legacyUpload) snapshot() *model.LegacyUpload {
+	upload.mutex.Lock()
+	defer upload.mutex.Unlock()
+
+	modelLegacyUpload := upload.upload
+
+	return &modelLegacyUpload
+}
+
+func (upload *legacyUpload) isRemoved() bool {
+	upload.mutex.Lock()
+	defer upload.mutex.Unlock()
+
+	return upload.removed
+}
+
+func (upload *legacyUpload) expired(now time.Time) bool {
+	upload.mutex.Lock()
+	defer upload.mutex.Unlock()
+
+	return upload.upload.ExpiresAt.Before(now)
+}
+
+// discard removes the temporary file of the upload, a chunk being read stops
+// before its next write
+func (upload *legacyUpload) discard() {
+	upload.mutex.Lock()
+	defer upload.mutex.Unlock()
+
+	upload.removed = true
+
+	upload.file.Close()
+	os.Remove(upload.file.Name())
+}
+
+// write writes the bytes of a chunk at the offset and extends the expiration
+// of the upload
+func (upload *legacyUpload) write(p []byte, offset int64, expiration time.Duration) error {
+	upload.mutex.Lock()
+	defer upload.mutex.Unlock()
+
+	if upload.removed {
+		return ErrNotFound{Message: OrderErrorMessageUploadNotFound}
+	}
+
+	if _, err := upload.file.WriteAt(p, offset); err != nil {
+		return err
+	}
+
+	if end := offset + int64(len(p)); end > upload.upload.Received {
+		upload.upload.Received = end
+	}
+
+	upload.upload.ExpiresAt = time.Now().UTC().Add(expiration)
+
+	return nil
+}
+
+type legacyUploads struct {
+	mutex   sync.Mutex
+	uploads map[string]*legacyUpload
+}
+
+func newLegacyUploads() *legacyUploads {
+	return &legacyUploads{
+		uploads: make(map[string]*legacyUpload),
+	}
+}
+
+func (uploads *legacyUploads) add(upload *legacyUpload) {
+	uploads.purge()
+
+	uploads.mutex.Lock()
+	defer uploads.mutex.Unlock()
+
+	uploads.uploads[upload.upload.ID] = upload
+}
+
+func (uploads *legacyUploads) get(uploadID string) (*legacyUpload, error) {
+	uploads.purge()
+
+	uploads.mutex.Lock()
+	defer uploads.mutex.Unlock()
+
+	upload, ok := uploads.uploads[uploadID]
+
+	if !ok {
+		return nil, ErrNotFound{Message: OrderErrorMessageUploadNotFound}
+	}
+
+	return upload, nil
+}
+
+// purge takes the expired uploads out of the uploads and discards them, the
+// files are removed after releasing the uploads
+func (uploads *legacyUploads) purge() {
+	now := time.Now().UTC()
+	expired := []*legacyUpload{}
+
+	uploads.mutex.Lock()
+
+	for uploadID, upload := range uploads.uploads {
+		if upload.expired(now) {
+			delete(uploads.uploads, uploadID)
+			expired = append(expired, upload)
+		}
+	}
+
+	uploads.mutex.Unlock()
+
+	for _, upload := range expired {
+		upload.discard()
+	}
+}
+
+// remove takes the upload out of the uploads, so no chunk is written after
+// it, when the check of the upload succeeds. The chunk being read is waited
+// for before taking the lock of the uploads.
+func (uploads *legacyUploads) remove(uploadID string, check func(modelLegacyUpload *model.LegacyUpload) error) (*legacyUpload, error) {
+	upload, err := uploads.get(uploadID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	upload.chunks.Lock()
+	defer upload.chunks.Unlock()
+
+	uploads.mutex.Lock()
+	defer uploads.mutex.Unlock()
+
+	upload.mutex.Lock()
+	defer upload.mutex.Unlock()
+
+	if uploads.uploads[uploadID] != upload || upload.removed {
+		return nil, ErrNotFound{Message: OrderErrorMessageUploadNotFound}
+	}
+
+	if err := check(&upload.upload); err != nil {
+		return nil, err
+	}
+
+	delete(uploads.uploads, uploadID)
+	upload.removed = true
+
+	return upload, nil
+}
+
+func (usecaseOrder *UseCaseOrder) legacyUploadExpiration() time.Duration {
+	expiration, err := time.ParseDuration(usecaseOrder.Config.LegacyUploadExpiration)
+
+	if err != nil || expiration <= 0 {
+		return OrderLegacyUploadExpiration
+	}
+
+	return expiration
+}
+
+// CreateLegacyUpload creates the upload session of a file sent in chunks, the
+// size of the file is optional and limits the chunks when informed
+func (usecaseOrder *UseCaseOrder) CreateLegacyUpload(modelLegacyUpload *model.LegacyUpload) (*model.LegacyUpload, error) {
+	if modelLegacyUpload.Size < 0 {
+		return nil, ErrParamValidate{Message: OrderErrorMessageUploadSizeInvalid}
+	}
+
+	if limit := usecaseOrder.Config.LegacyImportMaxSize; limit > 0 && modelLegacyUpload.Size > limit {
+		return nil, ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageFileSizeLimit, limit)}
+	}
+
+	file, err := os.CreateTemp(usecaseOrder.Config.LegacyImportTempDir, "legacy-upload-*.txt")
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	upload := &legacyUpload{
+		upload: model.LegacyUpload{
+			ID:          uuid.New().String(),
+			FileName:    modelLegacyUpload.FileName,
+			ContentType: modelLegacyUpload.ContentType,
+			Size:        modelLegacyUpload.Size,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(usecaseOrder.legacyUploadExpiration()),
+		},
+		file: file,
+	}
+
+	usecaseOrder.legacyUploads.add(upload)
+
+	return upload.snapshot(), nil
+}
+
+func (usecaseOrder *UseCaseOrder) GetLegacyUpload(uploadID string) (*model.LegacyUpload, error) {
+	upload, err := usecaseOrder.legacyUploads.get(uploadID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return upload.snapshot(), nil
+}
+
+// LegacyUploadChunk writes the chunk at the offset, which can not be after the
+// bytes received, or after the bytes received with the offset
+// OrderLegacyUploadOffsetAppend. The bytes of the chunk written before an
+// error reading it are kept, so the upload is resumed from the bytes received.
+// The body of the chunk is read without the lock of the upload, which is only
+// taken to write each buffer read, so the upload is read while it receives
+// a chunk.
+func (usecaseOrder *UseCaseOrder) LegacyUploadChunk(uploadID string, offset int64, chunk io.Reader) (*model.LegacyUpload, error) {
+	upload, err := usecaseOrder.legacyUploads.get(uploadID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	upload.chunks.Lock()
+	defer upload.chunks.Unlock()
+
+	modelLegacyUpload := upload.snapshot()
+
+	if upload.isRemoved() {
+		return nil, ErrNotFound{Message: OrderErrorMessageUploadNotFound}
+	}
+
+	if offset == OrderLegacyUploadOffsetAppend {
+		offset = modelLegacyUpload.Received
+	}
+
+	if offset < 0 || offset > modelLegacyUpload.Received {
+		return nil, ErrConflict{Message: fmt.Sprintf(OrderErrorMessageUploadOffset, offset, modelLegacyUpload.Received)}
+	}
+
+	limit, message := modelLegacyUpload.Size, fmt.Sprintf(OrderErrorMessageUploadSizeExceeded, modelLegacyUpload.Size)
+
+	if limit == 0 && usecaseOrder.Config.LegacyImportMaxSize > 0 {
+		limit, message = usecaseOrder.Config.LegacyImportMaxSize, fmt.Sprintf(OrderErrorMessageFileSizeLimit, usecaseOrder.Config.LegacyImportMaxSize)
+	}
+
+	expiration := usecaseOrder.legacyUploadExpiration()
+	buffer := make([]byte, legacyUploadBufferSize)
+
+	for {
+		n, errRead := chunk.Read(buffer)
+
+		if n > 0 {
+			if limit > 0 && offset+int64(n) > limit {
+				err = ErrFileValidate{Message: message}
+				break
+			}
+
+			if err = upload.write(buffer[:n], offset, expiration); err != nil {
+				break
+			}
+
+			offset += int64(n)
+		}
+
+		if errRead == io.EOF {
+			break
+		}
+
+		if errRead != nil {
+			err = ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageFileRead, errRead)}
+			break
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the upload expires after the expiration without chunks
+	if err = upload.write(nil, offset, expiration); err != nil {
+		return nil, err
+	}
+
+	return upload.snapshot(), nil
+}
+
+func (usecaseOrder *UseCaseOrder) DeleteLegacyUpload(uploadID string) error {
+	upload, err := usecaseOrder.legacyUploads.remove(uploadID, func(modelLegacyUpload *model.LegacyUpload) error {
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	upload.discard()
+
+	return nil
+}
+
+// LegacyUploadImport finalizes the upload importing the file received, the
+// upload is kept to be completed when its file is incomplete or the options
+// are invalid
+func (usecaseOrder *UseCaseOrder) LegacyUploadImport(uploadID string, modelLegacyImportOptions *model.LegacyImportOptions) (*model.LegacyImportResult, error) {
+	upload, err := usecaseOrder.legacyUploadFinalize(uploadID, modelLegacyImportOptions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer upload.discard()
+
+	return usecaseOrder.LegacyImport(upload.file, modelLegacyImportOptions)
+}
+
+// LegacyUploadImportAsync finalizes the upload importing the file received in
+// background, the temporary file of the upload is used by the job
+func (usecaseOrder *UseCaseOrder) LegacyUploadImportAsync(uploadID string, modelLegacyImportOptions *model.LegacyImportOptions) (*model.LegacyImportJob, error) {
+	upload, err := usecaseOrder.legacyUploadFinalize(uploadID, modelLegacyImportOptions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return usecaseOrder.legacyImportJobStart(upload.file, modelLegacyImportOptions), nil
+}
+
+func (usecaseOrder *UseCaseOrder) legacyUploadFinalize(uploadID string, modelLegacyImportOptions *model.LegacyImportOptions) (*legacyUpload, error) {
+	_, err := usecaseOrder.legacyImportOptionsValidate(modelLegacyImportOptions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	upload, err := usecaseOrder.legacyUploads.remove(uploadID, func(modelLegacyUpload *model.LegacyUpload) error {
+		if modelLegacyUpload.Size > 0 && modelLegacyUpload.Received != modelLegacyUpload.Size {
+			return ErrConflict{Message: fmt.Sprintf(OrderErrorMessageUploadIncomplete, modelLegacyUpload.Received, modelLegacyUpload.Size)}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = upload.file.Seek(0, io.SeekStart)
+
+	if err != nil {
+		upload.discard()
+		return nil, err
+	}
+
+	return upload, nil
+}
@@ -0,0 +1,233 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	mock_cache "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/cache"
+	mock_repository "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/util"
+)
+
+// testOrderUploadReader fails after reading its content, as a connection lost
+type testOrderUploadReader struct {
+	content io.Reader
+}
+
+func (reader testOrderUploadReader) Read(p []byte) (int, error) {
+	n, err := reader.content.Read(p)
+
+	if err == io.EOF {
+		err = errors.New("connection reset")
+	}
+
+	return n, err
+}
+
+func TestOrderLegacyUpload(t *testing.T) {
+	mockRepository := new(mock_repository.MockRepository)
+	mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+	mockRepositoryOrder.On("ListLegacyImports").Return(nil, repository.ErrNotFound{Message: "not found"})
+	mockRepositoryOrder.On("LegacyBulkInsert").Return(nil)
+	mockRepository.On("Order").Return(mockRepositoryOrder)
+
+	mockCache := new(mock_cache.MockCache)
+	mockCacheOrder := new(mock_cache.MockCacheOrder)
+	mockCacheOrder.On("ClearAll").Return(nil)
+	mockCache.On("Order").Return(mockCacheOrder)
+
+	usecaseOrder := NewOrder(mockRepository, mockCache, &util.Config{LegacyImportTempDir: t.TempDir()})
+
+	content := []byte("0000000070                              Palmer Prosacco00000007530000000003     1836.7420210308\n" +
+		"0000000075                                  Bobbie Batz00000007980000000002     1578.5720211116")
+	size := int64(len(content))
+
+	_, err := usecaseOrder.CreateLegacyUpload(&model.LegacyUpload{ContentType: "text/plain", Size: -1})
+
+	if !reflect.DeepEqual(err, ErrParamValidate{Message: OrderErrorMessageUploadSizeInvalid}) {
+		t.Errorf("CreateLegacyUpload() got error = %v, want = %v.", err, ErrParamValidate{Message: OrderErrorMessageUploadSizeInvalid})
+	}
+
+	modelLegacyUpload, err := usecaseOrder.CreateLegacyUpload(&model.LegacyUpload{ContentType: "text/plain", Size: size})
+
+	if err != nil {
+		t.Fatalf("CreateLegacyUpload() got error = %v.", err)
+	}
+
+	uploadID := modelLegacyUpload.ID
+
+	// the chunk can not leave a gap after the bytes received
+	_, err = usecaseOrder.LegacyUploadChunk(uploadID, 10, bytes.NewReader(content[10:20]))
+
+	if !reflect.DeepEqual(err, ErrConflict{Message: fmt.Sprintf(OrderErrorMessageUploadOffset, 10, 0)}) {
+		t.Errorf("LegacyUploadChunk() got error = %v, want = %v.", err, ErrConflict{Message: fmt.Sprintf(OrderErrorMessageUploadOffset, 10, 0)})
+	}
+
+	// the bytes read before the connection was lost are kept
+	_, err = usecaseOrder.LegacyUploadChunk(uploadID, 0, testOrderUploadReader{content: bytes.NewReader(content[:50])})
+
+	if _, ok := err.(ErrFileValidate); !ok {
+		t.Errorf("LegacyUploadChunk() got error = %v, want ErrFileValidate.", err)
+	}
+
+	modelLegacyUpload, err = usecaseOrder.GetLegacyUpload(uploadID)
+
+	if err != nil || modelLegacyUpload.Received != 50 {
+		t.Fatalf("GetLegacyUpload() got received = %v, error = %v, want = 50.", modelLegacyUpload, err)
+	}
+
+	_, err = usecaseOrder.LegacyUploadImport(uploadID, &model.LegacyImportOptions{})
+
+	if !reflect.DeepEqual(err, ErrConflict{Message: fmt.Sprintf(OrderErrorMessageUploadIncomplete, 50, size)}) {
+		t.Errorf("LegacyUploadImport() got error = %v, want = %v.", err, ErrConflict{Message: fmt.Sprintf(OrderErrorMessageUploadIncomplete, 50, size)})
+	}
+
+	// the chunk sent again overwrites the bytes received
+	_, err = usecaseOrder.LegacyUploadChunk(uploadID, 40, bytes.NewReader(content[40:100]))
+
+	if err != nil {
+		t.Fatalf("LegacyUploadChunk() got error = %v.", err)
+	}
+
+	_, err = usecaseOrder.LegacyUploadChunk(uploadID, OrderLegacyUploadOffsetAppend, bytes.NewReader(append(content[100:], '\n')))
+
+	if !reflect.DeepEqual(err, ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageUploadSizeExceeded, size)}) {
+		t.Errorf("LegacyUploadChunk() got error = %v, want = %v.", err, ErrFileValidate{Message: fmt.Sprintf(OrderErrorMessageUploadSizeExceeded, size)})
+	}
+
+	modelLegacyUpload, err = usecaseOrder.LegacyUploadChunk(uploadID, OrderLegacyUploadOffsetAppend, bytes.NewReader(content[100:]))
+
+	if err != nil || modelLegacyUpload.Received != size {
+		t.Fatalf("LegacyUploadChunk() got upload = %v, error = %v, want received = %v.", modelLegacyUpload, err, size)
+	}
+
+	modelLegacyImportResult, err := usecaseOrder.LegacyUploadImport(uploadID, &model.LegacyImportOptions{})
+
+	if err != nil {
+		t.Fatalf("LegacyUploadImport() got error = %v.", err)
+	}
+
+	if modelLegacyImportResult.Accepted != 2 {
+		t.Errorf("LegacyUploadImport() got accepted = %v, want = 2.", modelLegacyImportResult.Accepted)
+	}
+
+	_, err = usecaseOrder.GetLegacyUpload(uploadID)
+
+	if !reflect.DeepEqual(err, ErrNotFound{Message: OrderErrorMessageUploadNotFound}) {
+		t.Errorf("GetLegacyUpload() got error = %v, want = %v.", err, ErrNotFound{Message: OrderErrorMessageUploadNotFound})
+	}
+}
+
+func TestOrderDeleteLegacyUpload(t *testing.T) {
+	usecaseOrder := NewOrder(new(mock_repository.MockRepository), new(mock_cache.MockCache), &util.Config{LegacyImportTempDir: t.TempDir()})
+
+	modelLegacyUpload, err := usecaseOrder.CreateLegacyUpload(&model.LegacyUpload{ContentType: "text/plain"})
+
+	if err != nil {
+		t.Fatalf("CreateLegacyUpload() got error = %v.", err)
+	}
+
+	if err := usecaseOrder.DeleteLegacyUpload(modelLegacyUpload.ID); err != nil {
+		t.Errorf("DeleteLegacyUpload() got error = %v.", err)
+	}
+
+	_, err = usecaseOrder.LegacyUploadChunk(modelLegacyUpload.ID, OrderLegacyUploadOffsetAppend, bytes.NewBufferString("record"))
+
+	if !reflect.DeepEqual(err, ErrNotFound{Message: OrderErrorMessageUploadNotFound}) {
+		t.Errorf("LegacyUploadChunk() got error = %v, want = %v.", err, ErrNotFound{Message: OrderErrorMessageUploadNotFound})
+	}
+
+	err = usecaseOrder.DeleteLegacyUpload(modelLegacyUpload.ID)
+
+	if !reflect.DeepEqual(err, ErrNotFound{Message: OrderErrorMessageUploadNotFound}) {
+		t.Errorf("DeleteLegacyUpload() got error = %v, want = %v.", err, ErrNotFound{Message: OrderErrorMessageUploadNotFound})
+	}
+}
+
+func TestOrderLegacyUploadChunkReading(t *testing.T) {
+	usecaseOrder := NewOrder(new(mock_repository.MockRepository), new(mock_cache.MockCache), &util.Config{LegacyImportTempDir: t.TempDir()})
+
+	modelLegacyUpload, err := usecaseOrder.CreateLegacyUpload(&model.LegacyUpload{ContentType: "text/plain"})
+
+	if err != nil {
+		t.Fatalf("CreateLegacyUpload() got error = %v.", err)
+	}
+
+	reader, writer := io.Pipe()
+	done := make(chan error)
+
+	go func() {
+		_, err := usecaseOrder.LegacyUploadChunk(modelLegacyUpload.ID, OrderLegacyUploadOffsetAppend, reader)
+		done <- err
+	}()
+
+	writer.Write([]byte("record"))
+
+	// the upload is read while the chunk waits for the rest of its body
+	got := make(chan *model.LegacyUpload)
+
+	go func() {
+		for {
+			modelLegacyUpload, err := usecaseOrder.GetLegacyUpload(modelLegacyUpload.ID)
+
+			// the bytes read are written after the write in the pipe returns
+			if err != nil || modelLegacyUpload.Received > 0 {
+				got <- modelLegacyUpload
+				return
+			}
+
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	select {
+	case modelLegacyUpload := <-got:
+		if modelLegacyUpload == nil || modelLegacyUpload.Received != 6 {
+			t.Errorf("GetLegacyUpload() got upload = %v, want received = 6.", modelLegacyUpload)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("GetLegacyUpload() blocked by the chunk being read.")
+	}
+
+	writer.Close()
+
+	if err := <-done; err != nil {
+		t.Errorf("LegacyUploadChunk() got error = %v.", err)
+	}
+}
+
+func TestOrderLegacyUploadExpired(t *testing.T) {
+	tempDir := t.TempDir()
+
+	usecaseOrder := NewOrder(new(mock_repository.MockRepository), new(mock_cache.MockCache), &util.Config{
+		LegacyImportTempDir:    tempDir,
+		LegacyUploadExpiration: "1ns",
+	})
+
+	modelLegacyUpload, err := usecaseOrder.CreateLegacyUpload(&model.LegacyUpload{ContentType: "text/plain"})
+
+	if err != nil {
+		t.Fatalf("CreateLegacyUpload() got error = %v.", err)
+	}
+
+	time.Sleep(time.Millisecond)
+
+	_, err = usecaseOrder.GetLegacyUpload(modelLegacyUpload.ID)
+
+	if !reflect.DeepEqual(err, ErrNotFound{Message: OrderErrorMessageUploadNotFound}) {
+		t.Errorf("GetLegacyUpload() got error = %v, want = %v.", err, ErrNotFound{Message: OrderErrorMessageUploadNotFound})
+	}
+
+	// the file of the expired upload is removed when it is read
+	if files, _ := os.ReadDir(tempDir); len(files) > 0 {
+		t.Errorf("GetLegacyUpload() got temporary files = %v of the expired upload.", len(files))
+	}
+}
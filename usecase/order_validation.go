@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+	"unicode/utf8"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+)
+
+var (
+	OrderBuyDateMin               = time.Date(1900, 01, 01, 00, 00, 00, 000, time.UTC)
+	OrderRangeMaxDays             = 31
+	OrderUserNameMinLength        = 2
+	OrderErrorMessageRuleViolated = "%v value violates the rule %v"
+)
+
+// orderValidationPolicy is the validation policy of the config with its
+// values parsed and the defaults applied
+type orderValidationPolicy struct {
+	model.ValidationPolicy
+	buyDateMin      time.Time
+	productValueMin *model.Money
+	productValueMax *model.Money
+	userNamePattern *regexp.Regexp
+}
+
+// newOrderValidationPolicy parses the policy validated by the load of the
+// config, an invalid value is replaced by its default
+func newOrderValidationPolicy(modelValidationPolicy model.ValidationPolicy) *orderValidationPolicy {
+	policy := &orderValidationPolicy{ValidationPolicy: modelValidationPolicy, buyDateMin: OrderBuyDateMin}
+
+	if buyDateMin, err := time.Parse("2006-01-02", modelValidationPolicy.BuyDateMin); err == nil {
+		policy.buyDateMin = buyDateMin
+	}
+
+	if productValueMin, err := model.ParseMoney(modelValidationPolicy.ProductValueMin); err == nil {
+		policy.productValueMin = &productValueMin
+	}
+
+	if productValueMax, err := model.ParseMoney(modelValidationPolicy.ProductValueMax); err == nil {
+		policy.productValueMax = &productValueMax
+	}
+
+	if modelValidationPolicy.UserNamePattern != "" {
+		policy.userNamePattern, _ = regexp.Compile(modelValidationPolicy.UserNamePattern)
+	}
+
+	if policy.UserNameMinLength <= 0 {
+		policy.UserNameMinLength = OrderUserNameMinLength
+	}
+
+	if policy.RangeMaxDays <= 0 {
+		policy.RangeMaxDays = OrderRangeMaxDays
+	}
+
+	return policy
+}
+
+// at evaluates the policy at the moment of the request, the window of the
+// buy dates moves with the current date
+func (policy *orderValidationPolicy) at(now time.Time) *orderValidation {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	validation := &orderValidation{
+		policy:     policy,
+		buyDateMin: policy.buyDateMin,
+		buyDateMax: today.AddDate(0, 0, policy.BuyDateFutureDays),
+	}
+
+	if policy.BuyDatePastDays > 0 {
+		if buyDateMin := today.AddDate(0, 0, -policy.BuyDatePastDays); buyDateMin.After(validation.buyDateMin) {
+			validation.buyDateMin = buyDateMin
+		}
+	}
+
+	return validation
+}
+
+// orderValidation is the policy evaluated at the moment of a request
+type orderValidation struct {
+	policy     *orderValidationPolicy
+	buyDateMin time.Time
+	buyDateMax time.Time
+}
+
+func (validation *orderValidation) buyDate(buyDate time.Time) bool {
+	return !buyDate.Before(validation.buyDateMin) && !buyDate.After(validation.buyDateMax)
+}
+
+// buyDateMessage formats the message of the violation of the window of the
+// buy dates with its dates
+func (validation *orderValidation) buyDateMessage(format string) string {
+	return fmt.Sprintf(format, validation.buyDateMin.Format("2006-01-02"), validation.buyDateMax.Format("2006-01-02"))
+}
+
+func (validation *orderValidation) productValue(productValue model.Money) bool {
+	policy := validation.policy
+
+	return (policy.productValueMin == nil || productValue >= *policy.productValueMin) &&
+		(policy.productValueMax == nil || productValue <= *policy.productValueMax)
+}
+
+// id checks the id against the limits of the rule, a zero limit is unlimited
+func (validation *orderValidation) id(rule string, id int64) bool {
+	policy := validation.policy
+
+	var min, max int64
+
+	switch rule {
+	case model.ValidationRuleUserIDRange:
+		min, max = policy.UserIDMin, policy.UserIDMax
+	case model.ValidationRuleOrderIDRange:
+		min, max = policy.OrderIDMin, policy.OrderIDMax
+	case model.ValidationRuleProductIDRange:
+		min, max = policy.ProductIDMin, policy.ProductIDMax
+	}
+
+	return (min == 0 || id >= min) && (max == 0 || id <= max)
+}
+
+// userName returns the rule violated by the user name, empty when it is valid
+func (validation *orderValidation) userName(userName string) string {
+	policy := validation.policy
+	length := utf8.RuneCountInString(userName)
+
+	if length < policy.UserNameMinLength || (policy.UserNameMaxLength > 0 && length > policy.UserNameMaxLength) {
+		return model.ValidationRuleUserNameLength
+	}
+
+	if policy.userNamePattern != nil && !policy.userNamePattern.MatchString(userName) {
+		return model.ValidationRuleUserNamePattern
+	}
+
+	return ""
+}
+
+func (validation *orderValidation) rangeDays(days int64) bool {
+	return days <= int64(validation.policy.RangeMaxDays)
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	mock_cache "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/cache"
+	mock_repository "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/util"
+)
+
+func TestOrderValidationPolicyRecord(t *testing.T) {
+	today := time.Now().UTC()
+
+	record := func(userID int64, userName string, orderID int64, productID int64, productValue string, buyDate time.Time) string {
+		return fmt.Sprintf("%010d%45s%010d%010d%12s%s", userID, userName, orderID, productID, productValue, buyDate.Format("20060102"))
+	}
+
+	ruleError := func(code string, field string, columnStart int, columnEnd int, value string, rule string, message string) model.LegacyRecordsError {
+		return model.LegacyRecordsError{
+			{
+				Line:    1,
+				Message: message,
+				Errors: []model.LegacyFieldError{
+					{Code: code, Field: field, ColumnStart: columnStart, ColumnEnd: columnEnd, Value: value, Rule: rule, Message: message},
+				},
+			},
+		}
+	}
+
+	type test struct {
+		name       string
+		inputFile  string
+		inputCfg   model.ValidationPolicy
+		wantErrors model.LegacyRecordsError
+	}
+
+	tests := []test{
+		{
+			name:       "Valid",
+			inputFile:  record(70, "Palmer Prosacco", 753, 3, "1836.74", today),
+			inputCfg:   model.ValidationPolicy{UserIDMin: 1, UserIDMax: 100, UserNameMaxLength: 20, UserNamePattern: `^[\p{L} ]+$`, ProductValueMin: "0.01"},
+			wantErrors: model.LegacyRecordsError{},
+		},
+		{
+			name:       "UserIDRangeError",
+			inputFile:  record(70, "Palmer Prosacco", 753, 3, "1836.74", today),
+			inputCfg:   model.ValidationPolicy{UserIDMax: 69},
+			wantErrors: ruleError(OrderErrorCodeUserIDOutOfRange, model.LegacyFieldUserID, 1, 10, "0000000070", model.ValidationRuleUserIDRange, "UserID value violates the rule user_id_range"),
+		},
+		{
+			name:       "OrderIDRangeError",
+			inputFile:  record(70, "Palmer Prosacco", 753, 3, "1836.74", today),
+			inputCfg:   model.ValidationPolicy{OrderIDMin: 1000},
+			wantErrors: ruleError(OrderErrorCodeOrderIDOutOfRange, model.LegacyFieldOrderID, 56, 65, "0000000753", model.ValidationRuleOrderIDRange, "OrderID value violates the rule order_id_range"),
+		},
+		{
+			name:       "ProductIDRangeError",
+			inputFile:  record(70, "Palmer Prosacco", 753, 0, "1836.74", today),
+			inputCfg:   model.ValidationPolicy{ProductIDMin: 1},
+			wantErrors: ruleError(OrderErrorCodeProductIDOutOfRange, model.LegacyFieldProductID, 66, 75, "0000000000", model.ValidationRuleProductIDRange, "ProductID value violates the rule product_id_range"),
+		},
+		{
+			name:       "ProductValueRangeError",
+			inputFile:  record(70, "Palmer Prosacco", 753, 3, "1836.74", today),
+			inputCfg:   model.ValidationPolicy{ProductValueMax: "1000"},
+			wantErrors: ruleError(OrderErrorCodeProductValueOutOfRange, model.LegacyFieldProductValue, 76, 87, "1836.74", model.ValidationRuleProductValueRange, "ProductValue value violates the rule product_value_range"),
+		},
+		{
+			name:       "UserNameLengthError",
+			inputFile:  record(70, "Palmer Prosacco", 753, 3, "1836.74", today),
+			inputCfg:   model.ValidationPolicy{UserNameMaxLength: 10},
+			wantErrors: ruleError(OrderErrorCodeUserNameInvalid, model.LegacyFieldUserName, 11, 55, "Palmer Prosacco", model.ValidationRuleUserNameLength, OrderErrorMessageUserNameInvalid),
+		},
+		{
+			name:       "UserNamePatternError",
+			inputFile:  record(70, "Palmer Prosacco 3rd", 753, 3, "1836.74", today),
+			inputCfg:   model.ValidationPolicy{UserNamePattern: `^[\p{L} ]+$`},
+			wantErrors: ruleError(OrderErrorCodeUserNameInvalid, model.LegacyFieldUserName, 11, 55, "Palmer Prosacco 3rd", model.ValidationRuleUserNamePattern, OrderErrorMessageUserNameInvalid),
+		},
+		{
+			name:       "BuyDatePastDaysError",
+			inputFile:  record(70, "Palmer Prosacco", 753, 3, "1836.74", today.AddDate(0, 0, -31)),
+			inputCfg:   model.ValidationPolicy{BuyDatePastDays: 30},
+			wantErrors: ruleError(OrderErrorCodeBuyDateBetween, model.LegacyFieldBuyDate, 88, 95, today.AddDate(0, 0, -31).Format("20060102"), model.ValidationRuleBuyDateWindow, fmt.Sprintf(OrderErrorMessageBuyDateBetween, today.AddDate(0, 0, -30).Format("2006-01-02"), today.Format("2006-01-02"))),
+		},
+		{
+			name:       "BuyDateFutureDays",
+			inputFile:  record(70, "Palmer Prosacco", 753, 3, "1836.74", today.AddDate(0, 0, 2)),
+			inputCfg:   model.ValidationPolicy{BuyDateFutureDays: 2},
+			wantErrors: model.LegacyRecordsError{},
+		},
+		{
+			name:       "BuyDateFutureError",
+			inputFile:  record(70, "Palmer Prosacco", 753, 3, "1836.74", today.AddDate(0, 0, 1)),
+			inputCfg:   model.ValidationPolicy{},
+			wantErrors: ruleError(OrderErrorCodeBuyDateBetween, model.LegacyFieldBuyDate, 88, 95, today.AddDate(0, 0, 1).Format("20060102"), model.ValidationRuleBuyDateWindow, fmt.Sprintf(OrderErrorMessageBuyDateBetween, "1900-01-01", today.Format("2006-01-02"))),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecaseOrder := NewOrder(new(mock_repository.MockRepository), new(mock_cache.MockCache), &util.Config{ValidationPolicy: tt.inputCfg})
+
+			modelLegacyValidateResult, err := usecaseOrder.LegacyValidate(bytes.NewBufferString(tt.inputFile), &model.LegacyImportOptions{Lenient: true})
+
+			if err != nil {
+				t.Fatalf("LegacyValidate() got error = %v.", err)
+			}
+
+			if !reflect.DeepEqual(modelLegacyValidateResult.Errors, tt.wantErrors) {
+				t.Errorf("LegacyValidate() got errors = %v, want = %v.", modelLegacyValidateResult.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestOrderValidationPolicyRangeBuyDate(t *testing.T) {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	type test struct {
+		name       string
+		inputParam *model.OrderRangeBuyDate
+		inputCfg   model.ValidationPolicy
+		wantError  error
+	}
+
+	tests := []test{
+		{
+			name:       "RangeMaxDaysError",
+			inputParam: &model.OrderRangeBuyDate{From: today.AddDate(0, 0, -8), To: today},
+			inputCfg:   model.ValidationPolicy{RangeMaxDays: 7},
+			wantError:  ErrParamValidate{Message: "the range is greater than 7 days of the rule range_max_days"},
+		},
+		{
+			name:       "BuyDateWindowError",
+			inputParam: &model.OrderRangeBuyDate{From: today.AddDate(0, 0, -11), To: today.AddDate(0, 0, 1)},
+			inputCfg:   model.ValidationPolicy{BuyDateMin: "2000-01-01", BuyDatePastDays: 10},
+			wantError: ErrParamValidate{Message: fmt.Sprintf(OrderRangeBuyDateErrorMessageFromBetween, today.AddDate(0, 0, -10).Format("2006-01-02"), today.Format("2006-01-02")) + ";" +
+				fmt.Sprintf(OrderRangeBuyDateErrorMessageToBetween, today.AddDate(0, 0, -10).Format("2006-01-02"), today.Format("2006-01-02"))},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecaseOrder := NewOrder(new(mock_repository.MockRepository), new(mock_cache.MockCache), &util.Config{ValidationPolicy: tt.inputCfg})
+
+			_, err := usecaseOrder.ListDetails(&model.OrderFilter{BuyDate: tt.inputParam}, &model.OrderPage{})
+
+			if !reflect.DeepEqual(err, tt.wantError) {
+				t.Errorf("ListDetails() got error = %v, want = %v.", err, tt.wantError)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/util"
+)
+
+type Product interface {
+	GetSummaryByProductID(productID int64) (*model.ProductSummary, error)
+	ListOrdersDetails(productID int64, modelOrderPage *model.OrderPage) (*model.OrdersDetailsPage, error)
+}
+
+type UseCaseProduct struct {
+	Repository repository.Repository
+	Config     *util.Config
+}
+
+func NewProduct(repository repository.Repository, config *util.Config) Product {
+	return &UseCaseProduct{
+		Repository: repository,
+		Config:     config,
+	}
+}
+
+func (usecaseProduct *UseCaseProduct) GetSummaryByProductID(productID int64) (*model.ProductSummary, error) {
+	return usecaseProduct.Repository.Product().GetSummaryByProductID(productID)
+}
+
+// ListOrdersDetails returns the orders with the product in the same pages of
+// the order list filtered by the product
+func (usecaseProduct *UseCaseProduct) ListOrdersDetails(productID int64, modelOrderPage *model.OrderPage) (*model.OrdersDetailsPage, error) {
+	err := pageLimitValidate(&modelOrderPage.Limit, usecaseProduct.Config.OrderPageMaxSize)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return usecaseProduct.Repository.Order().ListDetails(&model.OrderFilter{ProductID: &productID}, modelOrderPage)
+}
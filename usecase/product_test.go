@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	mock_repository "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/util"
+)
+
+func TestProductGetSummaryByProductID(t *testing.T) {
+	modelProductSummary := model.ProductSummary{
+		ProductID:     3,
+		TimesSold:     2,
+		Buyers:        1,
+		MinValue:      100954,
+		MaxValue:      183674,
+		AvgValue:      142314,
+		FirstSaleDate: "2021-03-08",
+		LastSaleDate:  "2021-03-08",
+	}
+
+	type test struct {
+		name       string
+		wantResult *model.ProductSummary
+		wantError  error
+		mockOn     func(*mock_repository.MockRepositoryProduct)
+	}
+
+	tests := []test{
+		{
+			name:       "RepositoryError",
+			wantResult: nil,
+			wantError:  errors.New("Repository Error"),
+			mockOn: func(mockRepositoryProduct *mock_repository.MockRepositoryProduct) {
+				mockRepositoryProduct.On("GetSummaryByProductID").Return(nil, errors.New("Repository Error"))
+			},
+		},
+		{
+			name:       "Success",
+			wantResult: &modelProductSummary,
+			wantError:  nil,
+			mockOn: func(mockRepositoryProduct *mock_repository.MockRepositoryProduct) {
+				mockRepositoryProduct.On("GetSummaryByProductID").Return(&modelProductSummary, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepository := new(mock_repository.MockRepository)
+			mockRepositoryProduct := new(mock_repository.MockRepositoryProduct)
+			mockRepository.On("Product").Return(mockRepositoryProduct)
+
+			tt.mockOn(mockRepositoryProduct)
+
+			usecaseProduct := NewProduct(mockRepository, &util.Config{})
+
+			modelProductSummary, err := usecaseProduct.GetSummaryByProductID(3)
+
+			if !reflect.DeepEqual(err, tt.wantError) {
+				t.Errorf("GetSummaryByProductID() got error = %v, want = %v.", err, tt.wantError)
+			}
+
+			if !reflect.DeepEqual(modelProductSummary, tt.wantResult) {
+				t.Errorf("GetSummaryByProductID() got result = %v, want = %v.", modelProductSummary, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestProductListOrdersDetails(t *testing.T) {
+	modelOrdersDetailsPage := model.OrdersDetailsPage{
+		OrdersDetails: model.OrdersDetails{
+			{
+				UserID:   70,
+				UserName: "Palmer Prosacco",
+				Orders: []model.OrderDetailsOrder{
+					{
+						OrderID: 753,
+						BuyDate: "2021-03-08",
+						Total:   183674,
+						Products: []model.OrderDetailsProduct{
+							{
+								ID:    3,
+								Value: 183674,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	type test struct {
+		name       string
+		inputPage  *model.OrderPage
+		wantPage   *model.OrderPage
+		wantResult *model.OrdersDetailsPage
+		wantError  error
+		mockOn     func(*mock_repository.MockRepository)
+	}
+
+	tests := []test{
+		{
+			name:       "ParamLimitError",
+			inputPage:  &model.OrderPage{Limit: 101},
+			wantPage:   &model.OrderPage{Limit: 101},
+			wantResult: nil,
+			wantError:  ErrParamValidate{Message: fmt.Sprintf(OrderPageErrorMessageLimitBetween, 100)},
+			mockOn: func(mockRepository *mock_repository.MockRepository) {
+			},
+		},
+		{
+			name:       "LimitDefaultSuccess",
+			inputPage:  &model.OrderPage{},
+			wantPage:   &model.OrderPage{Limit: 100},
+			wantResult: &modelOrdersDetailsPage,
+			wantError:  nil,
+			mockOn: func(mockRepository *mock_repository.MockRepository) {
+				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("ListDetails").Return(&modelOrdersDetailsPage, nil)
+				mockRepository.On("Order").Return(mockRepositoryOrder)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepository := new(mock_repository.MockRepository)
+
+			tt.mockOn(mockRepository)
+
+			usecaseProduct := NewProduct(mockRepository, &util.Config{OrderPageMaxSize: 100})
+
+			modelOrdersDetailsPage, err := usecaseProduct.ListOrdersDetails(3, tt.inputPage)
+
+			if !reflect.DeepEqual(err, tt.wantError) {
+				t.Errorf("ListOrdersDetails() got error = %v, want = %v.", err, tt.wantError)
+			}
+
+			if !reflect.DeepEqual(modelOrdersDetailsPage, tt.wantResult) {
+				t.Errorf("ListOrdersDetails() got result = %v, want = %v.", modelOrdersDetailsPage, tt.wantResult)
+			}
+
+			if !reflect.DeepEqual(tt.inputPage, tt.wantPage) {
+				t.Errorf("ListOrdersDetails() got page = %v, want = %v.", tt.inputPage, tt.wantPage)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/cache"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/util"
+)
+
+var (
+	ReportErrorMessagePeriodInvalid = fmt.Sprintf("The param period is invalid, the allowed values are %v, %v and %v", model.ReportPeriodDay, model.ReportPeriodWeek, model.ReportPeriodMonth)
+)
+
+type Report interface {
+	ListRevenue(modelReportFilter *model.ReportFilter) (*model.ReportsRevenue, error)
+	ListTopUsers(modelReportFilter *model.ReportFilter) (*model.ReportTopUsers, error)
+	ListTopProducts(modelReportFilter *model.ReportFilter) (*model.ReportTopProducts, error)
+	GetBasket(modelReportFilter *model.ReportFilter) (*model.ReportBasket, error)
+}
+
+type UseCaseReport struct {
+	Repository repository.Repository
+	Cache      cache.Cache
+	Config     *util.Config
+}
+
+func NewReport(repository repository.Repository, cache cache.Cache, config *util.Config) Report {
+	return &UseCaseReport{
+		Repository: repository,
+		Cache:      cache,
+		Config:     config,
+	}
+}
+
+func (usecaseReport *UseCaseReport) ListRevenue(modelReportFilter *model.ReportFilter) (*model.ReportsRevenue, error) {
+	if modelReportFilter.Period == "" {
+		modelReportFilter.Period = model.ReportPeriodDay
+	}
+
+	if modelReportFilter.Period != model.ReportPeriodDay && modelReportFilter.Period != model.ReportPeriodWeek && modelReportFilter.Period != model.ReportPeriodMonth {
+		return nil, ErrParamValidate{Message: ReportErrorMessagePeriodInvalid}
+	}
+
+	err := reportBuyDateValidate(modelReportFilter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return reportCached(usecaseReport, modelReportFilter,
+		usecaseReport.Cache.Report().GetRevenue,
+		usecaseReport.Repository.Report().ListRevenue,
+		usecaseReport.Cache.Report().SetRevenue,
+	)
+}
+
+func (usecaseReport *UseCaseReport) ListTopUsers(modelReportFilter *model.ReportFilter) (*model.ReportTopUsers, error) {
+	err := usecaseReport.reportTopValidate(modelReportFilter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return reportCached(usecaseReport, modelReportFilter,
+		usecaseReport.Cache.Report().GetTopUsers,
+		usecaseReport.Repository.Report().ListTopUsers,
+		usecaseReport.Cache.Report().SetTopUsers,
+	)
+}
+
+func (usecaseReport *UseCaseReport) ListTopProducts(modelReportFilter *model.ReportFilter) (*model.ReportTopProducts, error) {
+	err := usecaseReport.reportTopValidate(modelReportFilter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return reportCached(usecaseReport, modelReportFilter,
+		usecaseReport.Cache.Report().GetTopProducts,
+		usecaseReport.Repository.Report().ListTopProducts,
+		usecaseReport.Cache.Report().SetTopProducts,
+	)
+}
+
+func (usecaseReport *UseCaseReport) GetBasket(modelReportFilter *model.ReportFilter) (*model.ReportBasket, error) {
+	err := reportBuyDateValidate(modelReportFilter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return reportCached(usecaseReport, modelReportFilter,
+		usecaseReport.Cache.Report().GetBasket,
+		usecaseReport.Repository.Report().GetBasket,
+		usecaseReport.Cache.Report().SetBasket,
+	)
+}
+
+// reportTopValidate limits the size of the top reports as the pages of the
+// lists, the param limit of the request is the size
+func (usecaseReport *UseCaseReport) reportTopValidate(modelReportFilter *model.ReportFilter) error {
+	err := pageLimitValidate(&modelReportFilter.Size, usecaseReport.Config.ReportTopMaxSize)
+
+	if err != nil {
+		return err
+	}
+
+	return reportBuyDateValidate(modelReportFilter)
+}
+
+// reportBuyDateValidate does not limit the days of the period, the reports
+// are aggregated by the repository and cached
+func reportBuyDateValidate(modelReportFilter *model.ReportFilter) error {
+	if modelReportFilter.BuyDate != nil && modelReportFilter.BuyDate.To.Before(modelReportFilter.BuyDate.From) {
+		return ErrParamValidate{Message: OrderRangeBuyDateErrorMessageToSmallerFrom}
+	}
+
+	return nil
+}
+
+// reportCached returns the report from the cache of the current version of
+// the dataset, the report is loaded from the repository and cached on a miss
+func reportCached[T any](
+	usecaseReport *UseCaseReport,
+	modelReportFilter *model.ReportFilter,
+	get func(version string, modelReportFilter *model.ReportFilter) (*T, error),
+	load func(modelReportFilter *model.ReportFilter) (*T, error),
+	set func(version string, modelReportFilter *model.ReportFilter, report *T) error,
+) (*T, error) {
+	version, err := datasetVersion(usecaseReport.Repository)
+
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := get(version, modelReportFilter)
+
+	if err == nil {
+		return report, err
+	}
+
+	report, err = load(modelReportFilter)
+
+	if err == nil {
+		set(version, modelReportFilter, report)
+	}
+
+	return report, err
+}
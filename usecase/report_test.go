@@ -0,0 +1,421 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	mock_cache "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/cache"
+	mock_repository "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/util"
+)
+
+func TestReportListRevenue(t *testing.T) {
+	modelReportsRevenue := model.ReportsRevenue{
+		{
+			Period:  "2021-03-01",
+			Orders:  1,
+			Revenue: 284628,
+		},
+	}
+
+	type test struct {
+		name        string
+		inputFilter *model.ReportFilter
+		wantFilter  *model.ReportFilter
+		wantResult  *model.ReportsRevenue
+		wantError   error
+		mockOn      func(*mock_repository.MockRepository, *mock_cache.MockCache)
+	}
+
+	tests := []test{
+		{
+			name:        "ParamPeriodError",
+			inputFilter: &model.ReportFilter{Period: "year"},
+			wantFilter:  &model.ReportFilter{Period: "year"},
+			wantResult:  nil,
+			wantError:   ErrParamValidate{Message: ReportErrorMessagePeriodInvalid},
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+			},
+		},
+		{
+			name: "ParamBuyDateError",
+			inputFilter: &model.ReportFilter{
+				BuyDate: &model.OrderRangeBuyDate{From: time.Date(2021, 3, 8, 0, 0, 0, 0, time.UTC), To: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)},
+				Period:  model.ReportPeriodMonth,
+			},
+			wantFilter: &model.ReportFilter{
+				BuyDate: &model.OrderRangeBuyDate{From: time.Date(2021, 3, 8, 0, 0, 0, 0, time.UTC), To: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)},
+				Period:  model.ReportPeriodMonth,
+			},
+			wantResult: nil,
+			wantError:  ErrParamValidate{Message: OrderRangeBuyDateErrorMessageToSmallerFrom},
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+			},
+		},
+		{
+			name:        "VersionError",
+			inputFilter: &model.ReportFilter{Period: model.ReportPeriodMonth},
+			wantFilter:  &model.ReportFilter{Period: model.ReportPeriodMonth},
+			wantResult:  nil,
+			wantError:   errors.New("Repository Error"),
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("GetLegacyImportLive").Return(nil, errors.New("Repository Error"))
+				mockRepository.On("Order").Return(mockRepositoryOrder)
+				mockRepository.On("Report").Return(new(mock_repository.MockRepositoryReport))
+				mockCache.On("Report").Return(new(mock_cache.MockCacheReport))
+			},
+		},
+		{
+			name:        "CacheSuccess",
+			inputFilter: &model.ReportFilter{Period: model.ReportPeriodMonth},
+			wantFilter:  &model.ReportFilter{Period: model.ReportPeriodMonth},
+			wantResult:  &modelReportsRevenue,
+			wantError:   nil,
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("GetLegacyImportLive").Return(&model.LegacyImport{ID: 2, Live: true}, nil)
+				mockRepository.On("Order").Return(mockRepositoryOrder)
+				mockRepository.On("Report").Return(new(mock_repository.MockRepositoryReport))
+
+				mockCacheReport := new(mock_cache.MockCacheReport)
+				mockCacheReport.On("GetRevenue").Return(&modelReportsRevenue, nil)
+				mockCache.On("Report").Return(mockCacheReport)
+			},
+		},
+		{
+			name:        "RepositoryPeriodDefaultSuccess",
+			inputFilter: &model.ReportFilter{},
+			wantFilter:  &model.ReportFilter{Period: model.ReportPeriodDay},
+			wantResult:  &modelReportsRevenue,
+			wantError:   nil,
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("GetLegacyImportLive").Return(&model.LegacyImport{ID: 2, Live: true}, nil)
+				mockRepository.On("Order").Return(mockRepositoryOrder)
+
+				mockRepositoryReport := new(mock_repository.MockRepositoryReport)
+				mockRepositoryReport.On("ListRevenue").Return(&modelReportsRevenue, nil)
+				mockRepository.On("Report").Return(mockRepositoryReport)
+
+				mockCacheReport := new(mock_cache.MockCacheReport)
+				mockCacheReport.On("GetRevenue").Return(nil, errors.New("Cache Error"))
+				mockCacheReport.On("SetRevenue").Return(nil)
+				mockCache.On("Report").Return(mockCacheReport)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepository := new(mock_repository.MockRepository)
+			mockCache := new(mock_cache.MockCache)
+
+			tt.mockOn(mockRepository, mockCache)
+
+			usecaseReport := NewReport(mockRepository, mockCache, &util.Config{})
+
+			modelReportsRevenue, err := usecaseReport.ListRevenue(tt.inputFilter)
+
+			if !reflect.DeepEqual(err, tt.wantError) {
+				t.Errorf("ListRevenue() got error = %v, want = %v.", err, tt.wantError)
+			}
+
+			if !reflect.DeepEqual(modelReportsRevenue, tt.wantResult) {
+				t.Errorf("ListRevenue() got result = %v, want = %v.", modelReportsRevenue, tt.wantResult)
+			}
+
+			if !reflect.DeepEqual(tt.inputFilter, tt.wantFilter) {
+				t.Errorf("ListRevenue() got filter = %v, want = %v.", tt.inputFilter, tt.wantFilter)
+			}
+		})
+	}
+}
+
+func TestReportListTopUsers(t *testing.T) {
+	modelReportTopUsers := model.ReportTopUsers{
+		{
+			UserID:   70,
+			UserName: "Palmer Prosacco",
+			Orders:   1,
+			Total:    284628,
+		},
+	}
+
+	type test struct {
+		name        string
+		inputFilter *model.ReportFilter
+		wantFilter  *model.ReportFilter
+		wantResult  *model.ReportTopUsers
+		wantError   error
+		mockOn      func(*mock_repository.MockRepository, *mock_cache.MockCache)
+	}
+
+	tests := []test{
+		{
+			name:        "ParamLimitError",
+			inputFilter: &model.ReportFilter{Size: 101},
+			wantFilter:  &model.ReportFilter{Size: 101},
+			wantResult:  nil,
+			wantError:   ErrParamValidate{Message: fmt.Sprintf(OrderPageErrorMessageLimitBetween, 100)},
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+			},
+		},
+		{
+			name:        "RepositoryError",
+			inputFilter: &model.ReportFilter{Size: 1},
+			wantFilter:  &model.ReportFilter{Size: 1},
+			wantResult:  nil,
+			wantError:   errors.New("Repository Error"),
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("GetLegacyImportLive").Return(&model.LegacyImport{ID: 2, Live: true}, nil)
+				mockRepository.On("Order").Return(mockRepositoryOrder)
+
+				mockRepositoryReport := new(mock_repository.MockRepositoryReport)
+				mockRepositoryReport.On("ListTopUsers").Return(nil, errors.New("Repository Error"))
+				mockRepository.On("Report").Return(mockRepositoryReport)
+
+				mockCacheReport := new(mock_cache.MockCacheReport)
+				mockCacheReport.On("GetTopUsers").Return(nil, errors.New("Cache Error"))
+				mockCache.On("Report").Return(mockCacheReport)
+			},
+		},
+		{
+			name:        "LimitDefaultSuccess",
+			inputFilter: &model.ReportFilter{},
+			wantFilter:  &model.ReportFilter{Size: 100},
+			wantResult:  &modelReportTopUsers,
+			wantError:   nil,
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("GetLegacyImportLive").Return(&model.LegacyImport{ID: 2, Live: true}, nil)
+				mockRepository.On("Order").Return(mockRepositoryOrder)
+
+				mockRepositoryReport := new(mock_repository.MockRepositoryReport)
+				mockRepositoryReport.On("ListTopUsers").Return(&modelReportTopUsers, nil)
+				mockRepository.On("Report").Return(mockRepositoryReport)
+
+				mockCacheReport := new(mock_cache.MockCacheReport)
+				mockCacheReport.On("GetTopUsers").Return(nil, errors.New("Cache Error"))
+				mockCacheReport.On("SetTopUsers").Return(nil)
+				mockCache.On("Report").Return(mockCacheReport)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepository := new(mock_repository.MockRepository)
+			mockCache := new(mock_cache.MockCache)
+
+			tt.mockOn(mockRepository, mockCache)
+
+			usecaseReport := NewReport(mockRepository, mockCache, &util.Config{ReportTopMaxSize: 100})
+
+			modelReportTopUsers, err := usecaseReport.ListTopUsers(tt.inputFilter)
+
+			if !reflect.DeepEqual(err, tt.wantError) {
+				t.Errorf("ListTopUsers() got error = %v, want = %v.", err, tt.wantError)
+			}
+
+			if !reflect.DeepEqual(modelReportTopUsers, tt.wantResult) {
+				t.Errorf("ListTopUsers() got result = %v, want = %v.", modelReportTopUsers, tt.wantResult)
+			}
+
+			if !reflect.DeepEqual(tt.inputFilter, tt.wantFilter) {
+				t.Errorf("ListTopUsers() got filter = %v, want = %v.", tt.inputFilter, tt.wantFilter)
+			}
+		})
+	}
+}
+
+func TestReportListTopProducts(t *testing.T) {
+	modelReportTopProducts := model.ReportTopProducts{
+		{
+			ProductID: 3,
+			TimesSold: 3,
+			Revenue:   343302,
+		},
+	}
+
+	type test struct {
+		name        string
+		inputFilter *model.ReportFilter
+		wantFilter  *model.ReportFilter
+		wantResult  *model.ReportTopProducts
+		wantError   error
+		mockOn      func(*mock_repository.MockRepository, *mock_cache.MockCache)
+	}
+
+	tests := []test{
+		{
+			name: "ParamBuyDateError",
+			inputFilter: &model.ReportFilter{
+				BuyDate: &model.OrderRangeBuyDate{From: time.Date(2021, 3, 8, 0, 0, 0, 0, time.UTC), To: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)},
+				Size:    1,
+			},
+			wantFilter: &model.ReportFilter{
+				BuyDate: &model.OrderRangeBuyDate{From: time.Date(2021, 3, 8, 0, 0, 0, 0, time.UTC), To: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)},
+				Size:    1,
+			},
+			wantResult: nil,
+			wantError:  ErrParamValidate{Message: OrderRangeBuyDateErrorMessageToSmallerFrom},
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+			},
+		},
+		{
+			name:        "CacheSuccess",
+			inputFilter: &model.ReportFilter{Size: 1},
+			wantFilter:  &model.ReportFilter{Size: 1},
+			wantResult:  &modelReportTopProducts,
+			wantError:   nil,
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("GetLegacyImportLive").Return(&model.LegacyImport{ID: 2, Live: true}, nil)
+				mockRepository.On("Order").Return(mockRepositoryOrder)
+				mockRepository.On("Report").Return(new(mock_repository.MockRepositoryReport))
+
+				mockCacheReport := new(mock_cache.MockCacheReport)
+				mockCacheReport.On("GetTopProducts").Return(&modelReportTopProducts, nil)
+				mockCache.On("Report").Return(mockCacheReport)
+			},
+		},
+		{
+			name:        "LimitDefaultSuccess",
+			inputFilter: &model.ReportFilter{},
+			wantFilter:  &model.ReportFilter{Size: 100},
+			wantResult:  &modelReportTopProducts,
+			wantError:   nil,
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("GetLegacyImportLive").Return(&model.LegacyImport{ID: 2, Live: true}, nil)
+				mockRepository.On("Order").Return(mockRepositoryOrder)
+
+				mockRepositoryReport := new(mock_repository.MockRepositoryReport)
+				mockRepositoryReport.On("ListTopProducts").Return(&modelReportTopProducts, nil)
+				mockRepository.On("Report").Return(mockRepositoryReport)
+
+				mockCacheReport := new(mock_cache.MockCacheReport)
+				mockCacheReport.On("GetTopProducts").Return(nil, errors.New("Cache Error"))
+				mockCacheReport.On("SetTopProducts").Return(nil)
+				mockCache.On("Report").Return(mockCacheReport)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepository := new(mock_repository.MockRepository)
+			mockCache := new(mock_cache.MockCache)
+
+			tt.mockOn(mockRepository, mockCache)
+
+			usecaseReport := NewReport(mockRepository, mockCache, &util.Config{ReportTopMaxSize: 100})
+
+			modelReportTopProducts, err := usecaseReport.ListTopProducts(tt.inputFilter)
+
+			if !reflect.DeepEqual(err, tt.wantError) {
+				t.Errorf("ListTopProducts() got error = %v, want = %v.", err, tt.wantError)
+			}
+
+			if !reflect.DeepEqual(modelReportTopProducts, tt.wantResult) {
+				t.Errorf("ListTopProducts() got result = %v, want = %v.", modelReportTopProducts, tt.wantResult)
+			}
+
+			if !reflect.DeepEqual(tt.inputFilter, tt.wantFilter) {
+				t.Errorf("ListTopProducts() got filter = %v, want = %v.", tt.inputFilter, tt.wantFilter)
+			}
+		})
+	}
+}
+
+func TestReportGetBasket(t *testing.T) {
+	modelReportBasket := model.NewReportBasket(2, 5, 343302)
+
+	type test struct {
+		name        string
+		inputFilter *model.ReportFilter
+		wantResult  *model.ReportBasket
+		wantError   error
+		mockOn      func(*mock_repository.MockRepository, *mock_cache.MockCache)
+	}
+
+	tests := []test{
+		{
+			name: "ParamBuyDateError",
+			inputFilter: &model.ReportFilter{
+				BuyDate: &model.OrderRangeBuyDate{From: time.Date(2021, 3, 8, 0, 0, 0, 0, time.UTC), To: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)},
+			},
+			wantResult: nil,
+			wantError:  ErrParamValidate{Message: OrderRangeBuyDateErrorMessageToSmallerFrom},
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+			},
+		},
+		{
+			name:        "RepositoryNotFoundError",
+			inputFilter: &model.ReportFilter{},
+			wantResult:  nil,
+			wantError:   repository.ErrNotFound{Message: "not found"},
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("GetLegacyImportLive").Return(nil, repository.ErrNotFound{Message: "not found"})
+				mockRepository.On("Order").Return(mockRepositoryOrder)
+
+				mockRepositoryReport := new(mock_repository.MockRepositoryReport)
+				mockRepositoryReport.On("GetBasket").Return(nil, repository.ErrNotFound{Message: "not found"})
+				mockRepository.On("Report").Return(mockRepositoryReport)
+
+				mockCacheReport := new(mock_cache.MockCacheReport)
+				mockCacheReport.On("GetBasket").Return(nil, errors.New("Cache Error"))
+				mockCache.On("Report").Return(mockCacheReport)
+			},
+		},
+		{
+			name:        "RepositorySuccess",
+			inputFilter: &model.ReportFilter{},
+			wantResult:  modelReportBasket,
+			wantError:   nil,
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("GetLegacyImportLive").Return(&model.LegacyImport{ID: 2, Live: true}, nil)
+				mockRepository.On("Order").Return(mockRepositoryOrder)
+
+				mockRepositoryReport := new(mock_repository.MockRepositoryReport)
+				mockRepositoryReport.On("GetBasket").Return(modelReportBasket, nil)
+				mockRepository.On("Report").Return(mockRepositoryReport)
+
+				mockCacheReport := new(mock_cache.MockCacheReport)
+				mockCacheReport.On("GetBasket").Return(nil, errors.New("Cache Error"))
+				mockCacheReport.On("SetBasket").Return(nil)
+				mockCache.On("Report").Return(mockCacheReport)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepository := new(mock_repository.MockRepository)
+			mockCache := new(mock_cache.MockCache)
+
+			tt.mockOn(mockRepository, mockCache)
+
+			usecaseReport := NewReport(mockRepository, mockCache, &util.Config{})
+
+			modelReportBasket, err := usecaseReport.GetBasket(tt.inputFilter)
+
+			if !reflect.DeepEqual(err, tt.wantError) {
+				t.Errorf("GetBasket() got error = %v, want = %v.", err, tt.wantError)
+			}
+
+			if !reflect.DeepEqual(modelReportBasket, tt.wantResult) {
+				t.Errorf("GetBasket() got result = %v, want = %v.", modelReportBasket, tt.wantResult)
+			}
+		})
+	}
+}
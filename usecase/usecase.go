@@ -1,5 +1,7 @@
 package usecase
 
+import "github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+
 // ErrParamValidate denotes failing validate param.
 type ErrParamValidate struct {
 	Message string
@@ -13,9 +15,44 @@ func (epv ErrParamValidate) Error() string {
 // ErrRecordValidate denotes failing validate record.
 type ErrRecordValidate struct {
 	Message string
+	// first records with error, limited by the max number of errors
+	RecordsError model.LegacyRecordsError
+	// number of errors of the fields found in the file
+	Total     int
+	Truncated bool
 }
 
 // ErrRecordValidate returns the record validation error.
 func (erv ErrRecordValidate) Error() string {
 	return erv.Message
 }
+
+// ErrFileValidate denotes failing validate file.
+type ErrFileValidate struct {
+	Message string
+}
+
+// ErrFileValidate returns the file validation error.
+func (efv ErrFileValidate) Error() string {
+	return efv.Message
+}
+
+// ErrNotFound denotes failing not found.
+type ErrNotFound struct {
+	Message string
+}
+
+// ErrNotFound returns the not found error.
+func (enf ErrNotFound) Error() string {
+	return enf.Message
+}
+
+// ErrConflict denotes failing conflict with the current state.
+type ErrConflict struct {
+	Message string
+}
+
+// ErrConflict returns the conflict error.
+func (ec ErrConflict) Error() string {
+	return ec.Message
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/cache"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/util"
+)
+
+type User interface {
+	GetDetailsByUserID(userID int64) (*model.OrderDetails, error)
+	ListSummaries(modelUserFilter *model.UserFilter, modelUserPage *model.UserPage) (*model.UserSummariesPage, error)
+}
+
+type UseCaseUser struct {
+	Repository repository.Repository
+	Cache      cache.Cache
+	Config     *util.Config
+}
+
+func NewUser(repository repository.Repository, cache cache.Cache, config *util.Config) User {
+	return &UseCaseUser{
+		Repository: repository,
+		Cache:      cache,
+		Config:     config,
+	}
+}
+
+// GetDetailsByUserID returns the orders of the user from the cache of the
+// current version of the dataset, the merges change the orders of a user
+// without telling which users were affected
+func (usecaseUser *UseCaseUser) GetDetailsByUserID(userID int64) (*model.OrderDetails, error) {
+	version, err := datasetVersion(usecaseUser.Repository)
+
+	if err != nil {
+		return nil, err
+	}
+
+	modelOrderDetails, err := usecaseUser.Cache.User().GetDetailsByUserID(version, userID)
+
+	if err == nil {
+		return modelOrderDetails, err
+	}
+
+	modelOrderDetails, err = usecaseUser.Repository.User().GetDetailsByUserID(userID)
+
+	if err == nil {
+		usecaseUser.Cache.User().SetDetailsByUserID(version, modelOrderDetails)
+	}
+
+	return modelOrderDetails, err
+}
+
+func (usecaseUser *UseCaseUser) ListSummaries(modelUserFilter *model.UserFilter, modelUserPage *model.UserPage) (*model.UserSummariesPage, error) {
+	err := pageLimitValidate(&modelUserPage.Limit, usecaseUser.Config.UserPageMaxSize)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return usecaseUser.Repository.User().ListSummaries(modelUserFilter, modelUserPage)
+}
@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	mock_cache "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/cache"
+	mock_repository "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/service/database/repository"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/util"
+)
+
+func TestUserGetDetailsByUserID(t *testing.T) {
+	modelOrderDetails := model.OrderDetails{
+		UserID:   70,
+		UserName: "Palmer Prosacco",
+		Orders: []model.OrderDetailsOrder{
+			{
+				OrderID: 753,
+				BuyDate: "2021-03-08",
+				Total:   183674,
+				Products: []model.OrderDetailsProduct{
+					{
+						ID:    3,
+						Value: 183674,
+					},
+				},
+			},
+		},
+	}
+
+	type test struct {
+		name        string
+		inputUserID int64
+		wantResult  *model.OrderDetails
+		wantError   error
+		mockOn      func(*mock_repository.MockRepository, *mock_cache.MockCache)
+	}
+
+	tests := []test{
+		{
+			name:        "VersionError",
+			inputUserID: 70,
+			wantResult:  nil,
+			wantError:   errors.New("Repository Error"),
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("GetLegacyImportLive").Return(nil, errors.New("Repository Error"))
+				mockRepository.On("Order").Return(mockRepositoryOrder)
+			},
+		},
+		{
+			name:        "RepositoryNotFoundError",
+			inputUserID: 71,
+			wantResult:  nil,
+			wantError:   repository.ErrNotFound{Message: "not found"},
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("GetLegacyImportLive").Return(nil, repository.ErrNotFound{Message: "not found"})
+				mockRepository.On("Order").Return(mockRepositoryOrder)
+
+				mockRepositoryUser := new(mock_repository.MockRepositoryUser)
+				mockRepositoryUser.On("GetDetailsByUserID").Return(nil, repository.ErrNotFound{Message: "not found"})
+				mockRepository.On("User").Return(mockRepositoryUser)
+
+				mockCacheUser := new(mock_cache.MockCacheUser)
+				mockCacheUser.On("GetDetailsByUserID").Return(nil, errors.New("Cache Error"))
+				mockCache.On("User").Return(mockCacheUser)
+			},
+		},
+		{
+			name:        "CacheSuccess",
+			inputUserID: 70,
+			wantResult:  &modelOrderDetails,
+			wantError:   nil,
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("GetLegacyImportLive").Return(&model.LegacyImport{ID: 2, Live: true}, nil)
+				mockRepository.On("Order").Return(mockRepositoryOrder)
+
+				mockCacheUser := new(mock_cache.MockCacheUser)
+				mockCacheUser.On("GetDetailsByUserID").Return(&modelOrderDetails, nil)
+				mockCache.On("User").Return(mockCacheUser)
+			},
+		},
+		{
+			name:        "RepositorySuccess",
+			inputUserID: 70,
+			wantResult:  &modelOrderDetails,
+			wantError:   nil,
+			mockOn: func(mockRepository *mock_repository.MockRepository, mockCache *mock_cache.MockCache) {
+				mockRepositoryOrder := new(mock_repository.MockRepositoryOrder)
+				mockRepositoryOrder.On("GetLegacyImportLive").Return(&model.LegacyImport{ID: 2, Live: true}, nil)
+				mockRepository.On("Order").Return(mockRepositoryOrder)
+
+				mockRepositoryUser := new(mock_repository.MockRepositoryUser)
+				mockRepositoryUser.On("GetDetailsByUserID").Return(&modelOrderDetails, nil)
+				mockRepository.On("User").Return(mockRepositoryUser)
+
+				mockCacheUser := new(mock_cache.MockCacheUser)
+				mockCacheUser.On("GetDetailsByUserID").Return(nil, errors.New("Cache Error"))
+				mockCacheUser.On("SetDetailsByUserID").Return(nil)
+				mockCache.On("User").Return(mockCacheUser)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepository := new(mock_repository.MockRepository)
+			mockCache := new(mock_cache.MockCache)
+
+			tt.mockOn(mockRepository, mockCache)
+
+			usecaseUser := NewUser(mockRepository, mockCache, &util.Config{})
+
+			modelOrderDetails, err := usecaseUser.GetDetailsByUserID(tt.inputUserID)
+
+			if !reflect.DeepEqual(err, tt.wantError) {
+				t.Errorf("GetDetailsByUserID() got error = %v, want = %v.", err, tt.wantError)
+			}
+
+			if !reflect.DeepEqual(modelOrderDetails, tt.wantResult) {
+				t.Errorf("GetDetailsByUserID() got result = %v, want = %v.", modelOrderDetails, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestUserListSummaries(t *testing.T) {
+	modelUserSummariesPage := model.UserSummariesPage{
+		UserSummaries: model.UserSummaries{
+			{
+				UserID:       70,
+				UserName:     "Palmer Prosacco",
+				Orders:       1,
+				FirstBuyDate: "2021-03-08",
+				LastBuyDate:  "2021-03-08",
+				Total:        183674,
+			},
+		},
+	}
+
+	type test struct {
+		name       string
+		inputPage  *model.UserPage
+		wantPage   *model.UserPage
+		wantResult *model.UserSummariesPage
+		wantError  error
+		mockOn     func(*mock_repository.MockRepository)
+	}
+
+	tests := []test{
+		{
+			name:       "ParamLimitError",
+			inputPage:  &model.UserPage{Limit: 101},
+			wantPage:   &model.UserPage{Limit: 101},
+			wantResult: nil,
+			wantError:  ErrParamValidate{Message: fmt.Sprintf(OrderPageErrorMessageLimitBetween, 100)},
+			mockOn: func(mockRepository *mock_repository.MockRepository) {
+			},
+		},
+		{
+			name:       "LimitDefaultSuccess",
+			inputPage:  &model.UserPage{},
+			wantPage:   &model.UserPage{Limit: 100},
+			wantResult: &modelUserSummariesPage,
+			wantError:  nil,
+			mockOn: func(mockRepository *mock_repository.MockRepository) {
+				mockRepositoryUser := new(mock_repository.MockRepositoryUser)
+				mockRepositoryUser.On("ListSummaries").Return(&modelUserSummariesPage, nil)
+				mockRepository.On("User").Return(mockRepositoryUser)
+			},
+		},
+		{
+			name:       "RepositoryError",
+			inputPage:  &model.UserPage{Limit: 1},
+			wantPage:   &model.UserPage{Limit: 1},
+			wantResult: nil,
+			wantError:  errors.New("Repository Error"),
+			mockOn: func(mockRepository *mock_repository.MockRepository) {
+				mockRepositoryUser := new(mock_repository.MockRepositoryUser)
+				mockRepositoryUser.On("ListSummaries").Return(nil, errors.New("Repository Error"))
+				mockRepository.On("User").Return(mockRepositoryUser)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepository := new(mock_repository.MockRepository)
+			mockCache := new(mock_cache.MockCache)
+
+			tt.mockOn(mockRepository)
+
+			usecaseUser := NewUser(mockRepository, mockCache, &util.Config{UserPageMaxSize: 100})
+
+			modelUserSummariesPage, err := usecaseUser.ListSummaries(&model.UserFilter{}, tt.inputPage)
+
+			if !reflect.DeepEqual(err, tt.wantError) {
+				t.Errorf("ListSummaries() got error = %v, want = %v.", err, tt.wantError)
+			}
+
+			if !reflect.DeepEqual(modelUserSummariesPage, tt.wantResult) {
+				t.Errorf("ListSummaries() got result = %v, want = %v.", modelUserSummariesPage, tt.wantResult)
+			}
+
+			if !reflect.DeepEqual(tt.inputPage, tt.wantPage) {
+				t.Errorf("ListSummaries() got page = %v, want = %v.", tt.inputPage, tt.wantPage)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package util
 
 import (
+	"path/filepath"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
 	"github.com/spf13/viper"
 )
 
@@ -18,6 +21,53 @@ type Config struct {
 	DBMigrationURL           string `mapstructure:"DB_MIGRATION_URL"`
 	CacheURL                 string `mapstructure:"CACHE_URL"`
 	CacheExpiration          string `mapstructure:"CACHE_EXPIRATION"`
+	LegacyLayoutsPath        string `mapstructure:"LEGACY_LAYOUTS_PATH"`
+	// limit in bytes of the uploaded file, unlimited when zero
+	LegacyImportMaxSize int64 `mapstructure:"LEGACY_IMPORT_MAX_SIZE"`
+	// limit in bytes of a line of the file, the 64KB of the bufio.Scanner when zero
+	LegacyImportMaxRecordSize int `mapstructure:"LEGACY_IMPORT_MAX_RECORD_SIZE"`
+	// number of records persisted by statement, all of them at once when zero
+	LegacyImportChunkSize int `mapstructure:"LEGACY_IMPORT_CHUNK_SIZE"`
+	// number of goroutines parsing the records of the file, the number of CPUs
+	// when zero and sequential when one
+	LegacyImportParseWorkers int `mapstructure:"LEGACY_IMPORT_PARSE_WORKERS"`
+	// number of errors of the fields of the records returned, unlimited when zero
+	LegacyImportMaxErrors int `mapstructure:"LEGACY_IMPORT_MAX_ERRORS"`
+	// directory of the temporary files of the imports, the system one when empty
+	LegacyImportTempDir string `mapstructure:"LEGACY_IMPORT_TEMP_DIR"`
+	// limit in bytes of the decompressed content of the gzip and zip files, unlimited when zero
+	LegacyImportMaxDecompressedSize int64 `mapstructure:"LEGACY_IMPORT_MAX_DECOMPRESSED_SIZE"`
+	// number of imports kept in the history to be restored, unlimited when zero
+	LegacyImportHistorySize int `mapstructure:"LEGACY_IMPORT_HISTORY_SIZE"`
+	// copies the records of each import to the history to be restored, a
+	// replace writes the whole dataset twice, only the summary is kept when false
+	LegacyImportHistoryRecords bool `mapstructure:"LEGACY_IMPORT_HISTORY_RECORDS"`
+	// time an upload sent in chunks is kept without receiving chunks
+	LegacyUploadExpiration string `mapstructure:"LEGACY_UPLOAD_EXPIRATION"`
+	// directory watched for legacy files to import, disabled when empty
+	LegacyInboxDir string `mapstructure:"LEGACY_INBOX_DIR"`
+	// interval between the scans of the inbox directory
+	LegacyInboxPollInterval string `mapstructure:"LEGACY_INBOX_POLL_INTERVAL"`
+	// time the size of a file must stay unchanged to be imported, a file with
+	// the .done marker is imported right away
+	LegacyInboxStableTime string `mapstructure:"LEGACY_INBOX_STABLE_TIME"`
+	// options of the imports of the inbox files
+	LegacyInboxMode    string `mapstructure:"LEGACY_INBOX_MODE"`
+	LegacyInboxLayout  string `mapstructure:"LEGACY_INBOX_LAYOUT"`
+	LegacyInboxLenient bool   `mapstructure:"LEGACY_INBOX_LENIENT"`
+	// number of orders of a page of the order list, the default of the param
+	// limit and its maximum, unlimited when zero
+	OrderPageMaxSize int `mapstructure:"ORDER_PAGE_MAX_SIZE"`
+	// number of users of a page of the user list, the default of the param
+	// limit and its maximum, unlimited when zero
+	UserPageMaxSize int `mapstructure:"USER_PAGE_MAX_SIZE"`
+	// number of users or products of the top reports, the default of the
+	// param limit and its maximum, unlimited when zero
+	ReportTopMaxSize int `mapstructure:"REPORT_TOP_MAX_SIZE"`
+	// rules validating the orders and the params of the queries
+	model.ValidationPolicy `mapstructure:",squash"`
+	// loaded from the file LegacyLayoutsPath
+	LegacyLayouts model.LegacyLayouts `mapstructure:"-"`
 }
 
 // loadConfig reads configurations from file or environment variables
@@ -37,6 +87,41 @@ func LoadConfig(path string) (config *Config, err error) {
 	viper.SetDefault("DB_MIGRATION_URL", "")
 	viper.SetDefault("CACHE_URL", "")
 	viper.SetDefault("CACHE_EXPIRATION", "1m")
+	viper.SetDefault("LEGACY_LAYOUTS_PATH", "")
+	viper.SetDefault("LEGACY_IMPORT_MAX_SIZE", 10<<30)
+	viper.SetDefault("LEGACY_IMPORT_MAX_RECORD_SIZE", 1<<20)
+	viper.SetDefault("LEGACY_IMPORT_CHUNK_SIZE", 10000)
+	viper.SetDefault("LEGACY_IMPORT_PARSE_WORKERS", 0)
+	viper.SetDefault("LEGACY_IMPORT_MAX_ERRORS", 1000)
+	viper.SetDefault("LEGACY_IMPORT_TEMP_DIR", "")
+	viper.SetDefault("LEGACY_IMPORT_MAX_DECOMPRESSED_SIZE", 1<<30)
+	viper.SetDefault("LEGACY_IMPORT_HISTORY_SIZE", 10)
+	viper.SetDefault("LEGACY_IMPORT_HISTORY_RECORDS", true)
+	viper.SetDefault("LEGACY_UPLOAD_EXPIRATION", "24h")
+	viper.SetDefault("LEGACY_INBOX_DIR", "")
+	viper.SetDefault("LEGACY_INBOX_POLL_INTERVAL", "5s")
+	viper.SetDefault("LEGACY_INBOX_STABLE_TIME", "10s")
+	viper.SetDefault("LEGACY_INBOX_MODE", "")
+	viper.SetDefault("LEGACY_INBOX_LAYOUT", "")
+	viper.SetDefault("LEGACY_INBOX_LENIENT", false)
+	viper.SetDefault("ORDER_PAGE_MAX_SIZE", 1000)
+	viper.SetDefault("USER_PAGE_MAX_SIZE", 1000)
+	viper.SetDefault("REPORT_TOP_MAX_SIZE", 100)
+	viper.SetDefault("VALIDATION_BUY_DATE_MIN", "1900-01-01")
+	viper.SetDefault("VALIDATION_BUY_DATE_PAST_DAYS", 0)
+	viper.SetDefault("VALIDATION_BUY_DATE_FUTURE_DAYS", 0)
+	viper.SetDefault("VALIDATION_PRODUCT_VALUE_MIN", "")
+	viper.SetDefault("VALIDATION_PRODUCT_VALUE_MAX", "")
+	viper.SetDefault("VALIDATION_USER_ID_MIN", 0)
+	viper.SetDefault("VALIDATION_USER_ID_MAX", 0)
+	viper.SetDefault("VALIDATION_ORDER_ID_MIN", 0)
+	viper.SetDefault("VALIDATION_ORDER_ID_MAX", 0)
+	viper.SetDefault("VALIDATION_PRODUCT_ID_MIN", 0)
+	viper.SetDefault("VALIDATION_PRODUCT_ID_MAX", 0)
+	viper.SetDefault("VALIDATION_USER_NAME_MIN_LENGTH", 2)
+	viper.SetDefault("VALIDATION_USER_NAME_MAX_LENGTH", 0)
+	viper.SetDefault("VALIDATION_USER_NAME_PATTERN", "")
+	viper.SetDefault("VALIDATION_RANGE_MAX_DAYS", 31)
 
 	viper.AutomaticEnv()
 
@@ -51,5 +136,24 @@ func LoadConfig(path string) (config *Config, err error) {
 
 	err = viper.Unmarshal(&config)
 
+	if err != nil {
+		return config, err
+	}
+
+	err = ValidationPolicyValidate(&config.ValidationPolicy)
+
+	if err != nil {
+		return config, err
+	}
+
+	legacyLayoutsPath := config.LegacyLayoutsPath
+
+	// a relative path is resolved from the directory of the config file
+	if legacyLayoutsPath != "" && !filepath.IsAbs(legacyLayoutsPath) {
+		legacyLayoutsPath = filepath.Join(path, legacyLayoutsPath)
+	}
+
+	config.LegacyLayouts, err = LoadLegacyLayouts(legacyLayoutsPath)
+
 	return config, err
 }
@@ -0,0 +1,173 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+)
+
+// type accepted by each field of the legacy record
+var legacyLayoutFieldTypes = map[string]string{
+	model.LegacyFieldUserID:       model.LegacyFieldTypeInt,
+	model.LegacyFieldUserName:     model.LegacyFieldTypeString,
+	model.LegacyFieldOrderID:      model.LegacyFieldTypeInt,
+	model.LegacyFieldProductID:    model.LegacyFieldTypeInt,
+	model.LegacyFieldProductValue: model.LegacyFieldTypeDecimal,
+	model.LegacyFieldBuyDate:      model.LegacyFieldTypeDate,
+}
+
+// type accepted by each field of the header record, all of them optional
+var legacyLayoutHeaderFieldTypes = map[string]string{
+	model.LegacyFieldGenerationDate: model.LegacyFieldTypeDate,
+	model.LegacyFieldSource:         model.LegacyFieldTypeString,
+}
+
+// type accepted by each field of the trailer record
+var legacyLayoutTrailerFieldTypes = map[string]string{
+	model.LegacyFieldRecordCount:  model.LegacyFieldTypeInt,
+	model.LegacyFieldControlTotal: model.LegacyFieldTypeDecimal,
+}
+
+// LoadLegacyLayouts reads the layouts of the legacy records from a JSON file,
+// the default layout is included when the file does not redefine it.
+func LoadLegacyLayouts(path string) (model.LegacyLayouts, error) {
+	modelLegacyLayouts := model.LegacyLayouts{}
+
+	if path != "" {
+		file, err := os.Open(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		defer file.Close()
+
+		err = json.NewDecoder(file).Decode(&modelLegacyLayouts)
+
+		if err != nil {
+			return nil, fmt.Errorf("legacy layouts %v: %w", path, err)
+		}
+	}
+
+	names := make(map[string]bool)
+
+	for index := range modelLegacyLayouts {
+		modelLegacyLayout := &modelLegacyLayouts[index]
+
+		if names[modelLegacyLayout.Name] {
+			return nil, fmt.Errorf("legacy layout %v: duplicated", modelLegacyLayout.Name)
+		}
+
+		names[modelLegacyLayout.Name] = true
+
+		err := LegacyLayoutValidate(modelLegacyLayout)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !names[model.LegacyLayoutNameDefault] {
+		modelLegacyLayouts = append(modelLegacyLayouts, model.LegacyLayoutDefault)
+	}
+
+	return modelLegacyLayouts, nil
+}
+
+// LegacyLayoutValidate checks the fields of the layout and calculates the
+// record size when it is not informed.
+func LegacyLayoutValidate(modelLegacyLayout *model.LegacyLayout) error {
+	if modelLegacyLayout.Name == "" {
+		return fmt.Errorf("legacy layout: name is empty")
+	}
+
+	fields, size, err := legacyLayoutFieldsValidate(modelLegacyLayout.Name, "field", modelLegacyLayout.Fields, legacyLayoutFieldTypes)
+
+	if err != nil {
+		return err
+	}
+
+	for fieldName := range legacyLayoutFieldTypes {
+		if !fields[fieldName] {
+			return fmt.Errorf("legacy layout %v: field %v is missing", modelLegacyLayout.Name, fieldName)
+		}
+	}
+
+	_, _, err = legacyLayoutFieldsValidate(modelLegacyLayout.Name, "header field", modelLegacyLayout.Header, legacyLayoutHeaderFieldTypes)
+
+	if err != nil {
+		return err
+	}
+
+	fields, _, err = legacyLayoutFieldsValidate(modelLegacyLayout.Name, "trailer field", modelLegacyLayout.Trailer, legacyLayoutTrailerFieldTypes)
+
+	if err != nil {
+		return err
+	}
+
+	// the trailer is optional, but when defined it has all the control totals
+	for fieldName := range legacyLayoutTrailerFieldTypes {
+		if len(modelLegacyLayout.Trailer) > 0 && !fields[fieldName] {
+			return fmt.Errorf("legacy layout %v: trailer field %v is missing", modelLegacyLayout.Name, fieldName)
+		}
+	}
+
+	if modelLegacyLayout.Size == 0 {
+		modelLegacyLayout.Size = size
+	} else if modelLegacyLayout.Size < size {
+		return fmt.Errorf("legacy layout %v: size is smaller than the end of the fields (%v)", modelLegacyLayout.Name, size)
+	}
+
+	return nil
+}
+
+// legacyLayoutFieldsValidate checks the fields of a record of the layout and
+// returns the names of the fields and the end of the last one
+func legacyLayoutFieldsValidate(layoutName string, kind string, modelLegacyLayoutFields []model.LegacyLayoutField, fieldTypes map[string]string) (map[string]bool, int, error) {
+	fields := make(map[string]bool)
+	size := 0
+
+	for _, field := range modelLegacyLayoutFields {
+		fieldType, ok := fieldTypes[field.Name]
+
+		if !ok {
+			return nil, 0, fmt.Errorf("legacy layout %v: %v %v unknown", layoutName, kind, field.Name)
+		}
+
+		if fields[field.Name] {
+			return nil, 0, fmt.Errorf("legacy layout %v: %v %v duplicated", layoutName, kind, field.Name)
+		}
+
+		fields[field.Name] = true
+
+		if field.Type != fieldType {
+			return nil, 0, fmt.Errorf("legacy layout %v: %v %v type must be %v", layoutName, kind, field.Name, fieldType)
+		}
+
+		if field.Start < 1 || field.Length < 1 {
+			return nil, 0, fmt.Errorf("legacy layout %v: %v %v start and length must be greater than 0", layoutName, kind, field.Name)
+		}
+
+		switch field.Trim {
+		case model.LegacyFieldTrimNone, model.LegacyFieldTrimLeft, model.LegacyFieldTrimRight, model.LegacyFieldTrimBoth:
+		default:
+			return nil, 0, fmt.Errorf("legacy layout %v: %v %v trim %v invalid", layoutName, kind, field.Name, field.Trim)
+		}
+
+		if len(field.Pad) > 1 {
+			return nil, 0, fmt.Errorf("legacy layout %v: %v %v pad must be a single character", layoutName, kind, field.Name)
+		}
+
+		if field.Decimals < 0 {
+			return nil, 0, fmt.Errorf("legacy layout %v: %v %v decimals must not be negative", layoutName, kind, field.Name)
+		}
+
+		if end := field.Start - 1 + field.Length; end > size {
+			size = end
+		}
+	}
+
+	return fields, size, nil
+}
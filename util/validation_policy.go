@@ -0,0 +1,85 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+)
+
+// ValidationPolicyValidate checks the values of the validation policy, so
+// they can be parsed without errors when the requests are validated.
+func ValidationPolicyValidate(modelValidationPolicy *model.ValidationPolicy) error {
+	if modelValidationPolicy.BuyDateMin != "" {
+		if _, err := time.Parse("2006-01-02", modelValidationPolicy.BuyDateMin); err != nil {
+			return fmt.Errorf("validation policy buy date min %v: invalid", modelValidationPolicy.BuyDateMin)
+		}
+	}
+
+	days := map[string]int{
+		"buy date past days":   modelValidationPolicy.BuyDatePastDays,
+		"buy date future days": modelValidationPolicy.BuyDateFutureDays,
+		"user name min length": modelValidationPolicy.UserNameMinLength,
+		"user name max length": modelValidationPolicy.UserNameMaxLength,
+		"range max days":       modelValidationPolicy.RangeMaxDays,
+	}
+
+	for name, value := range days {
+		if value < 0 {
+			return fmt.Errorf("validation policy %v %d: negative", name, value)
+		}
+	}
+
+	productValueMin, err := validationPolicyMoney("product value min", modelValidationPolicy.ProductValueMin)
+
+	if err != nil {
+		return err
+	}
+
+	productValueMax, err := validationPolicyMoney("product value max", modelValidationPolicy.ProductValueMax)
+
+	if err != nil {
+		return err
+	}
+
+	if productValueMin != nil && productValueMax != nil && *productValueMin > *productValueMax {
+		return fmt.Errorf("validation policy product value min %v: greater than the max %v", *productValueMin, *productValueMax)
+	}
+
+	ranges := []struct {
+		name     string
+		min, max int64
+	}{
+		{"user id", modelValidationPolicy.UserIDMin, modelValidationPolicy.UserIDMax},
+		{"order id", modelValidationPolicy.OrderIDMin, modelValidationPolicy.OrderIDMax},
+		{"product id", modelValidationPolicy.ProductIDMin, modelValidationPolicy.ProductIDMax},
+		{"user name length", int64(modelValidationPolicy.UserNameMinLength), int64(modelValidationPolicy.UserNameMaxLength)},
+	}
+
+	for _, limits := range ranges {
+		if limits.min != 0 && limits.max != 0 && limits.min > limits.max {
+			return fmt.Errorf("validation policy %v min %d: greater than the max %d", limits.name, limits.min, limits.max)
+		}
+	}
+
+	if _, err := regexp.Compile(modelValidationPolicy.UserNamePattern); err != nil {
+		return fmt.Errorf("validation policy user name pattern %v: %w", modelValidationPolicy.UserNamePattern, err)
+	}
+
+	return nil
+}
+
+func validationPolicyMoney(name string, value string) (*model.Money, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	money, err := model.ParseMoney(value)
+
+	if err != nil {
+		return nil, fmt.Errorf("validation policy %v %v: invalid", name, value)
+	}
+
+	return &money, nil
+}
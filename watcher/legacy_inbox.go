@@ -0,0 +1,303 @@
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/usecase"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/util"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	LegacyInboxDirProcessed = "processed"
+	LegacyInboxDirFailed    = "failed"
+	LegacyInboxMarkerSuffix = ".done"
+	LegacyInboxReportSuffix = ".json"
+	LegacyInboxRequestedBy  = "inbox"
+	legacyInboxTimeFormat   = "20060102150405"
+)
+
+// legacyInboxPartialSuffixes are the extensions of the files still being
+// uploaded by the SFTP clients, renamed when the upload is complete
+var legacyInboxPartialSuffixes = []string{".part", ".tmp", ".filepart"}
+
+// legacyInboxFileTypes identifies the legacy file by extension, the format of
+// the compressed files is identified by the extension of the files inside it
+var legacyInboxFileTypes = map[string]model.LegacyImportOptions{
+	".csv":    {Format: model.LegacyImportFormatCSV},
+	".ndjson": {Format: model.LegacyImportFormatNDJSON},
+	".jsonl":  {Format: model.LegacyImportFormatNDJSON},
+	".gz":     {Compression: model.LegacyImportCompressionGzip},
+	".zip":    {Compression: model.LegacyImportCompressionZip},
+}
+
+// LegacyInbox imports the legacy files dropped in a directory. A file is
+// imported when its size is unchanged for the stable time or when its .done
+// marker exists, then it is moved to the processed directory or to the failed
+// directory with a JSON report of the error.
+type LegacyInbox struct {
+	Log          hclog.Logger
+	UsecaseOrder usecase.Order
+	Dir          string
+	PollInterval time.Duration
+	StableTime   time.Duration
+	// options of the imports, the format is identified by the file extension
+	Options model.LegacyImportOptions
+	// last size of the files waiting to be complete or that could not be
+	// moved out of the inbox, by name
+	files map[string]legacyInboxFile
+}
+
+type legacyInboxFile struct {
+	size    int64
+	modTime time.Time
+	// moment the size was seen for the first time
+	since time.Time
+	// the file was processed but not moved, it is skipped until it changes
+	unmovable bool
+}
+
+func NewLegacyInbox(log hclog.Logger, usecaseOrder usecase.Order, config *util.Config) (*LegacyInbox, error) {
+	pollInterval, err := time.ParseDuration(config.LegacyInboxPollInterval)
+
+	if err != nil || pollInterval <= 0 {
+		return nil, fmt.Errorf("legacy inbox poll interval %v: invalid", config.LegacyInboxPollInterval)
+	}
+
+	stableTime, err := time.ParseDuration(config.LegacyInboxStableTime)
+
+	if err != nil || stableTime < 0 {
+		return nil, fmt.Errorf("legacy inbox stable time %v: invalid", config.LegacyInboxStableTime)
+	}
+
+	for _, dir := range []string{LegacyInboxDirProcessed, LegacyInboxDirFailed} {
+		if err := os.MkdirAll(filepath.Join(config.LegacyInboxDir, dir), 0o755); err != nil {
+			return nil, err
+		}
+	}
+
+	return &LegacyInbox{
+		Log:          log,
+		UsecaseOrder: usecaseOrder,
+		Dir:          config.LegacyInboxDir,
+		PollInterval: pollInterval,
+		StableTime:   stableTime,
+		Options: model.LegacyImportOptions{
+			Mode:        config.LegacyInboxMode,
+			Layout:      config.LegacyInboxLayout,
+			Lenient:     config.LegacyInboxLenient,
+			RequestedBy: LegacyInboxRequestedBy,
+		},
+		files: make(map[string]legacyInboxFile),
+	}, nil
+}
+
+// Run scans the inbox at each poll interval until the context is done, the
+// import in progress is finished before returning
+func (inbox *LegacyInbox) Run(ctx context.Context) {
+	ticker := time.NewTicker(inbox.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		inbox.Scan(time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Scan imports the complete files of the inbox, in the order of their names
+func (inbox *LegacyInbox) Scan(now time.Time) {
+	entries, err := os.ReadDir(inbox.Dir)
+
+	if err != nil {
+		inbox.Log.Error("Error reading the legacy inbox", "dir", inbox.Dir, "error", err)
+		return
+	}
+
+	names := make(map[string]bool)
+
+	for _, entry := range entries {
+		names[entry.Name()] = true
+	}
+
+	for name := range inbox.files {
+		if !names[name] {
+			delete(inbox.files, name)
+		}
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if !entry.Type().IsRegular() || legacyInboxIgnored(name) {
+			continue
+		}
+
+		info, err := entry.Info()
+
+		if err != nil || inbox.unmovable(name, info) {
+			continue
+		}
+
+		if names[name+LegacyInboxMarkerSuffix] || inbox.stable(name, info, now) {
+			delete(inbox.files, name)
+
+			if !inbox.process(name, now) {
+				inbox.files[name] = legacyInboxFile{size: info.Size(), modTime: info.ModTime(), since: now, unmovable: true}
+			}
+		}
+	}
+}
+
+// legacyInboxIgnored reports whether the file is not a legacy file to be
+// imported, as the hidden files, the markers and the partial uploads
+func legacyInboxIgnored(name string) bool {
+	if strings.HasPrefix(name, ".") || strings.HasSuffix(name, LegacyInboxMarkerSuffix) {
+		return true
+	}
+
+	for _, suffix := range legacyInboxPartialSuffixes {
+		if strings.HasSuffix(strings.ToLower(name), suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// stable reports whether the size and the modification time of the file are
+// unchanged for the stable time, a file seen for the first time is not stable
+func (inbox *LegacyInbox) stable(name string, info os.FileInfo, now time.Time) bool {
+	file, ok := inbox.files[name]
+
+	if !ok || file.size != info.Size() || !file.modTime.Equal(info.ModTime()) {
+		inbox.files[name] = legacyInboxFile{size: info.Size(), modTime: info.ModTime(), since: now}
+		return false
+	}
+
+	return now.Sub(file.since) >= inbox.StableTime
+}
+
+// unmovable reports whether the file was processed but not moved out of the
+// inbox and is unchanged since then, so it is not imported at every scan
+func (inbox *LegacyInbox) unmovable(name string, info os.FileInfo) bool {
+	file, ok := inbox.files[name]
+
+	return ok && file.unmovable && file.size == info.Size() && file.modTime.Equal(info.ModTime())
+}
+
+// process imports the file and moves it out of the inbox with its marker,
+// returning false when the file could not be moved
+func (inbox *LegacyInbox) process(name string, now time.Time) bool {
+	path := filepath.Join(inbox.Dir, name)
+
+	modelLegacyImportResult, err := inbox.legacyImport(path, name)
+
+	moved := true
+
+	if err == nil {
+		inbox.Log.Info("Legacy inbox file imported", "file", name, "accepted", modelLegacyImportResult.Accepted, "rejected", modelLegacyImportResult.Rejected)
+
+		_, err = inbox.move(path, LegacyInboxDirProcessed, now)
+
+		if err != nil {
+			moved = false
+			inbox.Log.Error("Error moving the legacy inbox file, it is skipped until it changes", "file", name, "error", err)
+		}
+	} else {
+		inbox.Log.Error("Error importing the legacy inbox file", "file", name, "error", err)
+
+		target, errMove := inbox.move(path, LegacyInboxDirFailed, now)
+
+		if errMove != nil {
+			moved = false
+			inbox.Log.Error("Error moving the legacy inbox file, it is skipped until it changes", "file", name, "error", errMove)
+		} else if errMove = legacyInboxReportWrite(target+LegacyInboxReportSuffix, legacyInboxReport(name, err, now)); errMove != nil {
+			inbox.Log.Error("Error writing the legacy inbox report", "file", name, "error", errMove)
+		}
+	}
+
+	if err := os.Remove(path + LegacyInboxMarkerSuffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+		inbox.Log.Error("Error removing the legacy inbox marker", "file", name, "error", err)
+	}
+
+	return moved
+}
+
+func (inbox *LegacyInbox) legacyImport(path string, name string) (*model.LegacyImportResult, error) {
+	file, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	return inbox.UsecaseOrder.LegacyImport(file, legacyInboxOptions(inbox.Options, name))
+}
+
+// legacyInboxOptions returns the options of the import of the file with the
+// format identified by its extension, the fixed-width format when unknown
+func legacyInboxOptions(options model.LegacyImportOptions, name string) *model.LegacyImportOptions {
+	fileType := legacyInboxFileTypes[strings.ToLower(filepath.Ext(name))]
+
+	options.Format = fileType.Format
+	options.Compression = fileType.Compression
+	options.FileName = name
+
+	return &options
+}
+
+// move moves the file to the directory inside the inbox, a file with the same
+// name already moved is kept and the time is included in the name of the new one
+func (inbox *LegacyInbox) move(path string, dir string, now time.Time) (string, error) {
+	name := filepath.Base(path)
+	target := filepath.Join(inbox.Dir, dir, name)
+
+	if _, err := os.Stat(target); err == nil {
+		ext := filepath.Ext(name)
+		target = filepath.Join(inbox.Dir, dir, fmt.Sprintf("%v.%v%v", strings.TrimSuffix(name, ext), now.UTC().Format(legacyInboxTimeFormat), ext))
+	}
+
+	return target, os.Rename(path, target)
+}
+
+// legacyInboxReport describes the error of the import, with the errors of the
+// records when the file has invalid records
+func legacyInboxReport(name string, err error, now time.Time) *model.LegacyInboxReport {
+	modelLegacyInboxReport := &model.LegacyInboxReport{
+		File:     name,
+		FailedAt: now.UTC(),
+		Message:  err.Error(),
+	}
+
+	if errRecordValidate, ok := err.(usecase.ErrRecordValidate); ok {
+		modelLegacyInboxReport.Errors = errRecordValidate.RecordsError
+		modelLegacyInboxReport.ErrorsTotal = errRecordValidate.Total
+		modelLegacyInboxReport.ErrorsTruncated = errRecordValidate.Truncated
+	}
+
+	return modelLegacyInboxReport
+}
+
+func legacyInboxReportWrite(path string, modelLegacyInboxReport *model.LegacyInboxReport) error {
+	content, err := json.MarshalIndent(modelLegacyInboxReport, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, content, 0o644)
+}
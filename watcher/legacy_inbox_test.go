@@ -0,0 +1,325 @@
+package watcher
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	mock_usecase "github.com/CharlesSchiavinato/luizalabs-challenge-backend/mock/usecase"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/model"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/usecase"
+	"github.com/CharlesSchiavinato/luizalabs-challenge-backend/util"
+	"github.com/hashicorp/go-hclog"
+)
+
+var testLog = hclog.New(&hclog.LoggerOptions{Level: hclog.LevelFromString("OFF")})
+
+func TestLegacyInboxScan(t *testing.T) {
+	now := time.Date(2023, 6, 11, 10, 0, 0, 0, time.UTC)
+
+	errRecordValidate := usecase.ErrRecordValidate{
+		Message: usecase.OrderErrorMessageRecordValidate,
+		RecordsError: model.LegacyRecordsError{
+			{Line: 1, Message: usecase.OrderErrorMessageUserIDInvalid},
+		},
+		Total: 1,
+	}
+
+	type test struct {
+		name  string
+		files []string
+		// the file is rewritten between the scans
+		growing       bool
+		scans         []time.Duration
+		mockOn        func(*mock_usecase.MockUsecaseOrder)
+		wantInbox     []string
+		wantProcessed []string
+		wantFailed    []string
+		wantReport    *model.LegacyInboxReport
+	}
+
+	tests := []test{
+		{
+			name:  "Marker",
+			files: []string{"data_1.txt", "data_1.txt.done"},
+			scans: []time.Duration{0},
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("LegacyImport").Return(&model.LegacyImportResult{Accepted: 1}, nil)
+			},
+			wantInbox:     []string{},
+			wantProcessed: []string{"data_1.txt"},
+			wantFailed:    []string{},
+		},
+		{
+			name:          "NotStable",
+			files:         []string{"data_1.txt"},
+			scans:         []time.Duration{0, 5 * time.Second},
+			mockOn:        func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {},
+			wantInbox:     []string{"data_1.txt"},
+			wantProcessed: []string{},
+			wantFailed:    []string{},
+		},
+		{
+			name:  "Stable",
+			files: []string{"data_1.txt"},
+			scans: []time.Duration{0, 10 * time.Second},
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("LegacyImport").Return(&model.LegacyImportResult{Accepted: 1}, nil)
+			},
+			wantInbox:     []string{},
+			wantProcessed: []string{"data_1.txt"},
+			wantFailed:    []string{},
+		},
+		{
+			name:          "Growing",
+			files:         []string{"data_1.txt"},
+			growing:       true,
+			scans:         []time.Duration{0, 10 * time.Second, 20 * time.Second},
+			mockOn:        func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {},
+			wantInbox:     []string{"data_1.txt"},
+			wantProcessed: []string{},
+			wantFailed:    []string{},
+		},
+		{
+			name:          "Ignored",
+			files:         []string{".data_1.txt", "data_2.txt.part", "data_3.tmp.done"},
+			scans:         []time.Duration{0, 10 * time.Second},
+			mockOn:        func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {},
+			wantInbox:     []string{".data_1.txt", "data_2.txt.part", "data_3.tmp.done"},
+			wantProcessed: []string{},
+			wantFailed:    []string{},
+		},
+		{
+			name:  "ImportError",
+			files: []string{"data_1.txt", "data_1.txt.done"},
+			scans: []time.Duration{0},
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("LegacyImport").Return(nil, errRecordValidate)
+			},
+			wantInbox:     []string{},
+			wantProcessed: []string{},
+			wantFailed:    []string{"data_1.txt", "data_1.txt.json"},
+			wantReport: &model.LegacyInboxReport{
+				File:        "data_1.txt",
+				FailedAt:    now,
+				Message:     usecase.OrderErrorMessageRecordValidate,
+				Errors:      errRecordValidate.RecordsError,
+				ErrorsTotal: 1,
+			},
+		},
+		{
+			name:  "RepositoryError",
+			files: []string{"data_1.txt", "data_1.txt.done"},
+			scans: []time.Duration{0},
+			mockOn: func(mockUsecaseOrder *mock_usecase.MockUsecaseOrder) {
+				mockUsecaseOrder.On("LegacyImport").Return(nil, errors.New("Repository Error"))
+			},
+			wantInbox:     []string{},
+			wantProcessed: []string{},
+			wantFailed:    []string{"data_1.txt", "data_1.txt.json"},
+			wantReport: &model.LegacyInboxReport{
+				File:     "data_1.txt",
+				FailedAt: now,
+				Message:  "Repository Error",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			mockUsecaseOrder := new(mock_usecase.MockUsecaseOrder)
+			tt.mockOn(mockUsecaseOrder)
+
+			legacyInbox, err := NewLegacyInbox(testLog, mockUsecaseOrder, &util.Config{
+				LegacyInboxDir:          dir,
+				LegacyInboxPollInterval: "1s",
+				LegacyInboxStableTime:   "10s",
+			})
+
+			if err != nil {
+				t.Fatalf("NewLegacyInbox() got error = %v.", err)
+			}
+
+			for _, name := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte("record"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for index, scan := range tt.scans {
+				if tt.growing && index > 0 {
+					content := make([]byte, 6+index)
+
+					if err := os.WriteFile(filepath.Join(dir, tt.files[0]), content, 0o644); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				legacyInbox.Scan(now.Add(scan))
+			}
+
+			if got := testLegacyInboxFiles(t, dir); !reflect.DeepEqual(got, tt.wantInbox) {
+				t.Errorf("Scan() got inbox = %v, want = %v.", got, tt.wantInbox)
+			}
+
+			if got := testLegacyInboxFiles(t, filepath.Join(dir, LegacyInboxDirProcessed)); !reflect.DeepEqual(got, tt.wantProcessed) {
+				t.Errorf("Scan() got processed = %v, want = %v.", got, tt.wantProcessed)
+			}
+
+			if got := testLegacyInboxFiles(t, filepath.Join(dir, LegacyInboxDirFailed)); !reflect.DeepEqual(got, tt.wantFailed) {
+				t.Errorf("Scan() got failed = %v, want = %v.", got, tt.wantFailed)
+			}
+
+			if tt.wantReport == nil {
+				return
+			}
+
+			content, err := os.ReadFile(filepath.Join(dir, LegacyInboxDirFailed, "data_1.txt.json"))
+
+			if err != nil {
+				t.Fatalf("Scan() got report error = %v.", err)
+			}
+
+			modelLegacyInboxReport := &model.LegacyInboxReport{}
+
+			if err := json.Unmarshal(content, modelLegacyInboxReport); err != nil {
+				t.Fatalf("Scan() got report error = %v.", err)
+			}
+
+			if !reflect.DeepEqual(modelLegacyInboxReport, tt.wantReport) {
+				t.Errorf("Scan() got report = %v, want = %v.", modelLegacyInboxReport, tt.wantReport)
+			}
+		})
+	}
+}
+
+func TestLegacyInboxMoveExisting(t *testing.T) {
+	now := time.Date(2023, 6, 11, 10, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+
+	mockUsecaseOrder := new(mock_usecase.MockUsecaseOrder)
+	mockUsecaseOrder.On("LegacyImport").Return(&model.LegacyImportResult{}, nil)
+
+	legacyInbox, err := NewLegacyInbox(testLog, mockUsecaseOrder, &util.Config{
+		LegacyInboxDir:          dir,
+		LegacyInboxPollInterval: "1s",
+		LegacyInboxStableTime:   "0s",
+	})
+
+	if err != nil {
+		t.Fatalf("NewLegacyInbox() got error = %v.", err)
+	}
+
+	// the same file dropped twice is kept twice in the processed directory
+	for index := 0; index < 2; index++ {
+		if err := os.WriteFile(filepath.Join(dir, "data_1.txt"), []byte("record"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		legacyInbox.Scan(now)
+		legacyInbox.Scan(now)
+	}
+
+	want := []string{"data_1.20230611100000.txt", "data_1.txt"}
+
+	if got := testLegacyInboxFiles(t, filepath.Join(dir, LegacyInboxDirProcessed)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Scan() got processed = %v, want = %v.", got, want)
+	}
+}
+
+func TestLegacyInboxUnmovable(t *testing.T) {
+	now := time.Date(2023, 6, 11, 10, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+
+	mockUsecaseOrder := new(mock_usecase.MockUsecaseOrder)
+	mockUsecaseOrder.On("LegacyImport").Return(&model.LegacyImportResult{}, nil)
+
+	legacyInbox, err := NewLegacyInbox(testLog, mockUsecaseOrder, &util.Config{
+		LegacyInboxDir:          dir,
+		LegacyInboxPollInterval: "1s",
+		LegacyInboxStableTime:   "0s",
+	})
+
+	if err != nil {
+		t.Fatalf("NewLegacyInbox() got error = %v.", err)
+	}
+
+	// the file can not be moved to the processed directory replaced by a
+	// broken link, which is not a file of the inbox
+	processed := filepath.Join(dir, LegacyInboxDirProcessed)
+
+	if err := os.Remove(processed); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Symlink(filepath.Join(dir, "missing"), processed); err != nil {
+		t.Fatal(err)
+	}
+
+	// the unchanged file is imported only once, the changed file again
+	for index, content := range []string{"record", "", "record changed"} {
+		if content != "" {
+			if err := os.WriteFile(filepath.Join(dir, "data_1.txt"), []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		legacyInbox.Scan(now.Add(time.Duration(index) * time.Second))
+		legacyInbox.Scan(now.Add(time.Duration(index) * time.Second))
+	}
+
+	mockUsecaseOrder.AssertNumberOfCalls(t, "LegacyImport", 2)
+
+	want := []string{"data_1.txt"}
+
+	if got := testLegacyInboxFiles(t, dir); !reflect.DeepEqual(got, want) {
+		t.Errorf("Scan() got inbox = %v, want = %v.", got, want)
+	}
+}
+
+func TestLegacyInboxOptions(t *testing.T) {
+	options := model.LegacyImportOptions{Mode: model.LegacyImportModeMerge, Lenient: true, RequestedBy: LegacyInboxRequestedBy}
+
+	tests := map[string]model.LegacyImportOptions{
+		"data_1.txt":    {Mode: model.LegacyImportModeMerge, Lenient: true, RequestedBy: LegacyInboxRequestedBy, FileName: "data_1.txt"},
+		"data_1.CSV":    {Mode: model.LegacyImportModeMerge, Lenient: true, RequestedBy: LegacyInboxRequestedBy, FileName: "data_1.CSV", Format: model.LegacyImportFormatCSV},
+		"data_1.jsonl":  {Mode: model.LegacyImportModeMerge, Lenient: true, RequestedBy: LegacyInboxRequestedBy, FileName: "data_1.jsonl", Format: model.LegacyImportFormatNDJSON},
+		"data_1.csv.gz": {Mode: model.LegacyImportModeMerge, Lenient: true, RequestedBy: LegacyInboxRequestedBy, FileName: "data_1.csv.gz", Compression: model.LegacyImportCompressionGzip},
+		"data.zip":      {Mode: model.LegacyImportModeMerge, Lenient: true, RequestedBy: LegacyInboxRequestedBy, FileName: "data.zip", Compression: model.LegacyImportCompressionZip},
+	}
+
+	for name, want := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := legacyInboxOptions(options, name); !reflect.DeepEqual(*got, want) {
+				t.Errorf("legacyInboxOptions() got = %v, want = %v.", *got, want)
+			}
+		})
+	}
+}
+
+func testLegacyInboxFiles(t *testing.T, dir string) []string {
+	entries, err := os.ReadDir(dir)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := []string{}
+
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			names = append(names, entry.Name())
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}